@@ -0,0 +1,179 @@
+// Command twilio-cli is a small example program using the twirest package.
+// Credentials are read from TWILIO_ACCOUNT_SID and TWILIO_AUTH_TOKEN, results
+// are printed as JSON.
+//
+// Usage:
+//
+//	twilio-cli sms send -from +15005550006 -to +15005550001 -body "Hello"
+//	twilio-cli call make -from +15005550006 -to +15005550001 -url http://demo.twilio.com/docs/voice.xml
+//	twilio-cli messages list -since 2014-01-31
+//	twilio-cli recordings download -sid RE123 -out call.wav
+//	twilio-cli number search -country US -area-code 415
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/seanhagen/twilio/twirest"
+)
+
+func main() {
+	if len(os.Args) < 3 {
+		usage()
+	}
+
+	client, err := twirest.NewClient(os.Getenv("TWILIO_ACCOUNT_SID"),
+		os.Getenv("TWILIO_AUTH_TOKEN"))
+	if err != nil {
+		fail(err)
+	}
+
+	cmd, args := os.Args[1]+" "+os.Args[2], os.Args[3:]
+	switch cmd {
+	default:
+		usage()
+	case "sms send":
+		err = sendSMS(client, args)
+	case "call make":
+		err = makeCall(client, args)
+	case "messages list":
+		err = listMessages(client, args)
+	case "recordings download":
+		err = downloadRecording(client, args)
+	case "number search":
+		err = searchNumbers(client, args)
+	}
+	if err != nil {
+		fail(err)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: twilio-cli sms send|call make|messages list|"+
+		"recordings download|number search [flags]")
+	os.Exit(2)
+}
+
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, "error:", err)
+	os.Exit(1)
+}
+
+// printJSON writes v as indented JSON to stdout
+func printJSON(v interface{}) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+func sendSMS(client *twirest.TwilioClient, args []string) error {
+	fs := flag.NewFlagSet("sms send", flag.ExitOnError)
+	msg := twirest.SendMessage{}
+	fs.StringVar(&msg.From, "from", "", "sender number or alphanumeric ID")
+	fs.StringVar(&msg.To, "to", "", "recipient number")
+	fs.StringVar(&msg.Text, "body", "", "message text")
+	fs.StringVar(&msg.MediaUrl, "media", "", "url of media to attach")
+	fs.Parse(args)
+
+	resp, err := client.Request(msg, false)
+	if err != nil {
+		return err
+	}
+	return printJSON(resp.Message)
+}
+
+func makeCall(client *twirest.TwilioClient, args []string) error {
+	fs := flag.NewFlagSet("call make", flag.ExitOnError)
+	call := twirest.MakeCall{}
+	fs.StringVar(&call.From, "from", "", "caller number")
+	fs.StringVar(&call.To, "to", "", "called number")
+	fs.StringVar(&call.Url, "url", "", "url of the TwiML to run")
+	fs.Parse(args)
+
+	resp, err := client.Request(call, false)
+	if err != nil {
+		return err
+	}
+	return printJSON(resp.Call)
+}
+
+// listMessages prints the messages sent since a date, one page at a time
+func listMessages(client *twirest.TwilioClient, args []string) error {
+	fs := flag.NewFlagSet("messages list", flag.ExitOnError)
+	req := twirest.Messages{}
+	fs.StringVar(&req.DateSentAfter, "since", "", "list messages sent on or after YYYY-MM-DD")
+	fs.StringVar(&req.To, "to", "", "list messages to number")
+	fs.StringVar(&req.From, "from", "", "list messages from number")
+	fs.Parse(args)
+
+	ctx := context.Background()
+	resp, err := client.RequestWithContext(ctx, req, false)
+	for err == nil && resp.Messages != nil {
+		for _, m := range resp.Messages.Message {
+			if err := printJSON(m); err != nil {
+				return err
+			}
+		}
+		if resp.Messages.NextPageUri == "" {
+			return nil
+		}
+		resp, err = client.NextPage(ctx, resp.Messages.NextPageUri, req)
+	}
+	return err
+}
+
+// downloadRecording streams the audio of a recording to a file or stdout
+func downloadRecording(client *twirest.TwilioClient, args []string) error {
+	fs := flag.NewFlagSet("recordings download", flag.ExitOnError)
+	sid := fs.String("sid", "", "recording sid")
+	mp3 := fs.Bool("mp3", false, "download as MP3 instead of WAV")
+	dual := fs.Bool("dual", false, "keep both channels of dual channel recordings")
+	out := fs.String("out", "", "file to write to, stdout if empty")
+	fs.Parse(args)
+
+	channels := 1
+	if *dual {
+		channels = 2
+	}
+	resp, err := client.Request(twirest.RecordingDownload(*sid, channels, *mp3), false)
+	if err != nil {
+		return err
+	}
+	if resp.RecordingAudio == nil {
+		return fmt.Errorf("no audio for recording: '%s'", *sid)
+	}
+	defer resp.RecordingAudio.Data.Close()
+
+	var w io.Writer = os.Stdout
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+	_, err = io.Copy(w, resp.RecordingAudio.Data)
+	return err
+}
+
+func searchNumbers(client *twirest.TwilioClient, args []string) error {
+	fs := flag.NewFlagSet("number search", flag.ExitOnError)
+	req := twirest.AvailablePhoneNumbers{}
+	fs.StringVar(&req.CountryCode, "country", "US", "ISO country code")
+	fs.StringVar(&req.Type, "type", "Local", "Local, TollFree or Mobile")
+	fs.StringVar(&req.AreaCode, "area-code", "", "area code")
+	fs.StringVar(&req.Contains, "contains", "", "pattern the number contains")
+	fs.Parse(args)
+
+	resp, err := client.Request(req, false)
+	if err != nil {
+		return err
+	}
+	return printJSON(resp.AvailablePhoneNumbers)
+}
@@ -0,0 +1,61 @@
+package stream
+
+// G.711 mulaw constants
+const (
+	mulawBias = 0x84
+	mulawClip = 32635
+)
+
+// DecodeMulaw converts mulaw audio, the encoding of media streams, to 16 bit
+// linear PCM samples
+func DecodeMulaw(mulaw []byte) []int16 {
+	pcm := make([]int16, len(mulaw))
+	for i, u := range mulaw {
+		pcm[i] = mulawToLinear(u)
+	}
+	return pcm
+}
+
+// EncodeMulaw converts 16 bit linear PCM samples to mulaw audio, for media
+// sent on bidirectional streams
+func EncodeMulaw(pcm []int16) []byte {
+	mulaw := make([]byte, len(pcm))
+	for i, s := range pcm {
+		mulaw[i] = linearToMulaw(s)
+	}
+	return mulaw
+}
+
+// mulawToLinear decodes a mulaw sample
+func mulawToLinear(u byte) int16 {
+	u = ^u
+	exponent := (u >> 4) & 0x07
+	mantissa := int32(u & 0x0F)
+	sample := ((mantissa << 3) + mulawBias) << exponent
+	sample -= mulawBias
+	if u&0x80 != 0 {
+		return int16(-sample)
+	}
+	return int16(sample)
+}
+
+// linearToMulaw encodes a sample as mulaw
+func linearToMulaw(s int16) byte {
+	sample := int32(s)
+	var sign byte
+	if sample < 0 {
+		sign = 0x80
+		sample = -sample
+	}
+	if sample > mulawClip {
+		sample = mulawClip
+	}
+	sample += mulawBias
+
+	exponent := byte(7)
+	for mask := int32(0x4000); sample&mask == 0 && exponent > 0; mask >>= 1 {
+		exponent--
+	}
+	mantissa := byte(sample>>(exponent+3)) & 0x0F
+	return ^(sign | exponent<<4 | mantissa)
+}
@@ -0,0 +1,205 @@
+// Package stream provides the messages of twilio Media Streams, the json
+// frames twilio sends over the websocket a <Stream> connects to and the
+// frames sent back on bidirectional streams.
+package stream
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+)
+
+// Frame events
+const (
+	EventConnected = "connected"
+	EventStart     = "start"
+	EventMedia     = "media"
+	EventStop      = "stop"
+	EventMark      = "mark"
+	EventDTMF      = "dtmf"
+	EventClear     = "clear"
+)
+
+// Frame is a message received from twilio, one of *Connected, *Start,
+// *Media, *Stop, *Mark and *DTMF
+type Frame interface {
+	Event() string
+}
+
+// Connected is the first frame of a stream
+type Connected struct {
+	Protocol string `json:"protocol"`
+	Version  string `json:"version"`
+}
+
+// Start holds the metadata of the stream, sent once after Connected
+type Start struct {
+	SequenceNumber string        `json:"sequenceNumber"`
+	StreamSid      string        `json:"streamSid"`
+	Start          StartMetadata `json:"start"`
+}
+
+// StartMetadata describes the call and the audio of the stream.
+// CustomParameters are the <Parameter> nouns of the <Stream>.
+type StartMetadata struct {
+	AccountSid       string            `json:"accountSid"`
+	CallSid          string            `json:"callSid"`
+	StreamSid        string            `json:"streamSid"`
+	Tracks           []string          `json:"tracks"`
+	CustomParameters map[string]string `json:"customParameters"`
+	MediaFormat      MediaFormat       `json:"mediaFormat"`
+}
+
+// MediaFormat is the audio format of the stream, 8000 Hz mono mulaw
+type MediaFormat struct {
+	Encoding   string `json:"encoding"`
+	SampleRate int    `json:"sampleRate"`
+	Channels   int    `json:"channels"`
+}
+
+// Media holds a chunk of audio of a track
+type Media struct {
+	SequenceNumber string       `json:"sequenceNumber"`
+	StreamSid      string       `json:"streamSid"`
+	Media          MediaPayload `json:"media"`
+}
+
+// MediaPayload is a chunk of audio, Payload is the base64 encoded mulaw audio.
+// Timestamp is in milliseconds from the start of the stream.
+type MediaPayload struct {
+	Track     string `json:"track"`
+	Chunk     string `json:"chunk"`
+	Timestamp string `json:"timestamp"`
+	Payload   string `json:"payload"`
+}
+
+// Audio returns the mulaw audio of the media
+func (m *Media) Audio() ([]byte, error) {
+	return base64.StdEncoding.DecodeString(m.Media.Payload)
+}
+
+// PCM returns the audio of the media as 16 bit linear PCM samples
+func (m *Media) PCM() ([]int16, error) {
+	audio, err := m.Audio()
+	if err != nil {
+		return nil, err
+	}
+	return DecodeMulaw(audio), nil
+}
+
+// Stop is the last frame of a stream
+type Stop struct {
+	SequenceNumber string       `json:"sequenceNumber"`
+	StreamSid      string       `json:"streamSid"`
+	Stop           StopMetadata `json:"stop"`
+}
+
+// StopMetadata identifies the call of a stopped stream
+type StopMetadata struct {
+	AccountSid string `json:"accountSid"`
+	CallSid    string `json:"callSid"`
+}
+
+// Mark is sent when the audio before a mark sent on a bidirectional stream has
+// played, or was cleared
+type Mark struct {
+	SequenceNumber string   `json:"sequenceNumber"`
+	StreamSid      string   `json:"streamSid"`
+	Mark           MarkName `json:"mark"`
+}
+
+// MarkName names a mark
+type MarkName struct {
+	Name string `json:"name"`
+}
+
+// DTMF is a key pressed by the caller, on bidirectional streams
+type DTMF struct {
+	SequenceNumber string     `json:"sequenceNumber"`
+	StreamSid      string     `json:"streamSid"`
+	DTMF           DTMFDigits `json:"dtmf"`
+}
+
+// DTMFDigits is the key pressed and the track it was pressed on
+type DTMFDigits struct {
+	Track string `json:"track"`
+	Digit string `json:"digit"`
+}
+
+func (*Connected) Event() string { return EventConnected }
+func (*Start) Event() string     { return EventStart }
+func (*Media) Event() string     { return EventMedia }
+func (*Stop) Event() string      { return EventStop }
+func (*Mark) Event() string      { return EventMark }
+func (*DTMF) Event() string      { return EventDTMF }
+
+// Decode parses a message received from twilio into the Frame of its event
+func Decode(msg []byte) (Frame, error) {
+	var envelope struct {
+		Event string `json:"event"`
+	}
+	if err := json.Unmarshal(msg, &envelope); err != nil {
+		return nil, err
+	}
+
+	var frame Frame
+	switch envelope.Event {
+	default:
+		return nil, fmt.Errorf("non valid event: '%s'", envelope.Event)
+	case EventConnected:
+		frame = new(Connected)
+	case EventStart:
+		frame = new(Start)
+	case EventMedia:
+		frame = new(Media)
+	case EventStop:
+		frame = new(Stop)
+	case EventMark:
+		frame = new(Mark)
+	case EventDTMF:
+		frame = new(DTMF)
+	}
+	if err := json.Unmarshal(msg, frame); err != nil {
+		return nil, err
+	}
+	return frame, nil
+}
+
+// outbound is a frame sent to twilio on a bidirectional stream
+type outbound struct {
+	Event     string        `json:"event"`
+	StreamSid string        `json:"streamSid"`
+	Media     *outboundData `json:"media,omitempty"`
+	Mark      *MarkName     `json:"mark,omitempty"`
+}
+
+// outboundData is the audio of an outbound media frame
+type outboundData struct {
+	Payload string `json:"payload"`
+}
+
+// EncodeMedia returns the frame playing mulaw audio, 8000 Hz mono, on the call
+// of the stream
+func EncodeMedia(streamSid string, mulaw []byte) ([]byte, error) {
+	return json.Marshal(outbound{
+		Event:     EventMedia,
+		StreamSid: streamSid,
+		Media:     &outboundData{Payload: base64.StdEncoding.EncodeToString(mulaw)},
+	})
+}
+
+// EncodeMark returns the frame marking the end of the audio sent so far,
+// twilio sends a Mark named name back when it has played
+func EncodeMark(streamSid, name string) ([]byte, error) {
+	return json.Marshal(outbound{
+		Event:     EventMark,
+		StreamSid: streamSid,
+		Mark:      &MarkName{Name: name},
+	})
+}
+
+// EncodeClear returns the frame discarding the audio sent but not played yet,
+// for pending marks twilio sends a Mark back
+func EncodeClear(streamSid string) ([]byte, error) {
+	return json.Marshal(outbound{Event: EventClear, StreamSid: streamSid})
+}
@@ -0,0 +1,109 @@
+package stream
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDecode(t *testing.T) {
+	var tests = []struct {
+		Msg    string
+		Expect Frame
+	}{
+		{`{"event":"connected","protocol":"Call","version":"1.0.0"}`,
+			&Connected{Protocol: "Call", Version: "1.0.0"}},
+		{`{"event":"start","sequenceNumber":"1","start":{"accountSid":"AC123",` +
+			`"streamSid":"MZ123","callSid":"CA123","tracks":["inbound","outbound"],` +
+			`"customParameters":{"agent":"alice"},"mediaFormat":{"encoding":"audio/x-mulaw",` +
+			`"sampleRate":8000,"channels":1}},"streamSid":"MZ123"}`,
+			&Start{SequenceNumber: "1", StreamSid: "MZ123", Start: StartMetadata{
+				AccountSid: "AC123", CallSid: "CA123", StreamSid: "MZ123",
+				Tracks:           []string{"inbound", "outbound"},
+				CustomParameters: map[string]string{"agent": "alice"},
+				MediaFormat:      MediaFormat{"audio/x-mulaw", 8000, 1}}}},
+		{`{"event":"media","sequenceNumber":"3","media":{"track":"inbound","chunk":"1",` +
+			`"timestamp":"5","payload":"/38AgA=="},"streamSid":"MZ123"}`,
+			&Media{SequenceNumber: "3", StreamSid: "MZ123", Media: MediaPayload{
+				Track: "inbound", Chunk: "1", Timestamp: "5", Payload: "/38AgA=="}}},
+		{`{"event":"stop","sequenceNumber":"5","stop":{"accountSid":"AC123",` +
+			`"callSid":"CA123"},"streamSid":"MZ123"}`,
+			&Stop{SequenceNumber: "5", StreamSid: "MZ123",
+				Stop: StopMetadata{AccountSid: "AC123", CallSid: "CA123"}}},
+		{`{"event":"mark","sequenceNumber":"4","streamSid":"MZ123","mark":{"name":"greeting"}}`,
+			&Mark{SequenceNumber: "4", StreamSid: "MZ123", Mark: MarkName{"greeting"}}},
+		{`{"event":"dtmf","streamSid":"MZ123","sequenceNumber":"6",` +
+			`"dtmf":{"track":"inbound_track","digit":"1"}}`,
+			&DTMF{SequenceNumber: "6", StreamSid: "MZ123",
+				DTMF: DTMFDigits{Track: "inbound_track", Digit: "1"}}},
+		{`{"event":"unknown"}`, nil},
+		{`{"event":"media","media":"not an object"}`, nil},
+		{`not json`, nil},
+	}
+
+	for idx, test := range tests {
+		frame, err := Decode([]byte(test.Msg))
+		if (err == nil) != (test.Expect != nil) {
+			t.Errorf("Test %v failed; expected frame %v, got error %v", idx, test.Expect, err)
+			continue
+		}
+		if !reflect.DeepEqual(frame, test.Expect) {
+			t.Errorf("Test %v failed; expected %#v, got %#v", idx, test.Expect, frame)
+		}
+	}
+}
+
+func TestMediaPCM(t *testing.T) {
+	frame, err := Decode([]byte(`{"event":"media","media":{"payload":"/38AgA=="}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pcm, err := frame.(*Media).PCM()
+	expect := []int16{0, 0, -32124, 32124}
+	if err != nil || !reflect.DeepEqual(pcm, expect) {
+		t.Errorf("expected %v, got %v (%v)", expect, pcm, err)
+	}
+
+	if _, err := (&Media{Media: MediaPayload{Payload: "not base64!"}}).PCM(); err == nil {
+		t.Errorf("expected an error for a non valid payload")
+	}
+}
+
+func TestMulawRoundTrip(t *testing.T) {
+	for u := 0; u < 256; u++ {
+		got := EncodeMulaw(DecodeMulaw([]byte{byte(u)}))[0]
+		// mulaw has a positive and a negative zero
+		expect := byte(u)
+		if u == 0x7F {
+			expect = 0xFF
+		}
+		if got != expect {
+			t.Errorf("Test %v failed; got %#x", u, got)
+		}
+	}
+
+	clipped := EncodeMulaw([]int16{32767, -32768})
+	if clipped[0] != 0x80 || clipped[1] != 0x00 {
+		t.Errorf("expected clipped samples, got %#x", clipped)
+	}
+}
+
+func TestEncodeOutbound(t *testing.T) {
+	media, _ := EncodeMedia("MZ123", []byte{0xFF, 0x7F, 0x00, 0x80})
+	mark, _ := EncodeMark("MZ123", "greeting")
+	clear, _ := EncodeClear("MZ123")
+
+	var tests = []struct {
+		Got    []byte
+		Expect string
+	}{
+		{media, `{"event":"media","streamSid":"MZ123","media":{"payload":"/38AgA=="}}`},
+		{mark, `{"event":"mark","streamSid":"MZ123","mark":{"name":"greeting"}}`},
+		{clear, `{"event":"clear","streamSid":"MZ123"}`},
+	}
+
+	for idx, test := range tests {
+		if string(test.Got) != test.Expect {
+			t.Errorf("Test %v failed; expected %v, got %v", idx, test.Expect, string(test.Got))
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package twiml
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// MaxCallbackMetadata is the size limit of the metadata query parameters
+// added to a callback url, twilio limits the length of urls
+const MaxCallbackMetadata = 1024
+
+// AttachCallbackMetadata adds meta to the query of callbackURL, such as the
+// job a StatusCallback is about, and returns the url to configure. Twilio
+// signs the url it requests whole, so the url returned is the one to
+// validate callbacks with. The keys must start with a lowercase letter, the
+// parameters of twilio start with an uppercase one, and not be in the query
+// already. The encoded parameters are limited to MaxCallbackMetadata bytes.
+func AttachCallbackMetadata(callbackURL string, meta map[string]string) (string, error) {
+	u, err := url.Parse(callbackURL)
+	if err != nil {
+		return "", err
+	}
+	if !u.IsAbs() {
+		return "", fmt.Errorf("non valid callback url: '%s', not absolute", callbackURL)
+	}
+
+	query := u.Query()
+	added := url.Values{}
+	for key, val := range meta {
+		if key == "" || key[0] < 'a' || key[0] > 'z' {
+			return "", fmt.Errorf("non valid metadata key: '%s', reserved", key)
+		}
+		if _, ok := query[key]; ok {
+			return "", fmt.Errorf("non valid metadata key: '%s', in the url", key)
+		}
+		added.Set(key, val)
+		query.Set(key, val)
+	}
+	if size := len(added.Encode()); size > MaxCallbackMetadata {
+		return "", fmt.Errorf("callback metadata too large: %d bytes, limit %d", size,
+			MaxCallbackMetadata)
+	}
+
+	u.RawQuery = query.Encode()
+	return u.String(), nil
+}
+
+// ExtractCallbackMetadata returns the parameters of the query of the url of
+// a callback request, the metadata of AttachCallbackMetadata, nil if it has
+// none. Unlike the form, the query only holds what was in the url configured,
+// not the parameters twilio posts.
+func ExtractCallbackMetadata(r *http.Request) map[string]string {
+	query := r.URL.Query()
+	if len(query) == 0 {
+		return nil
+	}
+	meta := make(map[string]string, len(query))
+	for key := range query {
+		meta[key] = query.Get(key)
+	}
+	return meta
+}
@@ -0,0 +1,83 @@
+package twiml
+
+import (
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCallbackMetadataSigned(t *testing.T) {
+	const token = "secret"
+	callback, err := AttachCallbackMetadata("https://example.com/cb?tenant=a",
+		map[string]string{"job": "123", "note": "a&b c=d"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expect := "https://example.com/cb?job=123&note=a%26b+c%3Dd&tenant=a"; callback != expect {
+		t.Errorf("expected the url %v, got %v", expect, callback)
+	}
+
+	// twilio signs the url configured and the parameters it posts
+	form := url.Values{TwiAccountSid: {"AC123"}, TwiCallSid: {"CA123"},
+		TwiRecordingSid: {"RE123"}, TwiRecordingStatus: {"completed"}}
+	signature := Signature(token, callback, form)
+
+	var tests = []struct {
+		Target string
+		Valid  bool
+	}{
+		{callback, true},
+		{strings.Replace(callback, "job=123", "job=124", 1), false},
+	}
+	for idx, test := range tests {
+		r := httptest.NewRequest("POST", test.Target, strings.NewReader(form.Encode()))
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		r.Header.Set(TwiSignatureHeader, signature)
+		// the url the request was made to is what's validated
+		if err := ValidateSignature(r, test.Target, token); (err == nil) != test.Valid {
+			t.Errorf("Test %v failed; expected valid %v, got %v", idx, test.Valid, err)
+			continue
+		}
+		if !test.Valid {
+			continue
+		}
+		cb, err := ParseRecordingStatusCallback(r)
+		expect := map[string]string{"job": "123", "note": "a&b c=d", "tenant": "a"}
+		if err != nil || !reflect.DeepEqual(cb.Metadata, expect) {
+			t.Errorf("Test %v failed; expected the metadata %v, got %v %v", idx, expect,
+				cb.Metadata, err)
+		}
+		if cb.CallSid != "CA123" {
+			t.Errorf("Test %v failed; expected the parameters of twilio, got %+v", idx, cb)
+		}
+	}
+}
+
+func TestAttachCallbackMetadata(t *testing.T) {
+	var tests = []struct {
+		Url   string
+		Meta  map[string]string
+		Valid bool
+	}{
+		{"https://example.com/cb", map[string]string{"job": "1"}, true},
+		{"https://example.com/cb", nil, true},
+		{"https://example.com/cb", map[string]string{"CallSid": "CA1"}, false},
+		{"https://example.com/cb", map[string]string{"": "1"}, false},
+		{"https://example.com/cb", map[string]string{"_job": "1"}, false},
+		{"https://example.com/cb?job=1", map[string]string{"job": "2"}, false},
+		{"/cb", map[string]string{"job": "1"}, false},
+		{"https://example.com/cb", map[string]string{
+			"job": strings.Repeat("x", MaxCallbackMetadata)}, false},
+	}
+	for idx, test := range tests {
+		if _, err := AttachCallbackMetadata(test.Url, test.Meta); (err == nil) != test.Valid {
+			t.Errorf("Test %v failed; expected valid %v, got %v", idx, test.Valid, err)
+		}
+	}
+
+	if meta := ExtractCallbackMetadata(httptest.NewRequest("POST", "/cb", nil)); meta != nil {
+		t.Errorf("expected no metadata, got %v", meta)
+	}
+}
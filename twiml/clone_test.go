@@ -0,0 +1,82 @@
+package twiml
+
+import (
+	"encoding/xml"
+	"reflect"
+	"testing"
+)
+
+// triStateVerb has an optional boolean attribute, unset, true or false
+type triStateVerb struct {
+	XMLName xml.Name `xml:"TriState"`
+	Enabled *bool    `xml:"enabled,attr,omitempty"`
+}
+
+// everyVerb returns a response using every verb and noun
+func everyVerb() *Response {
+	yes, no := true, false
+	return &Response{Response: []interface{}{
+		Say{Voice: TwiAlice, Language: TwiEnglish, Loop: Loop(2), Text: "Hello"},
+		&Play{Loop: Loop(1), Url: "https://example.com/monkey.mp3"},
+		Pause{Length: Seconds(2)},
+		Gather{Action: "/menu", NumDigits: 1, NoFinishOnKey: true,
+			Nested: []interface{}{Say{Text: "Press 1"}, &Pause{Length: Seconds(1)}, Play{Digits: 1}}},
+		Record{Action: "/record", MaxLength: 30, Transcribe: true, PlayBeep: true},
+		Dial{Action: "/dial", Record: true, Nested: []interface{}{
+			Number{SendDigits: "ww1", Number: "+15005550006"},
+			&Client{Url: "/client", Name: "alice"},
+			Conference{Muted: true, StartConferenceOnEnter: true, Name: "room"},
+			Queue{Url: "/queue", Name: "support"},
+			Sip{Username: "alice", Address: "sip:alice@example.com"},
+		}},
+		Enqueue{WaitUrl: "/wait", Name: "support"},
+		Message{To: "+15005550006", Body: "Hi", Media: "https://example.com/cat.jpg"},
+		triStateVerb{Enabled: &yes},
+		triStateVerb{Enabled: &no},
+		triStateVerb{},
+		Leave{},
+		Redirect{Method: "POST", Url: "/next"},
+		Reject{Reason: "busy"},
+		Hangup{},
+	}}
+}
+
+func TestClone(t *testing.T) {
+	orig := everyVerb()
+	clone := orig.Clone()
+	if !reflect.DeepEqual(orig, clone) {
+		t.Fatalf("expected an equal clone, got %#v", clone)
+	}
+
+	// changing the clone leaves the original untouched
+	clone.Response[0] = Say{Text: "Bye"}
+	clone.Response[1].(*Play).Url = "changed"
+	gather := clone.Response[3].(Gather)
+	gather.Nested[0] = Say{Text: "changed"}
+	*gather.Nested[1].(*Pause).Length = 9
+	clone.Response[5].(Dial).Nested[1].(*Client).Name = "bob"
+	*clone.Response[8].(triStateVerb).Enabled = false
+	*clone.Response[9].(triStateVerb).Enabled = true
+	clone.Response = append(clone.Response[:12], Say{})
+
+	if !reflect.DeepEqual(orig, everyVerb()) {
+		t.Errorf("expected the original unchanged, got %#v", orig)
+	}
+	if clone.Response[10].(triStateVerb).Enabled != nil {
+		t.Errorf("expected an unset tri-state to stay unset")
+	}
+}
+
+func TestRenderDoesNotMutate(t *testing.T) {
+	r := everyVerb()
+	if _, err := r.Render(); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(r, everyVerb()) {
+		t.Errorf("expected Render to leave the response unchanged, got %#v", r)
+	}
+	_ = r.String()
+	if !reflect.DeepEqual(r, everyVerb()) {
+		t.Errorf("expected String to leave the response unchanged, got %#v", r)
+	}
+}
@@ -0,0 +1,62 @@
+package twiml
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// Twilio Connect, see https://www.twilio.com/docs/iam/connect
+
+// BuildConnectAuthorizeURL returns the url customers are sent to for
+// authorizing the connect app. state is passed back on the redirect to the
+// authorize callback url of the app.
+func BuildConnectAuthorizeURL(connectAppSid, state string) string {
+	u := "https://www.twilio.com/authorize/" + url.PathEscape(connectAppSid)
+	if state != "" {
+		u += "?" + TwiState + "=" + url.QueryEscape(state)
+	}
+	return u
+}
+
+// ParseConnectAuthorizeRedirect parses the redirect to the authorize callback
+// url of a connect app. An error is returned if the customer declined.
+func ParseConnectAuthorizeRedirect(r *http.Request) (accountSid, state string, err error) {
+	q := r.URL.Query()
+	state = q.Get(TwiState)
+	if e := q.Get(TwiError); e != "" {
+		return "", state, fmt.Errorf("connect authorization failed: '%s'", e)
+	}
+	accountSid = q.Get(TwiAccountSid)
+	if accountSid == "" {
+		return "", state, fmt.Errorf("missing parameter: '%s'", TwiAccountSid)
+	}
+	return accountSid, state, nil
+}
+
+// DeauthorizeCallback holds the parameters twilio sends to the deauthorize
+// callback url of a connect app when a customer revokes its access
+type DeauthorizeCallback struct {
+	AccountSid    string
+	ConnectAppSid string
+}
+
+// ParseDeauthorizeCallback validates the signature of a deauthorize callback
+// against the auth token of the account owning the connect app and parses
+// it. url is the deauthorize callback url configured on the connect app.
+func ParseDeauthorizeCallback(r *http.Request, url, authToken string) (
+	DeauthorizeCallback, error) {
+
+	if err := ValidateSignature(r, url, authToken); err != nil {
+		return DeauthorizeCallback{}, err
+	}
+
+	cb := DeauthorizeCallback{
+		AccountSid:    r.Form.Get(TwiAccountSid),
+		ConnectAppSid: r.Form.Get(TwiConnectAppSid),
+	}
+	if cb.AccountSid == "" {
+		return cb, fmt.Errorf("missing parameter: '%s'", TwiAccountSid)
+	}
+	return cb, nil
+}
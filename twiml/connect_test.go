@@ -0,0 +1,94 @@
+package twiml
+
+import (
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestSignature(t *testing.T) {
+	// example from the twilio security documentation
+	params := url.Values{
+		"CallSid": {"CA1234567890ABCDE"},
+		"Caller":  {"+12349013030"},
+		"Digits":  {"1234"},
+		"From":    {"+12349013030"},
+		"To":      {"+18005551212"},
+	}
+	got := Signature("12345", "https://mycompany.com/myapp.php?foo=1&bar=2", params)
+	if expect := "0/KCTR6DLpKmkAf8muzZqo1nDgQ="; got != expect {
+		t.Errorf("expected %#v, got %#v", expect, got)
+	}
+}
+
+func TestBuildConnectAuthorizeURL(t *testing.T) {
+	var tests = []struct {
+		State  string
+		Expect string
+	}{
+		{"", "https://www.twilio.com/authorize/CN123"},
+		{"abc", "https://www.twilio.com/authorize/CN123?state=abc"},
+		{"a b&c", "https://www.twilio.com/authorize/CN123?state=a+b%26c"},
+	}
+
+	for idx, test := range tests {
+		if got := BuildConnectAuthorizeURL("CN123", test.State); got != test.Expect {
+			t.Errorf("Test %v failed; expected %#v, got %#v", idx, test.Expect, got)
+		}
+	}
+}
+
+func TestParseConnectAuthorizeRedirect(t *testing.T) {
+	var tests = []struct {
+		Query      string
+		AccountSid string
+		State      string
+		Valid      bool
+	}{
+		{"AccountSid=AC456&state=a+b%26c", "AC456", "a b&c", true},
+		{"AccountSid=AC456", "AC456", "", true},
+		{"error=unauthorized&state=xyz", "", "xyz", false},
+		{"state=xyz", "", "xyz", false},
+	}
+
+	for idx, test := range tests {
+		r := httptest.NewRequest("GET", "https://example.com/connect?"+test.Query, nil)
+		sid, state, err := ParseConnectAuthorizeRedirect(r)
+		if (err == nil) != test.Valid || sid != test.AccountSid || state != test.State {
+			t.Errorf("Test %v failed; got %#v, %#v, %v", idx, sid, state, err)
+		}
+	}
+}
+
+func TestParseDeauthorizeCallback(t *testing.T) {
+	const callbackURL = "https://example.com/deauthorize"
+	form := url.Values{"AccountSid": {"AC456"}, "ConnectAppSid": {"CN123"}}
+
+	r := webhookRequest(form)
+	r.Header.Set(TwiSignatureHeader, Signature("token", callbackURL, form))
+	cb, err := ParseDeauthorizeCallback(r, callbackURL, "token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cb != (DeauthorizeCallback{AccountSid: "AC456", ConnectAppSid: "CN123"}) {
+		t.Errorf("unexpected callback %#v", cb)
+	}
+
+	var bad = []struct {
+		Signature string
+		Token     string
+	}{
+		{Signature("token", callbackURL, form), "other"},
+		{Signature("token", "https://example.com/other", form), "token"},
+		{"", "token"},
+	}
+	for idx, test := range bad {
+		r := webhookRequest(form)
+		if test.Signature != "" {
+			r.Header.Set(TwiSignatureHeader, test.Signature)
+		}
+		if _, err := ParseDeauthorizeCallback(r, callbackURL, test.Token); err == nil {
+			t.Errorf("Test %v failed; expected signature error", idx)
+		}
+	}
+}
@@ -58,15 +58,151 @@ const (
 	TwiToCity      = "ToCity"
 	TwiToState     = "ToState"
 	TwiToZip       = "ToZip"
-	TwiToCountry   = "Tocountry"
+	TwiToCountry   = "ToCountry"
+	// Geographic data of the called party and the caller
+	TwiCalled        = "Called"
+	TwiCalledCity    = "CalledCity"
+	TwiCalledState   = "CalledState"
+	TwiCalledZip     = "CalledZip"
+	TwiCalledCountry = "CalledCountry"
+	TwiCaller        = "Caller"
+	TwiCallerCity    = "CallerCity"
+	TwiCallerState   = "CallerState"
+	TwiCallerZip     = "CallerZip"
+	TwiCallerCountry = "CallerCountry"
 	//  Status callback
 	TwiCallDuration      = "CallDuration"
 	TwiRecordingUrl      = "RecordingUrl"
 	TwiRecordingSid      = "RecordingSid"
 	TwiRecordingDuration = "RecordingDuration"
+	// Recording status callback
+	TwiRecordingStatus    = "RecordingStatus"
+	TwiRecordingChannels  = "RecordingChannels"
+	TwiRecordingSource    = "RecordingSource"
+	TwiRecordingTrack     = "RecordingTrack"
+	TwiRecordingStartTime = "RecordingStartTime"
+	TwiConferenceSid      = "ConferenceSid"
+	TwiErrorCode          = "ErrorCode"
+	// Gather action
+	TwiDigits = "Digits"
+	// Queue wait url
+	TwiQueueSid         = "QueueSid"
+	TwiQueuePosition    = "QueuePosition"
+	TwiQueueTime        = "QueueTime"
+	TwiAvgQueueTime     = "AvgQueueTime"
+	TwiCurrentQueueSize = "CurrentQueueSize"
+	TwiMaxQueueSize     = "MaxQueueSize"
+	// Dial action
+	TwiDialCallStatus   = "DialCallStatus"
+	TwiDialCallSid      = "DialCallSid"
+	TwiDialCallDuration = "DialCallDuration"
+	TwiDialBridged      = "DialBridged"
+	// Conference status callback
+	TwiFriendlyName           = "FriendlyName"
+	TwiSequenceNumber         = "SequenceNumber"
+	TwiTimestamp              = "Timestamp"
+	TwiStatusCallbackEvent    = "StatusCallbackEvent"
+	TwiParticipantLabel       = "ParticipantLabel"
+	TwiMuted                  = "Muted"
+	TwiHold                   = "Hold"
+	TwiCoaching               = "Coaching"
+	TwiCallSidToCoach         = "CallSidToCoach"
+	TwiStartConferenceOnEnter = "StartConferenceOnEnter"
+	TwiEndConferenceOnExit    = "EndConferenceOnExit"
+	TwiReasonConferenceEnded  = "ReasonConferenceEnded"
+	TwiReason                 = "Reason"
+	// Incoming message
+	TwiMessageSid          = "MessageSid"
+	TwiMessagingServiceSid = "MessagingServiceSid"
+	TwiBody                = "Body"
+	TwiNumSegments         = "NumSegments"
+	TwiNumMedia            = "NumMedia"
+	TwiMediaUrl            = "MediaUrl"         // suffixed with the media index
+	TwiMediaContentType    = "MediaContentType" // suffixed with the media index
+	// Twilio Connect authorize redirect and deauthorize callback
+	TwiConnectAppSid = "ConnectAppSid"
+	TwiState         = "state"
+	TwiError         = "error"
 	// Below parameters are included in AddCallerId request response
 	TwiVerificationStatus  = "VerificationStatus"
 	TwiOutgoingCallerIdSid = "OutgoingCallerIdSid"
+	// SHAKEN/STIR
+	TwiStirVerstat       = "StirVerstat"
+	TwiStirPassportToken = "StirPassportToken"
+	// Debugger webhook
+	TwiSid              = "Sid"
+	TwiParentAccountSid = "ParentAccountSid"
+	TwiLevel            = "Level"
+	TwiPayloadType      = "PayloadType"
+	TwiPayload          = "Payload"
+	// Conversations webhook
+	TwiEventType         = "EventType"
+	TwiSource            = "Source"
+	TwiClientIdentity    = "ClientIdentity"
+	TwiChatServiceSid    = "ChatServiceSid"
+	TwiConversationSid   = "ConversationSid"
+	TwiParticipantSid    = "ParticipantSid"
+	TwiIndex             = "Index"
+	TwiAuthor            = "Author"
+	TwiAttributes        = "Attributes"
+	TwiDateCreated       = "DateCreated"
+	TwiUniqueName        = "UniqueName"
+	TwiConversationState = "State"
+	TwiBindingAddress    = "MessagingBinding.Address"
+	TwiBindingProxy      = "MessagingBinding.ProxyAddress"
+	// Transcription callback
+	TwiTranscriptionSid    = "TranscriptionSid"
+	TwiTranscriptionText   = "TranscriptionText"
+	TwiTranscriptionStatus = "TranscriptionStatus"
+	TwiTranscriptionUrl    = "TranscriptionUrl"
+	TwiConfidence          = "Confidence"
+)
+
+// TranscriptionStatus is the TranscriptionStatus of a transcription callback
+type TranscriptionStatus string
+
+// Transcription statuses
+const (
+	TwiTranscriptionCompleted TranscriptionStatus = "completed"
+	TwiTranscriptionFailed    TranscriptionStatus = "failed"
+)
+
+// ConversationsEventType is the EventType of a Conversations webhook. The
+// events ending in Add are pre-event webhooks, the reply to these can modify
+// or reject the action.
+type ConversationsEventType string
+
+// Conversations webhook events
+const (
+	TwiOnMessageAdd        ConversationsEventType = "onMessageAdd"
+	TwiOnMessageAdded      ConversationsEventType = "onMessageAdded"
+	TwiOnConversationAdd   ConversationsEventType = "onConversationAdd"
+	TwiOnConversationAdded ConversationsEventType = "onConversationAdded"
+)
+
+// StirVerstat is the SHAKEN/STIR verification result of an inbound call
+type StirVerstat string
+
+// SHAKEN/STIR verification results, the suffix is the attestation level
+const (
+	TwiStirPassedA      StirVerstat = "TN-Validation-Passed-A"
+	TwiStirPassedB      StirVerstat = "TN-Validation-Passed-B"
+	TwiStirPassedC      StirVerstat = "TN-Validation-Passed-C"
+	TwiStirFailedA      StirVerstat = "TN-Validation-Failed-A"
+	TwiStirFailedB      StirVerstat = "TN-Validation-Failed-B"
+	TwiStirFailedC      StirVerstat = "TN-Validation-Failed-C"
+	TwiStirFailed       StirVerstat = "TN-Validation-Failed"
+	TwiStirNoValidation StirVerstat = "No-TN-Validation"
+)
+
+// RecordingTrack is the audio track a recording holds
+type RecordingTrack string
+
+// Recording tracks
+const (
+	TwiTrackInbound  RecordingTrack = "inbound"
+	TwiTrackOutbound RecordingTrack = "outbound"
+	TwiTrackBoth     RecordingTrack = "both"
 )
 
 // Call status
@@ -80,3 +216,23 @@ const (
 	TwiNoAnswer   = "no-answer"
 	TwiCanceled   = "canceled"
 )
+
+// ConferenceEventType is the StatusCallbackEvent of a conference status
+// callback
+type ConferenceEventType string
+
+// Conference status callback events
+const (
+	TwiConferenceStart    ConferenceEventType = "conference-start"
+	TwiConferenceEnd      ConferenceEventType = "conference-end"
+	TwiParticipantJoin    ConferenceEventType = "participant-join"
+	TwiParticipantLeave   ConferenceEventType = "participant-leave"
+	TwiParticipantMute    ConferenceEventType = "participant-mute"
+	TwiParticipantUnmute  ConferenceEventType = "participant-unmute"
+	TwiParticipantHold    ConferenceEventType = "participant-hold"
+	TwiParticipantUnhold  ConferenceEventType = "participant-unhold"
+	TwiParticipantModify  ConferenceEventType = "participant-modify"
+	TwiParticipantSpeech  ConferenceEventType = "participant-speech-start"
+	TwiAnnouncementEnd    ConferenceEventType = "announcement-end"
+	TwiAnnouncementFailed ConferenceEventType = "announcement-failed"
+)
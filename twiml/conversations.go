@@ -0,0 +1,152 @@
+package twiml
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// ConversationsEvent holds the parameters of every Conversations webhook
+type ConversationsEvent struct {
+	EventType      ConversationsEventType
+	AccountSid     string
+	ChatServiceSid string
+	Source         string // SDK, API or SMS
+	ClientIdentity string
+}
+
+// ConversationMessage is an onMessageAdd or onMessageAdded webhook. MessageSid
+// and Index are only set once the message is added.
+type ConversationMessage struct {
+	ConversationsEvent
+	ConversationSid string
+	MessageSid      string
+	Index           int
+	ParticipantSid  string
+	Author          string
+	Body            string
+	Attributes      string // JSON
+	DateCreated     string
+}
+
+// ConversationAdded is an onConversationAdd or onConversationAdded webhook.
+// ConversationSid and State are only set once the conversation is added.
+type ConversationAdded struct {
+	ConversationsEvent
+	ConversationSid string
+	FriendlyName    string
+	UniqueName      string
+	Attributes      string // JSON
+	State           string
+	DateCreated     string
+	// BindingAddress and BindingProxy are set for conversations started by
+	// an SMS
+	BindingAddress string
+	BindingProxy   string
+}
+
+// parseConversationsEvent checks the signature of a Conversations webhook
+// request like ValidateSignature and that its EventType is one of types
+func parseConversationsEvent(r *http.Request, url, authToken string,
+	types ...ConversationsEventType) (ConversationsEvent, error) {
+	if err := ValidateSignature(r, url, authToken); err != nil {
+		return ConversationsEvent{}, err
+	}
+
+	e := ConversationsEvent{
+		EventType:      ConversationsEventType(r.PostForm.Get(TwiEventType)),
+		AccountSid:     r.PostForm.Get(TwiAccountSid),
+		ChatServiceSid: r.PostForm.Get(TwiChatServiceSid),
+		Source:         r.PostForm.Get(TwiSource),
+		ClientIdentity: r.PostForm.Get(TwiClientIdentity),
+	}
+	for _, t := range types {
+		if e.EventType == t {
+			return e, nil
+		}
+	}
+	return e, fmt.Errorf("non valid %s: '%s'", TwiEventType, e.EventType)
+}
+
+// ParseConversationMessage checks the signature of an onMessageAdd or
+// onMessageAdded webhook request, then parses it
+func ParseConversationMessage(r *http.Request, url, authToken string) (ConversationMessage, error) {
+	e, err := parseConversationsEvent(r, url, authToken, TwiOnMessageAdd, TwiOnMessageAdded)
+	if err != nil {
+		return ConversationMessage{ConversationsEvent: e}, err
+	}
+
+	m := ConversationMessage{
+		ConversationsEvent: e,
+		ConversationSid:    r.PostForm.Get(TwiConversationSid),
+		MessageSid:         r.PostForm.Get(TwiMessageSid),
+		ParticipantSid:     r.PostForm.Get(TwiParticipantSid),
+		Author:             r.PostForm.Get(TwiAuthor),
+		Body:               r.PostForm.Get(TwiBody),
+		Attributes:         r.PostForm.Get(TwiAttributes),
+		DateCreated:        r.PostForm.Get(TwiDateCreated),
+	}
+	if m.ConversationSid == "" {
+		return m, fmt.Errorf("missing parameter: '%s'", TwiConversationSid)
+	}
+	if m.Index, err = formInt(r, TwiIndex); err != nil {
+		return m, err
+	}
+	return m, nil
+}
+
+// ParseConversationAdded checks the signature of an onConversationAdd or
+// onConversationAdded webhook request, then parses it
+func ParseConversationAdded(r *http.Request, url, authToken string) (ConversationAdded, error) {
+	e, err := parseConversationsEvent(r, url, authToken, TwiOnConversationAdd, TwiOnConversationAdded)
+	if err != nil {
+		return ConversationAdded{ConversationsEvent: e}, err
+	}
+
+	return ConversationAdded{
+		ConversationsEvent: e,
+		ConversationSid:    r.PostForm.Get(TwiConversationSid),
+		FriendlyName:       r.PostForm.Get(TwiFriendlyName),
+		UniqueName:         r.PostForm.Get(TwiUniqueName),
+		Attributes:         r.PostForm.Get(TwiAttributes),
+		State:              r.PostForm.Get(TwiConversationState),
+		DateCreated:        r.PostForm.Get(TwiDateCreated),
+		BindingAddress:     r.PostForm.Get(TwiBindingAddress),
+		BindingProxy:       r.PostForm.Get(TwiBindingProxy),
+	}, nil
+}
+
+// EventChanges are the fields a reply to a pre-event webhook changes, the
+// ones left empty are kept
+type EventChanges struct {
+	Body         string `json:"body,omitempty"`
+	Author       string `json:"author,omitempty"`
+	Attributes   string `json:"attributes,omitempty"`
+	FriendlyName string `json:"friendly_name,omitempty"`
+	UniqueName   string `json:"unique_name,omitempty"`
+}
+
+// AcceptEvent replies to a pre-event webhook letting the action go ahead
+// unchanged
+func AcceptEvent(w http.ResponseWriter) {
+	w.WriteHeader(http.StatusOK)
+}
+
+// RejectEvent replies to a pre-event webhook blocking the action, the reason
+// is the body of the reply and isn't shown to the client
+func RejectEvent(w http.ResponseWriter, reason string) {
+	http.Error(w, reason, http.StatusForbidden)
+}
+
+// ModifyEvent replies to a pre-event webhook letting the action go ahead
+// with the changes
+func ModifyEvent(w http.ResponseWriter, changes EventChanges) error {
+	b, err := json.Marshal(changes)
+	if err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_, err = w.Write(b)
+	return err
+}
@@ -0,0 +1,108 @@
+package twiml
+
+import (
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestParseConversationMessage(t *testing.T) {
+	const endpoint = "https://example.com/debugger"
+	form := url.Values{
+		TwiEventType:       {"onMessageAdded"},
+		TwiAccountSid:      {"AC123"},
+		TwiChatServiceSid:  {"IS123"},
+		TwiSource:          {"SDK"},
+		TwiClientIdentity:  {"alice"},
+		TwiConversationSid: {"CH123"},
+		TwiMessageSid:      {"IM123"},
+		TwiIndex:           {"4"},
+		TwiAuthor:          {"alice"},
+		TwiBody:            {"Hello & welcome"},
+		TwiAttributes:      {`{"importance":"high"}`},
+	}
+
+	m, err := ParseConversationMessage(debuggerRequest(form, "token"), endpoint, "token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.EventType != TwiOnMessageAdded || m.ChatServiceSid != "IS123" || m.Source != "SDK" ||
+		m.ConversationSid != "CH123" || m.MessageSid != "IM123" || m.Index != 4 ||
+		m.Author != "alice" || m.Body != "Hello & welcome" ||
+		m.Attributes != `{"importance":"high"}` {
+		t.Errorf("unexpected message %#v", m)
+	}
+
+	var tests = []struct {
+		Name  string
+		Value string
+		Token string
+	}{
+		{TwiEventType, "onMessageAdded", "wrong"},
+		{TwiEventType, "onConversationAdded", "token"},
+		{TwiConversationSid, "", "token"},
+		{TwiIndex, "four", "token"},
+	}
+	for idx, test := range tests {
+		f := url.Values{}
+		for k, v := range form {
+			f[k] = v
+		}
+		f.Set(test.Name, test.Value)
+		if _, err := ParseConversationMessage(debuggerRequest(f, test.Token), endpoint,
+			"token"); err == nil {
+			t.Errorf("Test %v failed; expected an error", idx)
+		}
+	}
+}
+
+func TestParseConversationAdded(t *testing.T) {
+	const endpoint = "https://example.com/debugger"
+	form := url.Values{
+		TwiEventType:      {"onConversationAdd"},
+		TwiAccountSid:     {"AC123"},
+		TwiSource:         {"SMS"},
+		TwiFriendlyName:   {"Support"},
+		TwiBindingAddress: {"+15005550006"},
+		TwiBindingProxy:   {"+15005550001"},
+	}
+
+	c, err := ParseConversationAdded(debuggerRequest(form, "token"), endpoint, "token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.EventType != TwiOnConversationAdd || c.ConversationSid != "" ||
+		c.FriendlyName != "Support" || c.BindingAddress != "+15005550006" ||
+		c.BindingProxy != "+15005550001" {
+		t.Errorf("unexpected conversation %#v", c)
+	}
+
+	form.Set(TwiEventType, "onMessageAdd")
+	if _, err := ParseConversationAdded(debuggerRequest(form, "token"), endpoint,
+		"token"); err == nil {
+		t.Errorf("expected an error for a message event")
+	}
+}
+
+func TestEventReplies(t *testing.T) {
+	var tests = []struct {
+		Reply  func(w *httptest.ResponseRecorder)
+		Status int
+		Body   string
+	}{
+		{func(w *httptest.ResponseRecorder) { AcceptEvent(w) }, 200, ""},
+		{func(w *httptest.ResponseRecorder) { RejectEvent(w, "profanity") }, 403, "profanity\n"},
+		{func(w *httptest.ResponseRecorder) {
+			ModifyEvent(w, EventChanges{Body: "****", Attributes: `{"moderated":true}`})
+		}, 200, `{"body":"****","attributes":"{\"moderated\":true}"}`},
+	}
+
+	for idx, test := range tests {
+		w := httptest.NewRecorder()
+		test.Reply(w)
+		if w.Code != test.Status || w.Body.String() != test.Body {
+			t.Errorf("Test %v failed; expected %v %#v, got %v %#v", idx, test.Status,
+				test.Body, w.Code, w.Body.String())
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package twiml
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+// DebuggerAlert is an alert posted to the debugger webhook of an account,
+// such as a webhook of ours failing. The details twilio sends as a JSON
+// Payload parameter are in the fields following Payload.
+type DebuggerAlert struct {
+	Sid              string
+	AccountSid       string
+	ParentAccountSid string
+	Timestamp        string
+	Level            string // Error or Warning
+	PayloadType      string
+	// Payload is the raw JSON payload
+	Payload string
+
+	ResourceSid string
+	ServiceSid  string
+	ErrorCode   int
+	Message     string
+	MoreInfo    map[string]string
+	// Request and Response are the failing request twilio made to our
+	// webhook, if the alert is about one
+	Request  DebuggerRequest
+	Response DebuggerResponse
+}
+
+// DebuggerRequest is the request twilio made to a webhook
+type DebuggerRequest struct {
+	Url        string            `json:"url"`
+	Method     string            `json:"method"`
+	Headers    map[string]string `json:"headers"`
+	Parameters map[string]string `json:"parameters"`
+}
+
+// DebuggerResponse is the response of a webhook
+type DebuggerResponse struct {
+	StatusCode int               `json:"status_code"`
+	Headers    map[string]string `json:"headers"`
+	Body       string            `json:"body"`
+}
+
+// debuggerPayload is the JSON in the Payload parameter
+type debuggerPayload struct {
+	ResourceSid string            `json:"resource_sid"`
+	ServiceSid  string            `json:"service_sid"`
+	ErrorCode   string            `json:"error_code"`
+	MoreInfo    map[string]string `json:"more_info"`
+	Webhook     struct {
+		Request  DebuggerRequest  `json:"request"`
+		Response DebuggerResponse `json:"response"`
+	} `json:"webhook"`
+}
+
+// ParseDebuggerWebhook checks the signature of a debugger webhook request
+// like ValidateSignature, then parses the alert and its payload
+func ParseDebuggerWebhook(r *http.Request, url, authToken string) (DebuggerAlert, error) {
+	if err := ValidateSignature(r, url, authToken); err != nil {
+		return DebuggerAlert{}, err
+	}
+
+	a := DebuggerAlert{
+		Sid:              r.PostForm.Get(TwiSid),
+		AccountSid:       r.PostForm.Get(TwiAccountSid),
+		ParentAccountSid: r.PostForm.Get(TwiParentAccountSid),
+		Timestamp:        r.PostForm.Get(TwiTimestamp),
+		Level:            r.PostForm.Get(TwiLevel),
+		PayloadType:      r.PostForm.Get(TwiPayloadType),
+		Payload:          r.PostForm.Get(TwiPayload),
+	}
+	if a.Payload == "" {
+		return a, fmt.Errorf("missing parameter: '%s'", TwiPayload)
+	}
+
+	var p debuggerPayload
+	if err := json.Unmarshal([]byte(a.Payload), &p); err != nil {
+		return a, fmt.Errorf("non valid %s: %v", TwiPayload, err)
+	}
+	a.ResourceSid = p.ResourceSid
+	a.ServiceSid = p.ServiceSid
+	a.MoreInfo = p.MoreInfo
+	a.Message = p.MoreInfo["msg"]
+	a.Request = p.Webhook.Request
+	a.Response = p.Webhook.Response
+	if p.ErrorCode != "" {
+		code, err := strconv.Atoi(p.ErrorCode)
+		if err != nil {
+			return a, fmt.Errorf("non valid error_code: '%s'", p.ErrorCode)
+		}
+		a.ErrorCode = code
+	}
+	return a, nil
+}
+
+// DebuggerHandler serves the debugger webhook at url, passing every alert to
+// each of handlers in turn. Requests that aren't signed with authToken are
+// refused with status 403 and ones that can't be parsed with status 400.
+func DebuggerHandler(url, authToken string, handlers ...func(DebuggerAlert)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := ValidateSignature(r, url, authToken); err != nil {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
+		alert, err := ParseDebuggerWebhook(r, url, authToken)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		for _, h := range handlers {
+			h(alert)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
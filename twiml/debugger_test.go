@@ -0,0 +1,104 @@
+package twiml
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+// testDebuggerPayload is a payload as sent for a failing voice webhook, its
+// response body holds xml
+const testDebuggerPayload = `{"resource_sid":"CA123","service_sid":null,"error_code":"11200",` +
+	`"more_info":{"msg":"An attempt to retrieve content from https://example.com/voice returned the HTTP status code 502","Msg":"HTTP retrieval failure","sourceComponent":"12000","httpResponse":"502","url":"https://example.com/voice","LogLevel":"ERROR"},` +
+	`"webhook":{"type":"application/json","request":{"url":"https://example.com/voice?team=a&b=c","method":"POST",` +
+	`"headers":{"X-Twilio-Signature":"abc"},"parameters":{"CallSid":"CA123","From":"+15005550006"}},` +
+	`"response":{"status_code":502,"headers":{"Content-Type":"text/html"},"body":"<html><body>Bad Gateway & more</body></html>"}}}`
+
+// debuggerRequest returns a debugger webhook request signed with token
+func debuggerRequest(form url.Values, token string) *http.Request {
+	const endpoint = "https://example.com/debugger"
+	r := httptest.NewRequest("POST", endpoint, strings.NewReader(form.Encode()))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	r.Header.Set(TwiSignatureHeader, Signature(token, endpoint, form))
+	return r
+}
+
+func TestParseDebuggerWebhook(t *testing.T) {
+	form := url.Values{
+		TwiSid:         {"NO123"},
+		TwiAccountSid:  {"AC123"},
+		TwiTimestamp:   {"2024-01-01T00:00:00Z"},
+		TwiLevel:       {"Error"},
+		TwiPayloadType: {"application/json"},
+		TwiPayload:     {testDebuggerPayload},
+	}
+
+	a, err := ParseDebuggerWebhook(debuggerRequest(form, "token"),
+		"https://example.com/debugger", "token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.Sid != "NO123" || a.Level != "Error" || a.ErrorCode != 11200 ||
+		a.ResourceSid != "CA123" || a.Payload != testDebuggerPayload ||
+		!strings.HasPrefix(a.Message, "An attempt to retrieve") ||
+		a.MoreInfo["httpResponse"] != "502" {
+		t.Errorf("unexpected alert %#v", a)
+	}
+	if a.Request.Url != "https://example.com/voice?team=a&b=c" || a.Request.Method != "POST" ||
+		a.Request.Parameters["From"] != "+15005550006" {
+		t.Errorf("unexpected request %#v", a.Request)
+	}
+	if a.Response.StatusCode != 502 ||
+		a.Response.Body != "<html><body>Bad Gateway & more</body></html>" {
+		t.Errorf("unexpected response %#v", a.Response)
+	}
+
+	var tests = []struct {
+		Payload string
+		Token   string
+	}{
+		{testDebuggerPayload, "wrong"},
+		{"", "token"},
+		{"{not json", "token"},
+		{`{"error_code":"E11200"}`, "token"},
+	}
+	for idx, test := range tests {
+		form.Set(TwiPayload, test.Payload)
+		_, err := ParseDebuggerWebhook(debuggerRequest(form, test.Token),
+			"https://example.com/debugger", "token")
+		if err == nil {
+			t.Errorf("Test %v failed; expected an error", idx)
+		}
+	}
+}
+
+func TestDebuggerHandler(t *testing.T) {
+	var first, second []DebuggerAlert
+	handler := DebuggerHandler("https://example.com/debugger", "token",
+		func(a DebuggerAlert) { first = append(first, a) },
+		func(a DebuggerAlert) { second = append(second, a) })
+
+	var tests = []struct {
+		Payload string
+		Token   string
+		Code    int
+	}{
+		{testDebuggerPayload, "token", http.StatusNoContent},
+		{testDebuggerPayload, "wrong", http.StatusForbidden},
+		{"{", "token", http.StatusBadRequest},
+	}
+
+	for idx, test := range tests {
+		w := httptest.NewRecorder()
+		handler(w, debuggerRequest(url.Values{TwiPayload: {test.Payload}}, test.Token))
+		if w.Code != test.Code {
+			t.Errorf("Test %v failed; expected %v, got %v", idx, test.Code, w.Code)
+		}
+	}
+	if len(first) != 1 || len(second) != 1 || first[0].ErrorCode != 11200 {
+		t.Errorf("expected the alert fanned out once to each handler, got %v and %v",
+			first, second)
+	}
+}
@@ -0,0 +1,109 @@
+package twiml
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+)
+
+// Status callback events
+const (
+	// call, such as the Number noun or MakeCall
+	TwiEventInitiated = "initiated"
+	TwiEventRinging   = "ringing"
+	TwiEventAnswered  = "answered"
+	TwiEventCompleted = "completed"
+	// conference
+	TwiEventStart        = "start"
+	TwiEventEnd          = "end"
+	TwiEventJoin         = "join"
+	TwiEventLeave        = "leave"
+	TwiEventMute         = "mute"
+	TwiEventHold         = "hold"
+	TwiEventModify       = "modify"
+	TwiEventSpeaker      = "speaker"
+	TwiEventAnnouncement = "announcement"
+	// recording, completed is shared with calls
+	TwiEventInProgress = "in-progress"
+	TwiEventAbsent     = "absent"
+)
+
+// EventContext is what an event list is for, each has its own events
+type EventContext string
+
+const (
+	TwiCallEvents       EventContext = "call"
+	TwiConferenceEvents EventContext = "conference"
+	TwiRecordingEvents  EventContext = "recording"
+)
+
+// allowedEvents are the events of each context
+var allowedEvents = map[EventContext][]string{
+	TwiCallEvents: {TwiEventInitiated, TwiEventRinging, TwiEventAnswered,
+		TwiEventCompleted},
+	TwiConferenceEvents: {TwiEventStart, TwiEventEnd, TwiEventJoin, TwiEventLeave,
+		TwiEventMute, TwiEventHold, TwiEventModify, TwiEventSpeaker,
+		TwiEventAnnouncement},
+	TwiRecordingEvents: {TwiEventInProgress, TwiEventCompleted, TwiEventAbsent},
+}
+
+// AllowedEvents returns the events of the context
+func AllowedEvents(ctx EventContext) []string {
+	return append([]string(nil), allowedEvents[ctx]...)
+}
+
+// Events is a list of status callback events. The REST API takes it as a
+// repeated parameter and TwiML as a space separated attribute.
+type Events []string
+
+// Validate returns an error if an event isn't one of the context's
+func (e Events) Validate(ctx EventContext) error {
+	allowed, ok := allowedEvents[ctx]
+	if !ok {
+		return fmt.Errorf("non valid event context: '%s'", ctx)
+	}
+next:
+	for _, ev := range e {
+		for _, a := range allowed {
+			if ev == a {
+				continue next
+			}
+		}
+		return fmt.Errorf("non valid %s event: '%s', expected one of %s", ctx, ev,
+			strings.Join(allowed, ", "))
+	}
+	return nil
+}
+
+// MarshalXMLAttr renders the events space separated, no attribute if empty
+func (e Events) MarshalXMLAttr(name xml.Name) (xml.Attr, error) {
+	if len(e) == 0 {
+		return xml.Attr{}, nil
+	}
+	return xml.Attr{Name: name, Value: strings.Join(e, " ")}, nil
+}
+
+// UnmarshalXMLAttr parses space separated events
+func (e *Events) UnmarshalXMLAttr(attr xml.Attr) error {
+	*e = strings.Fields(attr.Value)
+	return nil
+}
+
+func (n Number) validate() error {
+	return n.StatusCallbackEvent.Validate(TwiCallEvents)
+}
+
+func (c Client) validate() error {
+	return c.StatusCallbackEvent.Validate(TwiCallEvents)
+}
+
+func (s Sip) validate() error {
+	return s.StatusCallbackEvent.Validate(TwiCallEvents)
+}
+
+func (c Conference) validate() error {
+	if err := c.JitterBufferSize.Validate(); err != nil {
+		return err
+	}
+	return c.StatusCallbackEvent.Validate(TwiConferenceEvents)
+}
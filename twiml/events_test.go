@@ -0,0 +1,71 @@
+package twiml
+
+import (
+	"encoding/xml"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestEvents(t *testing.T) {
+	var tests = []struct {
+		Verb   interface{}
+		Expect string
+	}{
+		{Dial{Nested: []interface{}{Number{Number: "+15005550006", StatusCallback: "/status",
+			StatusCallbackEvent: Events{TwiEventInitiated, TwiEventAnswered}}}},
+			`<Number statusCallback="/status" statusCallbackEvent="initiated answered">+15005550006</Number>`},
+		{Dial{Nested: []interface{}{Client{Name: "alice",
+			StatusCallbackEvent: Events{TwiEventRinging}}}},
+			`<Client statusCallbackEvent="ringing">alice</Client>`},
+		{Dial{Nested: []interface{}{Sip{Address: "sip:alice@example.com",
+			StatusCallbackEvent: Events{TwiEventCompleted}}}},
+			`<Sip statusCallbackEvent="completed">sip:alice@example.com</Sip>`},
+		{Dial{Nested: []interface{}{Conference{Name: "room",
+			StatusCallbackEvent: Events{TwiEventStart, TwiEventJoin}}}},
+			`<Conference statusCallbackEvent="start join">room</Conference>`},
+		{Dial{Nested: []interface{}{Number{Number: "+15005550006"}}},
+			`<Number>+15005550006</Number>`},
+		{Dial{Nested: []interface{}{Number{StatusCallbackEvent: Events{TwiEventJoin}}}}, ""},
+		{Dial{Nested: []interface{}{&Conference{StatusCallbackEvent: Events{TwiEventAnswered}}}}, ""},
+	}
+
+	for idx, test := range tests {
+		out, err := Response{Response: []interface{}{test.Verb}}.Render()
+		if test.Expect == "" {
+			var rerr *RenderError
+			if !errors.As(err, &rerr) || !strings.Contains(rerr.Path, "Dial[0]>") {
+				t.Errorf("Test %v failed; expected an error at the noun, got %v", idx, err)
+			}
+			continue
+		}
+		if err != nil || !strings.Contains(string(out), test.Expect) {
+			t.Errorf("Test %v failed; expected %v, got %s (%v)", idx, test.Expect, out, err)
+		}
+	}
+}
+
+func TestEventsValidate(t *testing.T) {
+	err := Events{TwiEventStart, "joined"}.Validate(TwiConferenceEvents)
+	if err == nil || !strings.Contains(err.Error(), "conference") ||
+		!strings.Contains(err.Error(), "'joined'") ||
+		!strings.Contains(err.Error(), strings.Join(AllowedEvents(TwiConferenceEvents), ", ")) {
+		t.Errorf("expected an error naming the context and allowed events, got %v", err)
+	}
+	if err := (Events{}).Validate("sms"); err == nil {
+		t.Errorf("expected an error for an unknown context")
+	}
+	for ctx, allowed := range allowedEvents {
+		if err := Events(allowed).Validate(ctx); err != nil {
+			t.Errorf("expected the %v events to be valid, got %v", ctx, err)
+		}
+	}
+
+	var n Number
+	in := `<Number statusCallbackEvent=" ringing  answered">+15005550006</Number>`
+	if err := xml.Unmarshal([]byte(in), &n); err != nil ||
+		!reflect.DeepEqual(n.StatusCallbackEvent, Events{TwiEventRinging, TwiEventAnswered}) {
+		t.Errorf("expected ringing and answered, got %#v (%v)", n.StatusCallbackEvent, err)
+	}
+}
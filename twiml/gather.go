@@ -0,0 +1,51 @@
+package twiml
+
+import (
+	"encoding/xml"
+	"fmt"
+)
+
+// MarshalXML encodes the gather, adding finishOnKey="" if NoFinishOnKey is set
+func (g Gather) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	type gather Gather // without the MarshalXML method
+	start.Name = xml.Name{Local: "Gather"}
+	if g.NoFinishOnKey && g.FinishOnKey == "" {
+		start.Attr = append(start.Attr,
+			xml.Attr{Name: xml.Name{Local: "finishOnKey"}, Value: ""})
+	}
+	return e.EncodeElement(gather(g), start)
+}
+
+// warnGather appends the warnings for the finishOnKey and numDigits of g
+func warnGather(verb Warning, g Gather, warnings []Warning) []Warning {
+	warn := func(format string, args ...interface{}) {
+		verb.Message = fmt.Sprintf(format, args...)
+		warnings = append(warnings, verb)
+	}
+
+	switch {
+	case g.NoFinishOnKey && g.FinishOnKey != "":
+		warn("finishOnKey '%s' set with NoFinishOnKey, finishOnKey is disabled", g.FinishOnKey)
+	case g.NoFinishOnKey && g.NumDigits > 1:
+		warn("finishOnKey disabled with numDigits %d, callers entering fewer "+
+			"digits wait for the timeout", g.NumDigits)
+	case g.NumDigits == 1 && g.FinishOnKey != "":
+		warn("finishOnKey '%s' with numDigits 1, pressing it submits no digits",
+			g.FinishOnKey)
+	}
+
+	if k := g.FinishOnKey; k != "" {
+		switch {
+		case len(k) != 1 || !isKey(k[0]):
+			warn("non valid finishOnKey: '%s'", k)
+		case k[0] >= '0' && k[0] <= '9' && g.NumDigits != 1:
+			warn("finishOnKey '%s' is a digit, callers can't enter it", k)
+		}
+	}
+	return warnings
+}
+
+// isKey reports if c is a key on the keypad
+func isKey(c byte) bool {
+	return c >= '0' && c <= '9' || c == '#' || c == '*'
+}
@@ -0,0 +1,59 @@
+package twiml
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+)
+
+func TestGatherWarnings(t *testing.T) {
+	var tests = []struct {
+		Gather Gather
+		Expect []string
+	}{
+		{Gather{NumDigits: 1}, nil},
+		{Gather{NumDigits: 4, FinishOnKey: "#"}, nil},
+		{Gather{FinishOnKey: "*"}, nil},
+		{Gather{NumDigits: 1, FinishOnKey: "#"}, []string{"numDigits 1"}},
+		{Gather{NumDigits: 4, NoFinishOnKey: true}, []string{"fewer digits"}},
+		{Gather{NoFinishOnKey: true, FinishOnKey: "#"}, []string{"NoFinishOnKey"}},
+		{Gather{NumDigits: 4, FinishOnKey: "5"}, []string{"is a digit"}},
+		{Gather{FinishOnKey: "##"}, []string{"non valid finishOnKey"}},
+		{Gather{NumDigits: 1, FinishOnKey: "x"},
+			[]string{"numDigits 1", "non valid finishOnKey"}},
+	}
+
+	for idx, test := range tests {
+		r := Response{Response: []interface{}{Say{Text: "hi"}, test.Gather}}
+		warnings := r.Warnings()
+		if len(warnings) != len(test.Expect) {
+			t.Errorf("Test %v failed; expected %d warnings, got %v",
+				idx, len(test.Expect), warnings)
+			continue
+		}
+		for i, w := range warnings {
+			if w.Path != "Response>Gather[1]" || !strings.Contains(w.Message, test.Expect[i]) {
+				t.Errorf("Test %v failed; expected %#v, got %v", idx, test.Expect[i], w)
+			}
+		}
+	}
+}
+
+func TestGatherNoFinishOnKey(t *testing.T) {
+	var tests = []struct {
+		Gather Gather
+		Expect string
+	}{
+		{Gather{NumDigits: 4}, `<Gather numDigits="4"></Gather>`},
+		{Gather{NumDigits: 4, NoFinishOnKey: true},
+			`<Gather finishOnKey="" numDigits="4"></Gather>`},
+		{Gather{FinishOnKey: "*", NoFinishOnKey: true}, `<Gather finishOnKey="*"></Gather>`},
+	}
+
+	for idx, test := range tests {
+		out, err := xml.Marshal(test.Gather)
+		if err != nil || string(out) != test.Expect {
+			t.Errorf("Test %v failed; expected %#v, got %#v (%v)", idx, test.Expect, string(out), err)
+		}
+	}
+}
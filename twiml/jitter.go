@@ -0,0 +1,27 @@
+package twiml
+
+import "fmt"
+
+// JitterBufferSize is the size of the jitter buffer of a conference
+// participant. A larger buffer smooths out the audio of participants on poor
+// networks at the cost of delay. The same values are used by the Conference
+// noun and the JitterBufferSize parameter of the REST API. The zero value
+// leaves it to twilio, which uses a large buffer.
+type JitterBufferSize string
+
+const (
+	TwiJitterBufferOff    JitterBufferSize = "off"
+	TwiJitterBufferSmall  JitterBufferSize = "small"
+	TwiJitterBufferMedium JitterBufferSize = "medium"
+	TwiJitterBufferLarge  JitterBufferSize = "large"
+)
+
+// Validate returns an error if s isn't empty or one of the buffer sizes
+func (s JitterBufferSize) Validate() error {
+	switch s {
+	case "", TwiJitterBufferOff, TwiJitterBufferSmall, TwiJitterBufferMedium,
+		TwiJitterBufferLarge:
+		return nil
+	}
+	return fmt.Errorf("non valid jitterBufferSize: '%s'", string(s))
+}
@@ -0,0 +1,36 @@
+package twiml
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestJitterBufferSize(t *testing.T) {
+	var tests = []struct {
+		Conference Conference
+		Expect     string
+	}{
+		{Conference{JitterBufferSize: TwiJitterBufferSmall, Name: "room"},
+			`<Conference jitterBufferSize="small">room</Conference>`},
+		{Conference{JitterBufferSize: TwiJitterBufferOff, Name: "room"},
+			`<Conference jitterBufferSize="off">room</Conference>`},
+		{Conference{Name: "room"}, `<Conference>room</Conference>`},
+		{Conference{JitterBufferSize: "tiny", Name: "room"}, ""},
+	}
+
+	for idx, test := range tests {
+		out, err := Response{Response: []interface{}{
+			Dial{Nested: []interface{}{test.Conference}}}}.Render()
+		if test.Expect == "" {
+			var rerr *RenderError
+			if !errors.As(err, &rerr) || !strings.Contains(err.Error(), "non valid jitterBufferSize") {
+				t.Errorf("Test %v failed; expected a jitterBufferSize error, got %v", idx, err)
+			}
+			continue
+		}
+		if err != nil || !strings.Contains(string(out), test.Expect) {
+			t.Errorf("Test %v failed; expected %v, got %s (%v)", idx, test.Expect, out, err)
+		}
+	}
+}
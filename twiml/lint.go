@@ -0,0 +1,80 @@
+package twiml
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Warning is a verb that renders but likely doesn't behave as intended, unlike
+// a RenderError it doesn't stop the response from rendering. Path is the
+// breadcrumb of verbs from the Response, like in a RenderError, Index and Verb
+// are the index and name of the verb among its siblings.
+type Warning struct {
+	Path    string
+	Index   int
+	Verb    string
+	Message string
+}
+
+func (w Warning) String() string {
+	return fmt.Sprintf("twiml: %s: %s", w.Path, w.Message)
+}
+
+// Warnings lints the response for verbs that are never executed, as they
+// follow a terminal verb such as Hangup, and for verbs with attribute
+// combinations that produce confusing call behavior. Raw verbs and extra
+// attributes, which aren't checked beyond being well-formed, are warned of.
+func (r Response) Warnings() []Warning {
+	return warnNested("Response", r.Response, nil)
+}
+
+// warnNested appends the warnings of verbs and the verbs nested in them
+func warnNested(path string, verbs []interface{}, warnings []Warning) []Warning {
+	terminal := ""
+	for i, v := range verbs {
+		val := reflect.ValueOf(v)
+		if !val.IsValid() || (val.Kind() == reflect.Ptr && val.IsNil()) {
+			continue
+		}
+		verb := Warning{Path: verbPath(path, val, i), Index: i, Verb: verbName(val)}
+
+		if terminal != "" {
+			verb.Message = fmt.Sprintf("%s follows %s, it is never executed",
+				verb.Verb, terminal)
+			warnings = append(warnings, verb)
+		} else if terminalVerb(v) {
+			terminal = verb.Verb
+		}
+
+		// what the package doesn't model renders unchecked
+		for _, a := range extraAttrs(val) {
+			w := verb
+			w.Message = fmt.Sprintf("attribute %s isn't modeled, it isn't checked",
+				rawName(a.Name).Local)
+			warnings = append(warnings, w)
+		}
+
+		switch v := v.(type) {
+		case Raw, *Raw:
+			verb.Message = "raw xml is only checked to be well-formed"
+			warnings = append(warnings, verb)
+		case Gather:
+			warnings = warnGather(verb, v, warnings)
+		case *Gather:
+			warnings = warnGather(verb, *v, warnings)
+		}
+		if nested, ok := nestedVerbs(val); ok {
+			warnings = warnNested(verb.Path, nested, warnings)
+		}
+	}
+	return warnings
+}
+
+// terminalVerb reports if v ends the execution of the document it is in
+func terminalVerb(v interface{}) bool {
+	switch v.(type) {
+	case Hangup, *Hangup, Redirect, *Redirect, Reject, *Reject, Leave, *Leave:
+		return true
+	}
+	return false
+}
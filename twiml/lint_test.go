@@ -0,0 +1,40 @@
+package twiml
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestUnreachableWarnings(t *testing.T) {
+	type unreachable struct {
+		Path  string
+		Index int
+		Verb  string
+	}
+
+	var tests = []struct {
+		Verbs  []interface{}
+		Expect []unreachable
+	}{
+		{[]interface{}{Hangup{}, Say{Text: "bye"}},
+			[]unreachable{{"Response>Say[1]", 1, "Say"}}},
+		{[]interface{}{Say{}, &Redirect{Url: "/next"}, Play{}, Pause{}},
+			[]unreachable{{"Response>Play[2]", 2, "Play"}, {"Response>Pause[3]", 3, "Pause"}}},
+		{[]interface{}{Reject{}, Hangup{}},
+			[]unreachable{{"Response>Hangup[1]", 1, "Hangup"}}},
+		{[]interface{}{Gather{Nested: []interface{}{Say{}, Redirect{}, Play{}}}, Say{}},
+			[]unreachable{{"Response>Gather[0]>Play[2]", 2, "Play"}}},
+		{[]interface{}{Say{}, Play{}, Leave{}}, nil},
+		{[]interface{}{Gather{NumDigits: 1}, Redirect{Url: "/menu"}}, nil},
+	}
+
+	for idx, test := range tests {
+		var got []unreachable
+		for _, w := range (Response{Response: test.Verbs}).Warnings() {
+			got = append(got, unreachable{w.Path, w.Index, w.Verb})
+		}
+		if !reflect.DeepEqual(got, test.Expect) {
+			t.Errorf("Test %v failed; expected %v, got %v", idx, test.Expect, got)
+		}
+	}
+}
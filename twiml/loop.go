@@ -0,0 +1,25 @@
+package twiml
+
+// Attributes whose zero means something to twilio are *int, so the zero
+// value of the field can leave them out: nil omits the attribute, a pointer
+// to 0 renders it as "0". These are the loop of Say and Play, where 0 repeats
+// forever, the length of Pause and the timeout of Record, where 0 records
+// without stopping on silence.
+
+// Loop returns the loop attribute of a Say or Play repeating n times, 0
+// repeats until the call ends
+func Loop(n int) *int {
+	return &n
+}
+
+// LoopForever returns the loop attribute of a Say or Play repeating until
+// the call ends
+func LoopForever() *int {
+	return Loop(0)
+}
+
+// Seconds returns the length of a Pause or the timeout of a Record of n
+// seconds
+func Seconds(n int) *int {
+	return &n
+}
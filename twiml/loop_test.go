@@ -0,0 +1,40 @@
+package twiml
+
+import (
+	"encoding/xml"
+	"testing"
+)
+
+func TestZeroAttributes(t *testing.T) {
+	var tests = []struct {
+		Value  interface{}
+		Expect string
+	}{
+		{Say{Text: "Hi"}, `<Say>Hi</Say>`},
+		{Say{Loop: LoopForever(), Text: "Hi"}, `<Say loop="0">Hi</Say>`},
+		{Say{Loop: Loop(3), Text: "Hi"}, `<Say loop="3">Hi</Say>`},
+		{Play{Url: "a.mp3"}, `<Play>a.mp3</Play>`},
+		{Play{Loop: Loop(0), Url: "a.mp3"}, `<Play loop="0">a.mp3</Play>`},
+		{Play{Loop: Loop(2), Url: "a.mp3"}, `<Play loop="2">a.mp3</Play>`},
+		{Pause{}, `<Pause></Pause>`},
+		{Pause{Length: Seconds(0)}, `<Pause length="0"></Pause>`},
+		{Pause{Length: Seconds(5)}, `<Pause length="5"></Pause>`},
+		{Record{}, `<Record></Record>`},
+		{Record{Timeout: Seconds(0)}, `<Record timeout="0"></Record>`},
+		{Record{Timeout: Seconds(10)}, `<Record timeout="10"></Record>`},
+	}
+
+	for idx, test := range tests {
+		out, err := xml.Marshal(test.Value)
+		if err != nil || string(out) != test.Expect {
+			t.Errorf("Test %v failed; expected %v, got %v (%v)", idx, test.Expect,
+				string(out), err)
+		}
+	}
+
+	var say Say
+	if err := xml.Unmarshal([]byte(`<Say loop="0">Hi</Say>`), &say); err != nil ||
+		say.Loop == nil || *say.Loop != 0 {
+		t.Errorf("expected loop 0 parsed, got %v (%v)", say.Loop, err)
+	}
+}
@@ -0,0 +1,109 @@
+package twiml
+
+import (
+	"net/http"
+	"strconv"
+)
+
+// DefaultMenuRetries is the number of times a Menu prompts again after an
+// unmapped key or no input, unless configured WithMenuRetries
+const DefaultMenuRetries = 3
+
+// menuAttempt is the query parameter counting the prompts of a menu
+const menuAttempt = "attempt"
+
+// menu is the configuration of a Menu
+type menu struct {
+	prompt  Say
+	options map[string]string
+	retries int
+	invalid Say
+}
+
+// MenuOption configures a Menu
+type MenuOption func(*menu)
+
+// WithMenuRetries sets the number of times the menu prompts again before the
+// call is hung up
+func WithMenuRetries(retries int) MenuOption {
+	return func(m *menu) {
+		m.retries = retries
+	}
+}
+
+// WithMenuInvalid sets what is said before the menu prompts again
+func WithMenuInvalid(say Say) MenuOption {
+	return func(m *menu) {
+		m.invalid = say
+	}
+}
+
+// Menu builds a single key menu. options maps keys to the url the call is
+// redirected to when the key is pressed. The response gathers one key while
+// saying prompt. The handler dispatches the gathered key, prompting again
+// after an unmapped key or no input until the retries run out and the call is
+// hung up. The handler serves the menu itself when no key was gathered yet, so
+// it can be the voice url of the menu. The attempts are counted in the query
+// string of the relative gather action.
+func Menu(prompt Say, options map[string]string, opts ...MenuOption) (
+	Response, http.HandlerFunc) {
+
+	m := &menu{prompt: prompt, options: options, retries: DefaultMenuRetries}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m.response(0, false), m.serveHTTP
+}
+
+// response returns the menu prompt of the attempt, saying the invalid message
+// first if retry is set
+func (m *menu) response(attempt int, retry bool) Response {
+	action := "?" + menuAttempt + "=" + strconv.Itoa(attempt)
+
+	r := Response{}
+	if retry && m.invalid.Text != "" {
+		r.Response = append(r.Response, m.invalid)
+	}
+	r.Response = append(r.Response,
+		Gather{Action: action, Method: "POST", NumDigits: 1,
+			Nested: []interface{}{m.prompt}},
+		// reached when the gather times out without input
+		Redirect{Method: "POST", Url: action})
+	return r
+}
+
+// serveHTTP dispatches the key gathered by the menu
+func (m *menu) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var resp Response
+	digits := r.Form.Get(TwiDigits)
+	attempt, err := strconv.Atoi(r.URL.Query().Get(menuAttempt))
+	switch {
+	case err != nil:
+		// not from the menu, prompt
+		resp = m.response(0, false)
+	case m.options[digits] != "":
+		resp.Response = []interface{}{Redirect{Method: "POST", Url: m.options[digits]}}
+	case attempt < m.retries:
+		resp = m.response(attempt+1, true)
+	default:
+		resp.Response = []interface{}{Hangup{}}
+	}
+
+	writeResponse(w, resp)
+}
+
+// writeResponse renders resp to w
+func writeResponse(w http.ResponseWriter, resp Response) {
+	out, err := resp.Render()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/xml")
+	w.Write(out)
+}
@@ -0,0 +1,61 @@
+package twiml
+
+import (
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestMenu(t *testing.T) {
+	resp, handler := Menu(Say{Text: "Press 1 for sales, 2 for support"},
+		map[string]string{"1": "/sales", "2": "/support"},
+		WithMenuRetries(2), WithMenuInvalid(Say{Text: "Sorry"}))
+
+	gather := `<Gather action="?attempt=%s" method="POST" numDigits="1">` +
+		`<Say>Press 1 for sales, 2 for support</Say></Gather>` +
+		`<Redirect method="POST">?attempt=%s</Redirect>`
+	prompt := func(attempt string, invalid bool) string {
+		s := strings.Replace(gather, "%s", attempt, -1)
+		if invalid {
+			s = "<Say>Sorry</Say>" + s
+		}
+		return xml.Header + "<Response>" + s + "</Response>"
+	}
+
+	out, err := resp.Render()
+	if err != nil || string(out) != prompt("0", false) {
+		t.Errorf("expected %#v, got %#v (%v)", prompt("0", false), string(out), err)
+	}
+
+	var tests = []struct {
+		Query  string
+		Digits string
+		Expect string
+	}{
+		{"", "", prompt("0", false)},
+		{"?attempt=0", "1", xml.Header + `<Response><Redirect method="POST">/sales</Redirect></Response>`},
+		{"?attempt=1", "2", xml.Header + `<Response><Redirect method="POST">/support</Redirect></Response>`},
+		{"?attempt=0", "9", prompt("1", true)},
+		{"?attempt=1", "", prompt("2", true)},
+		{"?attempt=2", "#", xml.Header + `<Response><Hangup></Hangup></Response>`},
+	}
+
+	for idx, test := range tests {
+		form := url.Values{TwiCallSid: {"CA123"}}
+		if test.Digits != "" {
+			form.Set(TwiDigits, test.Digits)
+		}
+		r := httptest.NewRequest("POST", "/menu"+test.Query, strings.NewReader(form.Encode()))
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		w := httptest.NewRecorder()
+		handler(w, r)
+
+		if w.Code != http.StatusOK || w.Body.String() != test.Expect {
+			t.Errorf("Test %v failed; expected %#v, got %v %#v",
+				idx, test.Expect, w.Code, w.Body.String())
+		}
+	}
+}
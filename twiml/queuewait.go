@@ -0,0 +1,174 @@
+package twiml
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// QueueWait holds the parameters twilio sends with the requests of the wait
+// url of an Enqueue. Times are in seconds.
+type QueueWait struct {
+	CallSid          string
+	AccountSid       string
+	From             string
+	To               string
+	QueueSid         string
+	QueuePosition    int
+	QueueTime        int
+	AvgQueueTime     int
+	CurrentQueueSize int
+	MaxQueueSize     int
+}
+
+// ParseQueueWait parses the request of the wait url of a queue
+func ParseQueueWait(r *http.Request) (QueueWait, error) {
+	if err := r.ParseForm(); err != nil {
+		return QueueWait{}, err
+	}
+
+	w := QueueWait{
+		CallSid:    r.Form.Get(TwiCallSid),
+		AccountSid: r.Form.Get(TwiAccountSid),
+		From:       r.Form.Get(TwiFrom),
+		To:         r.Form.Get(TwiTo),
+		QueueSid:   r.Form.Get(TwiQueueSid),
+	}
+	for _, f := range []struct {
+		name string
+		v    *int
+	}{
+		{TwiQueuePosition, &w.QueuePosition},
+		{TwiQueueTime, &w.QueueTime},
+		{TwiAvgQueueTime, &w.AvgQueueTime},
+		{TwiCurrentQueueSize, &w.CurrentQueueSize},
+		{TwiMaxQueueSize, &w.MaxQueueSize},
+	} {
+		var err error
+		if *f.v, err = formInt(r, f.name); err != nil {
+			return w, err
+		}
+	}
+	return w, nil
+}
+
+// WaitConfig configures QueueWaitHandler
+type WaitConfig struct {
+	// Say sets the voice and language of what is said, its text is ignored
+	Say Say
+	// Announce returns what is said to the caller on every check of the
+	// queue, their position and the estimated wait by default
+	Announce func(QueueWait) string
+	// HoldMusic is the url of the music played between the checks, the
+	// caller hears silence if it's empty
+	HoldMusic string
+	// HoldLoop is the number of times the hold music is played between the
+	// checks, default 1
+	HoldLoop int
+	// HoldPause is the seconds of silence between the checks without hold
+	// music, default 30
+	HoldPause int
+	// ScheduleCallback, if set, is offered to the caller: pressing
+	// CallbackKey during the hold music calls it and the caller leaves the
+	// queue. The call continues after the Enqueue verb. If it fails the
+	// caller keeps waiting.
+	ScheduleCallback func(QueueWait) error
+	// CallbackKey is the key that asks for a callback, default 1
+	CallbackKey string
+	// CallbackPrompt offers the callback after the announcement
+	CallbackPrompt string
+	// CallbackConfirm is said before the caller leaves the queue
+	CallbackConfirm string
+}
+
+// defaults returns the configuration with the defaults filled in
+func (cfg WaitConfig) defaults() WaitConfig {
+	if cfg.Announce == nil {
+		cfg.Announce = announceWait
+	}
+	if cfg.HoldLoop <= 0 {
+		cfg.HoldLoop = 1
+	}
+	if cfg.HoldPause <= 0 {
+		cfg.HoldPause = 30
+	}
+	if cfg.CallbackKey == "" {
+		cfg.CallbackKey = "1"
+	}
+	if cfg.CallbackPrompt == "" {
+		cfg.CallbackPrompt = "To leave the queue and be called back, press " +
+			cfg.CallbackKey + "."
+	}
+	if cfg.CallbackConfirm == "" {
+		cfg.CallbackConfirm = "Thank you, we will call you back."
+	}
+	return cfg
+}
+
+// announceWait tells the caller's position and the average wait
+func announceWait(w QueueWait) string {
+	var text string
+	if w.QueuePosition > 0 {
+		text = fmt.Sprintf("You are number %d in the queue.", w.QueuePosition)
+	}
+	if w.AvgQueueTime > 0 {
+		minutes := (w.AvgQueueTime + 59) / 60
+		unit := "minutes"
+		if minutes == 1 {
+			unit = "minute"
+		}
+		text += fmt.Sprintf(" The estimated wait is %d %s.", minutes, unit)
+	}
+	return strings.TrimSpace(text)
+}
+
+// QueueWaitHandler returns the handler of the wait url of a queue. Twilio
+// requests the wait url again whenever its TwiML ends, so every request is a
+// check of the queue: the caller's position and estimated wait are announced
+// and the hold music played until the next one. With ScheduleCallback the
+// hold music is played in a Gather offering a callback, the Gather posts the
+// key pressed to the wait url.
+func QueueWaitHandler(cfg WaitConfig) http.HandlerFunc {
+	cfg = cfg.defaults()
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		wait, err := ParseQueueWait(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		var resp Response
+		if cfg.ScheduleCallback != nil && r.Form.Get(TwiDigits) == cfg.CallbackKey {
+			if err := cfg.ScheduleCallback(wait); err == nil {
+				resp.Response = []interface{}{cfg.say(cfg.CallbackConfirm), Leave{}}
+				writeResponse(w, resp)
+				return
+			}
+		}
+
+		if text := cfg.Announce(wait); text != "" {
+			resp.Response = append(resp.Response, cfg.say(text))
+		}
+		hold := []interface{}{Pause{Length: Seconds(cfg.HoldPause)}}
+		if cfg.HoldMusic != "" {
+			hold = []interface{}{Play{Loop: Loop(cfg.HoldLoop), Url: cfg.HoldMusic}}
+		}
+		if cfg.ScheduleCallback == nil {
+			resp.Response = append(resp.Response, hold...)
+		} else {
+			// without a key pressed the gather ends and the queue is checked
+			// again
+			resp.Response = append(resp.Response, Gather{Method: "POST", NumDigits: 1,
+				Timeout: 1, Nested: append([]interface{}{cfg.say(cfg.CallbackPrompt)}, hold...)})
+		}
+		writeResponse(w, resp)
+	}
+}
+
+// say returns a Say of text in the voice and language of the configuration
+func (cfg WaitConfig) say(text string) Say {
+	s := cfg.Say
+	s.Text = text
+	return s
+}
@@ -0,0 +1,84 @@
+package twiml
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestParseQueueWait(t *testing.T) {
+	form := url.Values{TwiCallSid: {"CA123"}, TwiQueueSid: {"QU123"}, TwiFrom: {"+15005550006"},
+		TwiQueuePosition: {"3"}, TwiQueueTime: {"42"}, TwiAvgQueueTime: {"150"},
+		TwiCurrentQueueSize: {"5"}, TwiMaxQueueSize: {"100"}}
+	w, err := ParseQueueWait(webhookRequest(form))
+	expect := QueueWait{CallSid: "CA123", From: "+15005550006", QueueSid: "QU123",
+		QueuePosition: 3, QueueTime: 42, AvgQueueTime: 150, CurrentQueueSize: 5, MaxQueueSize: 100}
+	if err != nil || w != expect {
+		t.Errorf("expected %+v, got %+v (%v)", expect, w, err)
+	}
+
+	form.Set(TwiQueuePosition, "first")
+	if _, err := ParseQueueWait(webhookRequest(form)); err == nil {
+		t.Errorf("expected an error for a non numeric QueuePosition")
+	}
+}
+
+func TestQueueWaitHandler(t *testing.T) {
+	var scheduled []QueueWait
+	callback := func(w QueueWait) error {
+		if w.From == "" {
+			return errors.New("no number to call back")
+		}
+		scheduled = append(scheduled, w)
+		return nil
+	}
+	wait := url.Values{TwiCallSid: {"CA123"}, TwiFrom: {"+15005550006"},
+		TwiQueuePosition: {"2"}, TwiAvgQueueTime: {"61"}}
+	pressed := func(digits, from string) url.Values {
+		return url.Values{TwiCallSid: {"CA123"}, TwiFrom: {from}, TwiDigits: {digits}}
+	}
+
+	var tests = []struct {
+		Config WaitConfig
+		Form   url.Values
+		Expect string
+	}{
+		{WaitConfig{}, wait, `<Response><Say>You are number 2 in the queue. ` +
+			`The estimated wait is 2 minutes.</Say><Pause length="30"></Pause></Response>`},
+		{WaitConfig{Say: Say{Voice: TwiAlice}, HoldMusic: "https://example.com/hold.mp3",
+			HoldLoop: 2}, url.Values{TwiQueuePosition: {"1"}},
+			`<Response><Say voice="alice">You are number 1 in the queue.</Say>` +
+				`<Play loop="2">https://example.com/hold.mp3</Play></Response>`},
+		{WaitConfig{Announce: func(QueueWait) string { return "" }, HoldPause: 10},
+			wait, `<Response><Pause length="10"></Pause></Response>`},
+		{WaitConfig{ScheduleCallback: callback, HoldMusic: "hold.mp3"}, wait,
+			`<Response><Say>You are number 2 in the queue. The estimated wait is 2 minutes.</Say>` +
+				`<Gather method="POST" timeout="1" numDigits="1"><Say>To leave the queue and ` +
+				`be called back, press 1.</Say><Play loop="1">hold.mp3</Play></Gather></Response>`},
+		{WaitConfig{ScheduleCallback: callback, CallbackConfirm: "Bye."},
+			pressed("1", "+15005550006"), `<Response><Say>Bye.</Say><Leave></Leave></Response>`},
+		// other keys and failed callbacks keep the caller waiting
+		{WaitConfig{ScheduleCallback: callback, Announce: func(QueueWait) string { return "" }},
+			pressed("2", "+15005550006"), `<Response><Gather method="POST" timeout="1" ` +
+				`numDigits="1"><Say>To leave the queue and be called back, press 1.</Say>` +
+				`<Pause length="30"></Pause></Gather></Response>`},
+		{WaitConfig{ScheduleCallback: callback, Announce: func(QueueWait) string { return "" },
+			CallbackKey: "9", CallbackPrompt: "Press 9."}, pressed("9", ""),
+			`<Response><Gather method="POST" timeout="1" numDigits="1"><Say>Press 9.</Say>` +
+				`<Pause length="30"></Pause></Gather></Response>`},
+	}
+
+	for idx, test := range tests {
+		rec := httptest.NewRecorder()
+		QueueWaitHandler(test.Config)(rec, webhookRequest(test.Form))
+		if got := rec.Body.String(); rec.Code != http.StatusOK ||
+			got != `<?xml version="1.0" encoding="UTF-8"?>`+"\n"+test.Expect {
+			t.Errorf("Test %v failed; expected %v, got %v %v", idx, test.Expect, rec.Code, got)
+		}
+	}
+	if len(scheduled) != 1 || scheduled[0].CallSid != "CA123" {
+		t.Errorf("expected one callback scheduled, got %+v", scheduled)
+	}
+}
@@ -0,0 +1,122 @@
+package twiml
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+)
+
+// Raw is a TwiML fragment included in the response verbatim, for verbs the
+// package doesn't model yet. The fragment is only checked to be well-formed
+// xml when the response is rendered, not that twilio understands it.
+type Raw struct {
+	XML string
+}
+
+// WithAttr returns an attribute for the ExtraAttrs of a verb, for attributes
+// the verb doesn't model yet. Extra attributes are rendered after the modeled
+// ones and may not repeat them.
+func WithAttr(name, value string) xml.Attr {
+	return xml.Attr{Name: xml.Name{Local: name}, Value: value}
+}
+
+// validate checks that the fragment is well-formed, it may hold several
+// elements and text
+func (r Raw) validate() error {
+	d := xml.NewDecoder(strings.NewReader("<Raw>" + r.XML + "</Raw>"))
+	for {
+		_, err := d.Token()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("non valid raw xml: %v", err)
+		}
+	}
+}
+
+// MarshalXML encodes the tokens of the fragment in place of the Raw
+func (r Raw) MarshalXML(e *xml.Encoder, _ xml.StartElement) error {
+	d := xml.NewDecoder(strings.NewReader(r.XML))
+	for {
+		tok, err := d.RawToken()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			t.Name = rawName(t.Name)
+			for i, a := range t.Attr {
+				t.Attr[i].Name = rawName(a.Name)
+			}
+			tok = t
+		case xml.EndElement:
+			t.Name = rawName(t.Name)
+			tok = t
+		}
+		if err := e.EncodeToken(tok); err != nil {
+			return err
+		}
+	}
+}
+
+// rawName keeps the prefix of a raw token in its name, the encoder would
+// take it for a namespace
+func rawName(n xml.Name) xml.Name {
+	if n.Space == "" {
+		return n
+	}
+	return xml.Name{Local: n.Space + ":" + n.Local}
+}
+
+// extraAttrs returns the ExtraAttrs of a verb
+func extraAttrs(val reflect.Value) []xml.Attr {
+	if val.Kind() == reflect.Ptr {
+		val = val.Elem()
+	}
+	if val.Kind() != reflect.Struct {
+		return nil
+	}
+	fld := val.FieldByName("ExtraAttrs")
+	if !fld.IsValid() {
+		return nil
+	}
+	attrs, _ := fld.Interface().([]xml.Attr)
+	return attrs
+}
+
+// checkExtraAttrs returns an error if an extra attribute of a verb has no
+// name or repeats another attribute, the xml wouldn't be well-formed
+func checkExtraAttrs(val reflect.Value) error {
+	attrs := extraAttrs(val)
+	if len(attrs) == 0 {
+		return nil
+	}
+	t := val.Type()
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	seen := map[string]bool{}
+	for i := 0; i < t.NumField(); i++ {
+		tag := strings.Split(t.Field(i).Tag.Get("xml"), ",")
+		if len(tag) > 1 && tag[1] == "attr" {
+			seen[tag[0]] = true
+		}
+	}
+	for _, a := range attrs {
+		name := rawName(a.Name).Local
+		if name == "" {
+			return fmt.Errorf("non valid attribute: no name")
+		}
+		if seen[name] {
+			return fmt.Errorf("non valid attribute: '%s' repeated", name)
+		}
+		seen[name] = true
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package twiml
+
+import (
+	"encoding/xml"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestRaw(t *testing.T) {
+	var tests = []struct {
+		Verbs  []interface{}
+		Expect string
+		Path   string // of the render error, if any
+	}{
+		{[]interface{}{Raw{XML: `<Pay chargeAmount="20.45" paymentConnector="Stripe"/>`}},
+			`<Response><Pay chargeAmount="20.45" paymentConnector="Stripe"></Pay></Response>`, ""},
+		{[]interface{}{Say{Text: "hi"}, Raw{XML: `<Refer><Sip>sip:a@b.com</Sip></Refer> &amp; <x:Y a="&lt;"/>`}},
+			`<Response><Say>hi</Say><Refer><Sip>sip:a@b.com</Sip></Refer> &amp; <x:Y a="&lt;"></x:Y></Response>`, ""},
+		{[]interface{}{Gather{Nested: []interface{}{Raw{XML: "<Say>a</Say>"}}}},
+			`<Response><Gather><Say>a</Say></Gather></Response>`, ""},
+		{[]interface{}{Say{Text: "hi"}, Raw{XML: "<Pay>"}}, "", "Response>Raw[1]"},
+		{[]interface{}{Gather{Nested: []interface{}{&Raw{XML: "<Pay></Refer>"}}}},
+			"", "Response>Gather[0]>Raw[0]"},
+		{[]interface{}{Raw{XML: "a & b"}}, "", "Response>Raw[0]"},
+		{[]interface{}{Say{Text: "hi", Voice: "alice",
+			ExtraAttrs: []xml.Attr{WithAttr("ssml", "true")}}},
+			`<Response><Say voice="alice" ssml="true">hi</Say></Response>`, ""},
+		{[]interface{}{Gather{NumDigits: 1, ExtraAttrs: []xml.Attr{WithAttr("input", "speech")},
+			Nested: []interface{}{Say{ExtraAttrs: []xml.Attr{WithAttr("voice", "man")}}}}},
+			"", "Response>Gather[0]>Say[0]"},
+		{[]interface{}{Say{ExtraAttrs: []xml.Attr{WithAttr("", "x")}}}, "", "Response>Say[0]"},
+		{[]interface{}{Say{ExtraAttrs: []xml.Attr{WithAttr("a", "1"), WithAttr("a", "2")}}},
+			"", "Response>Say[0]"},
+	}
+
+	for i, tt := range tests {
+		out, err := Response{Response: tt.Verbs}.Render()
+		if tt.Path != "" {
+			var re *RenderError
+			if !errors.As(err, &re) || re.Path != tt.Path {
+				t.Errorf("Test %v failed; expected a render error at %v, got %v", i, tt.Path, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Test %v failed; unexpected error %v", i, err)
+			continue
+		}
+		if got := strings.TrimPrefix(string(out), xml.Header); got != tt.Expect {
+			t.Errorf("Test %v failed; expected %v, got %v", i, tt.Expect, got)
+		}
+	}
+}
+
+func TestRawWarnings(t *testing.T) {
+	r := Response{Response: []interface{}{
+		Say{Text: "hi", ExtraAttrs: []xml.Attr{WithAttr("ssml", "true")}},
+		Raw{XML: "<Pay/>"},
+	}}
+	var got []string
+	for _, w := range r.Warnings() {
+		got = append(got, w.String())
+	}
+	expect := []string{
+		"twiml: Response>Say[0]: attribute ssml isn't modeled, it isn't checked",
+		"twiml: Response>Raw[1]: raw xml is only checked to be well-formed",
+	}
+	if strings.Join(got, "\n") != strings.Join(expect, "\n") {
+		t.Errorf("expected warnings %q, got %q", expect, got)
+	}
+}
@@ -0,0 +1,126 @@
+package twiml
+
+import (
+	"encoding/xml"
+	"fmt"
+	"reflect"
+)
+
+// RenderError reports the verb a response failed to render at. Path is the
+// breadcrumb of verbs from the Response, children carry their index.
+type RenderError struct {
+	Path string
+	Err  error
+}
+
+func (e *RenderError) Error() string {
+	return fmt.Sprintf("twiml: render failed at %s: %v", e.Path, e.Err)
+}
+
+// Unwrap returns the underlying validation or marshal error
+func (e *RenderError) Unwrap() error {
+	return e.Err
+}
+
+// Render returns the xml encoded response. Verbs that are nil or fail to
+// marshal are reported as a *RenderError, a document larger than
+// MaxDocumentSize as an *ErrDocumentTooLarge. The response isn't modified, so
+// a shared response can be rendered concurrently.
+func (r Response) Render() ([]byte, error) {
+	return r.RenderWithOptions(RenderOptions{})
+}
+
+// render returns the xml encoded response whatever its size
+func (r Response) render() ([]byte, error) {
+	if err := validateNested("Response", r.Response); err != nil {
+		return nil, err
+	}
+	out, err := xml.Marshal(r)
+	if err != nil {
+		return nil, locateError("Response", r.Response, err)
+	}
+	return append([]byte(xml.Header), out...), nil
+}
+
+// validator is a verb that checks its attributes before it's rendered
+type validator interface {
+	validate() error
+}
+
+// validateNested checks that no verb in verbs, or nested in them, is nil.
+// The xml encoder silently drops those. Verbs that are a validator, and the
+// extra attributes of verbs, are checked too.
+func validateNested(path string, verbs []interface{}) error {
+	for i, v := range verbs {
+		val := reflect.ValueOf(v)
+		if !val.IsValid() || (val.Kind() == reflect.Ptr && val.IsNil()) {
+			return &RenderError{
+				Path: fmt.Sprintf("%s>[%d]", path, i),
+				Err:  fmt.Errorf("nil verb"),
+			}
+		}
+		if err := checkExtraAttrs(val); err != nil {
+			return &RenderError{Path: verbPath(path, val, i), Err: err}
+		}
+		if vv, ok := v.(validator); ok {
+			if err := vv.validate(); err != nil {
+				return &RenderError{Path: verbPath(path, val, i), Err: err}
+			}
+		}
+		if nested, ok := nestedVerbs(val); ok {
+			if err := validateNested(verbPath(path, val, i), nested); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// locateError marshals verbs one by one to find the deepest one failing, err
+// is returned at path if no single verb fails
+func locateError(path string, verbs []interface{}, err error) error {
+	for i, v := range verbs {
+		if _, verr := xml.Marshal(v); verr != nil {
+			val := reflect.ValueOf(v)
+			if nested, ok := nestedVerbs(val); ok {
+				return locateError(verbPath(path, val, i), nested, verr)
+			}
+			return &RenderError{Path: verbPath(path, val, i), Err: verr}
+		}
+	}
+	return &RenderError{Path: path, Err: err}
+}
+
+// nestedVerbs returns the Nested verbs of container verbs such as Dial and
+// Gather
+func nestedVerbs(val reflect.Value) ([]interface{}, bool) {
+	if val.Kind() == reflect.Ptr {
+		val = val.Elem()
+	}
+	if val.Kind() != reflect.Struct {
+		return nil, false
+	}
+	fld := val.FieldByName("Nested")
+	if !fld.IsValid() {
+		return nil, false
+	}
+	nested, ok := fld.Interface().([]interface{})
+	return nested, ok
+}
+
+// verbPath appends the verb at index i to path
+func verbPath(path string, val reflect.Value, i int) string {
+	return fmt.Sprintf("%s>%s[%d]", path, verbName(val), i)
+}
+
+// verbName returns the type name of the verb
+func verbName(val reflect.Value) string {
+	t := val.Type()
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Name() == "" {
+		return t.String()
+	}
+	return t.Name()
+}
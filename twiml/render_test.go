@@ -0,0 +1,67 @@
+package twiml
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+// failingVerb fails to marshal, like a verb with an invalid attribute would
+type failingVerb struct{}
+
+func (failingVerb) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return fmt.Errorf("invalid attribute")
+}
+
+func TestRenderErrorPath(t *testing.T) {
+	var nilSay *Say
+
+	var tests = []struct {
+		Verbs []interface{}
+		Path  string
+	}{
+		{[]interface{}{Say{Text: "hi"}, failingVerb{}},
+			"Response>failingVerb[1]"},
+		{[]interface{}{Say{Text: "hi"}, Gather{Nested: []interface{}{failingVerb{}}}},
+			"Response>Gather[1]>failingVerb[0]"},
+		{[]interface{}{Dial{Nested: []interface{}{Number{Number: "+1"}, make(chan int)}}},
+			"Response>Dial[0]>chan int[1]"},
+		{[]interface{}{Hangup{}, nil},
+			"Response>[1]"},
+		{[]interface{}{Say{}, Gather{Nested: []interface{}{Pause{}, nilSay}}},
+			"Response>Gather[1]>[1]"},
+		{[]interface{}{&Gather{Nested: []interface{}{Say{}, failingVerb{}}}},
+			"Response>Gather[0]>failingVerb[1]"},
+	}
+
+	for idx, test := range tests {
+		r := Response{Response: test.Verbs}
+
+		_, err := r.Render()
+		var rerr *RenderError
+		if !errors.As(err, &rerr) || rerr.Path != test.Path {
+			t.Errorf("Test %v failed; expected path %#v, got %v", idx, test.Path, err)
+		}
+
+		err = r.Send(&bytes.Buffer{})
+		if !errors.As(err, &rerr) || rerr.Path != test.Path {
+			t.Errorf("Test %v failed; expected send path %#v, got %v", idx, test.Path, err)
+		}
+	}
+}
+
+func TestRender(t *testing.T) {
+	r := NewResponse()
+	r.Gather(Gather{NumDigits: 1}, Say{Text: "Press 1"})
+
+	out, err := r.Render()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := xml.Header + `<Response><Gather numDigits="1"><Say>Press 1</Say></Gather></Response>`
+	if string(out) != expect {
+		t.Errorf("expected %#v, got %#v", expect, string(out))
+	}
+}
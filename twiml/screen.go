@@ -0,0 +1,78 @@
+package twiml
+
+import "net/http"
+
+// ScreenConfig configures a call screening flow. The caller is dialed
+// through to Number, the callee hears Prompt once they answer and presses
+// AcceptKey to take the call. Any other key, or no key at all, hangs up the
+// callee and the Dial action takes over, for example to go to voicemail.
+type ScreenConfig struct {
+	Number   string
+	CallerId string
+	Timeout  int    // seconds to ring Number
+	Action   string // Dial action, reached when the call isn't accepted
+	// WhisperUrl is where WhisperHandler is served, AcceptUrl where
+	// AcceptHandler is
+	WhisperUrl string
+	AcceptUrl  string
+	Prompt     Say
+	AcceptKey  string // "1" if not set
+}
+
+// CallScreen holds the pieces of a call screening flow, as responses for
+// custom routing and as the handlers serving them
+type CallScreen struct {
+	// Dial dials the callee with the whisper url, answer calls with it
+	Dial Response
+	// Whisper is played to the callee, gathering their key
+	Whisper Response
+	// Accept bridges the call, it is empty
+	Accept Response
+	// Decline hangs up the callee, the caller continues at the Dial action
+	Decline Response
+
+	DialHandler    http.HandlerFunc
+	WhisperHandler http.HandlerFunc
+	AcceptHandler  http.HandlerFunc
+}
+
+// NewCallScreen builds the responses and handlers of a call screening flow
+func NewCallScreen(cfg ScreenConfig) CallScreen {
+	if cfg.AcceptKey == "" {
+		cfg.AcceptKey = "1"
+	}
+
+	s := CallScreen{
+		Dial: Response{Response: []interface{}{
+			Dial{Action: cfg.Action, Method: "POST", CallerId: cfg.CallerId,
+				Timeout: cfg.Timeout, Nested: []interface{}{
+					Number{Url: cfg.WhisperUrl, Method: "POST", Number: cfg.Number}}},
+		}},
+		Whisper: Response{Response: []interface{}{
+			Gather{Action: cfg.AcceptUrl, Method: "POST", NumDigits: 1,
+				Nested: []interface{}{cfg.Prompt}},
+			// reached when the callee doesn't press a key
+			Hangup{},
+		}},
+		Decline: Response{Response: []interface{}{Hangup{}}},
+	}
+
+	s.DialHandler = func(w http.ResponseWriter, r *http.Request) {
+		writeResponse(w, s.Dial)
+	}
+	s.WhisperHandler = func(w http.ResponseWriter, r *http.Request) {
+		writeResponse(w, s.Whisper)
+	}
+	s.AcceptHandler = func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if r.Form.Get(TwiDigits) == cfg.AcceptKey {
+			writeResponse(w, s.Accept)
+			return
+		}
+		writeResponse(w, s.Decline)
+	}
+	return s
+}
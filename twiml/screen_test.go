@@ -0,0 +1,77 @@
+package twiml
+
+import (
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestCallScreen(t *testing.T) {
+	screen := NewCallScreen(ScreenConfig{
+		Number:     "+15005550006",
+		CallerId:   "+15005550001",
+		Timeout:    20,
+		Action:     "/voicemail",
+		WhisperUrl: "/whisper",
+		AcceptUrl:  "/accept",
+		Prompt:     Say{Text: "Press 1 to accept the call"},
+	})
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/call", screen.DialHandler)
+	mux.HandleFunc("/whisper", screen.WhisperHandler)
+	mux.HandleFunc("/accept", screen.AcceptHandler)
+
+	const (
+		dial = xml.Header + `<Response><Dial action="/voicemail" method="POST" ` +
+			`timeout="20" callerId="+15005550001"><Number url="/whisper" method="POST">` +
+			`+15005550006</Number></Dial></Response>`
+		whisper = xml.Header + `<Response><Gather action="/accept" method="POST" ` +
+			`numDigits="1"><Say>Press 1 to accept the call</Say></Gather>` +
+			`<Hangup></Hangup></Response>`
+		accept  = xml.Header + `<Response></Response>`
+		decline = xml.Header + `<Response><Hangup></Hangup></Response>`
+	)
+
+	// webhook sequences of a screened call: the caller's call, the callee
+	// answering, then the callee's key
+	var tests = []struct {
+		Digits string
+		Expect string
+	}{
+		{"1", accept},
+		{"2", decline},
+		{"", decline},
+	}
+
+	for idx, test := range tests {
+		steps := []struct {
+			Path   string
+			Form   url.Values
+			Expect string
+		}{
+			{"/call", url.Values{TwiCallSid: {"CA1"}}, dial},
+			{"/whisper", url.Values{TwiCallSid: {"CA2"}}, whisper},
+			{"/accept", url.Values{TwiCallSid: {"CA2"}, TwiDigits: {test.Digits}}, test.Expect},
+		}
+		for _, step := range steps {
+			r := httptest.NewRequest("POST", step.Path, strings.NewReader(step.Form.Encode()))
+			r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, r)
+
+			if w.Code != http.StatusOK || w.Body.String() != step.Expect {
+				t.Errorf("Test %v failed; %v expected %#v, got %v %#v",
+					idx, step.Path, step.Expect, w.Code, w.Body.String())
+			}
+		}
+	}
+
+	out, _ := screen.Decline.Render()
+	if string(out) != decline {
+		t.Errorf("expected the Decline response %#v, got %#v", decline, string(out))
+	}
+}
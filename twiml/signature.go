@@ -0,0 +1,58 @@
+package twiml
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"sort"
+)
+
+// TwiSignatureHeader is the header carrying the signature of twilio requests
+const TwiSignatureHeader = "X-Twilio-Signature"
+
+// Signature computes the signature twilio sends with a request to url with
+// the POST parameters params, see
+// https://www.twilio.com/docs/usage/security#validating-requests. The
+// parameters are signed sorted by name and value, so the order they were
+// sent in doesn't matter.
+func Signature(authToken, url string, params map[string][]string) string {
+	keys := make([]string, 0, len(params))
+	for k := range params {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	data := url
+	for _, k := range keys {
+		vals := append([]string(nil), params[k]...)
+		sort.Strings(vals)
+		for _, v := range vals {
+			data += k + v
+		}
+	}
+
+	mac := hmac.New(sha1.New, []byte(authToken))
+	mac.Write([]byte(data))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// ValidateSignature checks that r was signed by twilio with authToken. url is
+// the full url twilio requested, as configured on the twilio side, since it
+// can't be reliably rebuilt behind proxies.
+func ValidateSignature(r *http.Request, url, authToken string) error {
+	if err := r.ParseForm(); err != nil {
+		return err
+	}
+	got := r.Header.Get(TwiSignatureHeader)
+	if got == "" {
+		return fmt.Errorf("missing header: '%s'", TwiSignatureHeader)
+	}
+
+	expect := Signature(authToken, url, r.PostForm)
+	if !hmac.Equal([]byte(got), []byte(expect)) {
+		return fmt.Errorf("non valid signature: '%s'", got)
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package twiml
+
+import (
+	"encoding/xml"
+	"fmt"
+	"reflect"
+)
+
+// MaxDocumentSize is the size of the largest TwiML document twilio accepts,
+// in bytes. A call given a larger one fails.
+const MaxDocumentSize = 64 * 1024
+
+// ErrDocumentTooLarge is the error of a response whose document is larger
+// than the limit. Path and VerbSize are the verb of the Response rendering
+// the most bytes and its size.
+type ErrDocumentTooLarge struct {
+	Size     int
+	Limit    int
+	Path     string
+	VerbSize int
+}
+
+func (e *ErrDocumentTooLarge) Error() string {
+	return fmt.Sprintf("twiml: document of %d bytes over the limit of %d, %s has %d",
+		e.Size, e.Limit, e.Path, e.VerbSize)
+}
+
+// RenderOptions configures RenderWithOptions
+type RenderOptions struct {
+	// MaxSize is the size limit of the document, MaxDocumentSize if zero
+	MaxSize int
+	// Continue, if set, degrades a document over the limit instead of
+	// failing it: trailing verbs of the Response are dropped until the
+	// document fits with a Redirect to Continue(next) appended, next being
+	// the index of the first verb dropped. The first verb is always kept, a
+	// response that doesn't fit with it alone fails.
+	Continue func(next int) string
+}
+
+// RenderWithOptions renders the response like Render, with the size limit
+// and fallback of opts
+func (r Response) RenderWithOptions(opts RenderOptions) ([]byte, error) {
+	out, err := r.render()
+	if err != nil {
+		return nil, err
+	}
+	limit := opts.MaxSize
+	if limit <= 0 {
+		limit = MaxDocumentSize
+	}
+	if len(out) <= limit {
+		return out, nil
+	}
+
+	sizes := verbSizes(r.Response)
+	if opts.Continue != nil {
+		// the verbs kept render at least the bytes of their sum
+		kept := 0
+		for _, size := range sizes {
+			kept += size
+		}
+		for n := len(r.Response) - 1; n >= 1; n-- {
+			kept -= sizes[n]
+			if kept > limit {
+				continue
+			}
+			short := Response{Response: append(r.Response[:n:n],
+				Redirect{Url: opts.Continue(n)})}
+			if out, err := short.render(); err == nil && len(out) <= limit {
+				return out, nil
+			}
+		}
+	}
+
+	e := &ErrDocumentTooLarge{Size: len(out), Limit: limit, Path: "Response"}
+	for i, size := range sizes {
+		if size > e.VerbSize {
+			e.Path = verbPath("Response", reflect.ValueOf(r.Response[i]), i)
+			e.VerbSize = size
+		}
+	}
+	return nil, e
+}
+
+// verbSizes returns the size of each verb rendered on its own
+func verbSizes(verbs []interface{}) []int {
+	sizes := make([]int, len(verbs))
+	for i, v := range verbs {
+		out, _ := xml.Marshal(v)
+		sizes[i] = len(out)
+	}
+	return sizes
+}
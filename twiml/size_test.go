@@ -0,0 +1,64 @@
+package twiml
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestDocumentSize(t *testing.T) {
+	// a gather of 46 bytes and the options of a paginated menu, 100 bytes each
+	options := func(n int) []interface{} {
+		verbs := []interface{}{Gather{Action: "/menu", NumDigits: 1}}
+		for i := 0; i < n; i++ {
+			verbs = append(verbs, Say{Text: strings.Repeat("x", 89)})
+		}
+		return verbs
+	}
+	next := func(n int) string { return "/menu?from=" + strconv.Itoa(n) }
+
+	var tests = []struct {
+		Verbs    []interface{}
+		Opts     RenderOptions
+		Size     int    // of the document, 0 if too large
+		Path     string // of the largest verb if too large
+		Redirect string // the continuation, if any
+	}{
+		{options(3), RenderOptions{}, 406, "", ""},
+		{options(3), RenderOptions{MaxSize: 406}, 406, "", ""},
+		{options(3), RenderOptions{MaxSize: 405}, 0, "Response>Say[1]", ""},
+		{append(options(3), Say{Text: strings.Repeat("y", 200)}), RenderOptions{MaxSize: 500},
+			0, "Response>Say[4]", ""},
+		{options(3), RenderOptions{MaxSize: 405, Continue: next}, 339, "", "/menu?from=3"},
+		{options(10), RenderOptions{MaxSize: 700, Continue: next}, 639, "", "/menu?from=6"},
+		{[]interface{}{Say{Text: strings.Repeat("x", 500)}, Hangup{}},
+			RenderOptions{MaxSize: 500, Continue: next}, 0, "Response>Say[0]", ""},
+		{options(700), RenderOptions{}, 0, "Response>Say[1]", ""},
+	}
+
+	for idx, test := range tests {
+		out, err := Response{Response: test.Verbs}.RenderWithOptions(test.Opts)
+		if test.Size == 0 {
+			var e *ErrDocumentTooLarge
+			if !errors.As(err, &e) || e.Path != test.Path || e.Size <= e.Limit {
+				t.Errorf("Test %v failed; expected too large at %v, got %v", idx, test.Path, err)
+			}
+			continue
+		}
+		if err != nil || len(out) != test.Size {
+			t.Errorf("Test %v failed; expected %v bytes, got %v %v", idx, test.Size, len(out), err)
+			continue
+		}
+		redirect := strings.HasSuffix(string(out),
+			"<Redirect>"+test.Redirect+"</Redirect></Response>")
+		if redirect != (test.Redirect != "") {
+			t.Errorf("Test %v failed; expected redirect %q, got %s", idx, test.Redirect, out)
+		}
+	}
+
+	// Render applies the limit of twilio
+	if _, err := (Response{Response: options(700)}).Render(); err == nil {
+		t.Errorf("expected a document over %v bytes to fail", MaxDocumentSize)
+	}
+}
@@ -0,0 +1,81 @@
+package twiml
+
+import (
+	"reflect"
+	"strings"
+	"text/template"
+)
+
+// RenderWithData renders a response holding text/template placeholders, such
+// as {{.AgentName}}, in the string attributes and text of its verbs. The
+// placeholders are replaced with data before the response is marshaled, so
+// the values are escaped like any other text. A placeholder data doesn't
+// have, or that doesn't parse, is reported as a *RenderError at its verb.
+// The response isn't modified, a template can be rendered concurrently.
+func (r Response) RenderWithData(data interface{}) ([]byte, error) {
+	c := r.Clone()
+	if err := substitute("Response", c.Response, data); err != nil {
+		return nil, err
+	}
+	return c.Render()
+}
+
+// substitute executes the placeholders of the verbs and their nested verbs
+// in place
+func substitute(path string, verbs []interface{}, data interface{}) error {
+	for i, verb := range verbs {
+		val := reflect.ValueOf(verb)
+		if !val.IsValid() || (val.Kind() == reflect.Ptr && val.IsNil()) {
+			continue // reported by Render
+		}
+		vpath := verbPath(path, val, i)
+
+		// a pointer verb is a copy already, a value is copied to be set
+		v := val
+		if v.Kind() == reflect.Ptr {
+			v = v.Elem()
+		} else {
+			v = reflect.New(val.Type()).Elem()
+			v.Set(val)
+		}
+		if v.Kind() != reflect.Struct {
+			continue
+		}
+
+		for f := 0; f < v.NumField(); f++ {
+			fld := v.Field(f)
+			if fld.Kind() != reflect.String || !fld.CanSet() ||
+				!strings.Contains(fld.String(), "{{") {
+				continue
+			}
+			out, err := execute(v.Type().Field(f).Name, fld.String(), data)
+			if err != nil {
+				return &RenderError{Path: vpath, Err: err}
+			}
+			fld.SetString(out)
+		}
+
+		if nested, ok := nestedVerbs(v); ok {
+			if err := substitute(vpath, nested, data); err != nil {
+				return err
+			}
+		}
+		if val.Kind() != reflect.Ptr {
+			verbs[i] = v.Interface()
+		}
+	}
+	return nil
+}
+
+// execute executes the template text with data
+func execute(name, text string, data interface{}) (string, error) {
+	tmpl, err := template.New(name).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", err
+	}
+	var out strings.Builder
+	if err := tmpl.Execute(&out, data); err != nil {
+		return "", err
+	}
+	return out.String(), nil
+}
@@ -0,0 +1,67 @@
+package twiml
+
+import (
+	"encoding/xml"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRenderWithData(t *testing.T) {
+	tmpl := Response{Response: []interface{}{
+		Say{Text: "Connecting you to {{.AgentName}}"},
+		&Gather{Action: "/menu?agent={{.AgentName | urlquery}}&team={{.Team}}",
+			NumDigits: 1, Nested: []interface{}{Say{Text: "Press 1 for {{.Team}}"}}},
+		Dial{Action: "/done", Number: "{{.Number}}"},
+		Pause{Length: Seconds(1)},
+	}}
+	orig := tmpl.Clone()
+
+	data := map[string]string{
+		"AgentName": `Tom & "Jerry" <tj@example.com>`,
+		"Team":      "sales",
+		"Number":    "+15005550006",
+	}
+	out, err := tmpl.RenderWithData(data)
+	expect := xml.Header + `<Response>` +
+		`<Say>Connecting you to Tom &amp; &#34;Jerry&#34; &lt;tj@example.com&gt;</Say>` +
+		`<Gather action="/menu?agent=Tom+%26+%22Jerry%22+%3Ctj%40example.com%3E&amp;team=sales" numDigits="1">` +
+		`<Say>Press 1 for sales</Say></Gather>` +
+		`<Dial action="/done">+15005550006</Dial>` +
+		`<Pause length="1"></Pause></Response>`
+	if err != nil || string(out) != expect {
+		t.Errorf("expected\n%v\ngot\n%s (%v)", expect, out, err)
+	}
+	if !reflect.DeepEqual(&tmpl, orig) {
+		t.Errorf("expected the template unchanged, got %#v", tmpl)
+	}
+
+	type agent struct{ AgentName string }
+	out, err = Response{Response: []interface{}{Say{Text: "Hi {{.AgentName}}"}}}.
+		RenderWithData(agent{"Ann"})
+	if err != nil || !strings.Contains(string(out), "<Say>Hi Ann</Say>") {
+		t.Errorf("expected struct data, got %s (%v)", out, err)
+	}
+}
+
+func TestRenderWithDataErrors(t *testing.T) {
+	var tests = []struct {
+		Resp Response
+		Path string
+	}{
+		{Response{Response: []interface{}{Say{Text: "{{.Missing}}"}}}, "Response>Say[0]"},
+		{Response{Response: []interface{}{Pause{}, Gather{Nested: []interface{}{
+			Play{}, &Say{Text: "{{.Team"}}}}}, "Response>Gather[1]>Say[1]"},
+		{Response{Response: []interface{}{Record{Action: "/r?x={{.Missing}}"}}},
+			"Response>Record[0]"},
+	}
+
+	for idx, test := range tests {
+		_, err := test.Resp.RenderWithData(map[string]string{"Team": "sales"})
+		var rerr *RenderError
+		if !errors.As(err, &rerr) || rerr.Path != test.Path {
+			t.Errorf("Test %v failed; expected error at %v, got %v", idx, test.Path, err)
+		}
+	}
+}
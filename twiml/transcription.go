@@ -0,0 +1,107 @@
+package twiml
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+// Confidence is how sure the transcriber is of a transcription, between 0
+// and 1
+type Confidence float64
+
+// NoConfidence is the Confidence of a transcription twilio sent none for
+const NoConfidence Confidence = -1
+
+// Known reports if a confidence was sent
+func (c Confidence) Known() bool {
+	return c >= 0
+}
+
+// TranscriptionResult holds the parameters twilio sends to the
+// transcribeCallback of the Record verb. A failed transcription has no
+// text, a completed one may have none for a silent recording.
+type TranscriptionResult struct {
+	AccountSid          string
+	CallSid             string
+	TranscriptionSid    string
+	TranscriptionStatus TranscriptionStatus
+	TranscriptionText   string
+	TranscriptionUrl    string
+	Confidence          Confidence
+	// RecordingSid and RecordingUrl are the transcribed recording
+	RecordingSid string
+	RecordingUrl string
+	// Metadata is the query of the callback url, see AttachCallbackMetadata
+	Metadata map[string]string
+}
+
+// Completed reports if the transcription succeeded
+func (t TranscriptionResult) Completed() bool {
+	return t.TranscriptionStatus == TwiTranscriptionCompleted
+}
+
+// ParseTranscriptionCallback parses a transcription callback request. Check
+// its signature with ValidateSignature first, or serve it with
+// TranscriptionHandler.
+func ParseTranscriptionCallback(r *http.Request) (TranscriptionResult, error) {
+	if err := r.ParseForm(); err != nil {
+		return TranscriptionResult{}, err
+	}
+
+	t := TranscriptionResult{
+		AccountSid:          r.Form.Get(TwiAccountSid),
+		CallSid:             r.Form.Get(TwiCallSid),
+		TranscriptionSid:    r.Form.Get(TwiTranscriptionSid),
+		TranscriptionStatus: TranscriptionStatus(r.Form.Get(TwiTranscriptionStatus)),
+		TranscriptionText:   r.Form.Get(TwiTranscriptionText),
+		TranscriptionUrl:    r.Form.Get(TwiTranscriptionUrl),
+		Confidence:          NoConfidence,
+		RecordingSid:        r.Form.Get(TwiRecordingSid),
+		RecordingUrl:        r.Form.Get(TwiRecordingUrl),
+		Metadata:            ExtractCallbackMetadata(r),
+	}
+	if t.TranscriptionSid == "" {
+		return t, fmt.Errorf("missing parameter: '%s'", TwiTranscriptionSid)
+	}
+	if t.RecordingSid == "" {
+		return t, fmt.Errorf("missing parameter: '%s'", TwiRecordingSid)
+	}
+	switch t.TranscriptionStatus {
+	case TwiTranscriptionCompleted, TwiTranscriptionFailed:
+	default:
+		return t, fmt.Errorf("non valid %s: '%s'", TwiTranscriptionStatus,
+			t.TranscriptionStatus)
+	}
+
+	if val := r.Form.Get(TwiConfidence); val != "" {
+		c, err := strconv.ParseFloat(val, 64)
+		if err != nil || c < 0 || c > 1 {
+			return t, fmt.Errorf("non valid %s: '%s'", TwiConfidence, val)
+		}
+		t.Confidence = Confidence(c)
+	}
+	return t, nil
+}
+
+// TranscriptionHandler serves the transcribeCallback at url, passing every
+// result, failed ones included, to each of handlers in turn. Requests that
+// aren't signed with authToken are refused with status 403 and ones that
+// can't be parsed with status 400.
+func TranscriptionHandler(url, authToken string, handlers ...func(TranscriptionResult)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := ValidateSignature(r, url, authToken); err != nil {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
+		res, err := ParseTranscriptionCallback(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		for _, h := range handlers {
+			h(res)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
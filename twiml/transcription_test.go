@@ -0,0 +1,81 @@
+package twiml
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestParseTranscriptionCallback(t *testing.T) {
+	var tests = []struct {
+		Form      url.Values
+		Completed bool
+		Text      string
+		Conf      Confidence
+		Err       bool
+	}{
+		{url.Values{TwiTranscriptionSid: {"TR1"}, TwiRecordingSid: {"RE1"},
+			TwiTranscriptionStatus: {"completed"}, TwiTranscriptionText: {"Call me back"},
+			TwiConfidence: {"0.87"}}, true, "Call me back", 0.87, false},
+		{url.Values{TwiTranscriptionSid: {"TR1"}, TwiRecordingSid: {"RE1"},
+			TwiTranscriptionStatus: {"failed"}}, false, "", NoConfidence, false},
+		{url.Values{TwiTranscriptionSid: {"TR1"}, TwiRecordingSid: {"RE1"},
+			TwiTranscriptionStatus: {"completed"}}, true, "", NoConfidence, false},
+		{url.Values{TwiRecordingSid: {"RE1"}, TwiTranscriptionStatus: {"completed"}},
+			false, "", 0, true},
+		{url.Values{TwiTranscriptionSid: {"TR1"}, TwiTranscriptionStatus: {"completed"}},
+			false, "", 0, true},
+		{url.Values{TwiTranscriptionSid: {"TR1"}, TwiRecordingSid: {"RE1"},
+			TwiTranscriptionText: {"Call me back"}}, false, "", 0, true},
+		{url.Values{TwiTranscriptionSid: {"TR1"}, TwiRecordingSid: {"RE1"},
+			TwiTranscriptionStatus: {"completed"}, TwiConfidence: {"1.5"}}, false, "", 0, true},
+	}
+
+	for idx, test := range tests {
+		r := httptest.NewRequest("POST", "/transcribed", strings.NewReader(test.Form.Encode()))
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		res, err := ParseTranscriptionCallback(r)
+		if test.Err {
+			if err == nil {
+				t.Errorf("Test %v failed; expected an error", idx)
+			}
+			continue
+		}
+		if err != nil || res.Completed() != test.Completed || res.TranscriptionText != test.Text ||
+			res.Confidence != test.Conf || res.Confidence.Known() != (test.Conf >= 0) ||
+			res.RecordingSid != "RE1" {
+			t.Errorf("Test %v failed; unexpected result %#v (%v)", idx, res, err)
+		}
+	}
+}
+
+func TestTranscriptionHandler(t *testing.T) {
+	var got []TranscriptionResult
+	handler := TranscriptionHandler("https://example.com/debugger", "token",
+		func(res TranscriptionResult) { got = append(got, res) })
+
+	form := url.Values{TwiTranscriptionSid: {"TR1"}, TwiRecordingSid: {"RE1"},
+		TwiTranscriptionStatus: {"failed"}}
+	var tests = []struct {
+		Form  url.Values
+		Token string
+		Code  int
+	}{
+		{form, "token", http.StatusNoContent},
+		{form, "wrong", http.StatusForbidden},
+		{url.Values{TwiTranscriptionSid: {"TR1"}}, "token", http.StatusBadRequest},
+	}
+
+	for idx, test := range tests {
+		w := httptest.NewRecorder()
+		handler(w, debuggerRequest(test.Form, test.Token))
+		if w.Code != test.Code {
+			t.Errorf("Test %v failed; expected %v, got %v", idx, test.Code, w.Code)
+		}
+	}
+	if len(got) != 1 || got[0].TranscriptionStatus != TwiTranscriptionFailed {
+		t.Errorf("expected the failed transcription passed on, got %v", got)
+	}
+}
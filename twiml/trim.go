@@ -0,0 +1,31 @@
+package twiml
+
+import "fmt"
+
+// TrimPolicy is whether the silence at the start and end of a recording is
+// trimmed. The same values are used by the Record and Dial verbs and the
+// Trim parameter of the REST API. The zero value leaves it to twilio, which
+// trims silence.
+type TrimPolicy string
+
+const (
+	TwiTrimSilence TrimPolicy = "trim-silence"
+	TwiDoNotTrim   TrimPolicy = "do-not-trim"
+)
+
+// Validate returns an error if p isn't empty or one of the trim policies
+func (p TrimPolicy) Validate() error {
+	switch p {
+	case "", TwiTrimSilence, TwiDoNotTrim:
+		return nil
+	}
+	return fmt.Errorf("non valid trim: '%s'", string(p))
+}
+
+func (r Record) validate() error {
+	return r.Trim.Validate()
+}
+
+func (d Dial) validate() error {
+	return d.Trim.Validate()
+}
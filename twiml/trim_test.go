@@ -0,0 +1,43 @@
+package twiml
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestTrimPolicy(t *testing.T) {
+	var tests = []struct {
+		Verb   interface{}
+		Expect string
+	}{
+		{Record{Trim: TwiTrimSilence}, `<Record trim="trim-silence"></Record>`},
+		{Record{Trim: TwiDoNotTrim}, `<Record trim="do-not-trim"></Record>`},
+		{Record{}, `<Record></Record>`},
+		{Dial{Record: true, Trim: TwiDoNotTrim, Number: "+15005550006"},
+			`<Dial record="true" trim="do-not-trim">+15005550006</Dial>`},
+		{&Dial{Trim: TwiTrimSilence}, `<Dial trim="trim-silence"></Dial>`},
+		{Record{Trim: "trim"}, ""},
+		{&Dial{Trim: "do_not_trim"}, ""},
+	}
+
+	for idx, test := range tests {
+		out, err := Response{Response: []interface{}{test.Verb}}.Render()
+		if test.Expect == "" {
+			var rerr *RenderError
+			if !errors.As(err, &rerr) || !strings.Contains(err.Error(), "non valid trim") {
+				t.Errorf("Test %v failed; expected a trim error, got %v", idx, err)
+			}
+			continue
+		}
+		if err != nil || !strings.Contains(string(out), test.Expect) {
+			t.Errorf("Test %v failed; expected %v, got %s (%v)", idx, test.Expect, out, err)
+		}
+	}
+
+	r := Response{}
+	r.Dial(Dial{Trim: TwiDoNotTrim}, Number{Number: "+15005550006"})
+	if r.Response[0].(Dial).Trim != TwiDoNotTrim {
+		t.Errorf("expected Response.Dial to keep Trim")
+	}
+}
@@ -17,14 +17,14 @@ func NewResponse() *Response {
 }
 
 // Action appends action verb structs to response. Valid verbs: Enqueue, Say,
-// Leave, Message, Pause, Play, Record, Redirect, Reject, Hangup
+// Leave, Message, Pause, Play, Record, Redirect, Reject, Hangup, Raw
 func (r *Response) Action(structs ...interface{}) error {
 	for _, s := range structs {
 		switch s := s.(type) {
 		default:
 			return fmt.Errorf("non valid verb: '%T'", s)
 		case Enqueue, Hangup, Leave, Message, Pause, Play, Record,
-			Redirect, Reject, Say:
+			Redirect, Reject, Say, Raw:
 			r.Response = append(r.Response, s)
 		}
 	}
@@ -32,7 +32,7 @@ func (r *Response) Action(structs ...interface{}) error {
 }
 
 // Dial appends dial action verb and noun structs to respose
-// Valid verb: Dial. Valid nouns: Client, Conference, Number, Queue, Sip
+// Valid verb: Dial. Valid nouns: Client, Conference, Number, Queue, Sip, Raw
 func (r *Response) Dial(structs ...interface{}) error {
 	d := Dial{}
 
@@ -48,8 +48,10 @@ func (r *Response) Dial(structs ...interface{}) error {
 			d.Action = s.Action
 			d.Method = s.Method
 			d.Record = s.Record
+			d.Trim = s.Trim
 			d.Number = s.Number
-		case Client, Conference, Number, Queue, Sip:
+			d.ExtraAttrs = s.ExtraAttrs
+		case Client, Conference, Number, Queue, Sip, Raw:
 			d.Nested = append(d.Nested, s)
 		}
 	}
@@ -58,7 +60,7 @@ func (r *Response) Dial(structs ...interface{}) error {
 }
 
 // Gather collects digits a caller enter by pressing the keypad
-// Valid verb: Gather. Valid nested verbs: Say, Pause, Play
+// Valid verb: Gather. Valid nested verbs: Say, Pause, Play, Raw
 func (r *Response) Gather(structs ...interface{}) error {
 	g := Gather{}
 
@@ -68,11 +70,13 @@ func (r *Response) Gather(structs ...interface{}) error {
 			return fmt.Errorf("non valid verb: '%T'", s)
 		case Gather:
 			g.FinishOnKey = s.FinishOnKey
+			g.NoFinishOnKey = s.NoFinishOnKey
 			g.NumDigits = s.NumDigits
 			g.Timeout = s.Timeout
 			g.Action = s.Action
 			g.Method = s.Method
-		case Say, Pause, Play: // Valid nested verbs
+			g.ExtraAttrs = s.ExtraAttrs
+		case Say, Pause, Play, Raw: // Valid nested verbs
 			g.Nested = append(g.Nested, s)
 		}
 
@@ -83,12 +87,16 @@ func (r *Response) Gather(structs ...interface{}) error {
 
 // Send sends xml encoded response to writer
 func (r Response) Send(w io.Writer) (err error) {
+	if err := validateNested("Response", r.Response); err != nil {
+		return err
+	}
+
 	enc := xml.NewEncoder(w)
 	enc.Indent("  ", "   ")
 
 	fmt.Fprintf(w, "%s", xml.Header)
 	if err := enc.Encode(r); err != nil {
-		return err
+		return locateError("Response", r.Response, err)
 	}
 	fmt.Fprintf(w, "\n")
 	return err
@@ -99,6 +107,7 @@ func (r Response) String() string {
 	output, err := xml.MarshalIndent(r, "  ", "    ")
 
 	if err != nil {
+		err = locateError("Response", r.Response, err)
 		fmt.Printf("Error: %v\n", err)
 	}
 	return xml.Header + string(output) + "\n"
@@ -3,115 +3,143 @@ package twiml
 import "encoding/xml"
 
 type Client struct {
-	XMLName xml.Name `xml:"Client"`
-	Method  string   `xml:"method,attr,omitempty"`
-	Url     string   `xml:"Url,omitempty"`
-	Name    string   `xml:",chardata"`
+	XMLName              xml.Name   `xml:"Client"`
+	Method               string     `xml:"method,attr,omitempty"`
+	Url                  string     `xml:"Url,omitempty"`
+	StatusCallback       string     `xml:"statusCallback,attr,omitempty"`
+	StatusCallbackEvent  Events     `xml:"statusCallbackEvent,attr,omitempty"`
+	StatusCallbackMethod string     `xml:"statusCallbackMethod,attr,omitempty"`
+	Name                 string     `xml:",chardata"`
+	ExtraAttrs           []xml.Attr `xml:",any,attr"` // see WithAttr
 }
 
 type Conference struct {
-	XMLName                xml.Name `xml:"Conference"`
-	Muted                  bool     `xml:"muted,attr,omitempty"`
-	Beep                   string   `xml:"beep,attr,omitempty"`
-	StartConferenceOnEnter bool     `xml:"startConferenceOnEnter,attr,omitempty"`
-	EndConferenceOnExit    bool     `xml:"endConferenceOnExit,attr,omitempty"`
-	WaitUrl                string   `xml:"waitUrl,attr,omitempty"`
-	WaitMethod             string   `xml:"waitMethod,attr,omitempty"`
-	MaxParticipants        int      `xml:"maxParticipants,attr,omitempty"`
-	Name                   string   `xml:",chardata"`
+	XMLName                xml.Name         `xml:"Conference"`
+	Muted                  bool             `xml:"muted,attr,omitempty"`
+	Beep                   string           `xml:"beep,attr,omitempty"`
+	StartConferenceOnEnter bool             `xml:"startConferenceOnEnter,attr,omitempty"`
+	EndConferenceOnExit    bool             `xml:"endConferenceOnExit,attr,omitempty"`
+	WaitUrl                string           `xml:"waitUrl,attr,omitempty"`
+	WaitMethod             string           `xml:"waitMethod,attr,omitempty"`
+	MaxParticipants        int              `xml:"maxParticipants,attr,omitempty"`
+	StatusCallback         string           `xml:"statusCallback,attr,omitempty"`
+	StatusCallbackEvent    Events           `xml:"statusCallbackEvent,attr,omitempty"`
+	StatusCallbackMethod   string           `xml:"statusCallbackMethod,attr,omitempty"`
+	JitterBufferSize       JitterBufferSize `xml:"jitterBufferSize,attr,omitempty"`
+	ParticipantLabel       string           `xml:"participantLabel,attr,omitempty"`
+	Name                   string           `xml:",chardata"`
+	ExtraAttrs             []xml.Attr       `xml:",any,attr"` // see WithAttr
 }
 
 type Dial struct {
-	XMLName                       xml.Name `xml:"Dial"`
-	Action                        string   `xml:"action,attr,omitempty"`
-	Method                        string   `xml:"method,attr,omitempty"`
-	Timeout                       int      `xml:"timeout,attr,omitempty"`
-	HangupOnStar                  bool     `xml:"hangupOnStar,attr,omitempty"`
-	TimeLimit                     int      `xml:"timeLimit,attr,omitempty"`
-	CallerId                      string   `xml:"callerId,attr,omitempty"`
-	Record                        bool     `xml:"record,attr,omitempty"`
-	RecordingStatusCallback       string   `xml:"recordingStatusCallback,attr,omitempty"`
-	RecordingStatusCallbackMethod string   `xml:"recordingStatusCallbackMethod,attr,omitempty"`
-	Number                        string   `xml:",chardata"`
+	XMLName                       xml.Name   `xml:"Dial"`
+	Action                        string     `xml:"action,attr,omitempty"`
+	Method                        string     `xml:"method,attr,omitempty"`
+	Timeout                       int        `xml:"timeout,attr,omitempty"`
+	HangupOnStar                  bool       `xml:"hangupOnStar,attr,omitempty"`
+	TimeLimit                     int        `xml:"timeLimit,attr,omitempty"`
+	CallerId                      string     `xml:"callerId,attr,omitempty"`
+	Record                        bool       `xml:"record,attr,omitempty"`
+	RecordingStatusCallback       string     `xml:"recordingStatusCallback,attr,omitempty"`
+	RecordingStatusCallbackMethod string     `xml:"recordingStatusCallbackMethod,attr,omitempty"`
+	Trim                          TrimPolicy `xml:"trim,attr,omitempty"`
+	Number                        string     `xml:",chardata"`
 	Nested                        []interface{}
+	ExtraAttrs                    []xml.Attr `xml:",any,attr"` // see WithAttr
 }
 
 type Enqueue struct {
-	XMLName       xml.Name `xml:"Enqueue"`
-	Action        string   `xml:"action,attr,omitempty"`
-	Method        string   `xml:"method,attr,omitempty"`
-	WaitUrl       string   `xml:"waitUrl,attr,omitempty"`
-	WaitUrlMethod string   `xml:"waitUrlMethod,attr,omitempty"`
-	Name          string   `xml:",chardata"`
+	XMLName       xml.Name   `xml:"Enqueue"`
+	Action        string     `xml:"action,attr,omitempty"`
+	Method        string     `xml:"method,attr,omitempty"`
+	WaitUrl       string     `xml:"waitUrl,attr,omitempty"`
+	WaitUrlMethod string     `xml:"waitUrlMethod,attr,omitempty"`
+	Name          string     `xml:",chardata"`
+	ExtraAttrs    []xml.Attr `xml:",any,attr"` // see WithAttr
 }
 
 type Hangup struct {
-	XMLName xml.Name `xml:"Hangup"`
+	XMLName    xml.Name   `xml:"Hangup"`
+	ExtraAttrs []xml.Attr `xml:",any,attr"` // see WithAttr
 }
 
 type Leave struct {
-	XMLName xml.Name `xml:"Leave"`
+	XMLName    xml.Name   `xml:"Leave"`
+	ExtraAttrs []xml.Attr `xml:",any,attr"` // see WithAttr
 }
 
 type Message struct {
-	XMLName        xml.Name `xml:"Message"`
-	To             string   `xml:"to,attr,omitempty"`
-	From           string   `xml:"from,attr,omitempty"`
-	Action         string   `xml:"action,attr,omitempty"`
-	Method         string   `xml:"method,attr,omitempty"`
-	StatusCallback string   `xml:"statusCallback,attr,omitempty"`
-	Body           string   `xml:"Body,omitempty"`
-	Media          string   `xml:"Media,omitempty"`
+	XMLName        xml.Name   `xml:"Message"`
+	To             string     `xml:"to,attr,omitempty"`
+	From           string     `xml:"from,attr,omitempty"`
+	Action         string     `xml:"action,attr,omitempty"`
+	Method         string     `xml:"method,attr,omitempty"`
+	StatusCallback string     `xml:"statusCallback,attr,omitempty"`
+	Body           string     `xml:"Body,omitempty"`
+	Media          string     `xml:"Media,omitempty"`
+	ExtraAttrs     []xml.Attr `xml:",any,attr"` // see WithAttr
 }
 
 type Number struct {
-	XMLName    xml.Name `xml:"Number"`
-	SendDigits string   `xml:"sendDigits,attr,omitempty"`
-	Url        string   `xml:"url,attr,omitempty"`
-	Method     string   `xml:"method,attr,omitempty"`
-	Number     string   `xml:",chardata"`
+	XMLName              xml.Name   `xml:"Number"`
+	SendDigits           string     `xml:"sendDigits,attr,omitempty"`
+	Url                  string     `xml:"url,attr,omitempty"`
+	Method               string     `xml:"method,attr,omitempty"`
+	Byoc                 string     `xml:"byoc,attr,omitempty"`
+	StatusCallback       string     `xml:"statusCallback,attr,omitempty"`
+	StatusCallbackEvent  Events     `xml:"statusCallbackEvent,attr,omitempty"`
+	StatusCallbackMethod string     `xml:"statusCallbackMethod,attr,omitempty"`
+	Number               string     `xml:",chardata"`
+	ExtraAttrs           []xml.Attr `xml:",any,attr"` // see WithAttr
 }
 
 type Pause struct {
-	XMLName xml.Name `xml:"Pause"`
-	Length  int      `xml:"length,attr,omitempty"`
+	XMLName    xml.Name   `xml:"Pause"`
+	Length     *int       `xml:"length,attr,omitempty"` // see Seconds
+	ExtraAttrs []xml.Attr `xml:",any,attr"`             // see WithAttr
 }
 
 type Play struct {
-	XMLName xml.Name `xml:"Play"`
-	Loop    int      `xml:"loop,attr,omitempty"`
-	Digits  int      `xml:"digits,attr,omitempty"`
-	Url     string   `xml:",chardata"`
+	XMLName    xml.Name   `xml:"Play"`
+	Loop       *int       `xml:"loop,attr,omitempty"` // see Loop and LoopForever
+	Digits     int        `xml:"digits,attr,omitempty"`
+	Url        string     `xml:",chardata"`
+	ExtraAttrs []xml.Attr `xml:",any,attr"` // see WithAttr
 }
 
 type Queue struct {
-	XMLName xml.Name `xml:"Queue"`
-	Url     string   `xml:"url,attr,omitempty"`
-	Method  string   `xml:"method,attr,omitempty"`
-	Name    string   `xml:",chardata"`
+	XMLName    xml.Name   `xml:"Queue"`
+	Url        string     `xml:"url,attr,omitempty"`
+	Method     string     `xml:"method,attr,omitempty"`
+	Name       string     `xml:",chardata"`
+	ExtraAttrs []xml.Attr `xml:",any,attr"` // see WithAttr
 }
 
 type Record struct {
-	XMLName            xml.Name `xml:"Record"`
-	Action             string   `xml:"action,attr,omitempty"`
-	Method             string   `xml:"method,attr,omitempty"`
-	Timeout            int      `xml:"timeout,attr,omitempty"`
-	FinishOnKey        string   `xml:"finishOnKey,attr,omitempty"`
-	MaxLength          int      `xml:"maxLength,attr,omitempty"`
-	Transcribe         bool     `xml:"transcribe,attr,omitempty"`
-	TranscribeCallback string   `xml:"transcribeCallback,attr,omitempty"`
-	PlayBeep           bool     `xml:"playBeep,attr,omitempty"`
+	XMLName            xml.Name   `xml:"Record"`
+	Action             string     `xml:"action,attr,omitempty"`
+	Method             string     `xml:"method,attr,omitempty"`
+	Timeout            *int       `xml:"timeout,attr,omitempty"` // see Seconds
+	FinishOnKey        string     `xml:"finishOnKey,attr,omitempty"`
+	MaxLength          int        `xml:"maxLength,attr,omitempty"`
+	Transcribe         bool       `xml:"transcribe,attr,omitempty"`
+	TranscribeCallback string     `xml:"transcribeCallback,attr,omitempty"`
+	PlayBeep           bool       `xml:"playBeep,attr,omitempty"`
+	Trim               TrimPolicy `xml:"trim,attr,omitempty"`
+	ExtraAttrs         []xml.Attr `xml:",any,attr"` // see WithAttr
 }
 
 type Redirect struct {
-	XMLName xml.Name `xml:"Redirect"`
-	Method  string   `xml:"method,attr,omitempty"`
-	Url     string   `xml:",chardata"`
+	XMLName    xml.Name   `xml:"Redirect"`
+	Method     string     `xml:"method,attr,omitempty"`
+	Url        string     `xml:",chardata"`
+	ExtraAttrs []xml.Attr `xml:",any,attr"` // see WithAttr
 }
 
 type Reject struct {
-	XMLName xml.Name `xml:"Reject"`
-	Reason  string   `xml:"reason,attr,omitempty"`
+	XMLName    xml.Name   `xml:"Reject"`
+	Reason     string     `xml:"reason,attr,omitempty"`
+	ExtraAttrs []xml.Attr `xml:",any,attr"` // see WithAttr
 }
 
 type Response struct {
@@ -120,20 +148,26 @@ type Response struct {
 }
 
 type Say struct {
-	XMLName  xml.Name `xml:"Say"`
-	Voice    string   `xml:"voice,attr,omitempty"`
-	Language string   `xml:"language,attr,omitempty"`
-	Loop     int      `xml:"loop,attr,omitempty"`
-	Text     string   `xml:",chardata"`
+	XMLName    xml.Name   `xml:"Say"`
+	Voice      string     `xml:"voice,attr,omitempty"`
+	Language   string     `xml:"language,attr,omitempty"`
+	Loop       *int       `xml:"loop,attr,omitempty"` // see Loop and LoopForever
+	Text       string     `xml:",chardata"`
+	ExtraAttrs []xml.Attr `xml:",any,attr"` // see WithAttr
 }
 
 type Sip struct {
-	XMLName  xml.Name `xml:"Sip"`
-	Username string   `xml:"username,attr,omitempty"`
-	Password string   `xml:"password,attr,omitempty"`
-	Url      string   `xml:"url,attr,omitempty"`
-	Method   string   `xml:"method,attr,omitempty"`
-	Address  string   `xml:",chardata"`
+	XMLName              xml.Name   `xml:"Sip"`
+	Username             string     `xml:"username,attr,omitempty"`
+	Password             string     `xml:"password,attr,omitempty"`
+	Url                  string     `xml:"url,attr,omitempty"`
+	Method               string     `xml:"method,attr,omitempty"`
+	Byoc                 string     `xml:"byoc,attr,omitempty"`
+	StatusCallback       string     `xml:"statusCallback,attr,omitempty"`
+	StatusCallbackEvent  Events     `xml:"statusCallbackEvent,attr,omitempty"`
+	StatusCallbackMethod string     `xml:"statusCallbackMethod,attr,omitempty"`
+	Address              string     `xml:",chardata"`
+	ExtraAttrs           []xml.Attr `xml:",any,attr"` // see WithAttr
 }
 
 type Gather struct {
@@ -143,5 +177,8 @@ type Gather struct {
 	Timeout     int      `xml:"timeout,attr,omitempty"`
 	FinishOnKey string   `xml:"finishOnKey,attr,omitempty"`
 	NumDigits   int      `xml:"numDigits,attr,omitempty"`
-	Nested      []interface{}
+	// NoFinishOnKey renders finishOnKey="", no key submits the digits
+	NoFinishOnKey bool `xml:"-"`
+	Nested        []interface{}
+	ExtraAttrs    []xml.Attr `xml:",any,attr"` // see WithAttr
 }
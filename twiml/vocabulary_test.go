@@ -25,6 +25,14 @@ var testVerbs = []struct {
 		},
 		ExpectXML: "<Dial recordingStatusCallback=\"testing\" recordingStatusCallbackMethod=\"POST\">5</Dial>",
 	},
+	{
+		Value:     Number{Number: "+15005550006", Byoc: "BY0123456789abcdef0123456789abcdef"},
+		ExpectXML: "<Number byoc=\"BY0123456789abcdef0123456789abcdef\">+15005550006</Number>",
+	},
+	{
+		Value:     Sip{Address: "sip:alice@example.com", Byoc: "BY0123456789abcdef0123456789abcdef"},
+		ExpectXML: "<Sip byoc=\"BY0123456789abcdef0123456789abcdef\">sip:alice@example.com</Sip>",
+	},
 }
 
 func TestDialRecordingStatus(t *testing.T) {
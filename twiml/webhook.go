@@ -0,0 +1,341 @@
+package twiml
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// InboundCall holds the parameters twilio sends with the voice request of an
+// incoming call. When a call is forwarded to a twilio number, To and Called
+// are the twilio number while ForwardedFrom is the number the caller dialed,
+// if the carrier passed it on. CallerName is only set when caller ID lookup
+// is enabled on the number.
+type InboundCall struct {
+	CallSid           string
+	AccountSid        string
+	From              string
+	To                string
+	CallStatus        string
+	ApiVersion        string
+	Direction         string
+	ForwardedFrom     string
+	CallerName        string
+	FromCity          string
+	FromState         string
+	FromZip           string
+	FromCountry       string
+	ToCity            string
+	ToState           string
+	ToZip             string
+	ToCountry         string
+	Called            string
+	CalledCity        string
+	CalledState       string
+	CalledZip         string
+	CalledCountry     string
+	Caller            string
+	CallerCity        string
+	CallerState       string
+	CallerZip         string
+	CallerCountry     string
+	StirVerstat       StirVerstat
+	StirPassportToken string
+}
+
+// Forwarded reports if the call was forwarded to the twilio number
+func (c InboundCall) Forwarded() bool {
+	return c.ForwardedFrom != ""
+}
+
+// ParseInboundCall parses the voice request of an incoming call
+func ParseInboundCall(r *http.Request) (InboundCall, error) {
+	if err := r.ParseForm(); err != nil {
+		return InboundCall{}, err
+	}
+
+	c := InboundCall{
+		CallSid:           r.Form.Get(TwiCallSid),
+		AccountSid:        r.Form.Get(TwiAccountSid),
+		From:              r.Form.Get(TwiFrom),
+		To:                r.Form.Get(TwiTo),
+		CallStatus:        r.Form.Get(TwiCallStatus),
+		ApiVersion:        r.Form.Get(TwiApiVersion),
+		Direction:         r.Form.Get(TwiDirection),
+		ForwardedFrom:     r.Form.Get(TwiForwardedFrom),
+		CallerName:        r.Form.Get(TwiCallerName),
+		FromCity:          r.Form.Get(TwiFromCity),
+		FromState:         r.Form.Get(TwiFromState),
+		FromZip:           r.Form.Get(TwiFromZip),
+		FromCountry:       r.Form.Get(TwiFromCountry),
+		ToCity:            r.Form.Get(TwiToCity),
+		ToState:           r.Form.Get(TwiToState),
+		ToZip:             r.Form.Get(TwiToZip),
+		ToCountry:         r.Form.Get(TwiToCountry),
+		Called:            r.Form.Get(TwiCalled),
+		CalledCity:        r.Form.Get(TwiCalledCity),
+		CalledState:       r.Form.Get(TwiCalledState),
+		CalledZip:         r.Form.Get(TwiCalledZip),
+		CalledCountry:     r.Form.Get(TwiCalledCountry),
+		Caller:            r.Form.Get(TwiCaller),
+		CallerCity:        r.Form.Get(TwiCallerCity),
+		CallerState:       r.Form.Get(TwiCallerState),
+		CallerZip:         r.Form.Get(TwiCallerZip),
+		CallerCountry:     r.Form.Get(TwiCallerCountry),
+		StirVerstat:       StirVerstat(r.Form.Get(TwiStirVerstat)),
+		StirPassportToken: r.Form.Get(TwiStirPassportToken),
+	}
+	if c.CallSid == "" {
+		return c, fmt.Errorf("missing parameter: '%s'", TwiCallSid)
+	}
+	return c, nil
+}
+
+// Passed reports if the caller's number was verified
+func (v StirVerstat) Passed() bool {
+	return strings.HasPrefix(string(v), "TN-Validation-Passed-")
+}
+
+// Attestation returns the attestation level (A, B or C) of the verification
+// result, or an empty string if the call carried no attestation
+func (v StirVerstat) Attestation() string {
+	switch v {
+	case TwiStirPassedA, TwiStirFailedA:
+		return "A"
+	case TwiStirPassedB, TwiStirFailedB:
+		return "B"
+	case TwiStirPassedC, TwiStirFailedC:
+		return "C"
+	}
+	return ""
+}
+
+// RecordingStatusCallback holds the parameters twilio sends to the recording
+// status callback of a call or conference recording. ConferenceSid is only set
+// for conference recordings, CallSid only for call recordings. Dual channel
+// recordings have RecordingChannels 2 with each party on its own channel.
+type RecordingStatusCallback struct {
+	AccountSid         string
+	CallSid            string
+	ConferenceSid      string
+	RecordingSid       string
+	RecordingUrl       string
+	RecordingStatus    string
+	RecordingDuration  int
+	RecordingChannels  int
+	RecordingSource    string
+	RecordingTrack     RecordingTrack
+	RecordingStartTime string
+	ErrorCode          string
+	// Metadata is the query of the callback url, see AttachCallbackMetadata
+	Metadata map[string]string
+}
+
+// DualChannel reports if the recording has a channel per party
+func (cb RecordingStatusCallback) DualChannel() bool {
+	return cb.RecordingChannels == 2
+}
+
+// ParseRecordingStatusCallback parses a recording status callback request
+func ParseRecordingStatusCallback(r *http.Request) (RecordingStatusCallback, error) {
+	if err := r.ParseForm(); err != nil {
+		return RecordingStatusCallback{}, err
+	}
+
+	cb := RecordingStatusCallback{
+		AccountSid:         r.Form.Get(TwiAccountSid),
+		CallSid:            r.Form.Get(TwiCallSid),
+		ConferenceSid:      r.Form.Get(TwiConferenceSid),
+		RecordingSid:       r.Form.Get(TwiRecordingSid),
+		RecordingUrl:       r.Form.Get(TwiRecordingUrl),
+		RecordingStatus:    r.Form.Get(TwiRecordingStatus),
+		RecordingSource:    r.Form.Get(TwiRecordingSource),
+		RecordingTrack:     RecordingTrack(r.Form.Get(TwiRecordingTrack)),
+		RecordingStartTime: r.Form.Get(TwiRecordingStartTime),
+		ErrorCode:          r.Form.Get(TwiErrorCode),
+		Metadata:           ExtractCallbackMetadata(r),
+	}
+	if cb.RecordingSid == "" {
+		return cb, fmt.Errorf("missing parameter: '%s'", TwiRecordingSid)
+	}
+
+	var err error
+	if cb.RecordingDuration, err = formInt(r, TwiRecordingDuration); err != nil {
+		return cb, err
+	}
+	if cb.RecordingChannels, err = formInt(r, TwiRecordingChannels); err != nil {
+		return cb, err
+	}
+	return cb, nil
+}
+
+// ConferenceEvent holds the parameters twilio sends to the status callback of
+// a conference. The participant fields are only set for participant events,
+// CallSidToCoach only for participants coaching another.
+type ConferenceEvent struct {
+	AccountSid             string
+	ConferenceSid          string
+	FriendlyName           string
+	StatusCallbackEvent    ConferenceEventType
+	SequenceNumber         int
+	Timestamp              string
+	CallSid                string
+	ParticipantLabel       string
+	Muted                  bool
+	Hold                   bool
+	Coaching               bool
+	CallSidToCoach         string
+	StartConferenceOnEnter bool
+	EndConferenceOnExit    bool
+	ReasonConferenceEnded  string
+	Reason                 string
+	// Metadata is the query of the callback url, see AttachCallbackMetadata
+	Metadata map[string]string
+}
+
+// ParseConferenceEvent parses a conference status callback request
+func ParseConferenceEvent(r *http.Request) (ConferenceEvent, error) {
+	if err := r.ParseForm(); err != nil {
+		return ConferenceEvent{}, err
+	}
+
+	ev := ConferenceEvent{
+		AccountSid:             r.Form.Get(TwiAccountSid),
+		ConferenceSid:          r.Form.Get(TwiConferenceSid),
+		FriendlyName:           r.Form.Get(TwiFriendlyName),
+		StatusCallbackEvent:    ConferenceEventType(r.Form.Get(TwiStatusCallbackEvent)),
+		Timestamp:              r.Form.Get(TwiTimestamp),
+		CallSid:                r.Form.Get(TwiCallSid),
+		ParticipantLabel:       r.Form.Get(TwiParticipantLabel),
+		Muted:                  r.Form.Get(TwiMuted) == "true",
+		Hold:                   r.Form.Get(TwiHold) == "true",
+		Coaching:               r.Form.Get(TwiCoaching) == "true",
+		CallSidToCoach:         r.Form.Get(TwiCallSidToCoach),
+		StartConferenceOnEnter: r.Form.Get(TwiStartConferenceOnEnter) == "true",
+		EndConferenceOnExit:    r.Form.Get(TwiEndConferenceOnExit) == "true",
+		ReasonConferenceEnded:  r.Form.Get(TwiReasonConferenceEnded),
+		Reason:                 r.Form.Get(TwiReason),
+		Metadata:               ExtractCallbackMetadata(r),
+	}
+	if ev.ConferenceSid == "" {
+		return ev, fmt.Errorf("missing parameter: '%s'", TwiConferenceSid)
+	}
+
+	var err error
+	if ev.SequenceNumber, err = formInt(r, TwiSequenceNumber); err != nil {
+		return ev, err
+	}
+	return ev, nil
+}
+
+// DialActionResult holds the parameters twilio sends to the action of a
+// Dial verb when the dialed call ends. RecordingUrl and RecordingSid are set
+// when the Dial records, the recording may still be processing.
+type DialActionResult struct {
+	AccountSid       string
+	CallSid          string
+	DialCallSid      string
+	DialCallStatus   string
+	DialCallDuration int
+	DialBridged      bool
+	RecordingUrl     string
+	RecordingSid     string
+	// Metadata is the query of the callback url, see AttachCallbackMetadata
+	Metadata map[string]string
+}
+
+// ParseDialAction parses the request to the action of a Dial verb
+func ParseDialAction(r *http.Request) (DialActionResult, error) {
+	if err := r.ParseForm(); err != nil {
+		return DialActionResult{}, err
+	}
+
+	res := DialActionResult{
+		AccountSid:     r.Form.Get(TwiAccountSid),
+		CallSid:        r.Form.Get(TwiCallSid),
+		DialCallSid:    r.Form.Get(TwiDialCallSid),
+		DialCallStatus: r.Form.Get(TwiDialCallStatus),
+		DialBridged:    r.Form.Get(TwiDialBridged) == "true",
+		RecordingUrl:   r.Form.Get(TwiRecordingUrl),
+		RecordingSid:   r.Form.Get(TwiRecordingSid),
+		Metadata:       ExtractCallbackMetadata(r),
+	}
+	if res.DialCallStatus == "" {
+		return res, fmt.Errorf("missing parameter: '%s'", TwiDialCallStatus)
+	}
+
+	var err error
+	if res.DialCallDuration, err = formInt(r, TwiDialCallDuration); err != nil {
+		return res, err
+	}
+	return res, nil
+}
+
+// formInt returns the integer value of parameter name, 0 if it isn't set
+func formInt(r *http.Request, name string) (int, error) {
+	val := r.Form.Get(name)
+	if val == "" {
+		return 0, nil
+	}
+	i, err := strconv.Atoi(val)
+	if err != nil {
+		return 0, fmt.Errorf("non valid %s: '%s'", name, val)
+	}
+	return i, nil
+}
+
+// InboundMessage holds the parameters twilio sends with the request of an
+// incoming message
+type InboundMessage struct {
+	MessageSid          string
+	AccountSid          string
+	MessagingServiceSid string
+	From                string
+	To                  string
+	Body                string
+	NumSegments         int
+	Media               []InboundMedia
+}
+
+// InboundMedia is a media attachment of an incoming message. Fetching Url
+// requires the account credentials when media authentication is enabled.
+type InboundMedia struct {
+	Url         string
+	ContentType string
+}
+
+// ParseInboundMessage parses the request of an incoming message
+func ParseInboundMessage(r *http.Request) (InboundMessage, error) {
+	if err := r.ParseForm(); err != nil {
+		return InboundMessage{}, err
+	}
+
+	m := InboundMessage{
+		MessageSid:          r.Form.Get(TwiMessageSid),
+		AccountSid:          r.Form.Get(TwiAccountSid),
+		MessagingServiceSid: r.Form.Get(TwiMessagingServiceSid),
+		From:                r.Form.Get(TwiFrom),
+		To:                  r.Form.Get(TwiTo),
+		Body:                r.Form.Get(TwiBody),
+	}
+	if m.MessageSid == "" {
+		return m, fmt.Errorf("missing parameter: '%s'", TwiMessageSid)
+	}
+
+	var err error
+	if m.NumSegments, err = formInt(r, TwiNumSegments); err != nil {
+		return m, err
+	}
+	numMedia, err := formInt(r, TwiNumMedia)
+	if err != nil {
+		return m, err
+	}
+	for i := 0; i < numMedia; i++ {
+		m.Media = append(m.Media, InboundMedia{
+			Url:         r.Form.Get(TwiMediaUrl + strconv.Itoa(i)),
+			ContentType: r.Form.Get(TwiMediaContentType + strconv.Itoa(i)),
+		})
+	}
+	return m, nil
+}
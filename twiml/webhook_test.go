@@ -0,0 +1,287 @@
+package twiml
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// webhookRequest builds a twilio style form encoded POST request
+func webhookRequest(form url.Values) *http.Request {
+	r := httptest.NewRequest("POST", "https://example.com/voice",
+		strings.NewReader(form.Encode()))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return r
+}
+
+func TestParseInboundCallStir(t *testing.T) {
+	var tests = []struct {
+		Verstat     string
+		Passed      bool
+		Attestation string
+	}{
+		{Verstat: "TN-Validation-Passed-A", Passed: true, Attestation: "A"},
+		{Verstat: "TN-Validation-Passed-C", Passed: true, Attestation: "C"},
+		{Verstat: "TN-Validation-Failed-B", Passed: false, Attestation: "B"},
+		{Verstat: "No-TN-Validation", Passed: false, Attestation: ""},
+		{Verstat: "", Passed: false, Attestation: ""},
+	}
+
+	for idx, test := range tests {
+		form := url.Values{
+			"CallSid":           {"CA123"},
+			"From":              {"+15005550006"},
+			"To":                {"+15005550001"},
+			"StirVerstat":       {test.Verstat},
+			"StirPassportToken": {"eyJhbGciOiJFUzI1NiJ9"},
+		}
+		c, err := ParseInboundCall(webhookRequest(form))
+		if err != nil {
+			t.Errorf("Test %v failed: %v", idx, err)
+			continue
+		}
+		if c.StirVerstat != StirVerstat(test.Verstat) ||
+			c.StirPassportToken != "eyJhbGciOiJFUzI1NiJ9" {
+			t.Errorf("Test %v failed; got %#v", idx, c)
+		}
+		if c.StirVerstat.Passed() != test.Passed {
+			t.Errorf("Test %v failed; expected passed %v", idx, test.Passed)
+		}
+		if got := c.StirVerstat.Attestation(); got != test.Attestation {
+			t.Errorf("Test %v failed; expected attestation %#v, got %#v",
+				idx, test.Attestation, got)
+		}
+	}
+}
+
+func TestParseInboundCallMissingSid(t *testing.T) {
+	_, err := ParseInboundCall(webhookRequest(url.Values{"From": {"+1"}}))
+	if err == nil {
+		t.Errorf("expected error for missing CallSid")
+	}
+}
+
+// forwardedCall is the voice request of a call from +14155550100 to
+// +16045550199, which the callee's carrier forwarded to the twilio number
+// +16045550123
+const forwardedCall = "AccountSid=AC123&ApiVersion=2010-04-01" +
+	"&CallSid=CA456&CallStatus=ringing&Direction=inbound" +
+	"&Called=%2B16045550123&CalledCity=VANCOUVER&CalledCountry=CA" +
+	"&CalledState=BC&CalledZip=" +
+	"&Caller=%2B14155550100&CallerCity=SAN+FRANCISCO&CallerCountry=US" +
+	"&CallerName=JANE+DOE&CallerState=CA&CallerZip=94105" +
+	"&ForwardedFrom=%2B16045550199" +
+	"&From=%2B14155550100&FromCity=SAN+FRANCISCO&FromCountry=US" +
+	"&FromState=CA&FromZip=94105" +
+	"&To=%2B16045550123&ToCity=VANCOUVER&ToCountry=CA&ToState=BC&ToZip="
+
+func TestParseInboundCallForwarded(t *testing.T) {
+	form, _ := url.ParseQuery(forwardedCall)
+	c, err := ParseInboundCall(webhookRequest(form))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expect := InboundCall{
+		CallSid:       "CA456",
+		AccountSid:    "AC123",
+		From:          "+14155550100",
+		To:            "+16045550123",
+		CallStatus:    "ringing",
+		ApiVersion:    "2010-04-01",
+		Direction:     "inbound",
+		ForwardedFrom: "+16045550199",
+		CallerName:    "JANE DOE",
+		FromCity:      "SAN FRANCISCO",
+		FromState:     "CA",
+		FromZip:       "94105",
+		FromCountry:   "US",
+		ToCity:        "VANCOUVER",
+		ToState:       "BC",
+		ToCountry:     "CA",
+		Called:        "+16045550123",
+		CalledCity:    "VANCOUVER",
+		CalledState:   "BC",
+		CalledCountry: "CA",
+		Caller:        "+14155550100",
+		CallerCity:    "SAN FRANCISCO",
+		CallerState:   "CA",
+		CallerZip:     "94105",
+		CallerCountry: "US",
+	}
+	if c != expect {
+		t.Errorf("expected %#v, got %#v", expect, c)
+	}
+	if !c.Forwarded() {
+		t.Errorf("expected call to be forwarded")
+	}
+}
+
+// recording status callbacks of a mono call recording, a dual channel call
+// recording and a conference recording
+const (
+	callRecording = "AccountSid=AC123&CallSid=CA123" +
+		"&RecordingSid=RE123&RecordingUrl=https%3A%2F%2Fapi.twilio.com%2F2010-04-01%2FAccounts%2FAC123%2FRecordings%2FRE123" +
+		"&RecordingStatus=completed&RecordingDuration=42&RecordingChannels=1" +
+		"&RecordingSource=RecordVerb&RecordingStartTime=Mon%2C+22+Aug+2022+18%3A24%3A03+%2B0000"
+	dualCallRecording = "AccountSid=AC123&CallSid=CA123" +
+		"&RecordingSid=RE456&RecordingUrl=https%3A%2F%2Fapi.twilio.com%2F2010-04-01%2FAccounts%2FAC123%2FRecordings%2FRE456" +
+		"&RecordingStatus=completed&RecordingDuration=17&RecordingChannels=2" +
+		"&RecordingSource=StartCallRecordingAPI&RecordingTrack=both"
+	conferenceRecording = "AccountSid=AC123&ConferenceSid=CF123" +
+		"&RecordingSid=RE789&RecordingUrl=https%3A%2F%2Fapi.twilio.com%2F2010-04-01%2FAccounts%2FAC123%2FRecordings%2FRE789" +
+		"&RecordingStatus=completed&RecordingDuration=300&RecordingChannels=1" +
+		"&RecordingSource=StartConferenceRecordingAPI"
+	dualConferenceRecording = "AccountSid=AC123&ConferenceSid=CF123" +
+		"&RecordingSid=RE012&RecordingStatus=completed&RecordingDuration=300" +
+		"&RecordingChannels=2&RecordingSource=StartConferenceRecordingAPI"
+)
+
+func TestParseRecordingStatusCallback(t *testing.T) {
+	var tests = []struct {
+		Form          string
+		RecordingSid  string
+		CallSid       string
+		ConferenceSid string
+		Duration      int
+		DualChannel   bool
+		Track         RecordingTrack
+	}{
+		{callRecording, "RE123", "CA123", "", 42, false, ""},
+		{dualCallRecording, "RE456", "CA123", "", 17, true, TwiTrackBoth},
+		{conferenceRecording, "RE789", "", "CF123", 300, false, ""},
+		{dualConferenceRecording, "RE012", "", "CF123", 300, true, ""},
+	}
+
+	for idx, test := range tests {
+		form, _ := url.ParseQuery(test.Form)
+		cb, err := ParseRecordingStatusCallback(webhookRequest(form))
+		if err != nil {
+			t.Errorf("Test %v failed: %v", idx, err)
+			continue
+		}
+		if cb.RecordingSid != test.RecordingSid || cb.CallSid != test.CallSid ||
+			cb.ConferenceSid != test.ConferenceSid ||
+			cb.RecordingDuration != test.Duration ||
+			cb.DualChannel() != test.DualChannel ||
+			cb.RecordingTrack != test.Track ||
+			cb.RecordingStatus != TwiCompleted {
+			t.Errorf("Test %v failed; got %#v", idx, cb)
+		}
+	}
+}
+
+func TestParseRecordingStatusCallbackErrors(t *testing.T) {
+	var tests = []url.Values{
+		{"CallSid": {"CA123"}},
+		{"RecordingSid": {"RE123"}, "RecordingDuration": {"long"}},
+		{"RecordingSid": {"RE123"}, "RecordingChannels": {"two"}},
+	}
+
+	for idx, form := range tests {
+		if _, err := ParseRecordingStatusCallback(webhookRequest(form)); err == nil {
+			t.Errorf("Test %v failed; expected error", idx)
+		}
+	}
+}
+
+// inboundMMS is the request of an incoming message with two attachments
+const inboundMMS = "AccountSid=AC123&ApiVersion=2010-04-01" +
+	"&Body=Look&From=%2B14155550100&To=%2B16045550123" +
+	"&MessageSid=MM123&SmsSid=MM123&NumSegments=1&NumMedia=2" +
+	"&MediaContentType0=image%2Fjpeg" +
+	"&MediaUrl0=https%3A%2F%2Fapi.twilio.com%2F2010-04-01%2FAccounts%2FAC123%2FMessages%2FMM123%2FMedia%2FME1" +
+	"&MediaContentType1=video%2Fmp4" +
+	"&MediaUrl1=https%3A%2F%2Fapi.twilio.com%2F2010-04-01%2FAccounts%2FAC123%2FMessages%2FMM123%2FMedia%2FME2"
+
+func TestParseInboundMessage(t *testing.T) {
+	form, _ := url.ParseQuery(inboundMMS)
+	m, err := ParseInboundMessage(webhookRequest(form))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const media = "https://api.twilio.com/2010-04-01/Accounts/AC123/Messages/MM123/Media/"
+	if m.MessageSid != "MM123" || m.Body != "Look" || m.NumSegments != 1 ||
+		len(m.Media) != 2 ||
+		m.Media[0] != (InboundMedia{Url: media + "ME1", ContentType: "image/jpeg"}) ||
+		m.Media[1] != (InboundMedia{Url: media + "ME2", ContentType: "video/mp4"}) {
+		t.Errorf("unexpected message %#v", m)
+	}
+
+	if _, err := ParseInboundMessage(webhookRequest(url.Values{"NumMedia": {"1"}})); err == nil {
+		t.Errorf("expected error for missing MessageSid")
+	}
+}
+
+func TestParseConferenceEvent(t *testing.T) {
+	var tests = []struct {
+		Form  url.Values
+		Event ConferenceEvent
+		Valid bool
+	}{
+		{url.Values{"ConferenceSid": {"CF123"}, "FriendlyName": {"support"},
+			"StatusCallbackEvent": {"participant-join"}, "SequenceNumber": {"3"},
+			"CallSid": {"CA123"}, "Muted": {"false"}, "Coaching": {"false"},
+			"EndConferenceOnExit": {"true"}},
+			ConferenceEvent{ConferenceSid: "CF123", FriendlyName: "support",
+				StatusCallbackEvent: TwiParticipantJoin, SequenceNumber: 3,
+				CallSid: "CA123", EndConferenceOnExit: true}, true},
+		{url.Values{"ConferenceSid": {"CF123"}, "StatusCallbackEvent": {"participant-join"},
+			"CallSid": {"CA456"}, "Coaching": {"true"}, "CallSidToCoach": {"CA123"}},
+			ConferenceEvent{ConferenceSid: "CF123", StatusCallbackEvent: TwiParticipantJoin,
+				CallSid: "CA456", Coaching: true, CallSidToCoach: "CA123"}, true},
+		{url.Values{"ConferenceSid": {"CF123"}, "StatusCallbackEvent": {"conference-end"},
+			"ReasonConferenceEnded": {"last-participant-left"}},
+			ConferenceEvent{ConferenceSid: "CF123", StatusCallbackEvent: TwiConferenceEnd,
+				ReasonConferenceEnded: "last-participant-left"}, true},
+		{url.Values{"StatusCallbackEvent": {"conference-start"}}, ConferenceEvent{}, false},
+		{url.Values{"ConferenceSid": {"CF123"}, "SequenceNumber": {"first"}},
+			ConferenceEvent{}, false},
+	}
+
+	for idx, test := range tests {
+		ev, err := ParseConferenceEvent(webhookRequest(test.Form))
+		if (err == nil) != test.Valid {
+			t.Errorf("Test %v failed; expected valid %v, got %v", idx, test.Valid, err)
+			continue
+		}
+		if test.Valid && !reflect.DeepEqual(ev, test.Event) {
+			t.Errorf("Test %v failed; expected %#v, got %#v", idx, test.Event, ev)
+		}
+	}
+}
+
+func TestParseDialAction(t *testing.T) {
+	const recUrl = "https://api.twilio.com/2010-04-01/Accounts/AC123/Recordings/RE0123456789abcdef0123456789abcdef"
+	var tests = []struct {
+		Form   url.Values
+		Result DialActionResult
+		Valid  bool
+	}{
+		{url.Values{"CallSid": {"CA123"}, "DialCallSid": {"CA456"},
+			"DialCallStatus": {"completed"}, "DialCallDuration": {"42"},
+			"DialBridged": {"true"}, "RecordingUrl": {recUrl}},
+			DialActionResult{CallSid: "CA123", DialCallSid: "CA456", DialCallStatus: "completed",
+				DialCallDuration: 42, DialBridged: true, RecordingUrl: recUrl}, true},
+		{url.Values{"CallSid": {"CA123"}, "DialCallStatus": {"no-answer"}},
+			DialActionResult{CallSid: "CA123", DialCallStatus: "no-answer"}, true},
+		{url.Values{"CallSid": {"CA123"}}, DialActionResult{}, false},
+		{url.Values{"DialCallStatus": {"completed"}, "DialCallDuration": {"long"}},
+			DialActionResult{}, false},
+	}
+
+	for idx, test := range tests {
+		res, err := ParseDialAction(webhookRequest(test.Form))
+		if (err == nil) != test.Valid {
+			t.Errorf("Test %v failed; expected valid %v, got %v", idx, test.Valid, err)
+			continue
+		}
+		if test.Valid && !reflect.DeepEqual(res, test.Result) {
+			t.Errorf("Test %v failed; expected %#v, got %#v", idx, test.Result, res)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package twirest
+
+import "context"
+
+// codeUnsubscribed is the exception code of messages to recipients that
+// replied STOP to the sender
+const codeUnsubscribed = 21610
+
+// BroadcastOpts configures Broadcast
+type BroadcastOpts struct {
+	// MediaUrl is attached to every message
+	MediaUrl string
+	// StatusCallback is set on every message
+	StatusCallback string
+	// MPS is the messages per second of the messaging service, which depends
+	// on its sender pool. DefaultMPS of SenderMessagingService if zero.
+	MPS float64
+	// ChunkSize is the number of recipients sent to at once, default 1000
+	ChunkSize int
+	// Concurrency is the maximum number of requests in flight, default 10
+	Concurrency int
+	// Clock is the time source for rate limiting, the clock of the client if
+	// nil
+	Clock Clock
+}
+
+// BroadcastReport is the outcome of a Broadcast. OptedOut are the recipients
+// that unsubscribed from the messaging service, to remove from the list sent
+// to. Failed are the recipients whose message failed for another reason.
+type BroadcastReport struct {
+	Results  []BulkResult
+	Sent     int
+	OptedOut []string
+	Failed   []string
+}
+
+// Broadcast sends body to all recipients through the messaging service
+// serviceSid, which picks the sender of each message from its pool. The
+// recipients are sent to in chunks with SendBulk, limited to the throughput of
+// the service. The results are in the order of recipients.
+func (twiClient *TwilioClient) Broadcast(ctx context.Context, serviceSid, body string,
+	recipients []string, opts BroadcastOpts) BroadcastReport {
+
+	chunkSize := opts.ChunkSize
+	if chunkSize <= 0 {
+		chunkSize = 1000
+	}
+	bulk := BulkOptions{
+		Senders:     map[string]SenderClass{serviceSid: SenderMessagingService},
+		Concurrency: opts.Concurrency,
+		Clock:       opts.Clock,
+	}
+	if opts.MPS > 0 {
+		bulk.MPS = map[SenderClass]float64{SenderMessagingService: opts.MPS}
+	}
+
+	report := BroadcastReport{Results: make([]BulkResult, 0, len(recipients))}
+	for start := 0; start < len(recipients); start += chunkSize {
+		end := start + chunkSize
+		if end > len(recipients) {
+			end = len(recipients)
+		}
+
+		msgs := make([]SendMessage, 0, end-start)
+		for _, to := range recipients[start:end] {
+			msgs = append(msgs, SendMessage{
+				MessagingServiceSid: serviceSid,
+				To:                  to,
+				Text:                body,
+				MediaUrl:            opts.MediaUrl,
+				StatusCallback:      opts.StatusCallback,
+			})
+		}
+		report.add(twiClient.SendBulk(ctx, msgs, bulk))
+	}
+	return report
+}
+
+// add aggregates the results of a chunk
+func (r *BroadcastReport) add(results []BulkResult) {
+	for _, res := range results {
+		r.Results = append(r.Results, res)
+		switch {
+		case res.Err == nil && res.Response.OK():
+			r.Sent++
+		case res.Response.Status.Twilio == codeUnsubscribed:
+			r.OptedOut = append(r.OptedOut, res.Message.To)
+		default:
+			r.Failed = append(r.Failed, res.Message.To)
+		}
+	}
+}
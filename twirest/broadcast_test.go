@@ -0,0 +1,72 @@
+package twirest
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestBroadcast(t *testing.T) {
+	optedOut := map[string]bool{"+15005550002": true, "+15005550005": true}
+
+	var mu sync.Mutex
+	sent := map[string]bool{}
+	client, ts := testClient(t, http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			r.ParseForm()
+			to := r.PostForm.Get("To")
+			if r.PostForm.Get("MessagingServiceSid") != "MG123" ||
+				r.PostForm.Get("From") != "" || r.PostForm.Get("Body") != "Sale!" {
+				t.Errorf("unexpected message %v", r.PostForm)
+			}
+
+			switch {
+			case optedOut[to]:
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(xmlHeader + `<TwilioResponse><RestException>` +
+					`<Code>21610</Code><Message>Attempt to send to unsubscribed recipient</Message>` +
+					`<Status>400</Status></RestException></TwilioResponse>`))
+			case to == "+15005550004":
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(xmlHeader + `<TwilioResponse><RestException>` +
+					`<Code>21211</Code><Message>Invalid 'To' Phone Number</Message>` +
+					`<Status>400</Status></RestException></TwilioResponse>`))
+			default:
+				mu.Lock()
+				sent[to] = true
+				mu.Unlock()
+				fmt.Fprintf(w, xmlHeader+`<TwilioResponse><Message><Sid>SM%s</Sid>`+
+					`</Message></TwilioResponse>`, to[1:])
+			}
+		}))
+	defer ts.Close()
+
+	var recipients []string
+	for i := 1; i <= 7; i++ {
+		recipients = append(recipients, fmt.Sprintf("+1500555000%d", i))
+	}
+
+	report := client.Broadcast(context.Background(), "MG123", "Sale!", recipients,
+		BroadcastOpts{ChunkSize: 3, MPS: 50, Clock: newFakeClock()})
+
+	if len(report.Results) != len(recipients) {
+		t.Fatalf("expected %v results, got %v", len(recipients), len(report.Results))
+	}
+	for i, res := range report.Results {
+		if res.Message.To != recipients[i] {
+			t.Errorf("result %v out of order: %v", i, res.Message.To)
+		}
+	}
+	if report.Sent != 4 || len(sent) != 4 {
+		t.Errorf("expected 4 sent, got %v (%v)", report.Sent, sent)
+	}
+	if expect := []string{"+15005550002", "+15005550005"}; !reflect.DeepEqual(report.OptedOut, expect) {
+		t.Errorf("expected opted out %v, got %v", expect, report.OptedOut)
+	}
+	if expect := []string{"+15005550004"}; !reflect.DeepEqual(report.Failed, expect) {
+		t.Errorf("expected failed %v, got %v", expect, report.Failed)
+	}
+}
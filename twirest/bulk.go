@@ -0,0 +1,301 @@
+package twirest
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SenderClass classifies a sender by the throughput twilio allows it
+type SenderClass string
+
+const (
+	SenderLongCode         SenderClass = "long-code"
+	SenderTollFree         SenderClass = "toll-free"
+	SenderShortCode        SenderClass = "short-code"
+	SenderAlphanumeric     SenderClass = "alphanumeric"
+	SenderMessagingService SenderClass = "messaging-service"
+)
+
+// DefaultMPS is the default number of messages per second sent from a
+// single sender of each class
+var DefaultMPS = map[SenderClass]float64{
+	SenderLongCode:         1,
+	SenderTollFree:         3,
+	SenderShortCode:        100,
+	SenderAlphanumeric:     1,
+	SenderMessagingService: 10,
+}
+
+// tollFreeCodes are the north american toll-free area codes
+var tollFreeCodes = []string{"800", "833", "844", "855", "866", "877", "888"}
+
+// ClassifySender classifies the sender of a message from its From number,
+// or its MessagingServiceSid if From isn't set
+func ClassifySender(msg SendMessage) SenderClass {
+	from := msg.From
+	switch {
+	case from == "" && msg.MessagingServiceSid != "":
+		return SenderMessagingService
+	case strings.HasPrefix(from, "+1") && len(from) == 12 &&
+		stringIn(from[2:5], tollFreeCodes):
+		return SenderTollFree
+	case strings.HasPrefix(from, "+"):
+		return SenderLongCode
+	case len(from) >= 5 && len(from) <= 6 && isDigits(from):
+		return SenderShortCode
+	}
+	return SenderAlphanumeric
+}
+
+// BulkOptions configures SendBulk
+type BulkOptions struct {
+	// MPS overrides the messages per second of sender classes in DefaultMPS
+	MPS map[SenderClass]float64
+	// Senders sets the class of senders that can't be classified from the
+	// number format, keyed by From or MessagingServiceSid
+	Senders map[string]SenderClass
+	// Concurrency is the maximum number of requests in flight, default 10
+	Concurrency int
+	// Clock is the time source for rate limiting, the clock of the client if
+	// nil
+	Clock Clock
+	// Validity is what is done when messages are estimated to expire in
+	// twilio's queue before they are sent, default ValidityWarn
+	Validity ValidityPolicy
+	// SplitFrom holds spare From numbers per From number, ValiditySplit
+	// spreads the messages of a sender over it and its spares
+	SplitFrom map[string][]string
+	// DryRun estimates when the messages are sent without sending them, the
+	// warnings of the ValidityPolicy aren't logged, PlanBulk returns them
+	DryRun bool
+	// Budget stops the send once the cost of the messages sent exceeds it,
+	// the messages not sent fail with *ErrBudgetExceeded. Zero is no budget.
+	Budget Money
+	// EstimatedPrice is the cost counted for a message while its price isn't
+	// known, twilio prices a message some time after it is sent
+	EstimatedPrice Money
+}
+
+// ErrBudgetExceeded is the error of the messages of a bulk send not sent as
+// the messages sent cost more than the budget
+type ErrBudgetExceeded struct {
+	Budget Money
+	Spent  Money
+	Sent   int
+}
+
+func (e *ErrBudgetExceeded) Error() string {
+	return fmt.Sprintf("spent %v on %d messages, over the budget of %v",
+		e.Spent, e.Sent, e.Budget)
+}
+
+// BulkResult is the outcome of one message of a bulk send
+type BulkResult struct {
+	// Message is the message sent, with the changes of the ValidityPolicy
+	Message  SendMessage
+	Response TwilioResponse
+	Err      error
+	// SendAt is the estimated time the message is sent after the start
+	SendAt time.Duration
+}
+
+// senderQueue holds the messages waiting for a sender
+type senderQueue struct {
+	interval time.Duration
+	next     time.Time
+	pending  []int
+}
+
+// SendBulk sends the messages rate limited per sender. Every From number (or
+// messaging service) is limited to the messages per second of its class, and
+// messages of different senders are interleaved so a saturated long code
+// doesn't hold back the messages of a short code. The results are in the
+// order of msgs, messages not sent because ctx was canceled have ctx's error.
+func (twiClient *TwilioClient) SendBulk(ctx context.Context, msgs []SendMessage,
+	opts BulkOptions) []BulkResult {
+
+	clock := opts.Clock
+	if clock == nil {
+		clock = twiClient.timeSource()
+	}
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = 10
+	}
+
+	msgs, est, err := opts.applyValidity(msgs)
+	results := make([]BulkResult, len(msgs))
+	for i, msg := range msgs {
+		results[i].Message = msg
+		results[i].SendAt = est.sendAt[i]
+		results[i].Err = err
+	}
+	if err != nil || opts.DryRun {
+		return results
+	}
+	for _, w := range est.Warnings {
+		twiClient.warnf("twilio bulk send: %s", w)
+	}
+
+	queues := make(map[string]*senderQueue)
+	var order []string // senders in order of appearance, breaks ties
+	for i, msg := range msgs {
+		key := senderKey(msg)
+		q, ok := queues[key]
+		if !ok {
+			q = &senderQueue{interval: opts.interval(key, msg)}
+			queues[key] = q
+			order = append(order, key)
+		}
+		q.pending = append(q.pending, i)
+	}
+
+	budget := &bulkBudget{limit: opts.Budget, estimate: opts.EstimatedPrice}
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, concurrency)
+	for ctx.Err() == nil {
+		// wait for a free worker before picking the next message
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			continue
+		}
+		if budget.exceeded() != nil {
+			break
+		}
+
+		// the sender that may send the soonest goes next
+		var q *senderQueue
+		for _, key := range order {
+			c := queues[key]
+			if len(c.pending) > 0 && (q == nil || c.next.Before(q.next)) {
+				q = c
+			}
+		}
+		if q == nil {
+			break
+		}
+
+		now := clock.Now()
+		if q.next.After(now) {
+			select {
+			case <-clock.After(q.next.Sub(now)):
+			case <-ctx.Done():
+				continue
+			}
+			now = clock.Now()
+		}
+
+		i := q.pending[0]
+		q.pending = q.pending[1:]
+		if q.next.Before(now) {
+			q.next = now
+		}
+		q.next = q.next.Add(q.interval)
+
+		budget.sent++
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			resp, err := twiClient.RequestWithContext(ctx, msgs[i], false)
+			results[i].Response = resp
+			results[i].Err = err
+			budget.add(resp, err)
+			<-sem
+		}(i)
+	}
+	wg.Wait()
+
+	notSent := ctx.Err()
+	if err := budget.exceeded(); err != nil {
+		notSent = err
+	}
+	for _, q := range queues {
+		for _, i := range q.pending {
+			results[i].Err = notSent
+		}
+	}
+	return results
+}
+
+// bulkBudget sums the cost of the messages of a bulk send
+type bulkBudget struct {
+	mu       sync.Mutex
+	limit    Money
+	estimate Money
+	spent    Money
+	sent     int
+	err      error
+}
+
+// add adds the cost of a sent message, its estimate if it isn't priced yet.
+// Messages that failed aren't charged.
+func (b *bulkBudget) add(resp TwilioResponse, err error) {
+	if b.limit.IsZero() || err != nil || resp.Message == nil {
+		return
+	}
+	cost := b.estimate
+	if price, ok, _ := resp.Message.ParsedPrice(); ok {
+		cost = price
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.spent, err = b.spent.Add(cost.Abs()); err != nil {
+		b.err = err
+		return
+	}
+	if over, err := b.spent.Cmp(b.limit); err != nil {
+		b.err = err
+	} else if over > 0 {
+		b.err = &ErrBudgetExceeded{Budget: b.limit, Spent: b.spent}
+	}
+}
+
+// exceeded returns the error stopping the send once the budget is exceeded
+func (b *bulkBudget) exceeded() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if e, ok := b.err.(*ErrBudgetExceeded); ok {
+		e.Sent = b.sent
+		e.Spent = b.spent
+	}
+	return b.err
+}
+
+// senderKey returns the sender of msg, its From number or messaging service
+func senderKey(msg SendMessage) string {
+	if msg.From == "" {
+		return msg.MessagingServiceSid
+	}
+	return msg.From
+}
+
+// interval returns the time between two messages of a sender
+func (opts BulkOptions) interval(key string, msg SendMessage) time.Duration {
+	class, ok := opts.Senders[key]
+	if !ok {
+		class = ClassifySender(msg)
+	}
+	mps, ok := opts.MPS[class]
+	if !ok {
+		mps = DefaultMPS[class]
+	}
+	if mps <= 0 {
+		return 0
+	}
+	return time.Duration(float64(time.Second) / mps)
+}
+
+// isDigits reports if s only contains the digits 0-9
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
@@ -0,0 +1,102 @@
+package twirest
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestClassifySender(t *testing.T) {
+	var tests = []struct {
+		Msg   SendMessage
+		Class SenderClass
+	}{
+		{SendMessage{From: "+16045550123"}, SenderLongCode},
+		{SendMessage{From: "+18885550123"}, SenderTollFree},
+		{SendMessage{From: "+448885550123"}, SenderLongCode},
+		{SendMessage{From: "55555"}, SenderShortCode},
+		{SendMessage{From: "ACMECORP"}, SenderAlphanumeric},
+		{SendMessage{MessagingServiceSid: "MG123"}, SenderMessagingService},
+	}
+
+	for idx, test := range tests {
+		if got := ClassifySender(test.Msg); got != test.Class {
+			t.Errorf("Test %v failed; expected %v, got %v", idx, test.Class, got)
+		}
+	}
+}
+
+func TestSendBulkInterleaves(t *testing.T) {
+	clock := newFakeClock()
+	start := clock.Now()
+
+	var sent []string
+	client, ts := testClient(t, http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			sent = append(sent, fmt.Sprintf("%s@%v",
+				r.FormValue("From"), clock.Now().Sub(start)))
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprint(w, `<TwilioResponse><Message><Sid>SM1</Sid></Message></TwilioResponse>`)
+		}))
+	defer ts.Close()
+
+	const long, short = "+16045550123", "55555"
+	msgs := []SendMessage{
+		{From: long, To: "+16045550001", Text: "1"},
+		{From: long, To: "+16045550002", Text: "2"},
+		{From: long, To: "+16045550003", Text: "3"},
+		{From: short, To: "+16045550004", Text: "4"},
+		{From: short, To: "+16045550005", Text: "5"},
+		{From: short, To: "+16045550006", Text: "6"},
+	}
+
+	results := client.SendBulk(context.Background(), msgs, BulkOptions{
+		Concurrency: 1,
+		Clock:       clock,
+	})
+
+	expect := []string{
+		long + "@0s",
+		short + "@0s",
+		short + "@10ms",
+		short + "@20ms",
+		long + "@1s",
+		long + "@2s",
+	}
+	if fmt.Sprint(sent) != fmt.Sprint(expect) {
+		t.Errorf("expected send order %v, got %v", expect, sent)
+	}
+	for idx, res := range results {
+		if res.Err != nil || res.Message.Text != msgs[idx].Text {
+			t.Errorf("Result %v failed: %#v", idx, res)
+		}
+	}
+}
+
+func TestSendBulkMPSOverride(t *testing.T) {
+	clock := newFakeClock()
+	start := clock.Now()
+
+	client, ts := testClient(t, http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprint(w, `<TwilioResponse><Message/></TwilioResponse>`)
+		}))
+	defer ts.Close()
+
+	var msgs []SendMessage
+	for i := 0; i < 5; i++ {
+		msgs = append(msgs, SendMessage{From: "+16045550123", To: "+16045550001"})
+	}
+	client.SendBulk(context.Background(), msgs, BulkOptions{
+		Senders: map[string]SenderClass{"+16045550123": SenderShortCode},
+		MPS:     map[SenderClass]float64{SenderShortCode: 2},
+		Clock:   clock,
+	})
+
+	if elapsed := clock.Now().Sub(start); elapsed != 2*time.Second {
+		t.Errorf("expected 5 messages at 2 MPS to take 2s, took %v", elapsed)
+	}
+}
@@ -0,0 +1,82 @@
+package twirest
+
+// BYOC (Bring Your Own Carrier) trunks on voice.twilio.com, see
+// https://www.twilio.com/docs/voice/api/byoc-trunk-resource
+
+// ListByocTrunks requests a list of the BYOC trunks on the account
+type ListByocTrunks struct {
+	domain   uri `url:"voice.twilio.com/v1"`
+	resource uri `url:"/ByocTrunks"`
+	PageSize int `url:"PageSize"` // at most 1000, default 50
+	Page     int `url:"Page"`
+}
+
+// FetchByocTrunk requests a single BYOC trunk
+type FetchByocTrunk struct {
+	domain   uri `url:"voice.twilio.com/v1"`
+	resource uri `url:"/ByocTrunks"`
+	Sid      string
+}
+
+// CreateByocTrunk creates a new BYOC trunk
+type CreateByocTrunk struct {
+	domain               uri    `url:"voice.twilio.com/v1"`
+	resource             uri    `url:"/ByocTrunks"`
+	FriendlyName         string `url:"FriendlyName"`
+	VoiceUrl             string `url:"VoiceUrl"`
+	VoiceMethod          string `url:"VoiceMethod"`
+	VoiceFallbackUrl     string `url:"VoiceFallbackUrl"`
+	VoiceFallbackMethod  string `url:"VoiceFallbackMethod"`
+	StatusCallbackUrl    string `url:"StatusCallbackUrl"`
+	StatusCallbackMethod string `url:"StatusCallbackMethod"`
+	CnamLookupEnabled    string `url:"CnamLookupEnabled"`
+	ConnectionPolicySid  string `url:"ConnectionPolicySid"`
+	FromDomainSid        string `url:"FromDomainSid"`
+}
+
+// UpdateByocTrunk changes the properties of a BYOC trunk
+type UpdateByocTrunk struct {
+	domain               uri `url:"voice.twilio.com/v1"`
+	resource             uri `url:"/ByocTrunks"`
+	Sid                  string
+	FriendlyName         string `url:"FriendlyName"`
+	VoiceUrl             string `url:"VoiceUrl"`
+	VoiceMethod          string `url:"VoiceMethod"`
+	VoiceFallbackUrl     string `url:"VoiceFallbackUrl"`
+	VoiceFallbackMethod  string `url:"VoiceFallbackMethod"`
+	StatusCallbackUrl    string `url:"StatusCallbackUrl"`
+	StatusCallbackMethod string `url:"StatusCallbackMethod"`
+	CnamLookupEnabled    string `url:"CnamLookupEnabled"`
+	ConnectionPolicySid  string `url:"ConnectionPolicySid"`
+	FromDomainSid        string `url:"FromDomainSid"`
+}
+
+// DeleteByocTrunk removes a BYOC trunk
+type DeleteByocTrunk struct {
+	domain   uri `url:"voice.twilio.com/v1"`
+	resource uri `url:"/ByocTrunks"`
+	Sid      string
+}
+
+type ByocTrunksResponse struct {
+	Meta       Meta                `json:"meta"`
+	ByocTrunks []ByocTrunkResponse `json:"byoc_trunks"`
+}
+
+type ByocTrunkResponse struct {
+	Sid                  string `json:"sid"`
+	AccountSid           string `json:"account_sid"`
+	FriendlyName         string `json:"friendly_name"`
+	VoiceUrl             string `json:"voice_url"`
+	VoiceMethod          string `json:"voice_method"`
+	VoiceFallbackUrl     string `json:"voice_fallback_url"`
+	VoiceFallbackMethod  string `json:"voice_fallback_method"`
+	StatusCallbackUrl    string `json:"status_callback_url"`
+	StatusCallbackMethod string `json:"status_callback_method"`
+	CnamLookupEnabled    bool   `json:"cnam_lookup_enabled"`
+	ConnectionPolicySid  string `json:"connection_policy_sid"`
+	FromDomainSid        string `json:"from_domain_sid"`
+	DateCreated          string `json:"date_created"`
+	DateUpdated          string `json:"date_updated"`
+	Url                  string `json:"url"`
+}
@@ -0,0 +1,176 @@
+package twirest
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// twilioTime is the format of the dates of the 2010 API
+const twilioTime = time.RFC1123Z
+
+// CallInfo is a call with typed fields. Times are zero if not set, such as
+// the EndTime of a call in progress. Price is negative, the cost of the call.
+type CallInfo struct {
+	Sid           string
+	ParentCallSid string
+	AccountSid    string
+	From          string
+	To            string
+	Status        string
+	Direction     string
+	AnsweredBy    string
+	Annotation    string
+	DateCreated   time.Time
+	StartTime     time.Time
+	EndTime       time.Time
+	Duration      time.Duration
+	Price         float64
+	PriceUnit     string
+}
+
+// NewCallInfo converts a call of a response to a CallInfo
+func NewCallInfo(c CallResponse) (CallInfo, error) {
+	info := CallInfo{
+		Sid:           c.Sid,
+		ParentCallSid: c.ParentCallSid,
+		AccountSid:    c.AccountSid,
+		From:          c.From,
+		To:            c.To,
+		Status:        c.Status,
+		Direction:     c.Direction,
+		AnsweredBy:    c.AnsweredBy,
+		Annotation:    c.Annotation,
+		Duration:      time.Duration(c.Duration) * time.Second,
+		PriceUnit:     c.PriceUnit,
+	}
+
+	var err error
+	if info.DateCreated, err = parseTime(c.DateCreated); err != nil {
+		return info, err
+	}
+	if info.StartTime, err = parseTime(c.StartTime); err != nil {
+		return info, err
+	}
+	if info.EndTime, err = parseTime(c.EndTime); err != nil {
+		return info, err
+	}
+	if info.Price, err = parsePrice(c.Price); err != nil {
+		return info, err
+	}
+	return info, nil
+}
+
+// MessageInfo is a message with typed fields
+type MessageInfo struct {
+	Sid                 string
+	AccountSid          string
+	MessagingServiceSid string
+	From                string
+	To                  string
+	Body                string
+	Status              string
+	Direction           string
+	DateCreated         time.Time
+	DateSent            time.Time
+	NumSegments         int
+	Price               float64
+	PriceUnit           string
+}
+
+// NewMessageInfo converts a message of a response to a MessageInfo
+func NewMessageInfo(m MessageResponse) (MessageInfo, error) {
+	info := MessageInfo{
+		Sid:                 m.Sid,
+		AccountSid:          m.AccountSid,
+		MessagingServiceSid: m.MessagingServiceSid,
+		From:                m.From,
+		To:                  m.To,
+		Body:                m.Body,
+		Status:              m.Status,
+		Direction:           m.Direction,
+		PriceUnit:           m.PriceUnit,
+	}
+
+	var err error
+	if info.DateCreated, err = parseTime(m.DateCreated); err != nil {
+		return info, err
+	}
+	if info.DateSent, err = parseTime(m.DateSent); err != nil {
+		return info, err
+	}
+	if m.NumSegments != "" {
+		if info.NumSegments, err = strconv.Atoi(m.NumSegments); err != nil {
+			return info, fmt.Errorf("non valid NumSegments: '%s'", m.NumSegments)
+		}
+	}
+	if info.Price, err = parsePrice(m.Price); err != nil {
+		return info, err
+	}
+	return info, nil
+}
+
+// parseTime parses a date of the 2010 API, an empty date is the zero time
+func parseTime(val string) (time.Time, error) {
+	if val == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse(twilioTime, val)
+	if err != nil {
+		return t, fmt.Errorf("non valid time: '%s'", val)
+	}
+	return t, nil
+}
+
+// parsePrice parses a price such as '-0.02000', an empty price is zero
+func parsePrice(val string) (float64, error) {
+	p, err := parseUsage(val)
+	if err != nil {
+		return 0, fmt.Errorf("non valid price: '%s'", val)
+	}
+	return p, nil
+}
+
+// CallOutcome is the outcome of dialing a call
+type CallOutcome string
+
+// Call outcomes
+const (
+	OutcomeAnsweredHuman   CallOutcome = "answered-by-human"
+	OutcomeAnsweredMachine CallOutcome = "answered-by-machine"
+	OutcomeAnsweredUnknown CallOutcome = "answered-unknown"
+	OutcomeBusy            CallOutcome = "busy"
+	OutcomeFailed          CallOutcome = "failed"
+	OutcomeNoAnswer        CallOutcome = "no-answer"
+	OutcomeCanceled        CallOutcome = "canceled"
+	OutcomeInProgress      CallOutcome = "in-progress"
+)
+
+// ClassifyCallOutcome combines the Status and the AnsweredBy of answering
+// machine detection of a call into its outcome. Completed calls without
+// detection, or where it couldn't tell, are answered-unknown. Fax machines
+// count as machines. Calls that haven't ended are in-progress.
+func ClassifyCallOutcome(c CallInfo) CallOutcome {
+	switch c.Status {
+	case TwiBusy:
+		return OutcomeBusy
+	case TwiFailed:
+		return OutcomeFailed
+	case TwiNoAnswer:
+		return OutcomeNoAnswer
+	case TwiCanceled:
+		return OutcomeCanceled
+	case TwiCompleted:
+	default:
+		return OutcomeInProgress
+	}
+
+	switch c.AnsweredBy {
+	case TwiHuman:
+		return OutcomeAnsweredHuman
+	case TwiMachineStart, TwiMachineEndBeep, TwiMachineEndSilence,
+		TwiMachineEndOther, TwiFax:
+		return OutcomeAnsweredMachine
+	}
+	return OutcomeAnsweredUnknown
+}
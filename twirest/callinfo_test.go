@@ -0,0 +1,52 @@
+package twirest
+
+import "testing"
+
+func TestClassifyCallOutcome(t *testing.T) {
+	answeredBy := []string{"", TwiHuman, TwiMachineStart, TwiMachineEndBeep,
+		TwiMachineEndSilence, TwiMachineEndOther, TwiFax, TwiUnknown}
+	completed := []CallOutcome{OutcomeAnsweredUnknown, OutcomeAnsweredHuman,
+		OutcomeAnsweredMachine, OutcomeAnsweredMachine, OutcomeAnsweredMachine,
+		OutcomeAnsweredMachine, OutcomeAnsweredMachine, OutcomeAnsweredUnknown}
+
+	var tests = []struct {
+		Status string
+		Expect []CallOutcome
+	}{
+		{TwiCompleted, completed},
+		{TwiBusy, nil},
+		{TwiFailed, nil},
+		{TwiNoAnswer, nil},
+		{TwiCanceled, nil},
+		{TwiQueued, nil},
+		{TwiRinging, nil},
+		{TwiInProgress, nil},
+	}
+	single := map[string]CallOutcome{
+		TwiBusy: OutcomeBusy, TwiFailed: OutcomeFailed, TwiNoAnswer: OutcomeNoAnswer,
+		TwiCanceled: OutcomeCanceled, TwiQueued: OutcomeInProgress,
+		TwiRinging: OutcomeInProgress, TwiInProgress: OutcomeInProgress,
+	}
+
+	for idx, test := range tests {
+		for i, by := range answeredBy {
+			expect := single[test.Status]
+			if test.Expect != nil {
+				expect = test.Expect[i]
+			}
+			got := ClassifyCallOutcome(CallInfo{Status: test.Status, AnsweredBy: by})
+			if got != expect {
+				t.Errorf("Test %v failed; %v answered by %#v: expected %v, got %v",
+					idx, test.Status, by, expect, got)
+			}
+		}
+	}
+}
+
+func TestNewCallInfoAnnotation(t *testing.T) {
+	info, err := NewCallInfo(CallResponse{Sid: "CA1", Status: TwiCompleted,
+		AnsweredBy: TwiMachineEndBeep, Annotation: "campaign-42"})
+	if err != nil || info.AnsweredBy != TwiMachineEndBeep || info.Annotation != "campaign-42" {
+		t.Errorf("expected AnsweredBy and Annotation, got %#v (%v)", info, err)
+	}
+}
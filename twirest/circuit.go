@@ -0,0 +1,201 @@
+package twirest
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// CircuitState is the state of the circuit breaker of a host
+type CircuitState int
+
+const (
+	// CircuitClosed lets requests through
+	CircuitClosed CircuitState = iota
+	// CircuitOpen fails requests at once until the cool-down has passed
+	CircuitOpen
+	// CircuitHalfOpen lets a single probe request through, closing the
+	// circuit if it succeeds and opening it again if it doesn't
+	CircuitHalfOpen
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	}
+	return "CircuitState(" + strconv.Itoa(int(s)) + ")"
+}
+
+// CircuitOptions configures WithCircuitBreaker
+type CircuitOptions struct {
+	// Threshold is the number of consecutive 503 responses of a host that
+	// open its circuit, default 5
+	Threshold int
+	// CoolDown is how long the circuit stays open, default 30 seconds. A
+	// longer Retry-After of the last 503 is honored.
+	CoolDown time.Duration
+	// OnStateChange is called when the circuit of a host changes state, for
+	// logging and metrics. It must not block.
+	OnStateChange func(host string, from, to CircuitState)
+	// Clock is the time source, the clock of the client if nil
+	Clock Clock
+}
+
+// ErrCircuitOpen is returned instead of making a request while the circuit
+// of its host is open
+type ErrCircuitOpen struct {
+	Host  string
+	Until time.Time
+}
+
+func (e *ErrCircuitOpen) Error() string {
+	return fmt.Sprintf("circuit open for %s until %s", e.Host,
+		e.Until.Format(time.RFC3339))
+}
+
+// WithCircuitBreaker makes the client stop sending requests to a host that
+// keeps responding 503 Service Unavailable, as during a twilio incident,
+// instead of adding to the load with retries. Each host, such as
+// api.twilio.com or monitor.twilio.com, has its own circuit. Requests to a
+// host with an open circuit fail with *ErrCircuitOpen. The client gets its
+// own copy of the transport so clients sharing a transport aren't affected.
+func WithCircuitBreaker(opts CircuitOptions) ClientOption {
+	if opts.Threshold <= 0 {
+		opts.Threshold = 5
+	}
+	if opts.CoolDown <= 0 {
+		opts.CoolDown = 30 * time.Second
+	}
+	return ClientOption{apply: func(c *TwilioClient) {
+		opts := opts
+		if opts.Clock == nil {
+			opts.Clock = clientClock{c}
+		}
+		// the breaker of an earlier WithCircuitBreaker is replaced
+		base, _ := c.transport()
+		hc := *c.httpclient
+		hc.Transport = &circuitTransport{
+			base:     base,
+			opts:     opts,
+			circuits: map[string]*circuit{},
+		}
+		c.httpclient = &hc
+	}}
+}
+
+// circuit is the breaker state of a host
+type circuit struct {
+	state    CircuitState
+	failures int
+	until    time.Time
+	probing  bool
+}
+
+// circuitTransport is a http.RoundTripper with a circuit per host
+type circuitTransport struct {
+	base     http.RoundTripper // nil for http.DefaultTransport
+	opts     CircuitOptions
+	mu       sync.Mutex
+	circuits map[string]*circuit
+}
+
+// around returns a breaker with the options of t around base, for the
+// options applied after WithCircuitBreaker that replace the transport
+func (t *circuitTransport) around(base http.RoundTripper) *circuitTransport {
+	return &circuitTransport{base: base, opts: t.opts, circuits: map[string]*circuit{}}
+}
+
+func (t *circuitTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	host := r.URL.Host
+	if err := t.allow(host); err != nil {
+		return nil, err
+	}
+	base := t.base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	resp, err := base.RoundTrip(r)
+	t.record(host, resp, err)
+	return resp, err
+}
+
+// allow returns an *ErrCircuitOpen if a request to host mustn't be made
+func (t *circuitTransport) allow(host string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	c := t.circuits[host]
+	if c == nil {
+		return nil
+	}
+	if c.state == CircuitOpen && !t.opts.Clock.Now().Before(c.until) {
+		t.setState(host, c, CircuitHalfOpen)
+	}
+	switch {
+	case c.state == CircuitOpen, c.state == CircuitHalfOpen && c.probing:
+		return &ErrCircuitOpen{Host: host, Until: c.until}
+	case c.state == CircuitHalfOpen:
+		c.probing = true
+	}
+	return nil
+}
+
+// record updates the circuit of host with the outcome of a request
+func (t *circuitTransport) record(host string, resp *http.Response, err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	c := t.circuits[host]
+	if c == nil {
+		c = &circuit{}
+		t.circuits[host] = c
+	}
+
+	unavailable := resp != nil && resp.StatusCode == http.StatusServiceUnavailable
+	switch {
+	case c.state == CircuitHalfOpen && (unavailable || err != nil):
+		c.probing = false
+		t.open(host, c, resp)
+	case unavailable:
+		c.failures++
+		if c.state == CircuitClosed && c.failures >= t.opts.Threshold {
+			t.open(host, c, resp)
+		}
+	case err == nil:
+		c.failures = 0
+		c.probing = false
+		if c.state != CircuitClosed {
+			t.setState(host, c, CircuitClosed)
+		}
+	}
+}
+
+// open opens the circuit for the cool-down, or the Retry-After of resp if
+// that is longer
+func (t *circuitTransport) open(host string, c *circuit, resp *http.Response) {
+	coolDown := t.opts.CoolDown
+	if resp != nil {
+		if secs, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil &&
+			time.Duration(secs)*time.Second > coolDown {
+			coolDown = time.Duration(secs) * time.Second
+		}
+	}
+	c.until = t.opts.Clock.Now().Add(coolDown)
+	t.setState(host, c, CircuitOpen)
+}
+
+// setState changes the state of the circuit and calls the hook
+func (t *circuitTransport) setState(host string, c *circuit, state CircuitState) {
+	from := c.state
+	c.state = state
+	if t.opts.OnStateChange != nil && from != state {
+		t.opts.OnStateChange(host, from, state)
+	}
+}
@@ -0,0 +1,174 @@
+package twirest
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/cookiejar"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	var script []int
+	var served int
+	client, ts := testClient(t, http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			served++
+			status := http.StatusOK
+			if strings.HasPrefix(r.URL.Path, "/2010-04-01") && len(script) > 0 {
+				status, script = script[0], script[1:]
+			}
+			if status == http.StatusServiceUnavailable {
+				w.Header().Set("Retry-After", "60")
+			}
+			w.WriteHeader(status)
+			if strings.HasPrefix(r.URL.Path, "/2010-04-01") {
+				fmt.Fprint(w, `<TwilioResponse><Queue><Sid>QU1</Sid></Queue></TwilioResponse>`)
+			} else {
+				fmt.Fprint(w, `{"alerts":[],"meta":{}}`)
+			}
+		}))
+	defer ts.Close()
+
+	clock := newFakeClock()
+	var changes []string
+	WithCircuitBreaker(CircuitOptions{
+		Threshold: 3,
+		CoolDown:  10 * time.Second,
+		Clock:     clock,
+		OnStateChange: func(host string, from, to CircuitState) {
+			changes = append(changes, fmt.Sprintf("%s %v>%v", host, from, to))
+		},
+	}).apply(client)
+
+	const unavailable = http.StatusServiceUnavailable
+	var tests = []struct {
+		Wait   time.Duration
+		Script []int
+		Open   bool // the request fails with ErrCircuitOpen
+	}{
+		{0, []int{unavailable}, false},
+		{0, []int{http.StatusOK}, false}, // resets the count
+		{0, []int{unavailable}, false},
+		{0, []int{unavailable}, false},
+		{0, []int{unavailable}, false}, // third in a row opens
+		{0, nil, true},
+		{30 * time.Second, nil, true},                 // Retry-After is longer than the cool-down
+		{30 * time.Second, []int{unavailable}, false}, // half-open probe fails
+		{0, nil, true},
+		{60 * time.Second, []int{http.StatusOK}, false}, // probe closes
+		{0, []int{http.StatusOK}, false},
+	}
+
+	for idx, test := range tests {
+		clock.Advance(test.Wait)
+		script = test.Script
+		before := served
+
+		_, err := client.Request(Queue{Sid: "QU1"}, false)
+		var open *ErrCircuitOpen
+		if isOpen := errors.As(err, &open); isOpen != test.Open {
+			t.Errorf("Test %v failed; expected open %v, got %v", idx, test.Open, err)
+		} else if isOpen && (open.Host != "api.twilio.com" || served != before) {
+			t.Errorf("Test %v failed; expected no request to %v, served %v",
+				idx, open.Host, served-before)
+		}
+
+		// the circuit of other hosts stays closed
+		if _, err := client.Request(ListAlerts{}, false); err != nil {
+			t.Errorf("Test %v failed; expected monitor requests to pass, got %v", idx, err)
+		}
+	}
+
+	expect := []string{
+		"api.twilio.com closed>open",
+		"api.twilio.com open>half-open",
+		"api.twilio.com half-open>open",
+		"api.twilio.com open>half-open",
+		"api.twilio.com half-open>closed",
+	}
+	if fmt.Sprint(changes) != fmt.Sprint(expect) {
+		t.Errorf("expected state changes %v, got %v", expect, changes)
+	}
+}
+
+func TestCircuitHalfOpenSingleProbe(t *testing.T) {
+	clock := newFakeClock()
+	tr := &circuitTransport{
+		opts:     CircuitOptions{Threshold: 1, CoolDown: time.Second, Clock: clock},
+		circuits: map[string]*circuit{},
+	}
+	tr.record("api.twilio.com", &http.Response{StatusCode: 503, Header: http.Header{}}, nil)
+	clock.Advance(time.Second)
+
+	if err := tr.allow("api.twilio.com"); err != nil {
+		t.Fatalf("expected the probe allowed, got %v", err)
+	}
+	if err := tr.allow("api.twilio.com"); err == nil {
+		t.Errorf("expected a single probe while half-open")
+	}
+	if err := tr.allow("lookups.twilio.com"); err != nil {
+		t.Errorf("expected other hosts allowed, got %v", err)
+	}
+}
+
+func TestCircuitBreakerKeptByTransportOptions(t *testing.T) {
+	jar, _ := cookiejar.New(nil)
+	dial := func(ctx context.Context, network, addr string) (net.Conn, error) {
+		return nil, errors.New("not dialed")
+	}
+	breaker := WithCircuitBreaker(CircuitOptions{Threshold: 3})
+	roots := x509.NewCertPool()
+	named := func(tr *http.Transport) bool {
+		return tr.TLSClientConfig != nil && tr.TLSClientConfig.ServerName == "twilio"
+	}
+	var tests = []struct {
+		Opts  []ClientOption
+		Check func(*http.Transport) bool // of the transport under the breaker
+	}{
+		{[]ClientOption{breaker, WithTLSConfig(&tls.Config{ServerName: "twilio"})}, named},
+		{[]ClientOption{breaker, WithRootCAs(roots)}, func(tr *http.Transport) bool {
+			return tr.TLSClientConfig != nil && tr.TLSClientConfig.RootCAs == roots
+		}},
+		{[]ClientOption{breaker, WithDialContext(dial)}, func(tr *http.Transport) bool {
+			return tr.DialContext != nil
+		}},
+		{[]ClientOption{WithHTTPClient(&http.Client{Jar: jar}), breaker,
+			WithTLSConfig(&tls.Config{ServerName: "twilio"})}, named},
+	}
+
+	for idx, test := range tests {
+		client, err := NewClient("AC123", "token", test.Opts...)
+		if err != nil {
+			t.Errorf("Test %v failed; %v", idx, err)
+			continue
+		}
+		ct, ok := client.httpclient.Transport.(*circuitTransport)
+		if !ok {
+			t.Errorf("Test %v failed; expected the circuit breaker, got %T", idx,
+				client.httpclient.Transport)
+			continue
+		}
+		tr, ok := ct.base.(*http.Transport)
+		if !ok || !test.Check(tr) {
+			t.Errorf("Test %v failed; expected the option on the transport under the breaker",
+				idx)
+		}
+		if idx == 3 && client.httpclient.Jar != jar {
+			t.Errorf("Test %v failed; expected the jar of the http client to be kept", idx)
+		}
+	}
+
+	// a round tripper that isn't a *http.Transport can't be copied
+	_, err := NewClient("AC123", "token", WithHTTPClient(&http.Client{
+		Transport: rerouteTransport{}}), breaker, WithDialContext(dial))
+	if err == nil {
+		t.Errorf("Expected an error replacing the dialer of a custom round tripper")
+	}
+}
@@ -0,0 +1,86 @@
+package twirest
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock is the source of time of the features that wait or expire: retries,
+// rate limiting, circuit breaking, deduplication, the caches of owned numbers
+// and lookups, bulk sending and polling. Sleeping for d is receiving from
+// After(d). The client keeps time with the time package unless WithClock
+// gives it another Clock, such as a FakeClock in tests.
+type Clock interface {
+	// Now returns the current time
+	Now() time.Time
+	// After sends the current time on the returned channel once d passed
+	After(d time.Duration) <-chan time.Time
+}
+
+// WithClock makes the client and its time dependent options keep time with
+// clock, whichever order the options are given in. Options with a Clock
+// field of their own, such as RetryOptions, use it instead if it's set.
+func WithClock(clock Clock) ClientOption {
+	return ClientOption{apply: func(c *TwilioClient) {
+		c.clock = clock
+	}}
+}
+
+// timeSource returns the Clock of the client, the real clock if it has none
+func (twiClient *TwilioClient) timeSource() Clock {
+	if twiClient.clock == nil {
+		return realClock{}
+	}
+	return twiClient.clock
+}
+
+// clientClock is the Clock of a client as options see it, it follows a
+// WithClock given after the option
+type clientClock struct {
+	c *TwilioClient
+}
+
+func (cc clientClock) Now() time.Time                         { return cc.c.timeSource().Now() }
+func (cc clientClock) After(d time.Duration) <-chan time.Time { return cc.c.timeSource().After(d) }
+
+// realClock is the Clock of the time package
+type realClock struct{}
+
+func (realClock) Now() time.Time                         { return time.Now() }
+func (realClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+// FakeClock is a Clock whose time only moves when it is waited on or
+// advanced. After moves the time forward by d and returns at once, so code
+// that waits runs without sleeping and sees the time it waited pass.
+type FakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+// NewFakeClock returns a FakeClock set to start
+func NewFakeClock(start time.Time) *FakeClock {
+	return &FakeClock{now: start}
+}
+
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *FakeClock) After(d time.Duration) <-chan time.Time {
+	ch := make(chan time.Time, 1)
+	ch <- c.Advance(d)
+	return ch
+}
+
+// Advance moves the time forward by d and returns the new time, to expire
+// what was cached or claimed without waiting
+func (c *FakeClock) Advance(d time.Duration) time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if d > 0 {
+		c.now = c.now.Add(d)
+	}
+	return c.now
+}
@@ -0,0 +1,56 @@
+package twirest
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// newFakeClock returns a FakeClock at the start of 2024
+func newFakeClock() *FakeClock {
+	return NewFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+}
+
+func TestFakeClock(t *testing.T) {
+	clock := newFakeClock()
+	start := clock.Now()
+
+	if got := <-clock.After(time.Second); !got.Equal(start.Add(time.Second)) {
+		t.Errorf("expected After to fire at %v, got %v", start.Add(time.Second), got)
+	}
+	clock.Advance(time.Minute)
+	clock.Advance(-time.Hour)
+	if got := clock.Now().Sub(start); got != time.Minute+time.Second {
+		t.Errorf("expected %v passed, got %v", time.Minute+time.Second, got)
+	}
+}
+
+func TestWithClock(t *testing.T) {
+	srv := &flakyServer{failures: 2, status: http.StatusServiceUnavailable}
+	clock := newFakeClock()
+	start := clock.Now()
+	// the options keep time with the clock given after them
+	client, ts := testClient(t, srv, WithRetry(RetryOptions{}),
+		WithCircuitBreaker(CircuitOptions{Threshold: 5}),
+		WithDedupe(time.Hour, NewMemoryDedupeStore()), WithClock(clock))
+	defer ts.Close()
+
+	msg := SendMessage{From: "+15005550006", To: "+15005550001", Text: "Hi"}
+	if _, err := client.RequestWithContext(context.Background(), Messages{}, false); err != nil {
+		t.Fatal(err)
+	}
+	// the Retry-After of the two failures
+	if waited := clock.Now().Sub(start); waited != 4*time.Second {
+		t.Errorf("expected the retries to wait 4s on the clock, waited %v", waited)
+	}
+
+	client.Request(msg, false)
+	if _, err := client.Request(msg, false); err == nil {
+		t.Errorf("expected the duplicate suppressed")
+	}
+	clock.Advance(time.Hour)
+	if _, err := client.Request(msg, false); err != nil {
+		t.Errorf("expected the dedupe window to pass on the clock, got %v", err)
+	}
+}
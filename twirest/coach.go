@@ -0,0 +1,76 @@
+package twirest
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/seanhagen/twilio/twiml"
+)
+
+// codeNotFound is the exception code of requests on a resource that doesn't
+// exist, such as adding a participant to a conference that already ended
+const codeNotFound = 20404
+
+// CoachPolicy decides if a participant joining a conference gets coached. It
+// returns the coach to call, at least From and To, and whether to coach.
+type CoachPolicy func(ev twiml.ConferenceEvent) (coach CreateParticipant, ok bool)
+
+// CoachHooks are called by AutoCoachHandler with the outcome of coaching a
+// participant, for logging. A nil hook is skipped.
+type CoachHooks struct {
+	// OnCoach is called when the coach was added to the conference
+	OnCoach func(ev twiml.ConferenceEvent, coach *ParticipantResponse)
+	// OnConferenceEnded is called when the conference ended before the coach
+	// was added, which is not an error
+	OnConferenceEnded func(ev twiml.ConferenceEvent)
+	// OnError is called when adding the coach failed
+	OnError func(ev twiml.ConferenceEvent, err error)
+}
+
+// AutoCoachHandler handles conference status callbacks, adding a coach to the
+// conference when a participant joins that policy picks. The coach is added
+// with Coaching set and CallSidToCoach the joining participant. Coaches
+// joining are never coached themselves. The callback is always acknowledged
+// once parsed, the outcome is reported to hooks.
+func (twiClient *TwilioClient) AutoCoachHandler(policy CoachPolicy,
+	hooks CoachHooks) http.Handler {
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ev, err := twiml.ParseConferenceEvent(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if ev.StatusCallbackEvent == twiml.TwiParticipantJoin && !ev.Coaching {
+			twiClient.coach(r.Context(), ev, policy, hooks)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
+
+// coach adds the coach policy picks for the participant of ev
+func (twiClient *TwilioClient) coach(ctx context.Context, ev twiml.ConferenceEvent,
+	policy CoachPolicy, hooks CoachHooks) {
+
+	coach, ok := policy(ev)
+	if !ok {
+		return
+	}
+	coach.Sid = ev.ConferenceSid
+	coach.Coaching = TwiTrue
+	coach.CallSidToCoach = ev.CallSid
+
+	resp, err := twiClient.RequestWithContext(ctx, coach, false)
+	switch {
+	case resp.Status.Twilio == codeNotFound:
+		if hooks.OnConferenceEnded != nil {
+			hooks.OnConferenceEnded(ev)
+		}
+	case err != nil:
+		if hooks.OnError != nil {
+			hooks.OnError(ev, err)
+		}
+	case hooks.OnCoach != nil:
+		hooks.OnCoach(ev, resp.Participant)
+	}
+}
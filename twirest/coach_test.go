@@ -0,0 +1,105 @@
+package twirest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/seanhagen/twilio/twiml"
+)
+
+func TestAutoCoachHandler(t *testing.T) {
+	const ended = `<RestException><Code>20404</Code><Message>The requested resource ` +
+		`was not found</Message><Status>404</Status></RestException>`
+
+	// the fake twilio accepts coaches for CF123, CF456 has ended
+	var posted []url.Values
+	client, ts := testClient(t, http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			r.ParseForm()
+			posted = append(posted, r.PostForm)
+			if r.URL.Path == "/2010-04-01/Accounts/AC123/Conferences/CF456/Participants" {
+				w.WriteHeader(http.StatusNotFound)
+				w.Write([]byte(xmlHeader + "<TwilioResponse>" + ended + "</TwilioResponse>"))
+				return
+			}
+			w.Write([]byte(xmlHeader + `<TwilioResponse><Participant><ConferenceSid>CF123` +
+				`</ConferenceSid><CallSid>CA999</CallSid><Coaching>true</Coaching>` +
+				`<CallSidToCoach>` + r.PostForm.Get("CallSidToCoach") + `</CallSidToCoach>` +
+				`</Participant></TwilioResponse>`))
+		}))
+	defer ts.Close()
+
+	flagged := map[string]bool{"CA123": true}
+	policy := func(ev twiml.ConferenceEvent) (CreateParticipant, bool) {
+		return CreateParticipant{From: "+15005550006", To: "client:supervisor"},
+			flagged[ev.CallSid]
+	}
+
+	var outcomes []string
+	hooks := CoachHooks{
+		OnCoach: func(ev twiml.ConferenceEvent, coach *ParticipantResponse) {
+			outcomes = append(outcomes, "coach "+coach.CallSid+" for "+coach.CallSidToCoach)
+		},
+		OnConferenceEnded: func(ev twiml.ConferenceEvent) {
+			outcomes = append(outcomes, "ended "+ev.ConferenceSid)
+		},
+		OnError: func(ev twiml.ConferenceEvent, err error) {
+			outcomes = append(outcomes, "error "+err.Error())
+		},
+	}
+	handler := client.AutoCoachHandler(policy, hooks)
+
+	var tests = []struct {
+		Form     url.Values
+		Status   int
+		Outcomes []string
+		Posted   int
+	}{
+		// flagged agent joins
+		{url.Values{"ConferenceSid": {"CF123"}, "StatusCallbackEvent": {"participant-join"},
+			"CallSid": {"CA123"}}, http.StatusNoContent, []string{"coach CA999 for CA123"}, 1},
+		// the coach joining isn't coached
+		{url.Values{"ConferenceSid": {"CF123"}, "StatusCallbackEvent": {"participant-join"},
+			"CallSid": {"CA999"}, "Coaching": {"true"}, "CallSidToCoach": {"CA123"}},
+			http.StatusNoContent, nil, 0},
+		// agent not flagged
+		{url.Values{"ConferenceSid": {"CF123"}, "StatusCallbackEvent": {"participant-join"},
+			"CallSid": {"CA777"}}, http.StatusNoContent, nil, 0},
+		// other events
+		{url.Values{"ConferenceSid": {"CF123"}, "StatusCallbackEvent": {"participant-leave"},
+			"CallSid": {"CA123"}}, http.StatusNoContent, nil, 0},
+		// the conference ended before the coach joined
+		{url.Values{"ConferenceSid": {"CF456"}, "StatusCallbackEvent": {"participant-join"},
+			"CallSid": {"CA123"}}, http.StatusNoContent, []string{"ended CF456"}, 1},
+		{url.Values{"StatusCallbackEvent": {"participant-join"}}, http.StatusBadRequest, nil, 0},
+	}
+
+	for idx, test := range tests {
+		outcomes, posted = nil, nil
+		r := httptest.NewRequest("POST", "/conference", strings.NewReader(test.Form.Encode()))
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+
+		if w.Code != test.Status {
+			t.Errorf("Test %v failed; expected status %v, got %v", idx, test.Status, w.Code)
+		}
+		if strings.Join(outcomes, ",") != strings.Join(test.Outcomes, ",") {
+			t.Errorf("Test %v failed; expected %v, got %v", idx, test.Outcomes, outcomes)
+		}
+		if len(posted) != test.Posted {
+			t.Errorf("Test %v failed; expected %v participant requests, got %v",
+				idx, test.Posted, len(posted))
+			continue
+		}
+		for _, form := range posted {
+			if form.Get("Coaching") != "true" || form.Get("CallSidToCoach") != "CA123" ||
+				form.Get("To") != "client:supervisor" {
+				t.Errorf("Test %v failed; unexpected coach %v", idx, form)
+			}
+		}
+	}
+}
@@ -0,0 +1,175 @@
+package twirest
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Request structs tag their parameters url:"To", like the encoding packages
+// do, and their resources url:"/Messages". The legacy raw tags, the name and
+// '=' such as `To=`, are still encoded the old way for now on structs defined
+// outside the package, their type is logged once as deprecated.
+// CheckStructTags finds the fields to retag.
+
+// Warning is a struct field whose tag the encoder doesn't read as intended
+type Warning struct {
+	Field   string
+	Tag     string
+	Message string
+}
+
+func (w Warning) String() string {
+	return fmt.Sprintf("%s `%s`: %s", w.Field, w.Tag, w.Message)
+}
+
+// CheckStructTags returns a Warning for every field of the request struct
+// type t that needs migrating: fields with a legacy raw tag, and url tags on
+// fields of a type that is never sent, such as a struct or []int. Run it in the tests of the package defining the requests, an
+// empty result means the struct is encoded without the legacy fallback.
+func CheckStructTags(t reflect.Type) []Warning {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return []Warning{{Message: fmt.Sprintf("%v is not a struct", t)}}
+	}
+
+	var warnings []Warning
+	for i := 0; i < t.NumField(); i++ {
+		fld := t.Field(i)
+		if fld.PkgPath != "" || fld.Tag == "" {
+			continue // unexported, resource tags
+		}
+		name, legacy := paramTag(fld.Tag)
+		switch {
+		case legacy:
+			warnings = append(warnings, Warning{Field: fld.Name, Tag: string(fld.Tag),
+				Message: fmt.Sprintf("legacy raw tag, use url:\"%s\"", name)})
+		case name != "" && !formKind(fld.Type):
+			warnings = append(warnings, Warning{Field: fld.Name, Tag: string(fld.Tag),
+				Message: fmt.Sprintf("url tag on a field of type %v, it isn't sent", fld.Type)})
+		}
+	}
+	return warnings
+}
+
+// paramTag returns the parameter name of a field tag and if the tag is a
+// legacy raw one. The name of a tag without a url key is "".
+func paramTag(tag reflect.StructTag) (name string, legacy bool) {
+	if !keyedTag(tag) {
+		return strings.TrimSuffix(string(tag), "="), true
+	}
+	name = tag.Get("url")
+	if i := strings.Index(name, ","); i >= 0 {
+		name = name[:i] // options such as omitempty, empty values aren't sent
+	}
+	if name == "-" {
+		name = ""
+	}
+	return name, false
+}
+
+// keyedTag reports if the tag has the conventional key:"value" structure
+func keyedTag(tag reflect.StructTag) bool {
+	s := string(tag)
+	i := strings.Index(s, `:"`)
+	return i > 0 && !strings.ContainsAny(s[:i], " \"=/")
+}
+
+// timeType is the reflect.Type of time.Time
+var timeType = reflect.TypeOf(time.Time{})
+
+// formKind reports if fields of type t can be encoded: strings, []string,
+// Money, and bools, numbers and times or pointers to them, see typedValue
+func formKind(t reflect.Type) bool {
+	if t == moneyType || t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.String {
+		return true
+	}
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.String, reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16,
+		reflect.Int32, reflect.Int64, reflect.Float32, reflect.Float64:
+		return true
+	}
+	return t == timeType
+}
+
+// tagOption reports if the url tag has the option, such as date in
+// url:"StartDate,date"
+func tagOption(tag reflect.StructTag, option string) bool {
+	opts := strings.Split(tag.Get("url"), ",")
+	for _, opt := range opts[1:] {
+		if opt == option {
+			return true
+		}
+	}
+	return false
+}
+
+// paramPrefix returns the escaped parameter name and '=', the operators of
+// filters such as StartTime<= are escaped with the name
+func paramPrefix(name string) string {
+	return url.QueryEscape(name) + "="
+}
+
+// packageType reports if t is a request struct of this package
+func packageType(t reflect.Type) bool {
+	return t.PkgPath() == moneyType.PkgPath()
+}
+
+// foreignForm reports if reqSt is a struct defined outside the package with
+// fields to encode
+func foreignForm(reqSt interface{}) bool {
+	t := reflect.TypeOf(reqSt)
+	return t != nil && t.Kind() == reflect.Struct && !packageType(t) &&
+		len(formFields(t)) > 0
+}
+
+// foreignRequest reports if t is a request struct defined outside the package,
+// a struct with the resource, path or domain field of the package's own
+func foreignRequest(t reflect.Type) bool {
+	if t.Kind() != reflect.Struct || packageType(t) {
+		return false
+	}
+	for _, name := range []string{"resource", "path", "domain"} {
+		if _, ok := t.FieldByName(name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// legacyLogged holds the types whose legacy tags were logged
+var legacyLogged sync.Map
+
+// logLegacyTags logs once per type that a struct defined outside the package
+// is encoded from legacy raw tags
+func logLegacyTags(reqSt interface{}, logger Logger) {
+	if logger == nil || !foreignForm(reqSt) {
+		return
+	}
+	t := reflect.TypeOf(reqSt)
+	if !legacyFields(t) {
+		return
+	}
+	if _, logged := legacyLogged.LoadOrStore(t, true); !logged {
+		logger.Printf("twirest: deprecated raw struct tags on %v, tag its fields "+
+			"url:\"Name\" (see CheckStructTags)", t)
+	}
+}
+
+// legacyFields reports if any field of t is encoded from a legacy raw tag
+func legacyFields(t reflect.Type) bool {
+	for _, f := range formFields(t) {
+		if f.legacy {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,108 @@
+package twirest
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// legacyMessages is a request struct tagged the way the package's own are,
+// with raw tags. The aliased struct types have no package, like the types
+// of another package they're not the package's own.
+type legacyMessages = struct {
+	resource string `/Messages.json`
+	To       string `To=`
+	DateSent string `DateSent>=`
+	Sent     []string
+}
+
+// taggedMessages is legacyMessages with url tags
+type taggedMessages = struct {
+	resource string `/Messages.json`
+	To       string `url:"To"`
+	DateSent string `url:"DateSent>,omitempty"`
+	Skipped  string `url:"-"`
+	Page     []int  `url:"Page"`
+}
+
+func TestLegacyTags(t *testing.T) {
+	var tests = []struct {
+		Req      interface{}
+		Resource string
+		Expect   string
+		Logged   bool
+	}{
+		{legacyMessages{To: "+15005550006", DateSent: "2020-01-02"}, "/Messages.json",
+			"To=%2B15005550006&DateSent%3E=2020-01-02", true},
+		{taggedMessages{To: "+15005550006", DateSent: "2020-01-02", Skipped: "x", Page: []int{2}},
+			"/Messages.json", "To=%2B15005550006&DateSent%3E=2020-01-02", false},
+		{Messages{To: "+15005550006"}, "/Messages", "To=%2B15005550006", false},
+	}
+
+	for idx, test := range tests {
+		legacyLogged.Delete(reflect.TypeOf(test.Req))
+
+		if got := Encode(test.Req); got != test.Expect {
+			t.Errorf("Test %v failed; expected %v, got %v", idx, test.Expect, got)
+		}
+
+		var logged []string
+		rt := &urlTransport{}
+		client, err := NewClient("AC123", "token", WithHTTPClient(&http.Client{Transport: rt}),
+			WithLogger(LoggerFunc(func(format string, args ...interface{}) {
+				logged = append(logged, fmt.Sprintf(format, args...))
+			})))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := 0; i < 2; i++ {
+			if _, err := client.Request(test.Req, false); err != nil {
+				t.Errorf("Test %v failed; %v", idx, err)
+			}
+		}
+		expectURL := "https://api.twilio.com/2010-04-01/Accounts/AC123" + test.Resource +
+			"?" + test.Expect
+		if len(rt.urls) != 2 || rt.urls[0] != expectURL {
+			t.Errorf("Test %v failed; expected %v, got %v", idx, expectURL, rt.urls)
+		}
+
+		var notices int
+		for _, line := range logged {
+			if strings.Contains(line, "deprecated raw struct tags") {
+				notices++
+			}
+		}
+		if expect := map[bool]int{true: 1}[test.Logged]; notices != expect {
+			t.Errorf("Test %v failed; expected %v deprecation notices, got %v: %v",
+				idx, expect, notices, logged)
+		}
+	}
+}
+
+func TestCheckStructTags(t *testing.T) {
+	var tests = []struct {
+		Type   reflect.Type
+		Fields []string
+	}{
+		{reflect.TypeOf(legacyMessages{}), []string{"To", "DateSent"}},
+		{reflect.TypeOf(&legacyMessages{}), []string{"To", "DateSent"}},
+		{reflect.TypeOf(taggedMessages{}), []string{"Page"}},
+		{reflect.TypeOf(struct {
+			To string `url:"To" json:"to"`
+		}{}), nil},
+		{reflect.TypeOf(""), []string{""}},
+	}
+
+	for idx, test := range tests {
+		var fields []string
+		for _, w := range CheckStructTags(test.Type) {
+			fields = append(fields, w.Field)
+		}
+		if !reflect.DeepEqual(fields, test.Fields) {
+			t.Errorf("Test %v failed; expected warnings for %v, got %v", idx,
+				test.Fields, CheckStructTags(test.Type))
+		}
+	}
+}
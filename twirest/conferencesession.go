@@ -0,0 +1,187 @@
+package twirest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/seanhagen/twilio/twiml"
+)
+
+// ConferenceSession addresses the participants of a conference by the labels
+// they were given in TwiML. Join returns the Conference noun of a participant
+// with its label and records the label, the REST requests of the session only
+// take labels it recorded. The conference is found by its FriendlyName, the
+// name of the noun. It has no Sid until its first participant joined, so it
+// is looked for again with backoff until it started.
+type ConferenceSession struct {
+	Name string
+	// Attempts is the number of times the conference is looked for before
+	// giving up, default 5
+	Attempts int
+	// Delay is the wait after the first attempt, doubled after each, default
+	// 1 second
+	Delay time.Duration
+
+	client *TwilioClient
+	mu     sync.Mutex
+	sid    string
+	labels map[string]bool
+}
+
+// NewConferenceSession returns the session of the conference named
+// friendlyName
+func (twiClient *TwilioClient) NewConferenceSession(friendlyName string) *ConferenceSession {
+	return &ConferenceSession{
+		Name:   friendlyName,
+		client: twiClient,
+		labels: make(map[string]bool),
+	}
+}
+
+// Join returns the noun c joining the conference of the session as the
+// participant label, recording the label
+func (s *ConferenceSession) Join(label string, c twiml.Conference) twiml.Conference {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.labels[label] = true
+	c.Name = s.Name
+	c.ParticipantLabel = label
+	return c
+}
+
+// Labels returns the labels recorded, sorted
+func (s *ConferenceSession) Labels() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	labels := make([]string, 0, len(s.labels))
+	for label := range s.labels {
+		labels = append(labels, label)
+	}
+	sort.Strings(labels)
+	return labels
+}
+
+// Sid returns the Sid of the conference, looking for the latest conference of
+// the name that didn't complete until it is found or the attempts are used up
+func (s *ConferenceSession) Sid(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	sid := s.sid
+	s.mu.Unlock()
+	if sid != "" {
+		return sid, nil
+	}
+
+	attempts := s.Attempts
+	if attempts <= 0 {
+		attempts = 5
+	}
+	delay := s.Delay
+	if delay <= 0 {
+		delay = time.Second
+	}
+	clock := s.client.timeSource()
+
+	for attempt := 1; ; attempt++ {
+		resp, err := s.client.RequestWithContext(ctx, Conferences{FriendlyName: s.Name}, false)
+		if err != nil {
+			return "", err
+		}
+		if resp.Conferences != nil {
+			for _, c := range resp.Conferences.Conference {
+				if c.Status != TwiCompleted {
+					s.mu.Lock()
+					s.sid = c.Sid
+					s.mu.Unlock()
+					return c.Sid, nil
+				}
+			}
+		}
+		if attempt == attempts {
+			return "", fmt.Errorf("conference '%s' not started after %d attempts",
+				s.Name, attempts)
+		}
+
+		select {
+		case <-clock.After(delay):
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
+		delay *= 2
+	}
+}
+
+// MuteByLabel mutes or unmutes the participant label
+func (s *ConferenceSession) MuteByLabel(ctx context.Context, label string,
+	muted bool) (*ParticipantResponse, error) {
+
+	return s.update(ctx, label, func(req *UpdateParticipant) {
+		req.Muted = fmt.Sprint(muted)
+	})
+}
+
+// HoldByLabel puts the participant label on hold, playing holdUrl if it
+// isn't empty, or takes it off hold
+func (s *ConferenceSession) HoldByLabel(ctx context.Context, label string, hold bool,
+	holdUrl string) (*ParticipantResponse, error) {
+
+	return s.update(ctx, label, func(req *UpdateParticipant) {
+		req.Hold = TwiFalse
+		if hold {
+			req.Hold = TwiTrue
+			req.HoldUrl = holdUrl
+		}
+	})
+}
+
+// KickByLabel removes the participant label from the conference
+func (s *ConferenceSession) KickByLabel(ctx context.Context, label string) error {
+	sid, err := s.participant(ctx, label)
+	if err != nil {
+		return err
+	}
+	_, err = s.client.RequestWithContext(ctx, DeleteParticipant{Sid: sid, CallSid: label}, false)
+	return s.checkEnded(sid, err)
+}
+
+// update sends the update set by set to the participant label
+func (s *ConferenceSession) update(ctx context.Context, label string,
+	set func(*UpdateParticipant)) (*ParticipantResponse, error) {
+
+	sid, err := s.participant(ctx, label)
+	if err != nil {
+		return nil, err
+	}
+	req := UpdateParticipant{Sid: sid, CallSid: label}
+	set(&req)
+	resp, err := s.client.RequestWithContext(ctx, req, false)
+	return resp.Participant, s.checkEnded(sid, err)
+}
+
+// participant checks that label was recorded and returns the Sid of the
+// conference
+func (s *ConferenceSession) participant(ctx context.Context, label string) (string, error) {
+	s.mu.Lock()
+	known := s.labels[label]
+	s.mu.Unlock()
+	if !known {
+		return "", fmt.Errorf("non valid participant label: '%s'", label)
+	}
+	return s.Sid(ctx)
+}
+
+// checkEnded forgets the conference sid if it's not found, the conference
+// ended and the name may be used by a new one
+func (s *ConferenceSession) checkEnded(sid string, err error) error {
+	if errors.Is(err, ErrNotFound) {
+		s.mu.Lock()
+		if s.sid == sid {
+			s.sid = ""
+		}
+		s.mu.Unlock()
+	}
+	return err
+}
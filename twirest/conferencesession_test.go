@@ -0,0 +1,118 @@
+package twirest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/seanhagen/twilio/twiml"
+)
+
+// conferenceServer is a scripted conference: it isn't found by name until
+// the lookup startAfter, it then ends once the participant ender is removed
+type conferenceServer struct {
+	startAfter int
+	lookups    int
+	ended      bool
+	requests   []string
+}
+
+func (s *conferenceServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	r.ParseForm()
+	path := strings.TrimPrefix(r.URL.Path, "/2010-04-01/Accounts/AC123")
+	s.requests = append(s.requests, r.Method+" "+path+" "+r.Form.Encode())
+
+	reply := func(body string) {
+		w.Write([]byte(xmlHeader + "<TwilioResponse>" + body + "</TwilioResponse>"))
+	}
+	switch {
+	case path == "/Conferences":
+		s.lookups++
+		conf := ""
+		if s.lookups > s.startAfter {
+			status := "in-progress"
+			if s.ended {
+				status = "completed"
+			}
+			conf = "<Conference><Sid>CF1</Sid><FriendlyName>" + r.Form.Get("FriendlyName") +
+				"</FriendlyName><Status>" + status + "</Status></Conference>"
+		}
+		reply("<Conferences>" + conf + "</Conferences>")
+	case s.ended:
+		w.WriteHeader(http.StatusNotFound)
+		reply("<RestException><Code>20404</Code><Status>404</Status></RestException>")
+	case r.Method == "DELETE":
+		s.ended = strings.HasSuffix(path, "/ender")
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		label := path[strings.LastIndex(path, "/")+1:]
+		reply("<Participant><ConferenceSid>CF1</ConferenceSid><Label>" + label +
+			"</Label><Muted>" + r.Form.Get("Muted") + "</Muted><Hold>" + r.Form.Get("Hold") +
+			"</Hold></Participant>")
+	}
+}
+
+func TestConferenceSession(t *testing.T) {
+	srv := &conferenceServer{startAfter: 2}
+	clock := newFakeClock()
+	client, ts := testClient(t, srv, WithClock(clock))
+	defer ts.Close()
+	ctx := context.Background()
+
+	session := client.NewConferenceSession("support 42")
+	noun := session.Join("agent one", twiml.Conference{Beep: "false"})
+	session.Join("ender", twiml.Conference{})
+	if noun.Name != "support 42" || noun.ParticipantLabel != "agent one" || noun.Beep != "false" {
+		t.Errorf("unexpected noun %+v", noun)
+	}
+	if labels := session.Labels(); !reflect.DeepEqual(labels, []string{"agent one", "ender"}) {
+		t.Errorf("unexpected labels %v", labels)
+	}
+
+	start := clock.Now()
+	p, err := session.MuteByLabel(ctx, "agent one", true)
+	if err != nil || p.Label != "agent one" || p.Muted != "true" {
+		t.Fatalf("expected the participant muted, got %+v %v", p, err)
+	}
+	// waited 1s then 2s for the conference to start
+	if waited := clock.Now().Sub(start); waited != 3*time.Second {
+		t.Errorf("expected to wait 3s for the conference, waited %v", waited)
+	}
+	if p, err = session.HoldByLabel(ctx, "agent one", true, "/hold"); err != nil || p.Hold != "true" {
+		t.Errorf("expected the participant on hold, got %+v %v", p, err)
+	}
+	if _, err = session.MuteByLabel(ctx, "stranger", true); err == nil {
+		t.Errorf("expected a label not joined to fail")
+	}
+	if err = session.KickByLabel(ctx, "ender"); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	if err = session.KickByLabel(ctx, "agent one"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected the ended conference not found, got %v", err)
+	}
+	// the ended conference is forgotten, no other started
+	session.Attempts = 2
+	if _, err = session.Sid(ctx); err == nil {
+		t.Errorf("expected the ended conference not found")
+	}
+
+	expect := []string{
+		"GET /Conferences FriendlyName=support+42",
+		"GET /Conferences FriendlyName=support+42",
+		"GET /Conferences FriendlyName=support+42",
+		"POST /Conferences/CF1/Participants/agent one Muted=true",
+		"POST /Conferences/CF1/Participants/agent one Hold=true&HoldUrl=%2Fhold",
+		"DELETE /Conferences/CF1/Participants/ender ",
+		"DELETE /Conferences/CF1/Participants/agent one ",
+		"GET /Conferences FriendlyName=support+42",
+		"GET /Conferences FriendlyName=support+42",
+	}
+	if !reflect.DeepEqual(srv.requests, expect) {
+		t.Errorf("expected the requests\n%v\ngot\n%v", strings.Join(expect, "\n"),
+			strings.Join(srv.requests, "\n"))
+	}
+}
@@ -0,0 +1,28 @@
+package twirest
+
+import "fmt"
+
+// codeAccessDenied is the exception code of requests on a connected account
+// that deauthorized the connect app or didn't grant it the permission needed.
+// Wrong credentials of the connect app owner are reported as 20003 instead.
+const codeAccessDenied = 20006
+
+// ErrConnectAccessDenied is returned by clients created WithConnectedAccount
+// when the connected account denied access to its resources
+type ErrConnectAccessDenied struct {
+	AccountSid string
+	Exception  *ExceptionResponse
+}
+
+func (e *ErrConnectAccessDenied) Error() string {
+	return fmt.Sprintf("connected account denied access: '%s' (%v)",
+		e.AccountSid, e.Exception)
+}
+
+// Unwrap returns the twilio exception as a *TwilioError
+func (e *ErrConnectAccessDenied) Unwrap() error {
+	if e.Exception == nil {
+		return nil
+	}
+	return newTwilioError(e.Exception)
+}
@@ -0,0 +1,50 @@
+package twirest
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"testing"
+)
+
+func TestConnectedAccount(t *testing.T) {
+	var tests = []struct {
+		Code   int
+		Denied bool
+	}{
+		{20003, false},
+		{20006, true},
+	}
+
+	for idx, test := range tests {
+		code := test.Code
+		client, ts := testClient(t, http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				user, pass, _ := r.BasicAuth()
+				if user != "AC123" || pass != "token" {
+					t.Errorf("Test %v failed; unexpected auth %v:%v", idx, user, pass)
+				}
+				if r.URL.Path != "/2010-04-01/Accounts/AC456/Calls/CA123" {
+					t.Errorf("Test %v failed; unexpected path %v", idx, r.URL.Path)
+				}
+				w.WriteHeader(http.StatusUnauthorized)
+				w.Write([]byte(xmlHeader + `<TwilioResponse><RestException>` +
+					`<Code>` + strconv.Itoa(code) + `</Code><Message>denied</Message>` +
+					`<Status>401</Status></RestException></TwilioResponse>`))
+			}), WithConnectedAccount("AC456"))
+
+		_, err := client.Request(Call{Sid: "CA123"}, false)
+		var denied *ErrConnectAccessDenied
+		if errors.As(err, &denied) != test.Denied {
+			t.Errorf("Test %v failed; got %v", idx, err)
+		}
+		if test.Denied && denied.AccountSid != "AC456" {
+			t.Errorf("Test %v failed; got account %v", idx, denied.AccountSid)
+		}
+		var exception *ExceptionResponse
+		if !errors.As(err, &exception) || exception.Code != code {
+			t.Errorf("Test %v failed; expected exception %v, got %v", idx, code, err)
+		}
+		ts.Close()
+	}
+}
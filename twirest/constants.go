@@ -17,6 +17,25 @@ const (
 	TwiNoAnswer   = "no-answer"
 )
 
+// Recording status strings, besides in-progress, completed and failed
+const (
+	TwiPaused     = "paused"
+	TwiStopped    = "stopped"
+	TwiProcessing = "processing"
+	TwiAbsent     = "absent"
+)
+
+// AnsweredBy results of answering machine detection
+const (
+	TwiHuman             = "human"
+	TwiMachineStart      = "machine_start"
+	TwiMachineEndBeep    = "machine_end_beep"
+	TwiMachineEndSilence = "machine_end_silence"
+	TwiMachineEndOther   = "machine_end_other"
+	TwiFax               = "fax"
+	TwiUnknown           = "unknown"
+)
+
 // UsageRecords categories
 const (
 	TwiCalls                   = "calls"
@@ -56,9 +75,23 @@ const (
 	TwiLastMonth = "LastMonth"
 )
 
+// Recording sources
+const (
+	TwiSourceDialVerb                    = "DialVerb"
+	TwiSourceRecordVerb                  = "RecordVerb"
+	TwiSourceConference                  = "Conference"
+	TwiSourceOutboundAPI                 = "OutboundAPI"
+	TwiSourceTrunking                    = "Trunking"
+	TwiSourceStartCallRecordingAPI       = "StartCallRecordingAPI"
+	TwiSourceStartConferenceRecordingAPI = "StartConferenceRecordingAPI"
+)
+
 // Account status strings
 const (
 	TwiClosed    = "closed"
 	TwiSuspended = "suspended"
 	TwiActive    = "active"
 )
+
+// ScheduleType of a message sent at SendAt
+const TwiScheduleFixed = "fixed"
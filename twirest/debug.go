@@ -0,0 +1,65 @@
+package twirest
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httputil"
+	"regexp"
+	"sync"
+)
+
+// debugWriter is where the requests and responses of a client are dumped
+type debugWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// WithDebugWriter dumps every http request of the client, retries included,
+// and its response to w as they go over the wire: the method, url, headers
+// and body. The credentials are redacted, as are the secret parameters such
+// as SipAuthPassword. Dumps hold personal data, only use it to debug.
+func WithDebugWriter(w io.Writer) ClientOption {
+	return ClientOption{apply: func(c *TwilioClient) {
+		c.debug = &debugWriter{w: w}
+	}}
+}
+
+// authHeader and secretParams match the values that are redacted in dumps
+var (
+	authHeader   = regexp.MustCompile(`(?m)^Authorization: [^\r\n]*`)
+	secretParams = regexp.MustCompile(`\b(AuthToken|SipAuthPassword)=[^&\s]*`)
+)
+
+// redact replaces the credentials and secret parameters in dump
+func (twiClient *TwilioClient) redact(dump []byte) []byte {
+	dump = authHeader.ReplaceAll(dump, []byte("Authorization: Basic [REDACTED]"))
+	dump = secretParams.ReplaceAll(dump, []byte("$1=[REDACTED]"))
+	if twiClient.authToken != "" {
+		dump = bytes.Replace(dump, []byte(twiClient.authToken), []byte("[REDACTED]"), -1)
+	}
+	return dump
+}
+
+// dump writes the request to the debug writer, or the response or error of
+// the request if either is set
+func (twiClient *TwilioClient) dump(httpReq *http.Request, resp *http.Response, err error) {
+	var dump []byte
+	switch {
+	case err != nil:
+		dump = []byte(fmt.Sprintf("error: %v\n", err))
+	case resp != nil:
+		dump, err = httputil.DumpResponse(resp, true)
+	default:
+		dump, err = httputil.DumpRequestOut(httpReq, true)
+	}
+	if err != nil {
+		dump = []byte(fmt.Sprintf("dump failed: %v\n", err))
+	}
+
+	d := twiClient.debug
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.w.Write(append(twiClient.redact(dump), "\n\n"...))
+}
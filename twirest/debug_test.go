@@ -0,0 +1,74 @@
+package twirest
+
+import (
+	"bytes"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestWithDebugWriter(t *testing.T) {
+	const token = "0123456789abcdef0123456789abcdef"
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(xmlHeader + "<TwilioResponse><Call><Sid>CA1</Sid></Call></TwilioResponse>"))
+	}))
+	defer ts.Close()
+	target, _ := url.Parse(ts.URL)
+
+	var dump bytes.Buffer
+	client, err := NewClient("AC123", token, WithDebugWriter(&dump),
+		WithHTTPClient(&http.Client{Transport: rerouteTransport{target}}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = client.Request(MakeCall{From: "+15005550006", To: "sip:agent@example.com",
+		Url: "https://example.com/twiml", SipAuthUsername: "agent",
+		SipAuthPassword: "hunter2"}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := dump.String()
+	for _, expect := range []string{
+		"POST /2010-04-01/Accounts/AC123/Calls HTTP/1.1",
+		"Host: api.twilio.com",
+		"Authorization: Basic [REDACTED]\r\n",
+		"Content-Type: application/x-www-form-urlencoded",
+		"To=sip%3Aagent%40example.com",
+		"SipAuthUsername=agent&SipAuthPassword=[REDACTED]",
+		"HTTP/1.1 200 OK",
+		"<Sid>CA1</Sid>",
+	} {
+		if !strings.Contains(got, expect) {
+			t.Errorf("expected %#v in the dump, got %v", expect, got)
+		}
+	}
+	creds := base64.StdEncoding.EncodeToString([]byte("AC123:" + token))
+	for _, secret := range []string{token, creds, "hunter2"} {
+		if strings.Contains(got, secret) {
+			t.Errorf("secret %v in the dump %v", secret, got)
+		}
+	}
+}
+
+func TestRedactDump(t *testing.T) {
+	client := &TwilioClient{authToken: "s3cret"}
+	var tests = []struct {
+		Dump   string
+		Expect string
+	}{
+		{"GET /x?AuthToken=abc&To=1 HTTP/1.1\r\nAuthorization: Bearer xyz\r\n",
+			"GET /x?AuthToken=[REDACTED]&To=1 HTTP/1.1\r\nAuthorization: Basic [REDACTED]\r\n"},
+		{"Body=my s3cret", "Body=my [REDACTED]"},
+		{"MyAuthToken=abc", "MyAuthToken=abc"},
+	}
+
+	for idx, test := range tests {
+		if got := string(client.redact([]byte(test.Dump))); got != test.Expect {
+			t.Errorf("Test %v failed; expected %#v, got %#v", idx, test.Expect, got)
+		}
+	}
+}
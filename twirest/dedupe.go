@@ -0,0 +1,207 @@
+package twirest
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrDuplicateSuppressed is returned by clients created WithDedupe when a
+// SendMessage matches a message sent within the dedupe window. MessageSid is
+// the Sid of the earlier message, empty if it's still being sent.
+type ErrDuplicateSuppressed struct {
+	To         string
+	MessageSid string
+}
+
+func (e *ErrDuplicateSuppressed) Error() string {
+	if e.MessageSid != "" {
+		return fmt.Sprintf("duplicate message suppressed: '%s' (sent as '%s')",
+			e.To, e.MessageSid)
+	}
+	return fmt.Sprintf("duplicate message suppressed: '%s'", e.To)
+}
+
+// DedupeStore keeps the keys of recently sent messages. Implementations
+// shared between processes, such as Redis with SET NX PX, must claim keys
+// atomically.
+type DedupeStore interface {
+	// Claim stores key for window if it isn't stored yet. Otherwise claimed
+	// is false and sid is the message Sid stored with key, if any.
+	Claim(ctx context.Context, key string, window time.Duration) (
+		sid string, claimed bool, err error)
+	// SetSid stores the Sid of the message sent for a claimed key
+	SetSid(ctx context.Context, key, sid string) error
+	// Release removes a claimed key when twilio rejected the message
+	Release(ctx context.Context, key string) error
+}
+
+// WithDedupe makes the client suppress SendMessage requests with the same
+// To, Body and MediaUrl as a message sent within window, returning an
+// *ErrDuplicateSuppressed instead. Use SkipDedupe to send a message anyway.
+// A MemoryDedupeStore without a clock of its own keeps time with the client.
+func WithDedupe(window time.Duration, store DedupeStore) ClientOption {
+	return ClientOption{apply: func(c *TwilioClient) {
+		if s, ok := store.(*MemoryDedupeStore); ok {
+			s.mu.Lock()
+			if s.clock == nil {
+				s.clock = clientClock{c}
+			}
+			s.mu.Unlock()
+		}
+		c.dedupe = &dedupeGuard{window: window, store: store}
+	}}
+}
+
+type skipDedupeKey struct{}
+
+// SkipDedupe returns a context that makes RequestWithContext send messages
+// without checking for duplicates
+func SkipDedupe(ctx context.Context) context.Context {
+	return context.WithValue(ctx, skipDedupeKey{}, true)
+}
+
+// dedupeGuard checks SendMessage requests against a DedupeStore
+type dedupeGuard struct {
+	window time.Duration
+	store  DedupeStore
+}
+
+// claim claims the key of a SendMessage request, an empty key is returned
+// for requests that aren't checked
+func (d *dedupeGuard) claim(ctx context.Context, reqStruct interface{}) (string, error) {
+	msg, ok := reqStruct.(SendMessage)
+	if d == nil || !ok || ctx.Value(skipDedupeKey{}) != nil {
+		return "", nil
+	}
+
+	key := dedupeKey(msg)
+	sid, claimed, err := d.store.Claim(ctx, key, d.window)
+	if err != nil {
+		return "", err
+	}
+	if !claimed {
+		return "", &ErrDuplicateSuppressed{To: msg.To, MessageSid: sid}
+	}
+	return key, nil
+}
+
+// done records the Sid of a sent message, or releases the key if twilio
+// rejected the message so it can be retried. After a transport error, a
+// timeout or a 5xx the message may have been sent, the key is held until the
+// window ends. Store errors are ignored, the worst case is a key that expires
+// without Sid or is held until the window ends.
+func (d *dedupeGuard) done(ctx context.Context, key string,
+	twir TwilioResponse, err error) {
+
+	if key == "" {
+		return
+	}
+	if err == nil && twir.OK() && twir.Message != nil {
+		d.store.SetSid(ctx, key, twir.Message.Sid)
+		return
+	}
+	if rejected(err) {
+		d.store.Release(ctx, key)
+	}
+}
+
+// rejected reports if err is twilio refusing a request with a 4xx, the
+// request wasn't carried out
+func rejected(err error) bool {
+	var te *TwilioError
+	return errors.As(err, &te) && te.Status >= 400 && te.Status < 500
+}
+
+// dedupeKey hashes the fields that make two messages the same
+func dedupeKey(msg SendMessage) string {
+	h := sha256.New()
+	for _, s := range []string{msg.To, msg.Text, msg.MediaUrl} {
+		h.Write([]byte(s))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// MemoryDedupeStore is a DedupeStore for a single process, expired keys are
+// evicted as new ones are claimed
+type MemoryDedupeStore struct {
+	mu        sync.Mutex
+	clock     Clock
+	entries   map[string]dedupeEntry
+	lastSweep time.Time
+}
+
+type dedupeEntry struct {
+	sid     string
+	expires time.Time
+}
+
+// NewMemoryDedupeStore returns an empty MemoryDedupeStore
+func NewMemoryDedupeStore() *MemoryDedupeStore {
+	return &MemoryDedupeStore{entries: make(map[string]dedupeEntry)}
+}
+
+func (s *MemoryDedupeStore) Claim(ctx context.Context, key string,
+	window time.Duration) (string, bool, error) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	clock := s.clock
+	if clock == nil {
+		clock = realClock{}
+	}
+	now := clock.Now()
+	s.sweep(now, window)
+	if e, ok := s.entries[key]; ok && now.Before(e.expires) {
+		return e.sid, false, nil
+	}
+	s.entries[key] = dedupeEntry{expires: now.Add(window)}
+	return "", true, nil
+}
+
+func (s *MemoryDedupeStore) SetSid(ctx context.Context, key, sid string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if e, ok := s.entries[key]; ok {
+		e.sid = sid
+		s.entries[key] = e
+	}
+	return nil
+}
+
+func (s *MemoryDedupeStore) Release(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.entries, key)
+	return nil
+}
+
+// Len returns the number of keys stored, including expired ones not yet
+// evicted
+func (s *MemoryDedupeStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.entries)
+}
+
+// sweep evicts expired keys, at most once per window
+func (s *MemoryDedupeStore) sweep(now time.Time, window time.Duration) {
+	if now.Sub(s.lastSweep) < window {
+		return
+	}
+	for key, e := range s.entries {
+		if !now.Before(e.expires) {
+			delete(s.entries, key)
+		}
+	}
+	s.lastSweep = now
+}
@@ -0,0 +1,132 @@
+package twirest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestDedupe(t *testing.T) {
+	sent := 0
+	status := 0
+	client, ts := testClient(t, http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			if status != 0 {
+				w.WriteHeader(status)
+				fmt.Fprintf(w, xmlHeader+`<TwilioResponse><RestException>`+
+					`<Code>20%d</Code><Status>%d</Status></RestException></TwilioResponse>`,
+					status, status)
+				return
+			}
+			sent++
+			fmt.Fprintf(w, xmlHeader+`<TwilioResponse><Message><Sid>SM%d</Sid>`+
+				`</Message></TwilioResponse>`, sent)
+		}))
+	defer ts.Close()
+
+	clock := newFakeClock()
+	store := NewMemoryDedupeStore()
+	store.clock = clock
+	WithDedupe(time.Hour, store).apply(client)
+
+	ctx := context.Background()
+	msg := SendMessage{To: "+15005550006", From: "+15005550001", Text: "Reminder"}
+
+	var tests = []struct {
+		Name    string
+		Req     interface{}
+		Ctx     context.Context
+		Status  int // of the response, 0 to send
+		Advance time.Duration
+		Dup     bool   // suppressed as a duplicate
+		DupSid  string // Sid of the suppressed duplicate's original
+		Sent    int
+	}{
+		{"first send", msg, ctx, 0, 0, false, "", 1},
+		{"duplicate", msg, ctx, 0, 0, true, "SM1", 1},
+		{"other body", SendMessage{To: msg.To, From: msg.From, Text: "Other"}, ctx, 0, 0, false, "", 2},
+		{"other media", SendMessage{To: msg.To, From: msg.From, Text: msg.Text,
+			MediaUrl: "http://x/y.png"}, ctx, 0, 0, false, "", 3},
+		{"skipped", msg, SkipDedupe(ctx), 0, 0, false, "", 4},
+		{"other request", Messages{To: msg.To}, ctx, 0, 0, false, "", 5},
+		{"after window", msg, ctx, 0, time.Hour, false, "", 6},
+		{"rejected", SendMessage{To: "+15005550009", From: msg.From, Text: "x"}, ctx, 400, 0, false, "", 6},
+		{"retry of rejected", SendMessage{To: "+15005550009", From: msg.From, Text: "x"}, ctx, 0, 0, false, "", 7},
+		// twilio may have sent it, held until the window ends
+		{"server error", SendMessage{To: "+15005550009", From: msg.From, Text: "y"}, ctx, 500, 0, false, "", 7},
+		{"retry of server error", SendMessage{To: "+15005550009", From: msg.From, Text: "y"}, ctx, 0, 0, true, "", 7},
+		{"retry after window", SendMessage{To: "+15005550009", From: msg.From, Text: "y"}, ctx, 0, time.Hour, false, "", 8},
+	}
+
+	for idx, test := range tests {
+		clock.Advance(test.Advance)
+		status = test.Status
+
+		_, err := client.RequestWithContext(test.Ctx, test.Req, false)
+		var dup *ErrDuplicateSuppressed
+		if errors.As(err, &dup) != test.Dup || (dup != nil && dup.MessageSid != test.DupSid) {
+			t.Errorf("Test %v (%v) failed; got %v", idx, test.Name, err)
+		}
+		if sent != test.Sent {
+			t.Errorf("Test %v (%v) failed; expected %v sent, got %v",
+				idx, test.Name, test.Sent, sent)
+		}
+	}
+}
+
+func TestDedupeTimeoutAfterAccept(t *testing.T) {
+	accepted := make(chan struct{}, 1)
+	release := make(chan struct{})
+	sent := 0
+	client, ts := testClient(t, http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			sent++
+			select {
+			case accepted <- struct{}{}:
+			default:
+			}
+			// twilio took the message, the response doesn't arrive in time
+			<-release
+		}))
+	defer ts.Close()
+	defer close(release)
+	WithDedupe(time.Hour, NewMemoryDedupeStore()).apply(client)
+
+	msg := SendMessage{To: "+15005550006", From: "+15005550001", Text: "Reminder"}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := client.RequestWithContext(ctx, msg, false); err == nil {
+		t.Fatalf("Expected the request to time out")
+	}
+	<-accepted
+
+	_, err := client.RequestWithContext(context.Background(), msg, false)
+	var dup *ErrDuplicateSuppressed
+	if !errors.As(err, &dup) {
+		t.Errorf("Expected the retry to be suppressed, got %v", err)
+	}
+	if sent != 1 {
+		t.Errorf("Expected 1 sent, got %v", sent)
+	}
+}
+
+func TestMemoryDedupeStoreEviction(t *testing.T) {
+	clock := newFakeClock()
+	store := NewMemoryDedupeStore()
+	store.clock = clock
+	ctx := context.Background()
+
+	for i := 0; i < 10; i++ {
+		store.Claim(ctx, fmt.Sprint(i), time.Minute)
+	}
+	clock.Advance(2 * time.Minute)
+	if _, claimed, _ := store.Claim(ctx, "new", time.Minute); !claimed {
+		t.Fatalf("expected claim")
+	}
+	if n := store.Len(); n != 1 {
+		t.Errorf("expected expired keys evicted, %v left", n)
+	}
+}
@@ -0,0 +1,145 @@
+package twirest
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// WithDialContext makes the client open its connections with dial, for
+// example to pin twilio's addresses, tune dialing or go through a SOCKS
+// proxy. The client gets its own copy of the transport so clients sharing a
+// transport aren't affected.
+func WithDialContext(dial func(ctx context.Context, network, addr string) (
+	net.Conn, error)) ClientOption {
+
+	return ClientOption{apply: func(c *TwilioClient) {
+		c.ownTransport().DialContext = dial
+	}}
+}
+
+// ownTransport gives the client its own copy of its transport and returns it.
+// The circuit breaker of WithCircuitBreaker stays around the copy, and the
+// rest of the http client, such as its Jar, is kept. A transport given with
+// WithHTTPClient that isn't a *http.Transport can't be copied, the option
+// fails.
+func (twiClient *TwilioClient) ownTransport() *http.Transport {
+	base, breaker := twiClient.transport()
+	tr, ok := base.(*http.Transport)
+	if !ok {
+		if base != nil {
+			twiClient.optErr = fmt.Errorf("non valid transport: %T, the option needs a "+
+				"*http.Transport", base)
+			return &http.Transport{}
+		}
+		tr = http.DefaultTransport.(*http.Transport)
+	}
+	tr = tr.Clone()
+	hc := *twiClient.httpclient
+	hc.Transport = tr
+	if breaker != nil {
+		hc.Transport = breaker.around(tr)
+	}
+	twiClient.httpclient = &hc
+	return tr
+}
+
+// transport returns the transport of the client under its circuit breaker,
+// and the breaker, nil if it has none
+func (twiClient *TwilioClient) transport() (http.RoundTripper, *circuitTransport) {
+	if ct, ok := twiClient.httpclient.Transport.(*circuitTransport); ok {
+		return ct.base, ct
+	}
+	return twiClient.httpclient.Transport, nil
+}
+
+// Resolver looks up the addresses of a host, *net.Resolver implements it
+type Resolver interface {
+	LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error)
+}
+
+// ErrAddressNotAllowed is returned by CIDRDialer when none of the addresses
+// a host resolves to are in the allowed ranges
+type ErrAddressNotAllowed struct {
+	Host string
+	IPs  []net.IP
+}
+
+func (e *ErrAddressNotAllowed) Error() string {
+	ips := make([]string, len(e.IPs))
+	for i, ip := range e.IPs {
+		ips[i] = ip.String()
+	}
+	return fmt.Sprintf("%s resolved to %s, not in allowed ranges",
+		e.Host, strings.Join(ips, ", "))
+}
+
+// CIDRDialer only connects to addresses in a list of allowed networks. Use
+// its DialContext with WithDialContext where egress is restricted to
+// twilio's published ranges, so a DNS change fails at once with a
+// descriptive error instead of timing out at the firewall.
+type CIDRDialer struct {
+	Resolver Resolver
+	Dialer   *net.Dialer
+	allowed  []*net.IPNet
+}
+
+// NewCIDRDialer creates a dialer allowing the networks in CIDR notation,
+// such as "54.172.60.0/23"
+func NewCIDRDialer(cidrs []string) (*CIDRDialer, error) {
+	d := &CIDRDialer{
+		Resolver: net.DefaultResolver,
+		Dialer:   &net.Dialer{},
+	}
+	for _, cidr := range cidrs {
+		_, n, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, err
+		}
+		d.allowed = append(d.allowed, n)
+	}
+	return d, nil
+}
+
+// DialContext resolves the host of addr and connects to the first of its
+// addresses that is allowed
+func (d *CIDRDialer) DialContext(ctx context.Context, network, addr string) (
+	net.Conn, error) {
+
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, err
+	}
+
+	var ips []net.IP
+	if ip := net.ParseIP(host); ip != nil {
+		ips = []net.IP{ip}
+	} else {
+		addrs, err := d.Resolver.LookupIPAddr(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+		for _, a := range addrs {
+			ips = append(ips, a.IP)
+		}
+	}
+
+	for _, ip := range ips {
+		if d.isAllowed(ip) {
+			return d.Dialer.DialContext(ctx, network,
+				net.JoinHostPort(ip.String(), port))
+		}
+	}
+	return nil, &ErrAddressNotAllowed{Host: host, IPs: ips}
+}
+
+func (d *CIDRDialer) isAllowed(ip net.IP) bool {
+	for _, n := range d.allowed {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,72 @@
+package twirest
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// fakeResolver resolves every host to the same addresses
+type fakeResolver []string
+
+func (f fakeResolver) LookupIPAddr(ctx context.Context, host string) (
+	[]net.IPAddr, error) {
+
+	var addrs []net.IPAddr
+	for _, ip := range f {
+		addrs = append(addrs, net.IPAddr{IP: net.ParseIP(ip)})
+	}
+	return addrs, nil
+}
+
+func TestCIDRDialerRejects(t *testing.T) {
+	d, err := NewCIDRDialer([]string{"54.172.60.0/23", "34.203.250.0/23"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.Resolver = fakeResolver{"203.0.113.7", "203.0.113.8"}
+
+	_, err = d.DialContext(context.Background(), "tcp", "api.twilio.com:443")
+	var notAllowed *ErrAddressNotAllowed
+	if !errors.As(err, &notAllowed) {
+		t.Fatalf("expected ErrAddressNotAllowed, got %v", err)
+	}
+	for _, s := range []string{"api.twilio.com", "203.0.113.7", "203.0.113.8"} {
+		if !strings.Contains(err.Error(), s) {
+			t.Errorf("expected %#v in error %#v", s, err.Error())
+		}
+	}
+}
+
+func TestCIDRDialerAllows(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {}))
+	defer ts.Close()
+	_, port, _ := net.SplitHostPort(ts.Listener.Addr().String())
+
+	d, err := NewCIDRDialer([]string{"127.0.0.0/8"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.Resolver = fakeResolver{"203.0.113.7", "127.0.0.1"}
+
+	client, err := NewClient("AC123", "token", WithDialContext(d.DialContext))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := client.Do("GET", "http://api.twilio.test:"+port+"/", nil)
+	if err != nil {
+		t.Fatalf("expected pinned dial to succeed, got %v", err)
+	}
+	resp.Body.Close()
+}
+
+func TestNewCIDRDialerInvalid(t *testing.T) {
+	if _, err := NewCIDRDialer([]string{"54.172.60.0"}); err == nil {
+		t.Errorf("expected error for invalid CIDR")
+	}
+}
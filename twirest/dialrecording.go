@@ -0,0 +1,75 @@
+package twirest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/seanhagen/twilio/twiml"
+)
+
+// dialRecordingPoll is the interval AwaitDialRecording polls at
+const dialRecordingPoll = 2 * time.Second
+
+// AwaitDialRecording waits until the recording of a dialed call, from the
+// action callback of a recording Dial, is completed and returns it ready for
+// DownloadRecording. The recording is fetched every few seconds, a 404 counts
+// as not ready yet as twilio may not know the recording right after the
+// callback. A recording that ends absent or failed is an error, as is
+// timeout passing on the clock of the client.
+func AwaitDialRecording(ctx context.Context, client *TwilioClient,
+	dialResult twiml.DialActionResult, timeout time.Duration) (RecordingInfo, error) {
+
+	sid, err := dialRecordingSid(dialResult)
+	if err != nil {
+		return RecordingInfo{}, err
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	clock := client.timeSource()
+	deadline := clock.Now().Add(timeout)
+
+	for {
+		rec, err := client.RecordingInfo(ctx, sid)
+		switch {
+		case errors.Is(err, ErrNotFound):
+		case err != nil:
+			return RecordingInfo{}, err
+		case rec.Status == TwiCompleted:
+			return RecordingInfo{Recording: *rec}, nil
+		case rec.Status == TwiAbsent, rec.Status == TwiFailed:
+			return RecordingInfo{Recording: *rec},
+				fmt.Errorf("recording %s: '%s'", sid, rec.Status)
+		}
+
+		wait := deadline.Sub(clock.Now())
+		if wait <= 0 {
+			return RecordingInfo{}, context.DeadlineExceeded
+		}
+		if wait > dialRecordingPoll {
+			wait = dialRecordingPoll
+		}
+		select {
+		case <-clock.After(wait):
+		case <-ctx.Done():
+			return RecordingInfo{}, ctx.Err()
+		}
+	}
+}
+
+// dialRecordingSid returns the RecordingSid of the dial action, taken from
+// its RecordingUrl if twilio didn't send it
+func dialRecordingSid(res twiml.DialActionResult) (string, error) {
+	sid := res.RecordingSid
+	if sid == "" && res.RecordingUrl != "" {
+		sid = path.Base(res.RecordingUrl)
+		sid = strings.TrimSuffix(sid, path.Ext(sid))
+	}
+	if sid == "" {
+		return "", fmt.Errorf("dial wasn't recorded: '%s'", res.DialCallSid)
+	}
+	return sid, optionalSid("RE", sid)
+}
@@ -0,0 +1,77 @@
+package twirest
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/seanhagen/twilio/twiml"
+)
+
+const testRecordingSid = "RE0123456789abcdef0123456789abcdef"
+
+// recordingServer answers 404 for the first requests, then the statuses
+type recordingServer struct {
+	mu       sync.Mutex
+	notFound int
+	statuses []string
+	requests int
+}
+
+func (s *recordingServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests++
+	if s.requests <= s.notFound || len(s.statuses) == 0 {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(xmlHeader + `<TwilioResponse><RestException><Code>20404</Code>` +
+			`<Message>not found</Message><Status>404</Status></RestException></TwilioResponse>`))
+		return
+	}
+	status := s.statuses[0]
+	if len(s.statuses) > 1 {
+		s.statuses = s.statuses[1:]
+	}
+	w.Write([]byte(xmlHeader + `<TwilioResponse><Recording><Sid>` + testRecordingSid +
+		`</Sid><Status>` + status + `</Status><Duration>12</Duration></Recording></TwilioResponse>`))
+}
+
+func TestAwaitDialRecording(t *testing.T) {
+	recUrl := "https://api.twilio.com/2010-04-01/Accounts/AC123/Recordings/" + testRecordingSid
+	var tests = []struct {
+		Result   twiml.DialActionResult
+		Server   *recordingServer
+		Requests int
+		Err      bool
+	}{
+		{twiml.DialActionResult{RecordingUrl: recUrl},
+			&recordingServer{notFound: 2, statuses: []string{TwiProcessing, TwiCompleted}}, 4, false},
+		{twiml.DialActionResult{RecordingSid: testRecordingSid, RecordingUrl: recUrl + ".mp3"},
+			&recordingServer{statuses: []string{TwiCompleted}}, 1, false},
+		{twiml.DialActionResult{RecordingUrl: recUrl + ".wav"},
+			&recordingServer{statuses: []string{TwiProcessing, TwiAbsent}}, 2, true},
+		{twiml.DialActionResult{RecordingUrl: recUrl}, &recordingServer{}, -1, true},
+		{twiml.DialActionResult{DialCallSid: "CA123"}, &recordingServer{}, 0, true},
+		{twiml.DialActionResult{RecordingUrl: "https://example.com/recording"},
+			&recordingServer{}, 0, true},
+	}
+
+	for idx, test := range tests {
+		client, ts := testClient(t, test.Server, WithClock(newFakeClock()))
+		info, err := AwaitDialRecording(context.Background(), client, test.Result,
+			time.Minute)
+		ts.Close()
+		if (err != nil) != test.Err {
+			t.Errorf("Test %v failed; expected error %v, got %v", idx, test.Err, err)
+		}
+		if test.Requests >= 0 && test.Server.requests != test.Requests {
+			t.Errorf("Test %v failed; expected %v requests, got %v", idx, test.Requests,
+				test.Server.requests)
+		}
+		if !test.Err && (info.Recording.Sid != testRecordingSid || info.Recording.Duration != 12) {
+			t.Errorf("Test %v failed; unexpected recording %#v", idx, info.Recording)
+		}
+	}
+}
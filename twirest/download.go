@@ -0,0 +1,202 @@
+package twirest
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// DownloadOptions configures DownloadRecording
+type DownloadOptions struct {
+	// Offset is the byte the download starts at, for resuming a download
+	Offset int64
+	// Progress is called after each chunk written with the bytes written so
+	// far and the total size, including Offset. total is -1 if unknown.
+	Progress func(written, total int64)
+}
+
+// DownloadResult is the outcome of a recording download. Resumed is false
+// when the server ignored the requested offset and sent the whole recording.
+type DownloadResult struct {
+	Written int64
+	Total   int64
+	Resumed bool
+}
+
+// RecordingInfo fetches the metadata of a recording, such as its Duration and
+// Channels, without downloading it
+func (twiClient *TwilioClient) RecordingInfo(ctx context.Context, sid string) (
+	*RecordingResponse, error) {
+
+	resp, err := twiClient.RequestWithContext(ctx, Recording{Sid: sid}, false)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Recording == nil {
+		return nil, fmt.Errorf("no recording in response: '%s'", sid)
+	}
+	return resp.Recording, nil
+}
+
+// DownloadRecording streams the audio of the recording req to w, starting at
+// opts.Offset. If the server doesn't honor the offset the bytes before it are
+// skipped, so w always continues where the earlier download stopped. The
+// number of bytes received is checked against the Content-Length.
+func (twiClient *TwilioClient) DownloadRecording(ctx context.Context, req Recording,
+	w io.Writer, opts DownloadOptions) (DownloadResult, error) {
+
+	response, res, err := twiClient.downloadRange(ctx, req, opts.Offset)
+	if err != nil {
+		return res, err
+	}
+	defer response.Body.Close()
+
+	var skipped int64
+	if opts.Offset > 0 && !res.Resumed {
+		if _, err := io.CopyN(io.Discard, response.Body, opts.Offset); err != nil {
+			return res, err
+		}
+		skipped = opts.Offset
+	}
+	return res, copyDownload(w, response, &res, opts, skipped)
+}
+
+// ResumeRecordingDownload downloads the audio of the recording req to file.
+// A partial file is continued from its size, if the server doesn't honor the
+// offset the file is truncated and downloaded again.
+func (twiClient *TwilioClient) ResumeRecordingDownload(ctx context.Context,
+	req Recording, file string, opts DownloadOptions) (DownloadResult, error) {
+
+	f, err := os.OpenFile(file, os.O_WRONLY|os.O_CREATE, 0644)
+	if err != nil {
+		return DownloadResult{}, err
+	}
+	defer f.Close()
+
+	st, err := f.Stat()
+	if err != nil {
+		return DownloadResult{}, err
+	}
+	opts.Offset = st.Size()
+
+	response, res, err := twiClient.downloadRange(ctx, req, opts.Offset)
+	if err != nil {
+		return res, err
+	}
+	defer response.Body.Close()
+
+	start := opts.Offset
+	if !res.Resumed {
+		start, opts.Offset = 0, 0
+		if err := f.Truncate(0); err != nil {
+			return res, err
+		}
+	}
+	if _, err := f.Seek(start, io.SeekStart); err != nil {
+		return res, err
+	}
+	if err := copyDownload(f, response, &res, opts, 0); err != nil {
+		return res, err
+	}
+	return res, f.Close()
+}
+
+// downloadRange requests the audio of a recording from offset on
+func (twiClient *TwilioClient) downloadRange(ctx context.Context, req Recording,
+	offset int64) (*http.Response, DownloadResult, error) {
+
+	res := DownloadResult{Total: -1}
+	req.GetRecording = true
+	httpReq, err := httpRequest(req, twiClient.accountSid, nil)
+	if err != nil {
+		return nil, res, err
+	}
+	if offset > 0 {
+		httpReq.Header.Set("Range", "bytes="+strconv.FormatInt(offset, 10)+"-")
+	}
+	twiClient.setAuth(httpReq)
+
+	response, err := twiClient.do(httpReq.WithContext(ctx))
+	if err != nil {
+		return nil, res, err
+	}
+
+	switch response.StatusCode {
+	case http.StatusOK:
+		res.Total = response.ContentLength
+	case http.StatusPartialContent:
+		start, total, err := contentRange(response.Header.Get("Content-Range"))
+		if err != nil || start != offset {
+			response.Body.Close()
+			return nil, res, fmt.Errorf("non valid content range: '%s'",
+				response.Header.Get("Content-Range"))
+		}
+		res.Resumed = true
+		res.Total = total
+	default:
+		response.Body.Close()
+		return nil, res, fmt.Errorf("non valid recording response: '%s'", response.Status)
+	}
+	return response, res, nil
+}
+
+// copyDownload copies the body to w, reporting progress, and checks the body
+// was complete. skipped are the bytes of the body already read.
+func copyDownload(w io.Writer, response *http.Response, res *DownloadResult,
+	opts DownloadOptions, skipped int64) error {
+
+	buf := make([]byte, 32*1024)
+	n := skipped
+	for {
+		nr, rerr := response.Body.Read(buf)
+		if nr > 0 {
+			nw, err := w.Write(buf[:nr])
+			n += int64(nw)
+			res.Written += int64(nw)
+			if err != nil {
+				return err
+			}
+			if opts.Progress != nil {
+				opts.Progress(opts.Offset+res.Written, res.Total)
+			}
+		}
+		if rerr == io.EOF {
+			break
+		}
+		if rerr != nil {
+			return rerr
+		}
+	}
+
+	if response.ContentLength >= 0 && n != response.ContentLength {
+		return fmt.Errorf("incomplete recording download: %d of %d bytes",
+			n, response.ContentLength)
+	}
+	return nil
+}
+
+// contentRange parses the start and total size of a Content-Range header such
+// as 'bytes 100-199/200', total is -1 if unknown
+func contentRange(header string) (start, total int64, err error) {
+	if !strings.HasPrefix(header, "bytes ") {
+		return 0, 0, fmt.Errorf("non valid content range: '%s'", header)
+	}
+	spec := strings.TrimPrefix(header, "bytes ")
+	slash := strings.IndexByte(spec, '/')
+	dash := strings.IndexByte(spec, '-')
+	if slash < 0 || dash < 0 || dash > slash {
+		return 0, 0, fmt.Errorf("non valid content range: '%s'", header)
+	}
+	if start, err = strconv.ParseInt(spec[:dash], 10, 64); err != nil {
+		return 0, 0, err
+	}
+	if spec[slash+1:] == "*" {
+		return start, -1, nil
+	}
+	total, err = strconv.ParseInt(spec[slash+1:], 10, 64)
+	return start, total, err
+}
@@ -0,0 +1,189 @@
+package twirest
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// recordingAudio is the audio the download test servers serve
+var recordingAudio = bytes.Repeat([]byte("0123456789abcdef"), 8192)
+
+// rangeServer serves recordingAudio, honoring Range requests unless
+// ignoreRange is set. The Range headers received are recorded.
+func rangeServer(t *testing.T, ignoreRange bool, ranges *[]string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/2010-04-01/Accounts/AC123/Recordings/RE123.mp3" {
+			t.Errorf("unexpected path %v", r.URL.Path)
+		}
+		*ranges = append(*ranges, r.Header.Get("Range"))
+		if ignoreRange {
+			r.Header.Del("Range")
+		}
+		w.Header().Set("Content-Type", "audio/mpeg")
+		http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(recordingAudio))
+	})
+}
+
+func TestDownloadRecording(t *testing.T) {
+	var tests = []struct {
+		Offset      int64
+		IgnoreRange bool
+		Resumed     bool
+		Written     int64
+	}{
+		{0, false, false, int64(len(recordingAudio))},
+		{1000, false, true, int64(len(recordingAudio)) - 1000},
+		{1000, true, false, int64(len(recordingAudio)) - 1000},
+	}
+
+	for idx, test := range tests {
+		var ranges []string
+		client, ts := testClient(t, rangeServer(t, test.IgnoreRange, &ranges))
+
+		var buf bytes.Buffer
+		var progress int64
+		res, err := client.DownloadRecording(context.Background(),
+			Recording{Sid: "RE123", GetMP3: true}, &buf, DownloadOptions{
+				Offset:   test.Offset,
+				Progress: func(written, total int64) { progress = written },
+			})
+		ts.Close()
+		if err != nil {
+			t.Errorf("Test %v failed; %v", idx, err)
+			continue
+		}
+
+		if res.Resumed != test.Resumed || res.Written != test.Written ||
+			res.Total != int64(len(recordingAudio)) {
+			t.Errorf("Test %v failed; unexpected result %#v", idx, res)
+		}
+		if !bytes.Equal(buf.Bytes(), recordingAudio[test.Offset:]) {
+			t.Errorf("Test %v failed; got %d bytes not from offset %d",
+				idx, buf.Len(), test.Offset)
+		}
+		if progress != int64(len(recordingAudio)) {
+			t.Errorf("Test %v failed; expected progress %d, got %d",
+				idx, len(recordingAudio), progress)
+		}
+		expect := ""
+		if test.Offset > 0 {
+			expect = "bytes=" + strconv.FormatInt(test.Offset, 10) + "-"
+		}
+		if len(ranges) != 1 || ranges[0] != expect {
+			t.Errorf("Test %v failed; expected Range %#v, got %#v", idx, expect, ranges)
+		}
+	}
+}
+
+func TestResumeRecordingDownload(t *testing.T) {
+	var tests = []struct {
+		Partial     int
+		IgnoreRange bool
+		Resumed     bool
+	}{
+		{0, false, false},
+		{5000, false, true},
+		{5000, true, false},
+	}
+
+	for idx, test := range tests {
+		var ranges []string
+		client, ts := testClient(t, rangeServer(t, test.IgnoreRange, &ranges))
+
+		file := filepath.Join(t.TempDir(), "RE123.mp3")
+		// the partial file ends with garbage if it is restarted
+		partial := append([]byte{}, recordingAudio[:test.Partial]...)
+		if test.IgnoreRange {
+			copy(partial, "corrupt")
+		}
+		if err := os.WriteFile(file, partial, 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		res, err := client.ResumeRecordingDownload(context.Background(),
+			Recording{Sid: "RE123", GetMP3: true}, file, DownloadOptions{})
+		ts.Close()
+		if err != nil {
+			t.Errorf("Test %v failed; %v", idx, err)
+			continue
+		}
+		if res.Resumed != test.Resumed {
+			t.Errorf("Test %v failed; expected resumed %v, got %v",
+				idx, test.Resumed, res.Resumed)
+		}
+		data, err := os.ReadFile(file)
+		if err != nil || !bytes.Equal(data, recordingAudio) {
+			t.Errorf("Test %v failed; file has %d of %d bytes (%v)",
+				idx, len(data), len(recordingAudio), err)
+		}
+	}
+}
+
+func TestDownloadRecordingIncomplete(t *testing.T) {
+	client, ts := testClient(t, http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Length", "1000")
+			w.Write(recordingAudio[:500])
+		}))
+	defer ts.Close()
+
+	_, err := client.DownloadRecording(context.Background(),
+		Recording{Sid: "RE123", GetMP3: true}, &bytes.Buffer{}, DownloadOptions{})
+	if err == nil {
+		t.Errorf("expected an error for a truncated download")
+	}
+}
+
+func TestContentRange(t *testing.T) {
+	var tests = []struct {
+		Header string
+		Start  int64
+		Total  int64
+		Valid  bool
+	}{
+		{"bytes 100-199/200", 100, 200, true},
+		{"bytes 0-99/*", 0, -1, true},
+		{"bytes */200", 0, 0, false},
+		{"items 0-1/2", 0, 0, false},
+		{"bytes 100/200", 0, 0, false},
+	}
+
+	for idx, test := range tests {
+		start, total, err := contentRange(test.Header)
+		if (err == nil) != test.Valid {
+			t.Errorf("Test %v failed; expected valid %v, got %v", idx, test.Valid, err)
+			continue
+		}
+		if test.Valid && (start != test.Start || total != test.Total) {
+			t.Errorf("Test %v failed; expected %d/%d, got %d/%d",
+				idx, test.Start, test.Total, start, total)
+		}
+	}
+}
+
+func TestRecordingInfo(t *testing.T) {
+	client, ts := testClient(t, http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			if !strings.HasSuffix(r.URL.Path, "/Recordings/RE123.xml") {
+				t.Errorf("unexpected path %v", r.URL.Path)
+			}
+			w.Write([]byte(xmlHeader + `<TwilioResponse><Recording><Sid>RE123</Sid>` +
+				`<Duration>3600</Duration><Channels>2</Channels></Recording></TwilioResponse>`))
+		}))
+	defer ts.Close()
+
+	rec, err := client.RecordingInfo(context.Background(), "RE123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rec.Duration != 3600 || rec.Channels != 2 {
+		t.Errorf("expected 3600s on 2 channels, got %vs on %v", rec.Duration, rec.Channels)
+	}
+}
@@ -0,0 +1,130 @@
+package twirest
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// TwilioError is the error of a request twilio responded to with an
+// exception. Branch on its Code with errors.As:
+//
+//	var te *twirest.TwilioError
+//	if errors.As(err, &te) && te.Code == 21211 {
+//
+// It wraps the *ExceptionResponse of the response, and is one of the
+// sentinels below for errors.Is if its code has one.
+type TwilioError struct {
+	Code     int
+	Message  string
+	MoreInfo string
+	Status   int // http status
+
+	exception *ExceptionResponse
+}
+
+// newTwilioError converts a parsed exception to a TwilioError
+func newTwilioError(ex *ExceptionResponse) *TwilioError {
+	return &TwilioError{
+		Code:      ex.Code,
+		Message:   ex.Message,
+		MoreInfo:  ex.MoreInfo,
+		Status:    ex.StatusCode,
+		exception: ex,
+	}
+}
+
+func (e *TwilioError) Error() string {
+	return e.Message + " (" + e.MoreInfo + ")"
+}
+
+// Unwrap returns the exception of the response
+func (e *TwilioError) Unwrap() error {
+	if e.exception == nil {
+		return nil
+	}
+	return e.exception
+}
+
+// maxErrorBody is the length of the start of a body a ResponseError holds
+const maxErrorBody = 512
+
+// ResponseError is the error of a response that isn't a twilio response: a
+// body that doesn't parse, such as the HTML error page of a proxy or an empty
+// body, or a failure status without an exception
+type ResponseError struct {
+	Status int    // http status
+	Body   string // the start of the body
+	Err    error  // the parse error, nil if the body parsed
+}
+
+// newResponseError returns the ResponseError of a response with status and
+// body that failed to parse with err
+func newResponseError(status int, body []byte, err error) *ResponseError {
+	if len(body) > maxErrorBody {
+		body = body[:maxErrorBody]
+	}
+	return &ResponseError{Status: status, Body: string(body), Err: err}
+}
+
+func (e *ResponseError) Error() string {
+	msg := fmt.Sprintf("unexpected response (http %d)", e.Status)
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+	return fmt.Sprintf("%s, body: %q", msg, e.Body)
+}
+
+// Unwrap returns the parse error
+func (e *ResponseError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports if target is the sentinel of the code of e
+func (e *TwilioError) Is(target error) bool {
+	s, ok := codeErrors[e.Code]
+	return ok && target == error(s)
+}
+
+// codeErrors are the sentinels by code
+var codeErrors = map[int]*TwilioError{}
+
+// sentinel creates the sentinel of code
+func sentinel(code int, message string) *TwilioError {
+	e := &TwilioError{
+		Code:     code,
+		Message:  message,
+		MoreInfo: "https://www.twilio.com/docs/errors/" + strconv.Itoa(code),
+	}
+	codeErrors[code] = e
+	return e
+}
+
+// Sentinels of common error codes, see https://www.twilio.com/docs/api/errors
+var (
+	// Authentication and API
+	ErrPermissionDenied = sentinel(20003, "permission denied")
+	ErrAccountNotActive = sentinel(20005, "account not active")
+	ErrAccessDenied     = sentinel(codeAccessDenied, "access denied")
+	ErrTestCredentials  = sentinel(20008, "resource not accessible with test credentials")
+	ErrNotFound         = sentinel(20404, "resource not found")
+	ErrTooManyRequests  = sentinel(20429, "too many requests")
+	// Call and message validation
+	ErrInvalidToNumber       = sentinel(21211, "invalid 'To' phone number")
+	ErrInvalidFromNumber     = sentinel(21212, "invalid 'From' phone number")
+	ErrToNumberUnreachable   = sentinel(21214, "'To' phone number cannot be reached")
+	ErrGeoPermission         = sentinel(21215, "geo permission not enabled for the number")
+	ErrBodyRequired          = sentinel(21602, "message body is required")
+	ErrInvalidSender         = sentinel(21606, "'From' number can't send messages to the destination")
+	ErrUnverifiedNumber      = sentinel(21608, "trial accounts can only send to verified numbers")
+	ErrUnsubscribedRecipient = sentinel(21610, "recipient unsubscribed")
+	ErrQueueFull             = sentinel(21611, "'From' number exceeded the queued messages limit")
+	ErrSMSUnreachable        = sentinel(21612, "'To' phone number not reachable by SMS")
+	ErrNotMobileNumber       = sentinel(21614, "'To' number is not a mobile number")
+	ErrBodyTooLong           = sentinel(21617, "message body exceeds 1600 characters")
+	// TwiML
+	ErrDialInvalidCallerId   = sentinel(13214, "Dial: invalid callerId")
+	ErrDialInvalidNumber     = sentinel(13223, "Dial: invalid phone number format")
+	ErrDialUnsupportedNumber = sentinel(13224, "Dial: number not supported or invalid")
+	ErrDialForbiddenNumber   = sentinel(13225, "Dial: forbidden phone number")
+	ErrDialNoInternational   = sentinel(13227, "Dial: no international authorization")
+)
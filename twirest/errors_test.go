@@ -0,0 +1,180 @@
+package twirest
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestTwilioError(t *testing.T) {
+	client, ts := testClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(xmlHeader + `<TwilioResponse><RestException><Code>21211</Code>` +
+			`<Message>The 'To' number +15005550001 is not a valid phone number.</Message>` +
+			`<MoreInfo>https://www.twilio.com/docs/errors/21211</MoreInfo>` +
+			`<Status>400</Status></RestException></TwilioResponse>`))
+	}))
+	defer ts.Close()
+
+	resp, err := client.Request(SendMessage{From: "+15005550006", To: "+15005550001",
+		Text: "Hi"}, false)
+	var te *TwilioError
+	if !errors.As(err, &te) {
+		t.Fatalf("expected a *TwilioError, got %T %v", err, err)
+	}
+	if te.Code != 21211 || te.Status != 400 || te.MoreInfo != "https://www.twilio.com/docs/errors/21211" ||
+		te.Message != "The 'To' number +15005550001 is not a valid phone number." {
+		t.Errorf("unexpected error %#v", te)
+	}
+	if resp.Status.Twilio != 21211 || resp.Status.Http != 400 {
+		t.Errorf("expected the status kept, got %#v", resp.Status)
+	}
+	// the exception is still available
+	var ex *ExceptionResponse
+	if !errors.As(err, &ex) || ex.Code != 21211 || err.Error() != ex.Error() {
+		t.Errorf("expected the exception wrapped, got %v", ex)
+	}
+}
+
+func TestPartialResponse(t *testing.T) {
+	var tests = []struct {
+		Req    interface{}
+		Type   string
+		Body   string
+		Code   int
+		Expect func(TwilioResponse) bool
+	}{
+		{SendMessage{From: "+15005550006", To: "+15005550001", Text: "Hi"}, "",
+			xmlHeader + `<TwilioResponse><RestException><Code>21211</Code>` +
+				`<Message>The 'To' number +15005550001 is not a valid phone number.</Message>` +
+				`<Status>400</Status></RestException><Message><To>+15005550001</To>` +
+				`<From>+15005550006</From></Message></TwilioResponse>`, 21211,
+			func(r TwilioResponse) bool {
+				return r.Message != nil && r.Message.To == "+15005550001"
+			}},
+		{ListAlerts{LogLevel: "bogus"}, "application/json",
+			`{"code":20001,"message":"Invalid LogLevel","more_info":"",` +
+				`"status":400,"meta":{"page":0,"page_size":50,"key":"alerts"}}`, 20001,
+			func(r TwilioResponse) bool {
+				return r.Alerts != nil && r.Alerts.Meta.Key == "alerts"
+			}},
+		{ListAlerts{}, "application/json",
+			`{"code":20001,"message":"Invalid","status":400}`, 20001,
+			func(r TwilioResponse) bool { return r.Alerts == nil }},
+	}
+
+	for idx, test := range tests {
+		client, ts := testClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if test.Type != "" {
+				w.Header().Set("Content-Type", test.Type)
+			}
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(test.Body))
+		}))
+		resp, err := client.Request(test.Req, false)
+		ts.Close()
+
+		var te *TwilioError
+		if !errors.As(err, &te) || te.Code != test.Code {
+			t.Errorf("Test %v failed; expected error %v, got %v", idx, test.Code, err)
+		}
+		if resp.Status.Http != 400 || resp.Status.Twilio != test.Code || resp.Exception == nil {
+			t.Errorf("Test %v failed; expected the status kept, got %#v", idx, resp.Status)
+		}
+		if !test.Expect(resp) {
+			t.Errorf("Test %v failed; unexpected partial response %#v", idx, resp)
+		}
+	}
+}
+
+func TestSentinelErrors(t *testing.T) {
+	var tests = []struct {
+		Code   int
+		Status int
+		Expect error
+	}{
+		{21211, 400, ErrInvalidToNumber},
+		{21610, 400, ErrUnsubscribedRecipient},
+		{21611, 400, ErrQueueFull},
+		{20003, 401, ErrPermissionDenied},
+		{20404, 404, ErrNotFound},
+		{13224, 400, ErrDialUnsupportedNumber},
+		{29999, 400, nil},
+	}
+
+	for idx, test := range tests {
+		client, ts := testClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(test.Status)
+			w.Write([]byte(xmlHeader + `<TwilioResponse><RestException><Code>` +
+				strconv.Itoa(test.Code) + `</Code><Message>failed</Message>` +
+				`<Status>` + strconv.Itoa(test.Status) + `</Status></RestException></TwilioResponse>`))
+		}))
+		_, err := client.Request(SendMessage{From: "+15005550006", To: "+15005550001", Text: "Hi"}, false)
+		ts.Close()
+
+		var te *TwilioError
+		if !errors.As(err, &te) || te.Code != test.Code {
+			t.Errorf("Test %v failed; expected a *TwilioError %v, got %v", idx, test.Code, err)
+		}
+		for _, s := range codeErrors {
+			if errors.Is(err, s) != (s == test.Expect) {
+				t.Errorf("Test %v failed; expected errors.Is %v to be %v", idx, s.Code,
+					s == test.Expect)
+			}
+		}
+	}
+
+	if len(codeErrors) < 20 || codeErrors[21211] != ErrInvalidToNumber {
+		t.Errorf("expected the sentinels mapped by code, got %v", codeErrors)
+	}
+}
+
+func TestResponseError(t *testing.T) {
+	html := "<html><body>" + strings.Repeat("Bad Gateway ", 100) + "</body></html>"
+	var tests = []struct {
+		Code  int
+		Body  string
+		Parse bool // expect a parse error
+	}{
+		{http.StatusBadGateway, html, true},
+		{http.StatusOK, html, true},
+		{http.StatusOK, xmlHeader + "<TwilioResponse><Message><Sid>SM1</Sid>", true},
+		{http.StatusInternalServerError, xmlHeader + "<TwilioResponse><Message>", true},
+		{http.StatusOK, "", true},
+		{http.StatusServiceUnavailable, "", true},
+		{http.StatusBadRequest, xmlHeader + "<TwilioResponse></TwilioResponse>", false},
+	}
+
+	for i, tt := range tests {
+		client, ts := testClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(tt.Code)
+			w.Write([]byte(tt.Body))
+		}))
+
+		resp, err := client.Request(Queues{}, false)
+		ts.Close()
+		var re *ResponseError
+		if !errors.As(err, &re) {
+			t.Errorf("Test %v failed; expected a *ResponseError, got %T %v", i, err, err)
+			continue
+		}
+		if re.Status != tt.Code || resp.Status.Http != tt.Code {
+			t.Errorf("Test %v failed; expected status %v, got %v", i, tt.Code, re.Status)
+		}
+		if (re.Err != nil) != tt.Parse {
+			t.Errorf("Test %v failed; expected parse error %v, got %v", i, tt.Parse, re.Err)
+		}
+		body := tt.Body
+		if len(body) > maxErrorBody {
+			body = body[:maxErrorBody]
+		}
+		if re.Body != body {
+			t.Errorf("Test %v failed; expected body %q, got %q", i, body, re.Body)
+		}
+		if !strings.Contains(err.Error(), strconv.Itoa(tt.Code)) {
+			t.Errorf("Test %v failed; expected the status in %q", i, err.Error())
+		}
+	}
+}
@@ -0,0 +1,239 @@
+package twirest
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// ExportFormat is the format of exported records
+type ExportFormat int
+
+const (
+	// ExportCSV writes a header line and a line per record, the columns are
+	// in the order of CallColumns or MessageColumns
+	ExportCSV ExportFormat = iota
+	// ExportJSONLines writes a json object per line, with the columns as
+	// keys in the same order
+	ExportJSONLines
+)
+
+// exportColumn is a column of an export and the value of a record in it
+type exportColumn struct {
+	name  string
+	value func(record interface{}) interface{}
+}
+
+// callColumns are the columns of exported calls
+var callColumns = []exportColumn{
+	{"sid", func(r interface{}) interface{} { return r.(CallInfo).Sid }},
+	{"parent_call_sid", func(r interface{}) interface{} { return r.(CallInfo).ParentCallSid }},
+	{"account_sid", func(r interface{}) interface{} { return r.(CallInfo).AccountSid }},
+	{"from", func(r interface{}) interface{} { return r.(CallInfo).From }},
+	{"to", func(r interface{}) interface{} { return r.(CallInfo).To }},
+	{"status", func(r interface{}) interface{} { return r.(CallInfo).Status }},
+	{"direction", func(r interface{}) interface{} { return r.(CallInfo).Direction }},
+	{"answered_by", func(r interface{}) interface{} { return r.(CallInfo).AnsweredBy }},
+	{"date_created", func(r interface{}) interface{} { return exportTime(r.(CallInfo).DateCreated) }},
+	{"start_time", func(r interface{}) interface{} { return exportTime(r.(CallInfo).StartTime) }},
+	{"end_time", func(r interface{}) interface{} { return exportTime(r.(CallInfo).EndTime) }},
+	{"duration", func(r interface{}) interface{} { return int(r.(CallInfo).Duration / time.Second) }},
+	{"price", func(r interface{}) interface{} { return r.(CallInfo).Price }},
+	{"price_unit", func(r interface{}) interface{} { return r.(CallInfo).PriceUnit }},
+}
+
+// messageColumns are the columns of exported messages
+var messageColumns = []exportColumn{
+	{"sid", func(r interface{}) interface{} { return r.(MessageInfo).Sid }},
+	{"account_sid", func(r interface{}) interface{} { return r.(MessageInfo).AccountSid }},
+	{"from", func(r interface{}) interface{} { return r.(MessageInfo).From }},
+	{"to", func(r interface{}) interface{} { return r.(MessageInfo).To }},
+	{"body", func(r interface{}) interface{} { return r.(MessageInfo).Body }},
+	{"status", func(r interface{}) interface{} { return r.(MessageInfo).Status }},
+	{"direction", func(r interface{}) interface{} { return r.(MessageInfo).Direction }},
+	{"date_created", func(r interface{}) interface{} { return exportTime(r.(MessageInfo).DateCreated) }},
+	{"date_sent", func(r interface{}) interface{} { return exportTime(r.(MessageInfo).DateSent) }},
+	{"num_segments", func(r interface{}) interface{} { return r.(MessageInfo).NumSegments }},
+	{"price", func(r interface{}) interface{} { return r.(MessageInfo).Price }},
+	{"price_unit", func(r interface{}) interface{} { return r.(MessageInfo).PriceUnit }},
+}
+
+// CallColumns returns the column names of exported calls in order
+func CallColumns() []string {
+	return columnNames(callColumns)
+}
+
+// MessageColumns returns the column names of exported messages in order
+func MessageColumns() []string {
+	return columnNames(messageColumns)
+}
+
+// ExportCalls writes the calls matching filter to w, one record per line, and
+// returns the number of records written. The pages are written as they are
+// fetched. Times are RFC3339 in UTC, empty (null in json) if not set.
+// Duration is in seconds.
+func (twiClient *TwilioClient) ExportCalls(ctx context.Context, filter Calls,
+	w io.Writer, format ExportFormat) (int, error) {
+
+	exp, err := newExporter(w, format, callColumns)
+	if err != nil {
+		return 0, err
+	}
+
+	resp, err := twiClient.RequestWithContext(ctx, filter, false)
+	for err == nil && resp.Calls != nil {
+		for _, c := range resp.Calls.Call {
+			info, ierr := NewCallInfo(c)
+			if ierr != nil {
+				return exp.count, fmt.Errorf("call %s: %v", c.Sid, ierr)
+			}
+			if err = exp.write(info); err != nil {
+				return exp.count, err
+			}
+		}
+		if err = exp.flush(); err != nil || resp.Calls.NextPageUri == "" {
+			break
+		}
+		resp, err = twiClient.NextPage(ctx, resp.Calls.NextPageUri, filter)
+	}
+	return exp.count, err
+}
+
+// ExportMessages writes the messages matching filter to w like ExportCalls.
+// The bodies are left out unless withBody is set, they may hold personal data.
+func (twiClient *TwilioClient) ExportMessages(ctx context.Context, filter Messages,
+	w io.Writer, format ExportFormat, withBody bool) (int, error) {
+
+	exp, err := newExporter(w, format, messageColumns)
+	if err != nil {
+		return 0, err
+	}
+
+	resp, err := twiClient.RequestWithContext(ctx, filter, false)
+	for err == nil && resp.Messages != nil {
+		for _, m := range resp.Messages.Message {
+			info, ierr := NewMessageInfo(m)
+			if ierr != nil {
+				return exp.count, fmt.Errorf("message %s: %v", m.Sid, ierr)
+			}
+			if !withBody {
+				info.Body = ""
+			}
+			if err = exp.write(info); err != nil {
+				return exp.count, err
+			}
+		}
+		if err = exp.flush(); err != nil || resp.Messages.NextPageUri == "" {
+			break
+		}
+		resp, err = twiClient.NextPage(ctx, resp.Messages.NextPageUri, filter)
+	}
+	return exp.count, err
+}
+
+// exporter writes records in an export format
+type exporter struct {
+	columns []exportColumn
+	csv     *csv.Writer
+	buf     *bufio.Writer
+	count   int
+}
+
+// newExporter returns an exporter writing to w, the csv header is written
+// right away
+func newExporter(w io.Writer, format ExportFormat, columns []exportColumn) (
+	*exporter, error) {
+
+	exp := &exporter{columns: columns}
+	switch format {
+	default:
+		return nil, fmt.Errorf("non valid export format: '%d'", format)
+	case ExportCSV:
+		exp.csv = csv.NewWriter(w)
+		return exp, exp.csv.Write(columnNames(columns))
+	case ExportJSONLines:
+		exp.buf = bufio.NewWriter(w)
+		return exp, nil
+	}
+}
+
+// write writes a record
+func (exp *exporter) write(record interface{}) error {
+	if exp.csv != nil {
+		line := make([]string, len(exp.columns))
+		for i, col := range exp.columns {
+			line[i] = csvValue(col.value(record))
+		}
+		if err := exp.csv.Write(line); err != nil {
+			return err
+		}
+		exp.count++
+		return nil
+	}
+
+	exp.buf.WriteByte('{')
+	for i, col := range exp.columns {
+		if i > 0 {
+			exp.buf.WriteByte(',')
+		}
+		name, _ := json.Marshal(col.name)
+		val, err := json.Marshal(col.value(record))
+		if err != nil {
+			return err
+		}
+		exp.buf.Write(name)
+		exp.buf.WriteByte(':')
+		exp.buf.Write(val)
+	}
+	if _, err := exp.buf.WriteString("}\n"); err != nil {
+		return err
+	}
+	exp.count++
+	return nil
+}
+
+// flush writes the buffered records
+func (exp *exporter) flush() error {
+	if exp.csv != nil {
+		exp.csv.Flush()
+		return exp.csv.Error()
+	}
+	return exp.buf.Flush()
+}
+
+// csvValue formats a column value for csv
+func csvValue(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case int:
+		return strconv.Itoa(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	return fmt.Sprint(v)
+}
+
+// exportTime formats t as RFC3339 in UTC, nil if t is zero
+func exportTime(t time.Time) interface{} {
+	if t.IsZero() {
+		return nil
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// columnNames returns the names of columns
+func columnNames(columns []exportColumn) []string {
+	names := make([]string, len(columns))
+	for i, col := range columns {
+		names[i] = col.name
+	}
+	return names
+}
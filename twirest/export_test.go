@@ -0,0 +1,189 @@
+package twirest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// exportServer serves two pages of calls and a page of messages
+func exportServer() http.Handler {
+	const calls = "/2010-04-01/Accounts/AC123/Calls"
+	pages := map[string]string{
+		calls + "?Status=completed": `<Calls nextpageuri="` + calls + `?Status=completed&amp;Page=1">` +
+			`<Call><Sid>CA1</Sid><AccountSid>AC123</AccountSid><From>+15005550006</From>` +
+			`<To>+15005550001</To><Status>completed</Status><Direction>outbound-api</Direction>` +
+			`<AnsweredBy>human</AnsweredBy><DateCreated>Tue, 31 Aug 2010 20:36:28 +0000</DateCreated>` +
+			`<StartTime>Tue, 31 Aug 2010 20:36:29 +0000</StartTime>` +
+			`<EndTime>Tue, 31 Aug 2010 20:36:44 +0200</EndTime><Duration>15</Duration>` +
+			`<Price>-0.03000</Price><PriceUnit>USD</PriceUnit></Call></Calls>`,
+		calls + "?Status=completed&Page=1": `<Calls nextpageuri="">` +
+			`<Call><Sid>CA2</Sid><ParentCallSid>CA1</ParentCallSid><From>Acme, Inc.</From>` +
+			`<Status>completed</Status><Duration>0</Duration></Call></Calls>`,
+		"/2010-04-01/Accounts/AC123/Messages": `<Messages nextpageuri="">` +
+			`<Message><Sid>SM1</Sid><From>+15005550006</From><To>+15005550001</To>` +
+			`<Body>My PIN is 1234</Body><Status>delivered</Status><NumSegments>1</NumSegments>` +
+			`<DateSent>Wed, 01 Sep 2010 10:00:00 +0000</DateSent><Price>-0.0075</Price>` +
+			`</Message></Messages>`,
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(xmlHeader + "<TwilioResponse>" + pages[r.URL.RequestURI()] +
+			"</TwilioResponse>"))
+	})
+}
+
+func TestExportCalls(t *testing.T) {
+	var tests = []struct {
+		Format ExportFormat
+		Expect string
+	}{
+		{ExportCSV, "sid,parent_call_sid,account_sid,from,to,status,direction,answered_by," +
+			"date_created,start_time,end_time,duration,price,price_unit\n" +
+			"CA1,,AC123,+15005550006,+15005550001,completed,outbound-api,human," +
+			"2010-08-31T20:36:28Z,2010-08-31T20:36:29Z,2010-08-31T18:36:44Z,15,-0.03,USD\n" +
+			`CA2,CA1,,"Acme, Inc.",,completed,,,,,,0,0,` + "\n"},
+		{ExportJSONLines, `{"sid":"CA1","parent_call_sid":"","account_sid":"AC123",` +
+			`"from":"+15005550006","to":"+15005550001","status":"completed",` +
+			`"direction":"outbound-api","answered_by":"human",` +
+			`"date_created":"2010-08-31T20:36:28Z","start_time":"2010-08-31T20:36:29Z",` +
+			`"end_time":"2010-08-31T18:36:44Z","duration":15,"price":-0.03,"price_unit":"USD"}` + "\n" +
+			`{"sid":"CA2","parent_call_sid":"CA1","account_sid":"","from":"Acme, Inc.",` +
+			`"to":"","status":"completed","direction":"","answered_by":"",` +
+			`"date_created":null,"start_time":null,"end_time":null,"duration":0,` +
+			`"price":0,"price_unit":""}` + "\n"},
+	}
+
+	client, ts := testClient(t, exportServer())
+	defer ts.Close()
+
+	for idx, test := range tests {
+		var buf bytes.Buffer
+		n, err := client.ExportCalls(context.Background(), Calls{Status: TwiCompleted},
+			&buf, test.Format)
+		if err != nil || n != 2 {
+			t.Errorf("Test %v failed; expected 2 records, got %v (%v)", idx, n, err)
+		}
+		if buf.String() != test.Expect {
+			t.Errorf("Test %v failed; expected\n%v\ngot\n%v", idx, test.Expect, buf.String())
+		}
+	}
+
+	if _, err := client.ExportCalls(context.Background(), Calls{}, &bytes.Buffer{},
+		ExportFormat(7)); err == nil {
+		t.Errorf("expected an error for a non valid format")
+	}
+}
+
+func TestExportMessages(t *testing.T) {
+	client, ts := testClient(t, exportServer())
+	defer ts.Close()
+
+	var tests = []struct {
+		WithBody bool
+		Expect   string
+	}{
+		{false, "SM1,,+15005550006,+15005550001,,delivered,,,2010-09-01T10:00:00Z,1,-0.0075,\n"},
+		{true, "SM1,,+15005550006,+15005550001,My PIN is 1234,delivered,,,2010-09-01T10:00:00Z,1,-0.0075,\n"},
+	}
+
+	for idx, test := range tests {
+		var buf bytes.Buffer
+		n, err := client.ExportMessages(context.Background(), Messages{}, &buf,
+			ExportCSV, test.WithBody)
+		if err != nil || n != 1 {
+			t.Errorf("Test %v failed; expected 1 record, got %v (%v)", idx, n, err)
+		}
+		lines := strings.SplitAfterN(buf.String(), "\n", 2)
+		if lines[0] != strings.Join(MessageColumns(), ",")+"\n" || lines[1] != test.Expect {
+			t.Errorf("Test %v failed; expected %#v, got %#v", idx, test.Expect, buf.String())
+		}
+	}
+}
+
+// lockedBuffer is a buffer written by an export while the test server reads
+// it
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestExportMessagesPages(t *testing.T) {
+	const messages = "/2010-04-01/Accounts/AC123/Messages"
+	page := func(next string, sids ...string) string {
+		out := `<Messages nextpageuri="` + next + `">`
+		for _, sid := range sids {
+			out += `<Message><Sid>` + sid + `</Sid><Status>delivered</Status></Message>`
+		}
+		return out + `</Messages>`
+	}
+	pages := map[string]string{
+		messages:             page(messages+"?Page=1", "SM1", "SM2"),
+		messages + "?Page=1": page(messages+"?Page=2", "SM3", "SM4"),
+		messages + "?Page=2": page("", "SM5"),
+	}
+
+	var out lockedBuffer
+	var beforeLast string // written when the last page is requested
+	client, ts := testClient(t, http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.RequestURI() == messages+"?Page=2" {
+				beforeLast = out.String()
+			}
+			w.Write([]byte(xmlHeader + "<TwilioResponse>" + pages[r.URL.RequestURI()] +
+				"</TwilioResponse>"))
+		}))
+	defer ts.Close()
+
+	n, err := client.ExportMessages(context.Background(), Messages{}, &out,
+		ExportJSONLines, false)
+	if err != nil || n != 5 {
+		t.Fatalf("expected 5 records, got %v (%v)", n, err)
+	}
+	var sids []string
+	for _, line := range strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n") {
+		var rec struct {
+			Sid string `json:"sid"`
+		}
+		if err := json.Unmarshal([]byte(line), &rec); err != nil {
+			t.Fatal(err)
+		}
+		sids = append(sids, rec.Sid)
+	}
+	if strings.Join(sids, ",") != "SM1,SM2,SM3,SM4,SM5" {
+		t.Errorf("expected the messages in order, got %v", sids)
+	}
+	// the pages are written as they arrive, not held until the last one
+	if strings.Count(beforeLast, "\n") != 4 || !strings.Contains(beforeLast, `"SM4"`) {
+		t.Errorf("expected the first 2 pages written before the last is requested, got %#v",
+			beforeLast)
+	}
+}
+
+func TestNewCallInfoErrors(t *testing.T) {
+	var tests = []CallResponse{
+		{Sid: "CA1", StartTime: "2010-08-31"},
+		{Sid: "CA1", Price: "free"},
+	}
+
+	for idx, test := range tests {
+		if _, err := NewCallInfo(test); err == nil {
+			t.Errorf("Test %v failed; expected error", idx)
+		}
+	}
+}
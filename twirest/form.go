@@ -0,0 +1,246 @@
+package twirest
+
+import (
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// formField is a request struct field that is encoded as a form parameter
+type formField struct {
+	index  int
+	slice  bool
+	money  bool
+	typed  bool // a bool, number or time, see typedValue
+	date   bool // a time sent as a date, the date option of the tag
+	legacy bool // a raw tag, see compat.go
+	name   string
+	prefix string // escaped parameter name and '='
+}
+
+// moneyType is the reflect.Type of Money
+var moneyType = reflect.TypeOf(Money{})
+
+// formFieldCache holds the []formField of each request struct type
+var formFieldCache sync.Map
+
+// formFields returns the fields of t that are encoded, the exported fields
+// with a tag of a kind formKind accepts
+func formFields(t reflect.Type) []formField {
+	if fields, ok := formFieldCache.Load(t); ok {
+		return fields.([]formField)
+	}
+
+	var fields []formField
+	for i := 0; i < t.NumField(); i++ {
+		fld := t.Field(i)
+		if fld.Tag == "" || fld.PkgPath != "" || pathControl(fld.Tag) {
+			continue
+		}
+		name, legacy := paramTag(fld.Tag)
+		if name == "" || !formKind(fld.Type) {
+			continue
+		}
+		f := formField{index: i, legacy: legacy, name: name, prefix: paramPrefix(name)}
+		switch {
+		case fld.Type == moneyType:
+			f.money = true
+		case fld.Type.Kind() == reflect.Slice:
+			f.slice = true
+		case fld.Type.Kind() != reflect.String:
+			f.typed = true
+			f.date = tagOption(fld.Tag, "date")
+		}
+		fields = append(fields, f)
+	}
+	formFieldCache.Store(t, fields)
+	return fields
+}
+
+// pathControl reports if the tag marks a field that picks or extends the url
+// path, these are never encoded whatever their type
+func pathControl(tag reflect.StructTag) bool {
+	return keyedTag(tag) && tag.Get("url") == "-"
+}
+
+// formBufPool holds the buffers request strings are built in
+var formBufPool = sync.Pool{
+	New: func() interface{} {
+		b := make([]byte, 0, 512)
+		return &b
+	},
+}
+
+// encodeForm encodes the tagged fields of the request struct reqSt that are
+// set, in field order
+func encodeForm(reqSt interface{}) string {
+	v := reflect.ValueOf(reqSt)
+	fields := formFields(v.Type())
+
+	bp := formBufPool.Get().(*[]byte)
+	b := (*bp)[:0]
+	for _, f := range fields {
+		fv := v.Field(f.index)
+		if f.money {
+			// read through the fields, Interface would make reqSt escape
+			m := Money{Micros: fv.Field(0).Int(), Currency: fv.Field(1).String()}
+			if val := m.formValue(); val != "" {
+				b = appendParam(b, f.prefix, val)
+			}
+			continue
+		}
+		if f.typed {
+			if val, ok := typedValue(fv, f.date); ok {
+				b = appendParam(b, f.prefix, val)
+			}
+			continue
+		}
+		if !f.slice {
+			if val := fv.String(); val != "" {
+				b = appendParam(b, f.prefix, val)
+			}
+			continue
+		}
+		for i := 0; i < fv.Len(); i++ {
+			b = appendParam(b, f.prefix, fv.Index(i).String())
+		}
+	}
+	if len(b) > 0 {
+		b = b[:len(b)-1] // remove the last '&'
+	}
+
+	qryStr := string(b)
+	*bp = b
+	formBufPool.Put(bp)
+	return qryStr
+}
+
+// Encode returns the parameters of the request struct as they are sent, in
+// the order of its fields. Twilio doesn't depend on the order of parameters,
+// compare encodings with EncodeCanonical. reqSt may be a pointer to one.
+func Encode(reqSt interface{}) string {
+	return queryString(derefRequest(reqSt))
+}
+
+// EncodeCanonical returns the parameters of the request struct sorted by
+// name, the values of a repeated parameter in field order. It doesn't change
+// when fields are reordered, so tests compare it, and it is the order
+// twiml.Signature signs the parameters of a webhook in.
+func EncodeCanonical(reqSt interface{}) string {
+	reqSt = derefRequest(reqSt)
+	if !hasForm(reqSt) {
+		return ""
+	}
+	return formValues(reqSt).Encode()
+}
+
+// derefRequest returns the request struct reqSt points to, reqSt if it
+// isn't a pointer to one
+func derefRequest(reqSt interface{}) interface{} {
+	if req, err := requestStruct(reqSt); err == nil {
+		return req
+	}
+	return reqSt
+}
+
+// formValues returns the parameters encodeForm encodes
+func formValues(reqSt interface{}) url.Values {
+	v := reflect.ValueOf(reqSt)
+	vals := url.Values{}
+	for _, f := range formFields(v.Type()) {
+		fv := v.Field(f.index)
+		switch {
+		case f.money:
+			if val := fv.Interface().(Money).formValue(); val != "" {
+				vals.Add(f.name, val)
+			}
+		case f.typed:
+			if val, ok := typedValue(fv, f.date); ok {
+				vals.Add(f.name, val)
+			}
+		case f.slice:
+			for i := 0; i < fv.Len(); i++ {
+				vals.Add(f.name, fv.Index(i).String())
+			}
+		case fv.String() != "":
+			vals.Add(f.name, fv.String())
+		}
+	}
+	return vals
+}
+
+// typedValue returns the parameter value of a bool, number or time field:
+// true or false, the decimal number, the time in RFC 3339 or as a date
+// (2006-01-02) if date is set. A field that isn't a pointer isn't set when
+// it's the zero value, a pointer when it's nil, so a pointer sends false or 0.
+func typedValue(fv reflect.Value, date bool) (string, bool) {
+	if fv.Kind() == reflect.Ptr {
+		if fv.IsNil() {
+			return "", false
+		}
+		fv = fv.Elem()
+	} else if fv.IsZero() {
+		return "", false
+	}
+
+	switch fv.Kind() {
+	case reflect.Bool:
+		return strconv.FormatBool(fv.Bool()), true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(fv.Int(), 10), true
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(fv.Float(), 'f', -1, 64), true
+	case reflect.String:
+		return fv.String(), true
+	}
+	t := fv.Interface().(time.Time)
+	if date {
+		return t.Format("2006-01-02"), true
+	}
+	return t.Format(time.RFC3339), true
+}
+
+// appendParam appends prefix, the escaped val and '&' to b
+func appendParam(b []byte, prefix, val string) []byte {
+	b = append(b, prefix...)
+	b = appendQueryEscape(b, val)
+	return append(b, '&')
+}
+
+// appendQueryEscape appends s to b escaped like url.QueryEscape
+func appendQueryEscape(b []byte, s string) []byte {
+	const hex = "0123456789ABCDEF"
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9',
+			c == '-', c == '_', c == '.', c == '~':
+			b = append(b, c)
+		case c == ' ':
+			b = append(b, '+')
+		default:
+			b = append(b, '%', hex[c>>4], hex[c&15])
+		}
+	}
+	return b
+}
+
+// redactedParams are the parameters whose values are secrets, these are
+// redacted when a request is logged
+var redactedParams = []string{"SipAuthPassword"}
+
+// redactQuery replaces the values of redactedParams in the query string
+func redactQuery(qryStr string) string {
+	params := strings.Split(qryStr, "&")
+	for i, p := range params {
+		for _, name := range redactedParams {
+			if strings.HasPrefix(p, name+"=") {
+				params[i] = name + "=REDACTED"
+			}
+		}
+	}
+	return strings.Join(params, "&")
+}
@@ -0,0 +1,476 @@
+package twirest
+
+import (
+	"math/rand"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// large request structs, the worst cases for encoding, held in interfaces as
+// Request receives them
+var (
+	benchIncomingNumber interface{} = CreateIncomingPhoneNumber{
+		PhoneNumber:          "+15005550006",
+		FriendlyName:         "Support line",
+		VoiceURL:             "https://example.com/voice",
+		VoiceMethod:          "POST",
+		VoiceFallbackURL:     "https://example.com/voice-fallback",
+		VoiceFallbackMethod:  "POST",
+		StatusCallback:       "https://example.com/status",
+		StatusCallbackMethod: "POST",
+		VoiceCallerIDLookup:  "true",
+		SMSUrl:               "https://example.com/sms",
+		SMSMethod:            "POST",
+		SMSFallbackURL:       "https://example.com/sms-fallback",
+		SMSFallbackMethod:    "POST",
+	}
+	benchUpdateNumber interface{} = UpdateIncomingPhoneNumber{
+		Sid:                 "PN123",
+		AccountSid:          "AC456",
+		FriendlyName:        "Moved line",
+		VoiceURL:            "https://example.com/voice",
+		VoiceMethod:         "POST",
+		VoiceFallbackURL:    "https://example.com/voice-fallback",
+		VoiceFallbackMethod: "POST",
+		SMSUrl:              "https://example.com/sms",
+		SMSMethod:           "POST",
+	}
+	benchParticipant interface{} = CreateParticipant{
+		Sid:                            "CF123",
+		From:                           "+15005550006",
+		To:                             "+15005550001",
+		Label:                          "customer",
+		StatusCallback:                 "https://example.com/status",
+		StatusCallbackEvents:           []string{"initiated", "ringing", "answered", "completed"},
+		EarlyMedia:                     TwiTrue,
+		StartConferenceOnEnter:         TwiTrue,
+		EndConferenceOnExit:            TwiTrue,
+		WaitUrl:                        "https://example.com/hold",
+		ConferenceStatusCallback:       "https://example.com/conference",
+		ConferenceStatusCallbackEvents: []string{"start", "end", "join", "leave"},
+	}
+	benchMessage interface{} = SendMessage{
+		From:           "+15005550006",
+		To:             "+15005550001",
+		Text:           "Your appointment is tomorrow at 10:00 & lasts 1h, reply C to cancel",
+		StatusCallback: "https://example.com/status?id=42",
+	}
+)
+
+func TestAppendQueryEscape(t *testing.T) {
+	var tests = []string{
+		"",
+		"plain",
+		"+15005550006",
+		"a b&c=d?e/f:g;h@i$j,k",
+		"-_.~",
+		"naïve 😀 \x00\xff",
+	}
+
+	for idx, test := range tests {
+		got := string(appendQueryEscape(nil, test))
+		if expect := url.QueryEscape(test); got != expect {
+			t.Errorf("Test %v failed; expected %#v, got %#v", idx, expect, got)
+		}
+	}
+}
+
+func TestEncodeFormMatchesValues(t *testing.T) {
+	var tests = []interface{}{
+		benchIncomingNumber, benchUpdateNumber, benchParticipant, benchMessage,
+	}
+
+	for idx, test := range tests {
+		got, err := url.ParseQuery(queryString(test))
+		if err != nil {
+			t.Fatal(err)
+		}
+		// twice, the second time from the cached fields and a reused buffer
+		again, _ := url.ParseQuery(queryString(test))
+		if got.Encode() != again.Encode() || len(got) == 0 {
+			t.Errorf("Test %v failed; got %#v then %#v", idx, got, again)
+		}
+	}
+}
+
+// TestRequestAllocs enforces the allocation budget of building a SendMessage
+// request, http.NewRequest included
+func TestRequestAllocs(t *testing.T) {
+	queryString(benchMessage) // warm the field cache
+	allocs := testing.AllocsPerRun(100, func() {
+		if _, err := httpRequest(benchMessage, "AC123", nil); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if allocs > 20 {
+		t.Errorf("expected at most 20 allocations, got %v", allocs)
+	}
+
+	allocs = testing.AllocsPerRun(100, func() { queryString(benchIncomingNumber) })
+	if allocs > 1 {
+		t.Errorf("expected at most 1 allocation encoding, got %v", allocs)
+	}
+}
+
+func benchmarkQueryString(b *testing.B, reqSt interface{}) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		queryString(reqSt)
+	}
+}
+
+func BenchmarkQueryStringCreateIncomingPhoneNumber(b *testing.B) {
+	benchmarkQueryString(b, benchIncomingNumber)
+}
+
+func BenchmarkQueryStringUpdateIncomingPhoneNumber(b *testing.B) {
+	benchmarkQueryString(b, benchUpdateNumber)
+}
+
+func BenchmarkQueryStringCreateParticipant(b *testing.B) {
+	benchmarkQueryString(b, benchParticipant)
+}
+
+func BenchmarkHttpRequestSendMessage(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		httpRequest(benchMessage, "AC123", nil)
+	}
+}
+
+// randomRequest returns a request of type t with its tagged fields set to
+// random values, some left empty
+func randomRequest(rnd *rand.Rand, t reflect.Type) interface{} {
+	const chars = "aZ09 &=?+%/é😀-_.~"
+	str := func() string {
+		if rnd.Intn(4) == 0 {
+			return ""
+		}
+		runes := []rune(chars)
+		var b strings.Builder
+		for i := rnd.Intn(8); i >= 0; i-- {
+			b.WriteRune(runes[rnd.Intn(len(runes))])
+		}
+		return b.String()
+	}
+
+	v := reflect.New(t).Elem()
+	for i := 0; i < t.NumField(); i++ {
+		fld := v.Field(i)
+		if t.Field(i).Tag == "" || !fld.CanSet() {
+			continue
+		}
+		switch {
+		case fld.Type() == moneyType:
+			fld.Set(reflect.ValueOf(Money{Micros: rnd.Int63n(100000) - 50000, Currency: "USD"}))
+		case fld.Kind() == reflect.String:
+			fld.SetString(str())
+		case fld.Kind() == reflect.Slice:
+			for n := rnd.Intn(3); n > 0; n-- {
+				fld.Set(reflect.Append(fld, reflect.ValueOf(str())))
+			}
+		}
+	}
+	return v.Interface()
+}
+
+func TestEncodeCanonical(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for _, typ := range RequestTypes() {
+		for n := 0; n < 50; n++ {
+			req := randomRequest(rnd, typ)
+			wire, err := url.ParseQuery(Encode(req))
+			if err != nil {
+				t.Fatalf("%v: %v", typ, err)
+			}
+			canonical, err := url.ParseQuery(EncodeCanonical(req))
+			if err != nil {
+				t.Fatalf("%v: %v", typ, err)
+			}
+			if !reflect.DeepEqual(wire, canonical) {
+				t.Fatalf("%v: expected equal values, got %v and %v", typ, wire, canonical)
+			}
+		}
+	}
+
+	// reordering fields changes the wire encoding only
+	type callA struct {
+		From string `url:"From"`
+		To   string `url:"To"`
+	}
+	type callB struct {
+		To   string `url:"To"`
+		From string `url:"From"`
+	}
+	a, b := callA{"+15005550006", "+15005550001"}, callB{"+15005550001", "+15005550006"}
+	if encodeForm(a) == encodeForm(b) || formValues(a).Encode() != formValues(b).Encode() {
+		t.Errorf("expected only the canonical encodings equal, got %v and %v",
+			formValues(a).Encode(), formValues(b).Encode())
+	}
+	if got := EncodeCanonical(benchMessage); !strings.HasPrefix(got, "Body=") {
+		t.Errorf("expected sorted parameters, got %v", got)
+	}
+}
+
+func TestPathControlFields(t *testing.T) {
+	const base = "https://api.twilio.com/2010-04-01/Accounts/AC123"
+	var tests = []struct {
+		Req   interface{}
+		Url   string
+		Query string
+	}{
+		{Message{Sid: "SM1", Media: true, MediaSid: "ME1"}, base + "/Messages/SM1/Media/ME1", ""},
+		{Call{Sid: "CA1", Recordings: true}, base + "/Calls/CA1/Recordings", ""},
+		{Call{Sid: "CA1", Notifications: true}, base + "/Calls/CA1/Notifications", ""},
+		{Recording{Sid: "RE1", GetRecording: true, GetMP3: true, RequestedChannels: "2"},
+			base + "/Recordings/RE1.mp3", "RequestedChannels=2"},
+		{QueueMember{Sid: "QU1", Front: true}, base + "/Queues/QU1/Members/Front", ""},
+		{DeQueue{Sid: "QU1", Front: true, Url: "https://example.com/agent"},
+			base + "/Queues/QU1/Members/Front", "Url=https%3A%2F%2Fexample.com%2Fagent"},
+		{AvailablePhoneNumbers{CountryCode: "US", Type: "Local", AreaCode: "510"},
+			base + "/AvailablePhoneNumbers/US/Local", "AreaCode=510"},
+	}
+
+	for idx, test := range tests {
+		u, err := urlString(test.Req, "AC123")
+		if err != nil || u != test.Url {
+			t.Errorf("Test %v failed; expected url %v, got %v (%v)", idx, test.Url, u, err)
+		}
+		if got := queryString(test.Req); got != test.Query {
+			t.Errorf("Test %v failed; expected query %#v, got %#v", idx, test.Query, got)
+		}
+	}
+
+	// the fields that aren't strings are path fields, marked so the encoder
+	// skips them when it learns other types
+	for _, rt := range RequestTypes() {
+		encoded := map[int]bool{}
+		for _, f := range formFields(rt) {
+			encoded[f.index] = true
+		}
+		for i := 0; i < rt.NumField(); i++ {
+			fld := rt.Field(i)
+			if fld.PkgPath != "" {
+				continue
+			}
+			if pathControl(fld.Tag) && encoded[i] {
+				t.Errorf("%v.%v is a path field but encoded", rt.Name(), fld.Name)
+			}
+			if !formKind(fld.Type) && !pathControl(fld.Tag) {
+				t.Errorf("%v.%v of type %v isn't marked url:\"-\"", rt.Name(), fld.Name, fld.Type)
+			}
+		}
+	}
+}
+
+// filledRequest sets every exported string field of the request struct req
+// to a value of its name, and []string fields to two, escaping included
+func filledRequest(req interface{}) interface{} {
+	v := reflect.New(reflect.TypeOf(req)).Elem()
+	v.Set(reflect.ValueOf(req))
+	for i := 0; i < v.NumField(); i++ {
+		fld := v.Type().Field(i)
+		if fld.PkgPath != "" {
+			continue
+		}
+		switch {
+		case fld.Type.Kind() == reflect.String:
+			v.Field(i).SetString(fld.Name + " &")
+		case fld.Type.Kind() == reflect.Slice && fld.Type.Elem().Kind() == reflect.String:
+			v.Field(i).Set(reflect.ValueOf([]string{fld.Name + "1", fld.Name + "2"}))
+		}
+	}
+	return v.Interface()
+}
+
+// TestQueryStringGolden checks the encoding of every parameter of the
+// request structs, tags included, against the encoding of the raw tags
+func TestQueryStringGolden(t *testing.T) {
+	const base = "https://api.twilio.com/2010-04-01/Accounts/AC123"
+	var tests = []struct {
+		Req    interface{}
+		Url    string
+		Expect string
+	}{
+		{SendMessage{}, base + "/Messages",
+			"Body=Text+%26&MediaUrl=MediaUrl+%26&From=From+%26&To=To+%26" +
+				"&MessagingServiceSid=MessagingServiceSid+%26" +
+				"&ApplicationSid=ApplicationSid+%26&StatusCallback=StatusCallback+%26" +
+				"&ValidityPeriod=ValidityPeriod+%26&ScheduleType=ScheduleType+%26"},
+		{MakeCall{}, base + "/Calls",
+			"From=From+%26&To=To+%26&Url=Url+%26&ApplicationSid=ApplicationSid+%26" +
+				"&Method=Method+%26&FallbackUrl=FallbackUrl+%26" +
+				"&FallbackMethod=FallbackMethod+%26&StatusCallback=StatusCallback+%26" +
+				"&StatusCallbackEvent=StatusCallbackEvents1" +
+				"&StatusCallbackEvent=StatusCallbackEvents2" +
+				"&StatusCallbackMethod=StatusCallbackMethod+%26&SendDigits=SendDigits+%26" +
+				"&MachineDetection=MachineDetection+%26" +
+				"&MachineDetectionTimeout=MachineDetectionTimeout+%26&Timeout=Timeout+%26" +
+				"&Record=Record+%26&RecordingChannels=RecordingChannels+%26&Trim=Trim+%26" +
+				"&SipAuthUsername=SipAuthUsername+%26&SipAuthPassword=SipAuthPassword+%26" +
+				"&CallerId=CallerId+%26&Byoc=Byoc+%26"},
+		{Messages{}, base + "/Messages",
+			"To=To+%26&From=From+%26&MessagingServiceSid=MessagingServiceSid+%26" +
+				"&DateSent=DateSent+%26&DateSent%3C=DateSentBefore+%26" +
+				"&DateSent%3E=DateSentAfter+%26"},
+		{UsageRecords{}, base + "/Usage/Records/SubResource &",
+			"Category=Category+%26&StartDate=StartDate+%26&EndDate=EndDate+%26"},
+		{AvailablePhoneNumbers{}, base + "/AvailablePhoneNumbers/CountryCode &/Type &",
+			"AreaCode=AreaCode+%26&Contains=Contains+%26&SmsEnabled=SmsEnabled+%26" +
+				"&MmsEnabled=MmsEnabled+%26&VoiceEnabled=VoiceEnabled+%26" +
+				"&FaxEnabled=FaxEnabled+%26" +
+				"&ExcludeAllAddressRequired=ExcludeAllAddressRequired+%26" +
+				"&ExcludeLocalAddressRequired=ExcludeLocalAddressRequired+%26" +
+				"&ExcludeForeignAddressRequired=ExcludeForeignAddressRequired+%26" +
+				"&Beta=Beta+%26"},
+	}
+	for idx, test := range tests {
+		req := filledRequest(test.Req)
+		if url, _ := urlString(req, "AC123"); url != test.Url {
+			t.Errorf("Test %v failed; expected the url %v, got %v", idx, test.Url, url)
+		}
+		if got := queryString(req); got != test.Expect {
+			t.Errorf("Test %v failed; expected\n%v\ngot\n%v", idx, test.Expect, got)
+		}
+		if warnings := CheckStructTags(reflect.TypeOf(req)); len(warnings) > 0 {
+			t.Errorf("Test %v failed; unexpected warnings %v", idx, warnings)
+		}
+	}
+}
+
+// typedFields has a field of every kind the encoder formats
+type typedFields = struct {
+	Record   bool       `url:"Record"`
+	Timeout  int        `url:"Timeout"`
+	Size     int64      `url:"Size"`
+	MaxPrice float64    `url:"MaxPrice"`
+	SendAt   time.Time  `url:"SendAt"`
+	Day      time.Time  `url:"Day,date"`
+	Trim     *bool      `url:"Trim"`
+	Limit    *int       `url:"Limit"`
+	Price    *float64   `url:"Price"`
+	Label    *string    `url:"Label"`
+	Start    *time.Time `url:"Start"`
+}
+
+func TestTypedFields(t *testing.T) {
+	at := time.Date(2020, 1, 2, 15, 4, 5, 0, time.FixedZone("EST", -5*3600))
+	no, zero, none, empty, never := false, 0, 0.0, "", time.Time{}
+	yes, limit, price, label := true, 20, 0.05, "a&b"
+
+	var tests = []struct {
+		Req    typedFields
+		Expect string
+	}{
+		{typedFields{}, ""},
+		{typedFields{Record: true, Timeout: 30, Size: 1 << 40, MaxPrice: 1.25, SendAt: at,
+			Day: at}, "Record=true&Timeout=30&Size=1099511627776&MaxPrice=1.25" +
+			"&SendAt=2020-01-02T15%3A04%3A05-05%3A00&Day=2020-01-02"},
+		{typedFields{Timeout: -1, MaxPrice: 0.1}, "Timeout=-1&MaxPrice=0.1"},
+		// the pointers are set, zero values included
+		{typedFields{Trim: &no, Limit: &zero, Price: &none, Label: &empty, Start: &never},
+			"Trim=false&Limit=0&Price=0&Label=&Start=0001-01-01T00%3A00%3A00Z"},
+		{typedFields{Trim: &yes, Limit: &limit, Price: &price, Label: &label, Start: &at},
+			"Trim=true&Limit=20&Price=0.05&Label=a%26b&Start=2020-01-02T15%3A04%3A05-05%3A00"},
+	}
+
+	for idx, test := range tests {
+		if got := Encode(test.Req); got != test.Expect {
+			t.Errorf("Test %v failed; expected %v, got %v", idx, test.Expect, got)
+		}
+		expect, _ := url.ParseQuery(test.Expect)
+		if got := formValues(test.Req); !reflect.DeepEqual(got, expect) {
+			t.Errorf("Test %v failed; expected the values %v, got %v", idx, expect, got)
+		}
+	}
+
+	if w := CheckStructTags(reflect.TypeOf(typedFields{})); len(w) != 0 {
+		t.Errorf("expected the typed fields to be sent, got %v", w)
+	}
+}
+
+func TestTypedRequestFields(t *testing.T) {
+	at := time.Date(2020, 1, 2, 15, 4, 5, 0, time.UTC)
+	unanswered, hour := 0, 3600
+	var tests = []struct {
+		Req    interface{}
+		Expect string
+		Valid  bool
+	}{
+		{MakeCall{From: "+15005550006", To: "+15005550001", Url: "https://example.com/twiml",
+			TimeoutSeconds: 30, RecordCall: true}, "Timeout=30&Record=true", true},
+		{MakeCall{From: "+15005550006", To: "+15005550001", Url: "https://example.com/twiml",
+			Timeout: "30", Record: "true"}, "Timeout=30&Record=true", true},
+		{MakeCall{From: "+15005550006", To: "+15005550001", Url: "https://example.com/twiml",
+			Timeout: "30", TimeoutSeconds: 30}, "", false},
+		{MakeCall{From: "+15005550006", To: "+15005550001", Url: "https://example.com/twiml",
+			Record: "true", RecordCall: true}, "", false},
+		{SendMessage{From: "+15005550006", To: "+15005550001", Text: "Hi",
+			ValiditySeconds: 60}, "ValidityPeriod=60", true},
+		{SendMessage{From: "+15005550006", To: "+15005550001", Text: "Hi",
+			ValidityPeriod: "60", ValiditySeconds: 60}, "", false},
+		{SendMessage{MessagingServiceSid: "MG" + strings.Repeat("0", 32), To: "+15005550001",
+			Text: "Hi", SendAt: at, ScheduleType: TwiScheduleFixed},
+			"SendAt=2020-01-02T15%3A04%3A05Z&ScheduleType=fixed", true},
+		{SendMessage{MessagingServiceSid: "MG" + strings.Repeat("0", 32), To: "+15005550001",
+			Text: "Hi", SendAt: at}, "", false},
+		{Calls{DurationOver: &unanswered, DurationUnder: &hour},
+			"Duration%3E=0&Duration%3C=3600", true},
+	}
+
+	for idx, test := range tests {
+		if err := validate(test.Req); (err == nil) != test.Valid {
+			t.Errorf("Test %v failed; expected valid %v, got %v", idx, test.Valid, err)
+			continue
+		}
+		if test.Valid && !strings.Contains(Encode(test.Req), test.Expect) {
+			t.Errorf("Test %v failed; expected %v in %v", idx, test.Expect, Encode(test.Req))
+		}
+	}
+}
+
+func TestDateRangeFilters(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2024, 1, d, 0, 0, 0, 0, time.UTC) }
+	var tests = []struct {
+		Req    interface{}
+		Expect string
+		Valid  bool
+	}{
+		{Messages{DateSentOnOrAfter: day(1), DateSentOnOrBefore: day(7)},
+			"DateSent%3E=2024-01-01&DateSent%3C=2024-01-07", true},
+		{Messages{To: "+15005550006", DateSentOnOrAfter: day(1)},
+			"To=%2B15005550006&DateSent%3E=2024-01-01", true},
+		{Messages{DateSentAfter: "2024-01-01", DateSentOnOrBefore: day(7)},
+			"DateSent%3E=2024-01-01&DateSent%3C=2024-01-07", true},
+		{Calls{StartTimeOnOrAfter: day(1), StartTimeOnOrBefore: day(31)},
+			"StartTime%3E=2024-01-01&StartTime%3C=2024-01-31", true},
+		{Notifications{MsgDateOnOrBefore: day(2)}, "MessageDate%3C=2024-01-02", true},
+		{Conferences{DateCreatedOnOrAfter: day(1), DateCreatedOnOrBefore: day(2)},
+			"DateCreated%3E=2024-01-01&DateCreated%3C=2024-01-02", true},
+		{Recordings{DateCreatedOnOrAfter: day(3)}, "DateCreated%3E=2024-01-03", true},
+		// only one field of a filter may be set
+		{Messages{DateSentAfter: "2024-01-01", DateSentOnOrAfter: day(1)}, "", false},
+		{Calls{StartTimeBefore: "2024-01-31", StartTimeOnOrBefore: day(31)}, "", false},
+		{Recordings{DateCreatedAfter: "2024-01-03", DateCreatedOnOrAfter: day(3)}, "", false},
+	}
+
+	for idx, test := range tests {
+		req, err := httpRequest(test.Req, "AC123", nil)
+		if (err == nil) != test.Valid {
+			t.Errorf("Test %v failed; expected valid %v, got %v", idx, test.Valid, err)
+			continue
+		}
+		if test.Valid && req.URL.RawQuery != test.Expect {
+			t.Errorf("Test %v failed; expected %v, got %v", idx, test.Expect, req.URL.RawQuery)
+		}
+	}
+
+	// twilio reads the operators back from the parameter names
+	vals, _ := url.ParseQuery(Encode(Messages{DateSentOnOrAfter: day(1)}))
+	if got := vals.Get("DateSent>"); got != "2024-01-01" {
+		t.Errorf("expected the parameter DateSent> of 2024-01-01, got %v", vals)
+	}
+}
@@ -0,0 +1,273 @@
+package twirest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Format is the format of a response, the 2010 API responds in XML or JSON
+type Format string
+
+const (
+	FormatXML  Format = "xml"
+	FormatJSON Format = "json"
+)
+
+// legacyJSON are the request types of the 2010 API that follow the format of
+// the client, see WithFormat. Their responses decode from XML and JSON into
+// the same structs, the other request types of the 2010 API are pinned to XML.
+var legacyJSON = []interface{}{
+	Messages{}, Message{}, SendMessage{},
+	Calls{}, Call{}, MakeCall{}, ModifyCall{},
+	Recordings{}, Recording{},
+	IncomingPhoneNumberList{}, CreateIncomingPhoneNumber{},
+	UpdateIncomingPhoneNumber{},
+	Queues{}, Queue{}, CreateQueue{}, ChangeQueue{},
+}
+
+// formatPins are the request types that respond in one format whatever the
+// format of the client, resources outside the 2010 API respond with JSON
+var formatPins = map[reflect.Type]Format{}
+
+func init() {
+	unpinned := map[reflect.Type]bool{}
+	for _, req := range legacyJSON {
+		unpinned[reflect.TypeOf(req)] = true
+	}
+	for _, req := range requestTypes {
+		t := reflect.TypeOf(req)
+		switch {
+		case isJSONRequest(req):
+			formatPins[t] = FormatJSON
+		case !unpinned[t]:
+			formatPins[t] = FormatXML
+		}
+	}
+}
+
+// WithFormat sets the format of the responses of the request types that
+// aren't pinned to one, default FormatXML. The responses are decoded into the
+// same structs whatever the format, Status.Format is the format used.
+func WithFormat(f Format) ClientOption {
+	return ClientOption{apply: func(c *TwilioClient) {
+		if f != FormatXML && f != FormatJSON {
+			c.optErr = fmt.Errorf("non valid format: '%s'", f)
+			return
+		}
+		c.format = f
+	}}
+}
+
+// requestFormat returns the format of the response to the request struct
+func (twiClient *TwilioClient) requestFormat(reqStruct interface{}) Format {
+	if f, ok := formatPins[reflect.TypeOf(reqStruct)]; ok {
+		return f
+	}
+	// audio is neither, the url keeps its extension
+	if rec, ok := reqStruct.(Recording); ok && rec.GetRecording {
+		return FormatXML
+	}
+	if twiClient.format != "" {
+		return twiClient.format
+	}
+	return FormatXML
+}
+
+// requestJSON makes a request of the 2010 API ask for JSON, the format is
+// chosen by the extension of the resource
+func requestJSON(httpReq *http.Request) {
+	ext := func(p string) string {
+		return strings.TrimSuffix(strings.TrimSuffix(p, ".xml"), ".json") + ".json"
+	}
+	httpReq.URL.Path = ext(httpReq.URL.Path)
+	if httpReq.URL.RawPath != "" {
+		httpReq.URL.RawPath = ext(httpReq.URL.RawPath)
+	}
+	httpReq.Header.Set("Accept", "application/json")
+}
+
+// decodeLegacyJSON parses a JSON response of the 2010 API into v, a pointer
+// to the struct its XML response is parsed into. The keys are the snake case
+// of the xml names, Capabilities>Voice is capabilities.voice and the list of
+// Message is messages. The values are converted to the types of the XML
+// fields: numbers and booleans to strings, quoted numbers to ints and null to
+// the zero value.
+func decodeLegacyJSON(data []byte, v interface{}) error {
+	return decodeLegacyValue(data, reflect.ValueOf(v).Elem())
+}
+
+func decodeLegacyValue(data json.RawMessage, v reflect.Value) error {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 || string(data) == "null" {
+		v.Set(reflect.Zero(v.Type()))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		obj := map[string]json.RawMessage{}
+		if err := json.Unmarshal(data, &obj); err != nil {
+			return err
+		}
+		return decodeLegacyObject(obj, v)
+	case reflect.Ptr:
+		p := reflect.New(v.Type().Elem())
+		if err := decodeLegacyValue(data, p.Elem()); err != nil {
+			return err
+		}
+		v.Set(p)
+	case reflect.Slice:
+		if v.Type().Elem().Kind() != reflect.Struct {
+			return json.Unmarshal(data, v.Addr().Interface())
+		}
+		var items []json.RawMessage
+		if err := json.Unmarshal(data, &items); err != nil {
+			return err
+		}
+		s := reflect.MakeSlice(v.Type(), len(items), len(items))
+		for i, item := range items {
+			if err := decodeLegacyValue(item, s.Index(i)); err != nil {
+				return err
+			}
+		}
+		v.Set(s)
+	case reflect.String:
+		if data[0] != '"' {
+			v.SetString(string(data))
+			return nil
+		}
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		v.SetString(s)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		s := strings.Trim(string(data), `"`)
+		if s == "" {
+			return nil
+		}
+		n, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		s := strings.Trim(string(data), `"`)
+		if s == "" {
+			return nil
+		}
+		n, err := strconv.ParseUint(s, 10, 64)
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+	default:
+		return json.Unmarshal(data, v.Addr().Interface())
+	}
+	return nil
+}
+
+// decodeLegacyObject parses the keys of a JSON object into the fields of the
+// struct v, the fields of embedded structs such as Page are keys of obj too
+func decodeLegacyObject(obj map[string]json.RawMessage, v reflect.Value) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		fld := t.Field(i)
+		if fld.PkgPath != "" {
+			continue
+		}
+		if fld.Anonymous && fld.Type.Kind() == reflect.Struct {
+			if err := decodeLegacyObject(obj, v.Field(i)); err != nil {
+				return err
+			}
+			continue
+		}
+		keys := legacyJSONKeys(fld)
+		if keys == nil {
+			continue
+		}
+		raw, ok := lookupJSON(obj, keys)
+		if !ok {
+			continue
+		}
+		if err := decodeLegacyValue(raw, v.Field(i)); err != nil {
+			return fmt.Errorf("non valid %s: %v", strings.Join(keys, "."), err)
+		}
+	}
+	return nil
+}
+
+// legacyJSONKeys returns the path of keys of the field in a JSON response,
+// nil if the field isn't in responses
+func legacyJSONKeys(fld reflect.StructField) []string {
+	if tag, ok := fld.Tag.Lookup("json"); ok {
+		name := strings.Split(tag, ",")[0]
+		if name == "-" {
+			return nil
+		}
+		if name != "" {
+			return []string{name}
+		}
+	}
+
+	name := fld.Name
+	if tag, ok := fld.Tag.Lookup("xml"); ok {
+		opts := strings.Split(tag, ",")
+		if opts[0] == "-" {
+			return nil
+		}
+		// attributes, such as the paging of lists, are named after the field
+		if opts[0] != "" && !strings.Contains(tag, ",attr") {
+			name = opts[0]
+		}
+	}
+	keys := strings.Split(name, ">")
+	for i := range keys {
+		keys[i] = snakeCase(keys[i])
+	}
+	if fld.Type.Kind() == reflect.Slice && fld.Type.Elem().Kind() == reflect.Struct {
+		keys[len(keys)-1] += "s"
+	}
+	return keys
+}
+
+// lookupJSON returns the value at the path of keys in obj
+func lookupJSON(obj map[string]json.RawMessage, keys []string) (json.RawMessage, bool) {
+	for _, key := range keys[:len(keys)-1] {
+		raw, ok := obj[key]
+		if !ok {
+			return nil, false
+		}
+		obj = map[string]json.RawMessage{}
+		if err := json.Unmarshal(raw, &obj); err != nil {
+			return nil, false
+		}
+	}
+	raw, ok := obj[keys[len(keys)-1]]
+	return raw, ok
+}
+
+// snakeCase converts an xml name to its JSON key, such as NumSegments to
+// num_segments and SMS to sms
+func snakeCase(name string) string {
+	var b strings.Builder
+	runes := []rune(name)
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) {
+			prev := runes[i-1]
+			next := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) ||
+				(unicode.IsUpper(prev) && next) {
+				b.WriteByte('_')
+			}
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
@@ -0,0 +1,157 @@
+package twirest
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// formatServer responds with the fixture of the format asked for
+type formatServer struct {
+	fixture string
+	paths   []string
+}
+
+func (s *formatServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.paths = append(s.paths, r.Header.Get("Accept")+" "+r.URL.Path)
+	ext := ".xml"
+	if strings.HasSuffix(r.URL.Path, ".json") {
+		ext = ".json"
+	}
+	body, err := ioutil.ReadFile(filepath.Join("testdata", s.fixture+ext))
+	if err != nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	w.Write(body)
+}
+
+func TestFormatConformance(t *testing.T) {
+	var tests = []struct {
+		Fixture string
+		Req     interface{}
+		Path    string
+		Got     func(TwilioResponse) interface{}
+	}{
+		{"message", Message{Sid: "SM1"}, "/Messages/SM1",
+			func(r TwilioResponse) interface{} { return r.Message }},
+		{"call", Call{Sid: "CA1"}, "/Calls/CA1",
+			func(r TwilioResponse) interface{} { return r.Call }},
+		{"recording", Recording{Sid: "RE1"}, "/Recordings/RE1.xml",
+			func(r TwilioResponse) interface{} { return r.Recording }},
+		{"incomingphonenumbers", IncomingPhoneNumberList{}, "/IncomingPhoneNumbers",
+			func(r TwilioResponse) interface{} { return r.IncomingPhoneNumbers }},
+		{"queue", Queue{Sid: "QU1"}, "/Queues/QU1",
+			func(r TwilioResponse) interface{} { return r.Queue }},
+	}
+
+	for idx, test := range tests {
+		srv := &formatServer{fixture: test.Fixture}
+		xmlClient, xmlTs := testClient(t, srv)
+		jsonClient, jsonTs := testClient(t, srv, WithFormat(FormatJSON))
+		xmlResp, xmlErr := xmlClient.Request(test.Req, false)
+		jsonResp, jsonErr := jsonClient.Request(test.Req, false)
+		xmlTs.Close()
+		jsonTs.Close()
+		if xmlErr != nil || jsonErr != nil {
+			t.Errorf("Test %v failed; unexpected errors %v, %v", idx, xmlErr, jsonErr)
+			continue
+		}
+
+		// the json extension replaces the xml one of recordings
+		base := "/2010-04-01/Accounts/AC123" + test.Path
+		expect := []string{"application/xml " + base,
+			"application/json " + strings.TrimSuffix(base, ".xml") + ".json"}
+		if !reflect.DeepEqual(srv.paths, expect) {
+			t.Errorf("Test %v failed; expected the requests %v, got %v", idx, expect, srv.paths)
+		}
+		if xmlResp.Status.Format != FormatXML || jsonResp.Status.Format != FormatJSON {
+			t.Errorf("Test %v failed; expected the formats xml and json, got %v and %v", idx,
+				xmlResp.Status.Format, jsonResp.Status.Format)
+		}
+		xmlGot, jsonGot := test.Got(xmlResp), test.Got(jsonResp)
+		if reflect.ValueOf(xmlGot).IsNil() || !reflect.DeepEqual(xmlGot, jsonGot) {
+			t.Errorf("Test %v failed; %v\nxml  %+v\njson %+v", idx, test.Fixture, xmlGot, jsonGot)
+		}
+	}
+}
+
+func TestFormatPins(t *testing.T) {
+	srv := &formatServer{fixture: "notifications"}
+	client, ts := testClient(t, srv, WithFormat(FormatJSON))
+	defer ts.Close()
+
+	// notifications aren't decoded from JSON, they stay XML
+	resp, err := client.Request(Notifications{}, false)
+	if err != nil || resp.Status.Format != FormatXML || resp.Notifications == nil {
+		t.Errorf("expected the notifications in XML, got %+v %v", resp.Status, err)
+	}
+	if expect := "application/xml /2010-04-01/Accounts/AC123/Notifications"; srv.paths[0] != expect {
+		t.Errorf("expected the request %v, got %v", expect, srv.paths[0])
+	}
+
+	var tests = []struct {
+		Req    interface{}
+		Expect Format
+	}{
+		{Notifications{}, FormatXML},
+		{Messages{}, FormatJSON},
+		{Recording{Sid: "RE1", GetRecording: true}, FormatXML},
+		{Lookup{Sid: "+15005550006"}, FormatJSON},
+	}
+	xmlClient, _ := NewClient("AC123", "token")
+	for idx, test := range tests {
+		if f := client.requestFormat(test.Req); f != test.Expect {
+			t.Errorf("Test %v failed; expected %v, got %v", idx, test.Expect, f)
+		}
+		// the default follows the pins, XML otherwise
+		if _, pinned := formatPins[reflect.TypeOf(test.Req)]; !pinned &&
+			xmlClient.requestFormat(test.Req) != FormatXML {
+			t.Errorf("Test %v failed; expected the default XML", idx)
+		}
+	}
+
+	if _, err := NewClient("AC123", "token", WithFormat("csv")); err == nil {
+		t.Errorf("expected a non valid format to fail")
+	}
+}
+
+func TestFormatNextPage(t *testing.T) {
+	srv := &formatServer{fixture: "incomingphonenumbers"}
+	client, ts := testClient(t, srv, WithFormat(FormatJSON))
+	defer ts.Close()
+
+	uri := "/2010-04-01/Accounts/AC123/IncomingPhoneNumbers.json?Page=1"
+	resp, err := client.NextPage(context.Background(), uri, IncomingPhoneNumberList{})
+	if err != nil || resp.IncomingPhoneNumbers == nil || resp.Status.Format != FormatJSON {
+		t.Fatalf("unexpected response %+v %v", resp.Status, err)
+	}
+	expect := "application/json /2010-04-01/Accounts/AC123/IncomingPhoneNumbers.json"
+	if srv.paths[0] != expect {
+		t.Errorf("expected the request %v, got %v", expect, srv.paths[0])
+	}
+}
+
+func TestSnakeCase(t *testing.T) {
+	var tests = []struct {
+		Name   string
+		Expect string
+	}{
+		{"Sid", "sid"},
+		{"NumSegments", "num_segments"},
+		{"SMS", "sms"},
+		{"VoiceCallerIdLookup", "voice_caller_id_lookup"},
+		{"SMSUrl", "sms_url"},
+		{"AddOnResults", "add_on_results"},
+		{"Page2Uri", "page2_uri"},
+	}
+	for idx, test := range tests {
+		if got := snakeCase(test.Name); got != test.Expect {
+			t.Errorf("Test %v failed; expected %v, got %v", idx, test.Expect, got)
+		}
+	}
+}
@@ -0,0 +1,259 @@
+package twirest
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrFromNotOwned is returned when a From number is neither an incoming phone
+// number of the account nor a verified outgoing caller ID. Suggestion is the
+// owned number closest to From, if any.
+type ErrFromNotOwned struct {
+	From       string
+	Suggestion string
+}
+
+func (e *ErrFromNotOwned) Error() string {
+	if e.Suggestion != "" {
+		return fmt.Sprintf("from number not owned or verified: '%s' (did you mean '%s'?)",
+			e.From, e.Suggestion)
+	}
+	return fmt.Sprintf("from number not owned or verified: '%s'", e.From)
+}
+
+const (
+	// ownedTTL is how long the owned numbers are cached by default
+	ownedTTL = 10 * time.Minute
+	// ownedMissRefresh is the minimum age of the cache before a number
+	// missing from it triggers a refresh
+	ownedMissRefresh = time.Minute
+)
+
+// ownedNumbers caches the numbers an account may use as From
+type ownedNumbers struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	numbers map[string]bool
+	fetched time.Time
+	// refresh is the listing in flight, shared by the callers waiting for it
+	refresh *ownedRefresh
+	// gen counts the invalidations, a listing started before one isn't cached
+	gen int
+}
+
+// ownedRefresh is a listing of the owned numbers, done is closed when
+// numbers or err are set
+type ownedRefresh struct {
+	done    chan struct{}
+	numbers map[string]bool
+	err     error
+	// waiters is the number of callers waiting, the listing is canceled when
+	// the last one gives up
+	waiters int
+	cancel  context.CancelFunc
+	gen     int
+	started time.Time
+}
+
+func newOwnedNumbers() *ownedNumbers {
+	return &ownedNumbers{ttl: ownedTTL}
+}
+
+// update invalidates the cache after requests that change the incoming
+// phone numbers of the account
+func (o *ownedNumbers) update(reqStruct interface{}) {
+	if o == nil {
+		return
+	}
+	switch reqSt := reqStruct.(type) {
+	case UpdateIncomingPhoneNumber:
+		if reqSt.AccountSid == "" {
+			return
+		}
+		o.invalidate()
+	case CreateIncomingPhoneNumber, DeleteIncomingPhoneNumber:
+		o.invalidate()
+	}
+}
+
+func (o *ownedNumbers) invalidate() {
+	o.mu.Lock()
+	o.numbers = nil
+	o.gen++
+	o.mu.Unlock()
+}
+
+// ValidateFrom checks that from is an incoming phone number of the account
+// or a verified outgoing caller ID. The numbers are listed from twilio and
+// cached, the cache is refreshed when it expires or when from isn't found in
+// it. Concurrent callers share a refresh, canceling ctx only stops the wait
+// of this caller. An *ErrFromNotOwned is returned if from can't be used.
+func (twiClient *TwilioClient) ValidateFrom(ctx context.Context, from string) error {
+	o := twiClient.owned
+	if o == nil {
+		return fmt.Errorf("client has no number cache, use NewClient")
+	}
+
+	o.mu.Lock()
+	numbers := o.numbers
+	now := twiClient.timeSource().Now()
+	age := now.Sub(o.fetched)
+	if numbers == nil || age > o.ttl || (!numbers[from] && age > ownedMissRefresh) {
+		r := o.refresh
+		if r == nil {
+			r = twiClient.startRefresh(ctx, now)
+		}
+		r.waiters++
+		o.mu.Unlock()
+
+		select {
+		case <-r.done:
+			if r.err != nil {
+				return r.err
+			}
+			numbers = r.numbers
+		case <-ctx.Done():
+			o.mu.Lock()
+			r.waiters--
+			if r.waiters == 0 {
+				r.cancel()
+				if o.refresh == r {
+					o.refresh = nil
+				}
+			}
+			o.mu.Unlock()
+			return ctx.Err()
+		}
+	} else {
+		o.mu.Unlock()
+	}
+
+	if numbers[from] {
+		return nil
+	}
+	return &ErrFromNotOwned{From: from, Suggestion: nearestNumber(from, numbers)}
+}
+
+// startRefresh lists the owned numbers in the background and caches them,
+// the owned numbers must be locked. The listing keeps the values of ctx but
+// not its cancellation, that's up to the waiters.
+func (twiClient *TwilioClient) startRefresh(ctx context.Context, now time.Time) *ownedRefresh {
+	o := twiClient.owned
+	ctx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	r := &ownedRefresh{
+		done:    make(chan struct{}),
+		cancel:  cancel,
+		gen:     o.gen,
+		started: now,
+	}
+	o.refresh = r
+
+	go func() {
+		defer cancel()
+		numbers, err := twiClient.listOwned(ctx)
+
+		o.mu.Lock()
+		r.numbers, r.err = numbers, err
+		if o.refresh == r {
+			o.refresh = nil
+			if err == nil && o.gen == r.gen {
+				o.numbers = numbers
+				o.fetched = r.started
+			}
+		}
+		o.mu.Unlock()
+		close(r.done)
+	}()
+	return r
+}
+
+// checkFrom validates the From number of send requests. Only phone numbers
+// are checked, messaging services, client and sip identities and alphanumeric
+// sender IDs are let through.
+func (twiClient *TwilioClient) checkFrom(ctx context.Context, reqStruct interface{}) error {
+	var from string
+	switch reqSt := reqStruct.(type) {
+	case SendMessage:
+		from = reqSt.From
+	case MakeCall:
+		from = reqSt.From
+	}
+	if !strings.HasPrefix(from, "+") {
+		return nil
+	}
+	return twiClient.ValidateFrom(ctx, from)
+}
+
+// listOwned lists all pages of incoming phone numbers and outgoing caller IDs
+func (twiClient *TwilioClient) listOwned(ctx context.Context) (map[string]bool, error) {
+	numbers := make(map[string]bool)
+
+	resp, err := twiClient.RequestWithContext(ctx, IncomingPhoneNumberList{}, false)
+	for err == nil && resp.IncomingPhoneNumbers != nil {
+		for _, n := range resp.IncomingPhoneNumbers.IncomingPhoneNumber {
+			numbers[n.PhoneNumber] = true
+		}
+		next := resp.IncomingPhoneNumbers.NextPageUri
+		if next == "" {
+			break
+		}
+		resp, err = twiClient.requestUri(ctx, next, IncomingPhoneNumberList{})
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err = twiClient.RequestWithContext(ctx, OutgoingCallerIds{}, false)
+	for err == nil && resp.OutgoingCallerIds != nil {
+		for _, n := range resp.OutgoingCallerIds.OutgoingCallerId {
+			numbers[n.PhoneNumber] = true
+		}
+		next := resp.OutgoingCallerIds.NextPageUri
+		if next == "" {
+			break
+		}
+		resp, err = twiClient.requestUri(ctx, next, OutgoingCallerIds{})
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return numbers, nil
+}
+
+// nearestNumber returns the number with the smallest edit distance to from
+func nearestNumber(from string, numbers map[string]bool) (nearest string) {
+	best := -1
+	for n := range numbers {
+		d := editDistance(from, n)
+		if best < 0 || d < best || (d == best && n < nearest) {
+			best = d
+			nearest = n
+		}
+	}
+	return nearest
+}
+
+// editDistance is the Levenshtein distance between a and b
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
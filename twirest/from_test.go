@@ -0,0 +1,156 @@
+package twirest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// ownedHandler serves two pages of incoming phone numbers and one page of
+// outgoing caller IDs, counting the listings made
+func ownedHandler(listings *int64, numbers *[]string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/IncomingPhoneNumbers") &&
+			r.Method == "GET" && r.URL.Query().Get("Page") == "":
+			atomic.AddInt64(listings, 1)
+			fmt.Fprintf(w, `<TwilioResponse><IncomingPhoneNumbers nextpageuri="%s">
+				<IncomingPhoneNumber><PhoneNumber>%s</PhoneNumber></IncomingPhoneNumber>
+				</IncomingPhoneNumbers></TwilioResponse>`,
+				"/2010-04-01/Accounts/AC123/IncomingPhoneNumbers?Page=1", (*numbers)[0])
+		case strings.HasSuffix(r.URL.Path, "/IncomingPhoneNumbers") && r.Method == "GET":
+			fmt.Fprint(w, `<TwilioResponse><IncomingPhoneNumbers>`)
+			for _, n := range (*numbers)[1:] {
+				fmt.Fprintf(w, `<IncomingPhoneNumber><PhoneNumber>%s</PhoneNumber></IncomingPhoneNumber>`, n)
+			}
+			fmt.Fprint(w, `</IncomingPhoneNumbers></TwilioResponse>`)
+		case strings.HasSuffix(r.URL.Path, "/OutgoingCallerIds"):
+			fmt.Fprint(w, `<TwilioResponse><OutgoingCallerIds><OutgoingCallerId>
+				<PhoneNumber>+15005550009</PhoneNumber></OutgoingCallerId>
+				</OutgoingCallerIds></TwilioResponse>`)
+		case strings.HasSuffix(r.URL.Path, "/IncomingPhoneNumbers") && r.Method == "POST":
+			*numbers = append(*numbers, r.FormValue("PhoneNumber"))
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprint(w, `<TwilioResponse><IncomingPhoneNumber/></TwilioResponse>`)
+		case strings.HasSuffix(r.URL.Path, "/Messages"):
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprint(w, `<TwilioResponse><Message><Sid>SM1</Sid></Message></TwilioResponse>`)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
+
+func TestValidateFrom(t *testing.T) {
+	var listings int64
+	numbers := []string{"+15005550006", "+15005550007"}
+	client, ts := testClient(t, ownedHandler(&listings, &numbers))
+	defer ts.Close()
+
+	ctx := context.Background()
+	for _, from := range []string{"+15005550006", "+15005550007", "+15005550009"} {
+		if err := client.ValidateFrom(ctx, from); err != nil {
+			t.Errorf("expected %v to be valid, got %v", from, err)
+		}
+	}
+	if listings != 1 {
+		t.Errorf("expected numbers to be listed once, got %v", listings)
+	}
+
+	err := client.ValidateFrom(ctx, "+15005550016")
+	var notOwned *ErrFromNotOwned
+	if !errors.As(err, &notOwned) {
+		t.Fatalf("expected ErrFromNotOwned, got %v", err)
+	}
+	if notOwned.Suggestion != "+15005550006" {
+		t.Errorf("expected suggestion +15005550006, got %v", notOwned.Suggestion)
+	}
+	// a fresh cache isn't refreshed on a miss
+	if listings != 1 {
+		t.Errorf("expected numbers to be listed once, got %v", listings)
+	}
+
+	// buying a number through the client invalidates the cache
+	_, err = client.Request(CreateIncomingPhoneNumber{PhoneNumber: "+15005550016"}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := client.ValidateFrom(ctx, "+15005550016"); err != nil {
+		t.Errorf("expected purchased number to be valid, got %v", err)
+	}
+	if listings != 2 {
+		t.Errorf("expected numbers to be listed twice, got %v", listings)
+	}
+}
+
+func TestFromValidationOption(t *testing.T) {
+	var listings int64
+	numbers := []string{"+15005550006", "+15005550007"}
+	client, ts := testClient(t, ownedHandler(&listings, &numbers),
+		WithFromValidation(0))
+	defer ts.Close()
+
+	var tests = []struct {
+		Msg SendMessage
+		Ok  bool
+	}{
+		{SendMessage{From: "+15005550006", To: "+15005550001", Text: "hi"}, true},
+		{SendMessage{From: "+15005550001", To: "+15005550006", Text: "hi"}, false},
+		{SendMessage{From: "ACMECORP", To: "+447700900123", Text: "hi"}, true},
+		{SendMessage{MessagingServiceSid: "MG123", To: "+15005550006", Text: "hi"}, true},
+	}
+
+	for idx, test := range tests {
+		_, err := client.Request(test.Msg, false)
+		if (err == nil) != test.Ok {
+			t.Errorf("Test %v failed; expected ok %v, got %v", idx, test.Ok, err)
+		}
+	}
+}
+
+func TestValidateFromSharedRefresh(t *testing.T) {
+	var listings int64
+	numbers := []string{"+15005550006", "+15005550007"}
+	owned := ownedHandler(&listings, &numbers)
+	listing := make(chan struct{}, 1)
+	release := make(chan struct{})
+	client, ts := testClient(t, http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Query().Get("Page") == "" && strings.HasSuffix(r.URL.Path, "/IncomingPhoneNumbers") {
+				listing <- struct{}{}
+				<-release
+			}
+			owned.ServeHTTP(w, r)
+		}))
+	defer ts.Close()
+
+	canceled, cancel := context.WithCancel(context.Background())
+	errs := make(chan error, 2)
+	go func() { errs <- client.ValidateFrom(canceled, "+15005550006") }()
+	<-listing
+	go func() { errs <- client.ValidateFrom(context.Background(), "+15005550007") }()
+	for waiting := 0; waiting < 2; time.Sleep(time.Millisecond) {
+		client.owned.mu.Lock()
+		waiting = client.owned.refresh.waiters
+		client.owned.mu.Unlock()
+	}
+
+	// the canceled caller returns while the listing is blocked, the other one
+	// keeps waiting for it
+	cancel()
+	if err := <-errs; !errors.Is(err, context.Canceled) {
+		t.Errorf("expected the canceled caller to return, got %v", err)
+	}
+	close(release)
+	if err := <-errs; err != nil {
+		t.Errorf("expected +15005550007 to be valid, got %v", err)
+	}
+	if atomic.LoadInt64(&listings) != 1 {
+		t.Errorf("expected numbers to be listed once, got %v", listings)
+	}
+}
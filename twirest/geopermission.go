@@ -0,0 +1,156 @@
+package twirest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// Dialing permissions resources
+// (https://www.twilio.com/docs/voice/api/dialingpermissions). The geographic
+// permissions of an account decide the countries it can reach, they live on
+// voice.twilio.com and use JSON responses. The geo permissions of messaging
+// have no public API, they are managed in the console.
+
+// codeGeoPermissionSMS is the exception code of a message to a country the
+// account isn't permitted to send to
+const codeGeoPermissionSMS = 21408
+
+// ErrGeoPermissionSMS is the sentinel of the error 21408, see
+// ErrGeoPermissionDisabled
+var ErrGeoPermissionSMS = sentinel(codeGeoPermissionSMS,
+	"permission to send to the region not enabled")
+
+// ErrGeoPermissionDisabled is the error of a message or call to a country the
+// geographic permissions of the account don't allow, the error 21408 or
+// 21215. Country is the ISO country code of To, "" if it isn't known. It
+// wraps the TwilioError of the response.
+type ErrGeoPermissionDisabled struct {
+	To      string
+	Country string
+	Err     *TwilioError
+}
+
+func (e *ErrGeoPermissionDisabled) Error() string {
+	country := e.Country
+	if country == "" {
+		country = "unknown country"
+	}
+	return fmt.Sprintf("geo permission for %s (%s) not enabled: %v", e.To, country, e.Err)
+}
+
+// Unwrap returns the TwilioError of the response
+func (e *ErrGeoPermissionDisabled) Unwrap() error {
+	return e.Err
+}
+
+// geoPermissionErr converts the TwilioError err of a message or call refused
+// for the geographic permissions to an *ErrGeoPermissionDisabled
+func geoPermissionErr(reqStruct interface{}, err error) error {
+	var te *TwilioError
+	if !errors.As(err, &te) ||
+		(te.Code != codeGeoPermissionSMS && te.Code != ErrGeoPermission.Code) {
+		return err
+	}
+	var to string
+	switch req := reqStruct.(type) {
+	case SendMessage:
+		to = req.To
+	case MakeCall:
+		to = req.To
+	default:
+		return err
+	}
+	return &ErrGeoPermissionDisabled{To: to, Country: countryOf(to), Err: te}
+}
+
+// DialingCountries requests the dialing permissions of countries
+type DialingCountries struct {
+	domain                          uri    `url:"voice.twilio.com/v1"`
+	resource                        uri    `url:"/DialingPermissions/Countries"`
+	IsoCode                         string `url:"IsoCode"`
+	Continent                       string `url:"Continent"`
+	CountryCode                     string `url:"CountryCode"`
+	LowRiskNumbersEnabled           Bool   `url:"LowRiskNumbersEnabled"`
+	HighRiskSpecialNumbersEnabled   Bool   `url:"HighRiskSpecialNumbersEnabled"`
+	HighRiskTollfraudNumbersEnabled Bool   `url:"HighRiskTollfraudNumbersEnabled"`
+	PageSize                        int    `url:"PageSize"` // at most 1000, default 50
+	Page                            int    `url:"Page"`
+}
+
+// DialingCountry requests the dialing permissions of a country
+type DialingCountry struct {
+	domain   uri    `url:"voice.twilio.com/v1"`
+	resource uri    `url:"/DialingPermissions/Countries"`
+	Sid      string // ISO country code
+}
+
+// UpdateDialingPermissions updates the dialing permissions of countries at
+// once, see NewUpdateDialingPermissions
+type UpdateDialingPermissions struct {
+	domain        uri    `url:"voice.twilio.com/v1"`
+	resource      uri    `url:"/DialingPermissions/BulkCountryUpdates"`
+	UpdateRequest string `url:"UpdateRequest"` // JSON list of updates
+}
+
+// DialingPermissionUpdate is the update of the permissions of a country,
+// permissions left empty are unchanged
+type DialingPermissionUpdate struct {
+	IsoCode                         string `json:"iso_code"`
+	LowRiskNumbersEnabled           Bool   `json:"low_risk_numbers_enabled,omitempty"`
+	HighRiskSpecialNumbersEnabled   Bool   `json:"high_risk_special_numbers_enabled,omitempty"`
+	HighRiskTollfraudNumbersEnabled Bool   `json:"high_risk_tollfraud_numbers_enabled,omitempty"`
+}
+
+type DialingCountriesResponse struct {
+	Meta      Meta                     `json:"meta"`
+	Countries []DialingCountryResponse `json:"content"`
+}
+
+type DialingCountryResponse struct {
+	IsoCode                         string   `json:"iso_code"`
+	Name                            string   `json:"name"`
+	Continent                       string   `json:"continent"`
+	CountryCodes                    []string `json:"country_codes"`
+	LowRiskNumbersEnabled           bool     `json:"low_risk_numbers_enabled"`
+	HighRiskSpecialNumbersEnabled   bool     `json:"high_risk_special_numbers_enabled"`
+	HighRiskTollfraudNumbersEnabled bool     `json:"high_risk_tollfraud_numbers_enabled"`
+	Url                             string   `json:"url"`
+}
+
+type DialingUpdateResponse struct {
+	UpdateCount   int    `json:"update_count"`
+	UpdateRequest string `json:"update_request"`
+}
+
+// NewUpdateDialingPermissions returns the request of updates
+func NewUpdateDialingPermissions(updates ...DialingPermissionUpdate) (
+	UpdateDialingPermissions, error) {
+
+	for _, u := range updates {
+		if len(u.IsoCode) != 2 {
+			return UpdateDialingPermissions{},
+				fmt.Errorf("non valid ISO country code: '%s'", u.IsoCode)
+		}
+	}
+	data, err := json.Marshal(updates)
+	if err != nil {
+		return UpdateDialingPermissions{}, err
+	}
+	return UpdateDialingPermissions{UpdateRequest: string(data)}, nil
+}
+
+// CheckDestinationAllowed reports if the geographic permissions of the
+// account allow calls to the low risk numbers of the country of number, an
+// E.164 number whose country is in CallingCodes
+func (twiClient *TwilioClient) CheckDestinationAllowed(number string) (bool, error) {
+	country := countryOf(number)
+	if country == "" {
+		return false, fmt.Errorf("non valid destination: '%s', unknown country", number)
+	}
+	resp, err := twiClient.Request(DialingCountry{Sid: country}, false)
+	if err != nil {
+		return false, err
+	}
+	return resp.DialingCountry != nil && resp.DialingCountry.LowRiskNumbersEnabled, nil
+}
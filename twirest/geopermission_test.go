@@ -0,0 +1,99 @@
+package twirest
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestGeoPermissionDisabled(t *testing.T) {
+	client, ts := testClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(xmlHeader + `<TwilioResponse><RestException><Code>21408</Code>` +
+			`<Message>Permission to send an SMS has not been enabled for the region ` +
+			`indicated by the 'To' number: +447911123456.</Message><Status>400</Status>` +
+			`</RestException></TwilioResponse>`))
+	}))
+	defer ts.Close()
+
+	var tests = []struct {
+		Req     interface{}
+		Country string
+		Typed   bool
+	}{
+		{SendMessage{From: "+15005550006", To: "+447911123456", Text: "Hi"}, "GB", true},
+		{SendMessage{From: "+15005550006", To: "+99912345678", Text: "Hi"}, "", true},
+		{Messages{To: "+447911123456"}, "", false},
+	}
+
+	for idx, test := range tests {
+		_, err := client.Request(test.Req, false)
+		var geo *ErrGeoPermissionDisabled
+		if errors.As(err, &geo) != test.Typed {
+			t.Errorf("Test %v failed; expected typed %v, got %T %v", idx, test.Typed, err, err)
+			continue
+		}
+		if !errors.Is(err, ErrGeoPermissionSMS) {
+			t.Errorf("Test %v failed; expected the sentinel, got %v", idx, err)
+		}
+		if test.Typed && (geo.Country != test.Country || geo.Err.Code != 21408) {
+			t.Errorf("Test %v failed; expected country %q, got %+v", idx, test.Country, geo)
+		}
+	}
+}
+
+func TestCheckDestinationAllowed(t *testing.T) {
+	var form string
+	client, ts := testClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/DialingPermissions/Countries/GB":
+			w.Write([]byte(`{"iso_code":"GB","name":"United Kingdom","continent":"EUROPE",` +
+				`"country_codes":["+44"],"low_risk_numbers_enabled":true}`))
+		case "/v1/DialingPermissions/Countries/DE":
+			w.Write([]byte(`{"iso_code":"DE","low_risk_numbers_enabled":false}`))
+		case "/v1/DialingPermissions/BulkCountryUpdates":
+			r.ParseForm()
+			form = r.PostForm.Get("UpdateRequest")
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"update_count":1,"update_request":"accepted"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"code":20404,"message":"not found","status":404}`))
+		}
+	}))
+	defer ts.Close()
+
+	var tests = []struct {
+		Number  string
+		Allowed bool
+		Valid   bool
+	}{
+		{"+447911123456", true, true},
+		{"+4915112345678", false, true},
+		{"+99912345678", false, false},
+		{"+33612345678", false, false},
+	}
+	for idx, test := range tests {
+		allowed, err := client.CheckDestinationAllowed(test.Number)
+		if allowed != test.Allowed || (err == nil) != test.Valid {
+			t.Errorf("Test %v failed; expected %v %v, got %v %v", idx, test.Allowed,
+				test.Valid, allowed, err)
+		}
+	}
+
+	req, err := NewUpdateDialingPermissions(DialingPermissionUpdate{IsoCode: "DE",
+		LowRiskNumbersEnabled: TwiTrue})
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := client.Request(req, false)
+	if err != nil || resp.DialingUpdate == nil || resp.DialingUpdate.UpdateCount != 1 {
+		t.Errorf("unexpected response %+v %v", resp.DialingUpdate, err)
+	}
+	if expect := `[{"iso_code":"DE","low_risk_numbers_enabled":"true"}]`; form != expect {
+		t.Errorf("expected the update %v, got %v", expect, form)
+	}
+	if _, err := NewUpdateDialingPermissions(DialingPermissionUpdate{IsoCode: "DEU"}); err == nil {
+		t.Errorf("expected a non valid country code to fail")
+	}
+}
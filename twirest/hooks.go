@@ -0,0 +1,67 @@
+package twirest
+
+import (
+	"net/http"
+	"time"
+)
+
+// RequestHook is called with every http request of a client before it is
+// sent, such as to add headers or log it for auditing
+type RequestHook func(*http.Request)
+
+// ResponseHook is called with the response to every http request of a
+// client and the time the request took, resp is nil if it failed
+type ResponseHook func(resp *http.Response, elapsed time.Duration)
+
+// WithRequestHook adds hook to the hooks the client calls before sending a
+// request, in the order they were added. Retried requests are passed to the
+// hooks on every attempt. The request is sent to its final url and has its
+// headers set. Hooks may add headers but can't change the authentication:
+// the Authorization header is restored after the hooks ran.
+func WithRequestHook(hook RequestHook) ClientOption {
+	return ClientOption{apply: func(c *TwilioClient) {
+		c.requestHooks = append(c.requestHooks, hook)
+	}}
+}
+
+// WithResponseHook adds hook to the hooks the client calls with every
+// response, in the order they were added, before it is parsed. Hooks
+// mustn't read or close the body of the response.
+func WithResponseHook(hook ResponseHook) ClientOption {
+	return ClientOption{apply: func(c *TwilioClient) {
+		c.responseHooks = append(c.responseHooks, hook)
+	}}
+}
+
+// sendHooked sends httpReq with the http client, calling the hooks around it
+// and dumping it to the debug writer
+func (twiClient *TwilioClient) sendHooked(httpReq *http.Request) (*http.Response, error) {
+	if len(twiClient.requestHooks) > 0 {
+		auth := httpReq.Header.Get("Authorization")
+		for _, hook := range twiClient.requestHooks {
+			hook(httpReq)
+		}
+		if auth != "" {
+			httpReq.Header.Set("Authorization", auth)
+		}
+	}
+
+	if twiClient.debug != nil {
+		twiClient.dump(httpReq, nil, nil)
+	}
+
+	if len(twiClient.responseHooks) == 0 && twiClient.debug == nil {
+		return twiClient.httpclient.Do(httpReq)
+	}
+	clock := twiClient.timeSource()
+	start := clock.Now()
+	resp, err := twiClient.httpclient.Do(httpReq)
+	if twiClient.debug != nil {
+		twiClient.dump(httpReq, resp, err)
+	}
+	elapsed := clock.Now().Sub(start)
+	for _, hook := range twiClient.responseHooks {
+		hook(resp, elapsed)
+	}
+	return resp, err
+}
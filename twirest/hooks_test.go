@@ -0,0 +1,52 @@
+package twirest
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestHooks(t *testing.T) {
+	clock := newFakeClock()
+	var seen []string
+	client, ts := testClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, _, ok := r.BasicAuth()
+		seen = append(seen, fmt.Sprintf("server %v %v %v", user, ok, r.Header.Get("X-Audit")))
+		clock.Advance(time.Second)
+		w.Write([]byte(xmlHeader + "<TwilioResponse></TwilioResponse>"))
+	}),
+		WithClock(clock),
+		WithRequestHook(func(r *http.Request) {
+			seen = append(seen, "request 1 "+r.URL.Path)
+			r.Header.Set("X-Audit", "a")
+		}),
+		WithResponseHook(func(resp *http.Response, d time.Duration) {
+			seen = append(seen, fmt.Sprintf("response 1 %v %v", resp.StatusCode, d))
+		}),
+		WithRequestHook(func(r *http.Request) {
+			seen = append(seen, "request 2 "+r.Header.Get("X-Audit"))
+			r.Header.Set("X-Audit", r.Header.Get("X-Audit")+"b")
+			// the authentication can't be removed
+			r.Header.Del("Authorization")
+		}),
+		WithResponseHook(func(resp *http.Response, d time.Duration) {
+			seen = append(seen, "response 2")
+		}))
+	defer ts.Close()
+
+	if _, err := client.Request(Queues{}, false); err != nil {
+		t.Fatal(err)
+	}
+	expect := []string{
+		"request 1 /2010-04-01/Accounts/AC123/Queues",
+		"request 2 a",
+		"server AC123 true ab",
+		"response 1 200 1s",
+		"response 2",
+	}
+	if !reflect.DeepEqual(seen, expect) {
+		t.Errorf("expected %v, got %v", expect, seen)
+	}
+}
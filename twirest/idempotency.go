@@ -0,0 +1,37 @@
+package twirest
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+)
+
+// IdempotencyHeader is the header of the idempotency key of a POST request
+const IdempotencyHeader = "Idempotency-Key"
+
+type idempotencyKey struct{}
+
+// WithIdempotencyKey returns a context that makes RequestWithContext send
+// POST requests with key, so a request sent again with the same key, such as
+// after a network timeout, is acted on once. The retries of WithRetry send
+// the key of the first attempt. GET and DELETE requests are sent without it.
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKey{}, key)
+}
+
+// NewIdempotencyKey returns a random key for WithIdempotencyKey, to keep
+// with the request while it may be sent again
+func NewIdempotencyKey() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// setIdempotencyKey sets the idempotency key of ctx on a POST request
+func setIdempotencyKey(ctx context.Context, httpReq *http.Request) {
+	key, _ := ctx.Value(idempotencyKey{}).(string)
+	if key != "" && httpReq.Method == "POST" {
+		httpReq.Header.Set(IdempotencyHeader, key)
+	}
+}
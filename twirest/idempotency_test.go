@@ -0,0 +1,54 @@
+package twirest
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestIdempotencyKey(t *testing.T) {
+	var mu sync.Mutex
+	var keys []string
+	attempts := 0
+	client, ts := testClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		keys = append(keys, r.Method+" "+r.Header.Get(IdempotencyHeader))
+		// the first POST times out at twilio's edge
+		if attempts++; attempts == 1 {
+			w.WriteHeader(http.StatusGatewayTimeout)
+			return
+		}
+		if r.Method == "DELETE" {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		w.Write([]byte(xmlHeader + "<TwilioResponse></TwilioResponse>"))
+	}), WithRetry(RetryOptions{RetryPOST: true, Clock: newFakeClock()}))
+	defer ts.Close()
+
+	key := NewIdempotencyKey()
+	if len(key) != 32 || key == NewIdempotencyKey() {
+		t.Errorf("expected a random key, got %v", key)
+	}
+	ctx := WithIdempotencyKey(context.Background(), key)
+	for _, req := range []interface{}{
+		SendMessage{From: "+15005550006", To: "+15005550001", Text: "Hi"},
+		Messages{}, DeleteRecording{Sid: "RE123"},
+	} {
+		if _, err := client.RequestWithContext(ctx, req, false); err != nil {
+			t.Error(err)
+		}
+	}
+	if _, err := client.Request(MakeCall{From: "+15005550006", To: "+15005550001",
+		Url: "https://example.com"}, false); err != nil {
+		t.Error(err)
+	}
+
+	expect := []string{"POST " + key, "POST " + key, "GET ", "DELETE ", "POST "}
+	if !reflect.DeepEqual(keys, expect) {
+		t.Errorf("expected %v, got %v", expect, keys)
+	}
+}
@@ -0,0 +1,110 @@
+package twirest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// Voice Insights resources (https://www.twilio.com/docs/voice/voice-insights/api).
+// The call summary has the quality metrics twilio measured on each edge of a
+// call, these live on insights.twilio.com and use JSON responses.
+
+// Call summary processing states
+const (
+	TwiProcessingComplete = "complete"
+	TwiProcessingPartial  = "partial"
+)
+
+// CallSummary requests the Voice Insights summary of a call. The summary is
+// complete about half an hour after the call ended, set ProcessingState to
+// partial to get what is known before.
+type CallSummary struct {
+	domain          uri    `url:"insights.twilio.com/v1"`
+	resource        uri    `url:"/Voice"`
+	Sid             string // Call Sid
+	subresource     uri    `url:"/Summary"`
+	ProcessingState string `url:"ProcessingState"`
+}
+
+type CallSummaryResponse struct {
+	AccountSid      string       `json:"account_sid"`
+	CallSid         string       `json:"call_sid"`
+	CallType        string       `json:"call_type"`
+	CallState       string       `json:"call_state"`
+	ProcessingState string       `json:"processing_state"`
+	StartTime       string       `json:"start_time"`
+	EndTime         string       `json:"end_time"`
+	Duration        int          `json:"duration"`         // in seconds
+	ConnectDuration int          `json:"connect_duration"` // in seconds
+	Tags            []string     `json:"tags"`             // such as high_jitter
+	CarrierEdge     *EdgeSummary `json:"carrier_edge"`
+	ClientEdge      *EdgeSummary `json:"client_edge"`
+	SdkEdge         *EdgeSummary `json:"sdk_edge"`
+	SipEdge         *EdgeSummary `json:"sip_edge"`
+	Url             string       `json:"url"`
+}
+
+// EdgeSummary holds the metrics of the media twilio received from (inbound)
+// and sent to (outbound) an edge of the call, the edges a call didn't use
+// are nil
+type EdgeSummary struct {
+	Metrics struct {
+		Inbound  *StreamMetrics `json:"inbound"`
+		Outbound *StreamMetrics `json:"outbound"`
+	} `json:"metrics"`
+}
+
+// StreamMetrics is the quality of one direction of the audio of an edge
+type StreamMetrics struct {
+	CodecName             string  `json:"codec_name"`
+	PacketsReceived       int     `json:"packets_received"`
+	PacketsLost           int     `json:"packets_lost"`
+	PacketsLossPercentage float64 `json:"packets_loss_percentage"`
+	Jitter                *Jitter `json:"jitter"`
+}
+
+// Jitter is the jitter of a stream in milliseconds
+type Jitter struct {
+	Max float64 `json:"max"`
+	Avg float64 `json:"avg"`
+}
+
+// ParticipantQuality is a conference participant with the quality of its
+// call. The 2010 API has no audio metrics, these come from Voice Insights.
+type ParticipantQuality struct {
+	Participant ParticipantResponse
+	// Summary is the partial or complete summary of the call, nil while
+	// Voice Insights has none
+	Summary *CallSummaryResponse
+}
+
+// ParticipantQuality fetches the participant callSid of the conference
+// conferenceSid and the Voice Insights summary of its call, partial while
+// the call is in progress
+func (twiClient *TwilioClient) ParticipantQuality(conferenceSid, callSid string) (
+	ParticipantQuality, error) {
+
+	ctx := context.Background()
+	var pq ParticipantQuality
+	resp, err := twiClient.RequestWithContext(ctx,
+		Participant{Sid: conferenceSid, CallSid: callSid}, false)
+	if err != nil {
+		return pq, err
+	}
+	if resp.Participant == nil {
+		return pq, fmt.Errorf("no participant in response: '%s'", callSid)
+	}
+	pq.Participant = *resp.Participant
+
+	resp, err = twiClient.RequestWithContext(ctx,
+		CallSummary{Sid: callSid, ProcessingState: TwiProcessingPartial}, false)
+	if errors.Is(err, ErrNotFound) {
+		return pq, nil
+	}
+	if err != nil {
+		return pq, err
+	}
+	pq.Summary = resp.CallSummary
+	return pq, nil
+}
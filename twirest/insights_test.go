@@ -0,0 +1,65 @@
+package twirest
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestParticipantQuality(t *testing.T) {
+	const summary = `{"call_sid": "CA1", "call_state": "completed",
+		"processing_state": "partial", "duration": 62, "tags": ["high_jitter"],
+		"carrier_edge": {"metrics": {"inbound": {"codec_name": "pcmu",
+		"packets_received": 3000, "packets_lost": 30, "packets_loss_percentage": 1,
+		"jitter": {"max": 48.5, "avg": 12.25}}}}}`
+	const notFound = `{"code": 20404, "message": "not found", "status": 404}`
+
+	var tests = []struct {
+		Summary string
+		Status  int
+		Jitter  float64
+	}{
+		{summary, http.StatusOK, 48.5},
+		{notFound, http.StatusNotFound, 0},
+	}
+
+	for idx, test := range tests {
+		var query string
+		client, ts := testClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if strings.HasSuffix(r.URL.Path, "/Summary") {
+				query = r.URL.RawQuery
+				w.WriteHeader(test.Status)
+				w.Write([]byte(test.Summary))
+				return
+			}
+			w.Write([]byte(xmlHeader + "<TwilioResponse><Participant>" +
+				"<CallSid>CA1</CallSid><ConferenceSid>CF1</ConferenceSid>" +
+				"<Label>customer</Label><Status>connected</Status><Hold>false</Hold>" +
+				"</Participant></TwilioResponse>"))
+		}))
+
+		pq, err := client.ParticipantQuality("CF1", "CA1")
+		ts.Close()
+		if err != nil {
+			t.Errorf("Test %v failed; %v", idx, err)
+			continue
+		}
+		if pq.Participant.Label != "customer" || pq.Participant.Status != "connected" {
+			t.Errorf("Test %v failed; participant %+v", idx, pq.Participant)
+		}
+		if query != "ProcessingState=partial" {
+			t.Errorf("Test %v failed; expected a partial summary, got query %v", idx, query)
+		}
+		if test.Jitter == 0 {
+			if pq.Summary != nil {
+				t.Errorf("Test %v failed; expected no summary, got %+v", idx, pq.Summary)
+			}
+			continue
+		}
+		if pq.Summary == nil || pq.Summary.CarrierEdge == nil ||
+			pq.Summary.CarrierEdge.Metrics.Inbound.Jitter.Max != test.Jitter ||
+			pq.Summary.Tags[0] != "high_jitter" || pq.Summary.SipEdge != nil {
+			t.Errorf("Test %v failed; expected jitter %v, got %+v", idx, test.Jitter, pq.Summary)
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package twirest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+)
+
+// jsonStreamThreshold is the size of a JSON response body above which it is
+// decoded as it is read
+var jsonStreamThreshold int64 = 1 << 20
+
+// DecodeStream reads a JSON list from r and passes its records to each one at
+// a time, so a large list isn't held in memory whole. The list is either a
+// JSON array or an object with arrays, such as a page of the Monitor Events;
+// other fields of the object are skipped. Reading stops at the first error
+// each returns, which is returned.
+func DecodeStream(r io.Reader, each func(json.RawMessage) error) error {
+	dec := json.NewDecoder(r)
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	switch tok {
+	case json.Delim('['):
+		return streamArray(dec, each)
+	case json.Delim('{'):
+	default:
+		return fmt.Errorf("non valid JSON list: '%v'", tok)
+	}
+
+	for dec.More() {
+		if _, err := dec.Token(); err != nil { // the key
+			return err
+		}
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		if tok == json.Delim('[') {
+			err = streamArray(dec, each)
+		} else {
+			err = skipValue(dec, tok)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	_, err = dec.Token()
+	return err
+}
+
+// streamArray passes the elements of the array whose '[' was read to each
+func streamArray(dec *json.Decoder, each func(json.RawMessage) error) error {
+	for dec.More() {
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return err
+		}
+		if err := each(raw); err != nil {
+			return err
+		}
+	}
+	_, err := dec.Token() // ']'
+	return err
+}
+
+// skipValue reads the rest of the value that starts with tok
+func skipValue(dec *json.Decoder, tok json.Token) error {
+	depth := 0
+	for {
+		switch tok {
+		case json.Delim('{'), json.Delim('['):
+			depth++
+		case json.Delim('}'), json.Delim(']'):
+			depth--
+		}
+		if depth == 0 {
+			return nil
+		}
+		var err error
+		if tok, err = dec.Token(); err != nil {
+			return err
+		}
+	}
+}
+
+// decodeJSONStream decodes a JSON object from r into the field of the
+// TwilioResponse that matches the request struct like decodeJSON, appending
+// the elements of its arrays one at a time
+func decodeJSONStream(reqStruct interface{}, r io.Reader, twir *TwilioResponse) error {
+	v, err := jsonTarget(reqStruct, twir)
+	if err != nil {
+		return err
+	}
+	sv := reflect.ValueOf(v).Elem()
+
+	dec := json.NewDecoder(r)
+	if tok, err := dec.Token(); err != nil {
+		return err
+	} else if tok != json.Delim('{') {
+		return fmt.Errorf("non valid JSON response: '%v'", tok)
+	}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		key, _ := tok.(string)
+
+		fv, ok := jsonField(sv, key)
+		switch {
+		case !ok:
+			var skip json.RawMessage
+			err = dec.Decode(&skip)
+		case fv.Kind() == reflect.Slice:
+			err = streamSlice(dec, fv)
+		default:
+			err = dec.Decode(fv.Addr().Interface())
+		}
+		if err != nil {
+			return err
+		}
+	}
+	_, err = dec.Token()
+	return err
+}
+
+// streamSlice decodes a JSON array into the slice fv an element at a time
+func streamSlice(dec *json.Decoder, fv reflect.Value) error {
+	tok, err := dec.Token()
+	if err != nil || tok == nil { // null
+		return err
+	}
+	if tok != json.Delim('[') {
+		return fmt.Errorf("non valid JSON array: '%v'", tok)
+	}
+	for dec.More() {
+		ev := reflect.New(fv.Type().Elem())
+		if err := dec.Decode(ev.Interface()); err != nil {
+			return err
+		}
+		fv.Set(reflect.Append(fv, ev.Elem()))
+	}
+	_, err = dec.Token()
+	return err
+}
+
+// jsonField returns the field of the struct sv that key decodes into
+func jsonField(sv reflect.Value, key string) (reflect.Value, bool) {
+	t := sv.Type()
+	for i := 0; i < t.NumField(); i++ {
+		name := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+		if name != "" && name != "-" && strings.EqualFold(name, key) {
+			return sv.Field(i), true
+		}
+	}
+	return reflect.Value{}, false
+}
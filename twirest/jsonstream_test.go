@@ -0,0 +1,143 @@
+package twirest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// eventsPage returns a page of n Monitor Events, each event about 300 bytes
+func eventsPage(n int) []byte {
+	var b bytes.Buffer
+	b.WriteString(`{"meta":{"page":0,"page_size":50,"key":"events",` +
+		`"next_page_url":"https://monitor.twilio.com/v1/Events?Page=1"},"events":[`)
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		fmt.Fprintf(&b, `{"sid":"AE%032d","account_sid":"AC123","actor_sid":"US123",`+
+			`"actor_type":"account","description":"Phone number updated",`+
+			`"event_data":{"friendly_name":{"previous":"a","updated":"b"}},`+
+			`"event_date":"2024-01-01T00:00:00Z","event_type":"phone-number.updated",`+
+			`"resource_sid":"PN123","resource_type":"phone-number","source":"api"}`, i)
+	}
+	b.WriteString(`]}`)
+	return b.Bytes()
+}
+
+func TestDecodeStream(t *testing.T) {
+	var tests = []struct {
+		Body   string
+		Expect []string
+		Err    bool
+	}{
+		{`[{"sid":"A"},{"sid":"B"}]`, []string{`{"sid":"A"}`, `{"sid":"B"}`}, false},
+		{`{"meta":{"page":0,"list":[1,2]},"alerts":[{"sid":"A"}],"key":"alerts"}`,
+			[]string{`{"sid":"A"}`}, false},
+		{`{"meta":{"page":0},"events":[]}`, nil, false},
+		{`"events"`, nil, true},
+		{`[{"sid":"A"},`, []string{`{"sid":"A"}`}, true},
+	}
+
+	for idx, test := range tests {
+		var got []string
+		err := DecodeStream(strings.NewReader(test.Body), func(raw json.RawMessage) error {
+			got = append(got, string(raw))
+			return nil
+		})
+		if (err != nil) != test.Err || !reflect.DeepEqual(got, test.Expect) {
+			t.Errorf("Test %v failed; expected %v (error %v), got %v (%v)", idx,
+				test.Expect, test.Err, got, err)
+		}
+	}
+}
+
+// countingReader counts the bytes read from it
+type countingReader struct {
+	r io.Reader
+	n int
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += n
+	return n, err
+}
+
+func TestDecodeStreamStops(t *testing.T) {
+	body := eventsPage(10000)
+	cr := &countingReader{r: bytes.NewReader(body)}
+	stop := errors.New("stop")
+
+	n := 0
+	err := DecodeStream(cr, func(json.RawMessage) error {
+		if n++; n == 3 {
+			return stop
+		}
+		return nil
+	})
+	if err != stop || n != 3 {
+		t.Errorf("expected to stop after 3 records, got %v records (%v)", n, err)
+	}
+	if cr.n > len(body)/100 {
+		t.Errorf("expected to stop reading the body, read %v of %v bytes", cr.n, len(body))
+	}
+}
+
+func TestJSONStreamResponse(t *testing.T) {
+	body := eventsPage(200)
+	client, ts := testClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(body)
+	}))
+	defer ts.Close()
+
+	whole, err := client.Request(ListEvents{}, false)
+	if err != nil || whole.Events == nil || len(whole.Events.Events) != 200 {
+		t.Fatalf("expected 200 events, got %v", err)
+	}
+
+	defer func(old int64) { jsonStreamThreshold = old }(jsonStreamThreshold)
+	jsonStreamThreshold = 1000
+	streamed, err := client.Request(ListEvents{}, false)
+	if err != nil || !reflect.DeepEqual(streamed.Events, whole.Events) {
+		t.Errorf("expected the streamed events to match, got %v", err)
+	}
+	if streamed.Events.Meta.NextPageUrl == "" {
+		t.Errorf("expected the meta of the page, got %#v", streamed.Events.Meta)
+	}
+}
+
+// BenchmarkDecodeJSON and BenchmarkDecodeJSONStream decode a 10MB page of
+// events, compare their B/op for the memory the body takes
+func BenchmarkDecodeJSON(b *testing.B) {
+	body := eventsPage(35000)
+	b.SetBytes(int64(len(body)))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		buf, _ := ioutil.ReadAll(bytes.NewReader(body))
+		twir := TwilioResponse{Status: ResponseStatus{Http: http.StatusOK}}
+		if err := decodeJSON(ListEvents{}, buf, &twir); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkDecodeJSONStream(b *testing.B) {
+	body := eventsPage(35000)
+	b.SetBytes(int64(len(body)))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		twir := TwilioResponse{Status: ResponseStatus{Http: http.StatusOK}}
+		if err := decodeJSONStream(ListEvents{}, bytes.NewReader(body), &twir); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
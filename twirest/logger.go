@@ -0,0 +1,61 @@
+package twirest
+
+import (
+	"log"
+	"sync"
+)
+
+// Logger receives the log output of a client, *log.Logger is one
+type Logger interface {
+	Printf(format string, args ...interface{})
+}
+
+// LoggerFunc is a function used as a Logger
+type LoggerFunc func(format string, args ...interface{})
+
+func (f LoggerFunc) Printf(format string, args ...interface{}) {
+	f(format, args...)
+}
+
+// WithLogger makes the client log the method and url of every request to l,
+// with the credentials and secret parameters redacted. Response bodies can
+// hold personal data and are only logged for requests made with logit set.
+// Without a logger nothing is logged unless logit is set, which logs to the
+// standard logger. The calls to l are serialized, it needn't be safe for
+// concurrent use.
+func WithLogger(l Logger) ClientOption {
+	return ClientOption{apply: func(c *TwilioClient) {
+		c.logger = &lockedLogger{l: l}
+	}}
+}
+
+// lockedLogger serializes the calls to a Logger shared by requests
+type lockedLogger struct {
+	mu sync.Mutex
+	l  Logger
+}
+
+func (ll *lockedLogger) Printf(format string, args ...interface{}) {
+	ll.mu.Lock()
+	defer ll.mu.Unlock()
+	ll.l.Printf(format, args...)
+}
+
+// requestLogger returns the logger of a request, nil if it isn't logged, and
+// whether the response body is logged too
+func (twiClient *TwilioClient) requestLogger(logit bool) (Logger, bool) {
+	if logit && twiClient.logger == nil {
+		return LoggerFunc(log.Printf), true
+	}
+	return twiClient.logger, logit
+}
+
+// warnf logs a warning to the client logger, the standard logger if it has
+// none. Warnings are only given for options that ask for them.
+func (twiClient *TwilioClient) warnf(format string, args ...interface{}) {
+	if twiClient.logger != nil {
+		twiClient.logger.Printf(format, args...)
+		return
+	}
+	log.Printf(format, args...)
+}
@@ -0,0 +1,66 @@
+package twirest
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"testing"
+)
+
+// bufLogger is a Logger writing to a buffer
+type bufLogger struct {
+	bytes.Buffer
+}
+
+func (l *bufLogger) Printf(format string, args ...interface{}) {
+	fmt.Fprintf(&l.Buffer, format+"\n", args...)
+}
+
+func TestWithLogger(t *testing.T) {
+	var std bytes.Buffer
+	log.SetOutput(&std)
+	defer log.SetOutput(os.Stderr)
+
+	var tests = []struct {
+		Logger bool
+		Logit  bool
+		Url    bool
+		Body   bool
+	}{
+		{false, false, false, false},
+		{false, true, true, true},
+		{true, false, true, false},
+		{true, true, true, true},
+	}
+
+	for idx, test := range tests {
+		std.Reset()
+		var opts []ClientOption
+		l := &bufLogger{}
+		if test.Logger {
+			opts = append(opts, WithLogger(l))
+		}
+		client, ts := testClient(t, &flakyServer{}, opts...)
+		client.Request(Messages{To: "+15005550001"}, test.Logit)
+		ts.Close()
+
+		out := std.String()
+		if test.Logger {
+			out = l.String()
+			if std.Len() > 0 {
+				t.Errorf("Test %v failed; expected nothing on the standard logger, got %v",
+					idx, std.String())
+			}
+		}
+		if strings.Contains(out, "making twilio GET request") != test.Url ||
+			strings.Contains(out, "<Sid>SM1</Sid>") != test.Body {
+			t.Errorf("Test %v failed; expected url %v and body %v, got %v", idx,
+				test.Url, test.Body, out)
+		}
+		if strings.Contains(out, "token") {
+			t.Errorf("Test %v failed; expected the auth token redacted, got %v", idx, out)
+		}
+	}
+}
@@ -0,0 +1,284 @@
+package twirest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Lookup v2 resources (https://www.twilio.com/docs/lookup/v2-api). A lookup
+// validates and formats a phone number for free, the data packages of Fields
+// are billed per number. Lookups live on lookups.twilio.com and use JSON
+// responses.
+
+// Lookup data packages
+const (
+	TwiLookupLineType   = "line_type_intelligence"
+	TwiLookupCallerName = "caller_name"
+)
+
+// Lookup requests the details of a phone number. Fields is a comma separated
+// list of data packages, CountryCode the ISO country of a number in national
+// format.
+type Lookup struct {
+	domain      uri    `url:"lookups.twilio.com/v2"`
+	resource    uri    `url:"/PhoneNumbers"`
+	Sid         string // Phone number
+	Fields      string `url:"Fields"`
+	CountryCode string `url:"CountryCode"`
+}
+
+type LookupResponse struct {
+	CallingCountryCode   string                `json:"calling_country_code"`
+	CountryCode          string                `json:"country_code"`
+	PhoneNumber          string                `json:"phone_number"`
+	NationalFormat       string                `json:"national_format"`
+	Valid                bool                  `json:"valid"`
+	ValidationErrors     []string              `json:"validation_errors"` // such as TOO_SHORT
+	CallerName           *CallerName           `json:"caller_name"`
+	LineTypeIntelligence *LineTypeIntelligence `json:"line_type_intelligence"`
+	Url                  string                `json:"url"`
+}
+
+type CallerName struct {
+	CallerName string `json:"caller_name"`
+	CallerType string `json:"caller_type"` // BUSINESS or CONSUMER
+	ErrorCode  *int   `json:"error_code"`
+}
+
+type LineTypeIntelligence struct {
+	CarrierName       string `json:"carrier_name"`
+	Type              string `json:"type"` // such as mobile, landline or nonFixedVoip
+	MobileCountryCode string `json:"mobile_country_code"`
+	MobileNetworkCode string `json:"mobile_network_code"`
+	ErrorCode         *int   `json:"error_code"`
+}
+
+// LookupCache holds lookup responses keyed by E.164 number. It is used by
+// the workers of a BulkLookup at once.
+type LookupCache interface {
+	Get(number string) (LookupResponse, bool)
+	Set(number string, resp LookupResponse)
+}
+
+// lookupCache is a LookupCache in memory whose responses expire
+type lookupCache struct {
+	ttl     time.Duration
+	clock   Clock
+	mu      sync.Mutex
+	entries map[string]lookupEntry
+}
+
+type lookupEntry struct {
+	resp    LookupResponse
+	expires time.Time
+}
+
+// NewLookupCache returns a LookupCache in memory keeping responses for ttl,
+// timed by clock, the time package if nil. Expired responses are dropped when
+// they're asked for again.
+func NewLookupCache(ttl time.Duration, clock Clock) LookupCache {
+	if clock == nil {
+		clock = realClock{}
+	}
+	return &lookupCache{ttl: ttl, clock: clock, entries: make(map[string]lookupEntry)}
+}
+
+func (c *lookupCache) Get(number string) (LookupResponse, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[number]
+	if !ok {
+		return LookupResponse{}, false
+	}
+	if !c.clock.Now().Before(e.expires) {
+		delete(c.entries, number)
+		return LookupResponse{}, false
+	}
+	return e.resp, true
+}
+
+func (c *lookupCache) Set(number string, resp LookupResponse) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[number] = lookupEntry{resp: resp, expires: c.clock.Now().Add(c.ttl)}
+}
+
+// BulkLookupOpts configures BulkLookup
+type BulkLookupOpts struct {
+	// Fields are the data packages of every lookup, see Lookup
+	Fields string
+	// Concurrency is the maximum number of lookups in flight, default 10
+	Concurrency int
+	// Cache holds the responses of earlier lookups, numbers found in it
+	// aren't looked up. Failed lookups aren't cached. Nil is no cache.
+	Cache LookupCache
+	// CallingCode is the country calling code of numbers in national
+	// format, such as 44. Their leading trunk 0 is dropped.
+	CallingCode string
+	// Progress, if set, is called with the number of lookups done out of
+	// the total every ProgressEvery lookups and after the last one
+	Progress func(done, total int)
+	// ProgressEvery is the number of lookups between Progress calls,
+	// default 100
+	ProgressEvery int
+}
+
+// LookupResult is the outcome of the lookup of one number
+type LookupResult struct {
+	Number string // E.164
+	Lookup *LookupResponse
+	Err    error
+	Cached bool
+}
+
+// ErrBulkLookupIncomplete is the error of a BulkLookup stopped by its
+// context before every number was looked up
+type ErrBulkLookupIncomplete struct {
+	Done  int
+	Total int
+	Err   error // the error of the context
+}
+
+func (e *ErrBulkLookupIncomplete) Error() string {
+	return fmt.Sprintf("bulk lookup stopped after %d of %d numbers: %v",
+		e.Done, e.Total, e.Err)
+}
+
+// Unwrap returns the error of the context
+func (e *ErrBulkLookupIncomplete) Unwrap() error {
+	return e.Err
+}
+
+// BulkLookup looks up numbers, at most opts.Concurrency at a time, through
+// the retries and rate limit of the client. Numbers are normalized to E.164
+// and those that are the same number are looked up once, the results are
+// keyed by the numbers as given. Numbers that can't be normalized have an
+// error result and aren't looked up. If ctx is canceled the results hold the
+// numbers looked up so far and the error is an *ErrBulkLookupIncomplete.
+func (twiClient *TwilioClient) BulkLookup(ctx context.Context, numbers []string,
+	opts BulkLookupOpts) (map[string]LookupResult, error) {
+
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = 10
+	}
+	every := opts.ProgressEvery
+	if every <= 0 {
+		every = 100
+	}
+
+	results := make(map[string]LookupResult, len(numbers))
+	given := make(map[string][]string) // the numbers as given per E.164
+	var order []string
+	for _, n := range numbers {
+		e164, err := normalizeE164(n, opts.CallingCode)
+		if err != nil {
+			results[n] = LookupResult{Number: n, Err: err}
+			continue
+		}
+		if _, ok := given[e164]; !ok {
+			order = append(order, e164)
+		}
+		given[e164] = append(given[e164], n)
+	}
+
+	var mu sync.Mutex
+	done := 0
+	record := func(res LookupResult) {
+		mu.Lock()
+		defer mu.Unlock()
+		for _, n := range given[res.Number] {
+			results[n] = res
+		}
+		done++
+		if opts.Progress != nil && (done%every == 0 || done == len(order)) {
+			opts.Progress(done, len(order))
+		}
+	}
+
+	var pending []string
+	for _, e164 := range order {
+		if opts.Cache != nil {
+			if resp, ok := opts.Cache.Get(e164); ok {
+				record(LookupResult{Number: e164, Lookup: &resp, Cached: true})
+				continue
+			}
+		}
+		pending = append(pending, e164)
+	}
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, concurrency)
+	for _, e164 := range pending {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		wg.Add(1)
+		go func(e164 string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			resp, err := twiClient.RequestWithContext(ctx,
+				Lookup{Sid: e164, Fields: opts.Fields}, false)
+			if err != nil && ctx.Err() != nil && errors.Is(err, ctx.Err()) {
+				return // not done, the lookup was cut short
+			}
+			res := LookupResult{Number: e164, Lookup: resp.Lookup, Err: err}
+			if err == nil && resp.Lookup != nil && opts.Cache != nil {
+				opts.Cache.Set(e164, *resp.Lookup)
+			}
+			record(res)
+		}(e164)
+	}
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil && done < len(order) {
+		return results, &ErrBulkLookupIncomplete{Done: done, Total: len(order), Err: err}
+	}
+	return results, nil
+}
+
+// normalizeE164 returns number in E.164 format, without the spaces, dashes,
+// dots and parentheses of a formatted number. A number without a leading +
+// or 00 is national, callingCode is prepended instead of its trunk 0.
+func normalizeE164(number, callingCode string) (string, error) {
+	s := strings.TrimSpace(number)
+	intl := strings.HasPrefix(s, "+")
+	if intl {
+		s = s[1:]
+	}
+	var b strings.Builder
+	for _, c := range s {
+		switch {
+		case c >= '0' && c <= '9':
+			b.WriteRune(c)
+		case strings.ContainsRune(" -.()", c):
+		default:
+			return "", fmt.Errorf("non valid phone number: '%s'", number)
+		}
+	}
+
+	digits := b.String()
+	if !intl && strings.HasPrefix(digits, "00") {
+		digits, intl = digits[2:], true
+	}
+	if !intl {
+		if callingCode == "" {
+			return "", fmt.Errorf("non valid phone number: '%s', no calling code", number)
+		}
+		digits = callingCode + strings.TrimPrefix(digits, "0")
+	}
+	// E.164 numbers have at most 15 digits
+	if len(digits) < 7 || len(digits) > 15 || digits[0] == '0' {
+		return "", fmt.Errorf("non valid phone number: '%s'", number)
+	}
+	return "+" + digits, nil
+}
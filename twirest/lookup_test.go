@@ -0,0 +1,166 @@
+package twirest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestNormalizeE164(t *testing.T) {
+	var tests = []struct {
+		Number      string
+		CallingCode string
+		Expect      string
+	}{
+		{"+15005550006", "", "+15005550006"},
+		{" +1 (500) 555-0006 ", "", "+15005550006"},
+		{"0044 20.7946.0958", "", "+442079460958"},
+		{"020 7946 0958", "44", "+442079460958"},
+		{"5005550006", "1", "+15005550006"},
+		{"5005550006", "", ""},
+		{"+1500555000x", "", ""},
+		{"+123", "", ""},
+		{"+1234567890123456", "", ""},
+		{"+0123456789", "", ""},
+	}
+
+	for idx, test := range tests {
+		got, err := normalizeE164(test.Number, test.CallingCode)
+		if (err == nil) != (test.Expect != "") || got != test.Expect {
+			t.Errorf("Test %v failed; expected %q, got %q %v", idx, test.Expect, got, err)
+		}
+	}
+}
+
+// lookupServer answers lookups with the number, 404 for numbers starting
+// with +1999, counting the requests per number
+type lookupServer struct {
+	mu    sync.Mutex
+	count map[string]int
+	// hook, if set, is called on every request before it is answered
+	hook func()
+}
+
+func (s *lookupServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	number := strings.TrimPrefix(r.URL.Path, "/v2/PhoneNumbers/")
+	s.mu.Lock()
+	s.count[number+"?"+r.URL.RawQuery]++
+	s.mu.Unlock()
+	if s.hook != nil {
+		s.hook()
+	}
+	if strings.HasPrefix(number, "+1999") {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"code":20404,"message":"not found","status":404}`))
+		return
+	}
+	w.Write([]byte(`{"phone_number":"` + number + `","valid":true,` +
+		`"line_type_intelligence":{"type":"mobile","carrier_name":"Acme"}}`))
+}
+
+func TestBulkLookup(t *testing.T) {
+	srv := &lookupServer{count: make(map[string]int)}
+	client, ts := testClient(t, srv)
+	defer ts.Close()
+
+	clock := newFakeClock()
+	cache := NewLookupCache(time.Hour, clock)
+	cache.Set("+15005550001", LookupResponse{PhoneNumber: "+15005550001"})
+
+	var progress []int
+	numbers := []string{"+15005550006", "+1 500 555 0006", "(500) 555-0007",
+		"+15005550001", "+19995550000", "bogus", "+15005550006"}
+	results, err := client.BulkLookup(context.Background(), numbers, BulkLookupOpts{
+		Fields:        TwiLookupLineType,
+		Concurrency:   2,
+		Cache:         cache,
+		CallingCode:   "1",
+		ProgressEvery: 3,
+		Progress: func(done, total int) {
+			progress = append(progress, done, total)
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expect := map[string]int{
+		"+15005550006?Fields=line_type_intelligence": 1,
+		"+15005550007?Fields=line_type_intelligence": 1,
+		"+19995550000?Fields=line_type_intelligence": 1,
+	}
+	if !reflect.DeepEqual(srv.count, expect) {
+		t.Errorf("expected the requests %v, got %v", expect, srv.count)
+	}
+	if !reflect.DeepEqual(progress, []int{3, 4, 4, 4}) {
+		t.Errorf("expected progress at 3 and 4 of 4, got %v", progress)
+	}
+	if len(results) != 6 {
+		t.Errorf("expected a result per distinct number given, got %v", results)
+	}
+	for _, n := range []string{"+15005550006", "+1 500 555 0006"} {
+		res := results[n]
+		if res.Err != nil || res.Number != "+15005550006" || res.Cached ||
+			res.Lookup.LineTypeIntelligence.Type != "mobile" {
+			t.Errorf("unexpected result of %v: %+v", n, res)
+		}
+	}
+	if res := results["+15005550001"]; !res.Cached || res.Lookup.PhoneNumber != "+15005550001" {
+		t.Errorf("expected the cached response, got %+v", res)
+	}
+	if res := results["+19995550000"]; !errors.Is(res.Err, ErrNotFound) {
+		t.Errorf("expected not found, got %+v", res)
+	}
+	if res := results["bogus"]; res.Err == nil || res.Lookup != nil {
+		t.Errorf("expected a non valid number, got %+v", res)
+	}
+
+	// responses are cached, errors aren't
+	if _, ok := cache.Get("+15005550007"); !ok {
+		t.Errorf("expected the response cached")
+	}
+	if _, ok := cache.Get("+19995550000"); ok {
+		t.Errorf("expected the failed lookup not cached")
+	}
+	clock.Advance(time.Hour)
+	if _, ok := cache.Get("+15005550007"); ok {
+		t.Errorf("expected the response expired")
+	}
+}
+
+func TestBulkLookupCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	srv := &lookupServer{count: make(map[string]int)}
+	srv.hook = func() {
+		srv.mu.Lock()
+		defer srv.mu.Unlock()
+		if len(srv.count) == 3 {
+			cancel()
+		}
+	}
+	client, ts := testClient(t, srv)
+	defer ts.Close()
+
+	numbers := []string{"+15005550001", "+15005550002", "+15005550003",
+		"+15005550004", "+15005550005"}
+	results, err := client.BulkLookup(ctx, numbers, BulkLookupOpts{Concurrency: 1})
+	var incomplete *ErrBulkLookupIncomplete
+	if !errors.As(err, &incomplete) || !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected an incomplete lookup, got %v", err)
+	}
+	if incomplete.Done != 2 || incomplete.Total != 5 || len(results) != 2 {
+		t.Errorf("expected 2 of 5 numbers done, got %v, %v", err, results)
+	}
+	for _, n := range numbers[:2] {
+		if res, ok := results[n]; !ok || res.Err != nil {
+			t.Errorf("expected the result of %v, got %+v", n, res)
+		}
+	}
+}
@@ -0,0 +1,218 @@
+package twirest
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/seanhagen/twilio/twiml"
+)
+
+// DefaultMediaTypes are the content types MirrorInboundMedia stores unless
+// configured WithMediaPolicy
+var DefaultMediaTypes = []string{"image/jpeg", "image/png", "image/gif"}
+
+// DefaultMediaMaxSize is the size limit of media MirrorInboundMedia stores
+// unless configured WithMediaPolicy, the size limit of MMS
+const DefaultMediaMaxSize = 5 * 1024 * 1024
+
+// mediaPolicy is the media MirrorInboundMedia accepts
+type mediaPolicy struct {
+	types   []string
+	maxSize int64
+}
+
+// WithMediaPolicy sets the content types and the maximum size in bytes of the
+// media MirrorInboundMedia stores, other media is skipped
+func WithMediaPolicy(contentTypes []string, maxSize int64) ClientOption {
+	return ClientOption{apply: func(c *TwilioClient) {
+		c.media = &mediaPolicy{types: contentTypes, maxSize: maxSize}
+	}}
+}
+
+// MediaStore stores downloaded media. Store returns where the media was stored
+// such as a path or url.
+type MediaStore interface {
+	Store(ctx context.Context, name, contentType string, r io.Reader) (
+		location string, err error)
+}
+
+// FileMediaStore stores media as files in Dir
+type FileMediaStore struct {
+	Dir string
+}
+
+// Store writes the media to a file named after name with the extension of the
+// content type
+func (s FileMediaStore) Store(ctx context.Context, name, contentType string,
+	r io.Reader) (string, error) {
+
+	name += mediaExtension(contentType)
+	file := filepath.Join(s.Dir, filepath.Base(name))
+
+	f, err := os.Create(file)
+	if err != nil {
+		return "", err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		os.Remove(file)
+		return "", err
+	}
+	return file, f.Close()
+}
+
+// mediaExtensions are the file extensions of common media types, these are
+// ambiguous in the mime package
+var mediaExtensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/gif":  ".gif",
+	"video/mp4":  ".mp4",
+	"audio/mpeg": ".mp3",
+}
+
+// mediaExtension returns the file extension of the content type
+func mediaExtension(contentType string) string {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	if ext, ok := mediaExtensions[mediaType]; ok {
+		return ext
+	}
+	if exts, _ := mime.ExtensionsByType(mediaType); len(exts) > 0 {
+		return exts[0]
+	}
+	return ""
+}
+
+// StoredMedia is the result of mirroring one media attachment. Skipped media
+// isn't stored because of the media policy, Reason says why. Err is set if
+// downloading or storing the media failed.
+type StoredMedia struct {
+	Url         string
+	ContentType string
+	Size        int64
+	Location    string
+	Skipped     bool
+	Reason      string
+	Err         error
+}
+
+// MirrorInboundMedia downloads the media attachments of an incoming message,
+// authenticating with the credentials of the client, and writes them to store.
+// Media is named after the message and media Sids. Media whose content type or
+// size isn't allowed by the media policy is skipped. An error is returned if
+// any media failed, the results tell which.
+func (twiClient *TwilioClient) MirrorInboundMedia(ctx context.Context,
+	msg twiml.InboundMessage, store MediaStore) ([]StoredMedia, error) {
+
+	policy := twiClient.media
+	if policy == nil {
+		policy = &mediaPolicy{types: DefaultMediaTypes, maxSize: DefaultMediaMaxSize}
+	}
+
+	results := make([]StoredMedia, len(msg.Media))
+	failed := 0
+	for i, m := range msg.Media {
+		res := &results[i]
+		res.Url = m.Url
+		res.ContentType = m.ContentType
+
+		if !policy.allows(m.ContentType) {
+			res.Skipped = true
+			res.Reason = fmt.Sprintf("content type not allowed: '%s'", m.ContentType)
+			continue
+		}
+
+		data, err := twiClient.downloadMedia(ctx, m.Url, policy, res)
+		if err != nil || res.Skipped {
+			if err != nil {
+				res.Err = err
+				failed++
+			}
+			continue
+		}
+
+		name := msg.MessageSid + "-" + path.Base(m.Url)
+		res.Location, err = store.Store(ctx, name, res.ContentType, bytes.NewReader(data))
+		if err != nil {
+			res.Err = err
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		return results, fmt.Errorf("failed to mirror %d of %d media of message: '%s'",
+			failed, len(msg.Media), msg.MessageSid)
+	}
+	return results, nil
+}
+
+// downloadMedia fetches the media at url. res is marked skipped if the
+// downloaded media isn't allowed by the policy.
+func (twiClient *TwilioClient) downloadMedia(ctx context.Context, url string,
+	policy *mediaPolicy, res *StoredMedia) ([]byte, error) {
+
+	httpReq, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	twiClient.setAuth(httpReq)
+
+	response, err := twiClient.do(httpReq.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("non valid media response: '%s'", response.Status)
+	}
+
+	// the content type of the download is authoritative
+	if ct := response.Header.Get("Content-Type"); ct != "" {
+		res.ContentType = ct
+		if !policy.allows(ct) {
+			res.Skipped = true
+			res.Reason = fmt.Sprintf("content type not allowed: '%s'", ct)
+			return nil, nil
+		}
+	}
+
+	if response.ContentLength > policy.maxSize {
+		res.Skipped = true
+		res.Reason = fmt.Sprintf("media too large: %d bytes", response.ContentLength)
+		return nil, nil
+	}
+	data, err := io.ReadAll(io.LimitReader(response.Body, policy.maxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > policy.maxSize {
+		res.Skipped = true
+		res.Reason = fmt.Sprintf("media too large: over %d bytes", policy.maxSize)
+		return nil, nil
+	}
+	res.Size = int64(len(data))
+	return data, nil
+}
+
+// allows reports if media of the content type may be stored, parameters of
+// the content type are ignored
+func (p *mediaPolicy) allows(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	for _, t := range p.types {
+		if strings.EqualFold(t, mediaType) {
+			return true
+		}
+	}
+	return false
+}
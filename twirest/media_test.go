@@ -0,0 +1,83 @@
+package twirest
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/seanhagen/twilio/twiml"
+)
+
+func TestMirrorInboundMedia(t *testing.T) {
+	media := map[string]struct {
+		ContentType string
+		Body        string
+	}{
+		"ME1": {"image/jpeg", "jpeg data"},
+		"ME2": {"image/png", strings.Repeat("x", 64)},
+		"ME3": {"text/html", "<html>"},
+	}
+	client, ts := testClient(t, http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			if user, pass, _ := r.BasicAuth(); user != "AC123" || pass != "token" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			m, ok := media[filepath.Base(r.URL.Path)]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Header().Set("Content-Type", m.ContentType)
+			w.Write([]byte(m.Body))
+		}), WithMediaPolicy([]string{"image/jpeg", "image/png", "image/gif"}, 32))
+	defer ts.Close()
+
+	const url = "https://api.twilio.com/2010-04-01/Accounts/AC123/Messages/MM123/Media/"
+	msg := twiml.InboundMessage{MessageSid: "MM123", Media: []twiml.InboundMedia{
+		{Url: url + "ME1", ContentType: "image/jpeg"},
+		{Url: url + "ME2", ContentType: "image/png"},
+		{Url: url + "ME3", ContentType: "image/jpeg"}, // lies about its type
+		{Url: url + "ME5", ContentType: "video/mp4"},
+		{Url: url + "ME6", ContentType: "image/gif"},
+	}}
+
+	dir := t.TempDir()
+	results, err := client.MirrorInboundMedia(context.Background(), msg, FileMediaStore{Dir: dir})
+	if err == nil {
+		t.Errorf("expected error for the missing media")
+	}
+
+	var tests = []struct {
+		Skipped  bool
+		Failed   bool
+		Location string
+	}{
+		{false, false, filepath.Join(dir, "MM123-ME1.jpg")},
+		{true, false, ""}, // too large
+		{true, false, ""}, // text/html
+		{true, false, ""}, // video/mp4 not allowed
+		{false, true, ""}, // not found
+	}
+	for idx, test := range tests {
+		res := results[idx]
+		if res.Skipped != test.Skipped || (res.Err != nil) != test.Failed ||
+			res.Location != test.Location {
+			t.Errorf("Test %v failed; got %#v", idx, res)
+		}
+		if res.Skipped && res.Reason == "" {
+			t.Errorf("Test %v failed; expected skip reason", idx)
+		}
+	}
+
+	data, _ := os.ReadFile(filepath.Join(dir, "MM123-ME1.jpg"))
+	if string(data) != "jpeg data" || results[0].Size != 9 {
+		t.Errorf("unexpected stored media %#v (%d bytes)", string(data), results[0].Size)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 1 {
+		t.Errorf("expected only one stored file, got %v", len(entries))
+	}
+}
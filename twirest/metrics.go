@@ -0,0 +1,38 @@
+package twirest
+
+import (
+	"reflect"
+	"time"
+)
+
+// Metrics collects the outcome of the requests of a client, such as into
+// counters and latency histograms per resource
+type Metrics interface {
+	// ObserveRequest is called once per request with the type name of the
+	// request struct, such as SendMessage, its http method, the http status
+	// of the response and the time the request took, retries and waits
+	// included. The status is 0 if there was no response: the request was
+	// non valid, refused by the client or failed in the network.
+	ObserveRequest(resource string, method string, status int, d time.Duration)
+}
+
+// WithMetrics makes the client report the outcome of every request to m
+func WithMetrics(m Metrics) ClientOption {
+	return ClientOption{apply: func(c *TwilioClient) {
+		c.metrics = m
+	}}
+}
+
+// observe reports the request of reqStruct started at start to the metrics,
+// with the status of the response twiResp points to
+func (twiClient *TwilioClient) observe(reqStruct interface{}, start time.Time,
+	twiResp *TwilioResponse) {
+
+	var resource string
+	if t := reflect.TypeOf(reqStruct); t != nil {
+		resource = t.Name()
+	}
+	d := twiClient.timeSource().Now().Sub(start)
+	twiClient.metrics.ObserveRequest(resource, requestMethod(reqStruct),
+		twiResp.Status.Http, d)
+}
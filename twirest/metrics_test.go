@@ -0,0 +1,66 @@
+package twirest
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeMetrics records the observed requests
+type fakeMetrics struct {
+	observed []string
+}
+
+func (m *fakeMetrics) ObserveRequest(resource, method string, status int, d time.Duration) {
+	m.observed = append(m.observed, fmt.Sprintf("%v %v %v %v", resource, method, status, d))
+}
+
+// failTransport fails every request as a network failure would
+type failTransport struct{}
+
+func (failTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	return nil, errors.New("connection refused")
+}
+
+func TestWithMetrics(t *testing.T) {
+	clock := newFakeClock()
+	metrics := &fakeMetrics{}
+	client, ts := testClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		clock.Advance(250 * time.Millisecond)
+		if strings.HasSuffix(r.URL.Path, "/Messages") {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(xmlHeader + "<TwilioResponse><RestException><Code>21211</Code>" +
+				"<Message>non valid To</Message><Status>400</Status></RestException></TwilioResponse>"))
+			return
+		}
+		w.Write([]byte(xmlHeader + "<TwilioResponse></TwilioResponse>"))
+	}), WithClock(clock), WithMetrics(metrics))
+	defer ts.Close()
+
+	client.Request(Queues{}, false)
+	client.Request(SendMessage{From: "+15005550006", To: "+15005550001", Text: "hi"}, false)
+	client.Request(MakeCall{From: "+15005550006", To: "+15005550001", SendDigits: "x"}, false)
+
+	failing, err := NewClient("AC123", "token", WithClock(clock), WithMetrics(metrics),
+		WithHTTPClient(&http.Client{Transport: failTransport{}}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := failing.Request(Queue{Sid: "QU1"}, false); err == nil {
+		t.Errorf("expected the network failure")
+	}
+
+	expect := []string{
+		"Queues GET 200 250ms",
+		"SendMessage POST 400 250ms",
+		"MakeCall POST 0 0s",
+		"Queue GET 0 0s",
+	}
+	if !reflect.DeepEqual(metrics.observed, expect) {
+		t.Errorf("expected %v, got %v", expect, metrics.observed)
+	}
+}
@@ -0,0 +1,159 @@
+package twirest
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// microsPerUnit is the number of micro-units in a unit of currency
+const microsPerUnit = 1000000
+
+// Money is an amount of a currency in micro-units, exact where a float64
+// would round. Prices twilio charges are negative.
+type Money struct {
+	Micros   int64  // millionths of a unit, -7500 is -0.0075
+	Currency string // ISO 4217 code such as USD
+}
+
+// ParseMoney parses a decimal amount as twilio formats prices, such as
+// "-0.00750", with at most six decimals
+func ParseMoney(amount, currency string) (Money, error) {
+	s := amount
+	neg := strings.HasPrefix(s, "-")
+	s = strings.TrimPrefix(strings.TrimPrefix(s, "-"), "+")
+
+	whole, frac := s, ""
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		whole, frac = s[:i], s[i+1:]
+	}
+	if (whole == "" && frac == "") || len(frac) > 6 ||
+		(whole != "" && !isDigits(whole)) || (frac != "" && !isDigits(frac)) {
+		return Money{}, fmt.Errorf("non valid amount: '%s'", amount)
+	}
+
+	var micros int64
+	if whole != "" {
+		units, err := strconv.ParseInt(whole, 10, 64)
+		if err != nil || units > (1<<63-1)/microsPerUnit {
+			return Money{}, fmt.Errorf("non valid amount: '%s'", amount)
+		}
+		micros = units * microsPerUnit
+	}
+	if frac != "" {
+		f, _ := strconv.ParseInt(frac+strings.Repeat("0", 6-len(frac)), 10, 64)
+		micros += f
+	}
+	if neg {
+		micros = -micros
+	}
+	return Money{Micros: micros, Currency: strings.ToUpper(currency)}, nil
+}
+
+// Add returns m plus o. A zero amount without a currency adds to any
+// currency, other amounts must be of the same currency.
+func (m Money) Add(o Money) (Money, error) {
+	cur, err := sameCurrency(m, o)
+	return Money{Micros: m.Micros + o.Micros, Currency: cur}, err
+}
+
+// Sub returns m minus o, of the same currency like Add
+func (m Money) Sub(o Money) (Money, error) {
+	return m.Add(o.Neg())
+}
+
+// Cmp compares m and o, of the same currency like Add, returning -1, 0 or 1
+// if m is less, equal or more than o
+func (m Money) Cmp(o Money) (int, error) {
+	_, err := sameCurrency(m, o)
+	switch {
+	case m.Micros < o.Micros:
+		return -1, err
+	case m.Micros > o.Micros:
+		return 1, err
+	}
+	return 0, err
+}
+
+// Neg returns the negated amount
+func (m Money) Neg() Money {
+	return Money{Micros: -m.Micros, Currency: m.Currency}
+}
+
+// Abs returns the absolute amount, the cost of a price
+func (m Money) Abs() Money {
+	if m.Micros < 0 {
+		return m.Neg()
+	}
+	return m
+}
+
+// IsZero reports if m is the zero Money
+func (m Money) IsZero() bool {
+	return m.Micros == 0 && m.Currency == ""
+}
+
+// Decimal formats the amount without trailing zeros, such as "-0.0075"
+func (m Money) Decimal() string {
+	micros := m.Micros
+	sign := ""
+	if micros < 0 {
+		sign = "-"
+		micros = -micros
+	}
+	s := sign + strconv.FormatInt(micros/microsPerUnit, 10)
+	if frac := micros % microsPerUnit; frac != 0 {
+		s += "." + strings.TrimRight(fmt.Sprintf("%06d", frac), "0")
+	}
+	return s
+}
+
+// String formats m as its amount and currency, such as "-0.0075 USD"
+func (m Money) String() string {
+	if m.Currency == "" {
+		return m.Decimal()
+	}
+	return m.Decimal() + " " + m.Currency
+}
+
+// formValue is the amount of a request parameter, empty if m is zero
+func (m Money) formValue() string {
+	if m.IsZero() {
+		return ""
+	}
+	return m.Decimal()
+}
+
+// sameCurrency returns the currency of m and o, an error if they differ
+func sameCurrency(m, o Money) (string, error) {
+	switch {
+	case m.IsZero():
+		return o.Currency, nil
+	case o.IsZero(), m.Currency == o.Currency:
+		return m.Currency, nil
+	}
+	return m.Currency, fmt.Errorf("currency mismatch: '%s' and '%s'",
+		m.Currency, o.Currency)
+}
+
+// parseResponsePrice parses the price of a response, ok is false if the
+// price isn't set yet, as for a queued message
+func parseResponsePrice(price, unit string) (m Money, ok bool, err error) {
+	if price == "" {
+		return Money{}, false, nil
+	}
+	m, err = ParseMoney(price, unit)
+	return m, err == nil, err
+}
+
+// ParsedPrice returns the Price of the message, ok is false while it isn't
+// known
+func (m MessageResponse) ParsedPrice() (price Money, ok bool, err error) {
+	return parseResponsePrice(m.Price, m.PriceUnit)
+}
+
+// ParsedPrice returns the Price of the call, ok is false while it isn't
+// known
+func (c CallResponse) ParsedPrice() (price Money, ok bool, err error) {
+	return parseResponsePrice(c.Price, c.PriceUnit)
+}
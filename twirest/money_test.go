@@ -0,0 +1,171 @@
+package twirest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestParseMoney(t *testing.T) {
+	var tests = []struct {
+		Amount  string
+		Micros  int64
+		Decimal string
+		Valid   bool
+	}{
+		{"-0.00750", -7500, "-0.0075", true},
+		{"0.05", 50000, "0.05", true},
+		{"+1.5", 1500000, "1.5", true},
+		{"12", 12000000, "12", true},
+		{".25", 250000, "0.25", true},
+		{"-3.", -3000000, "-3", true},
+		{"0.000001", 1, "0.000001", true},
+		{"-0.00000", 0, "0", true},
+		{"0.0000001", 0, "", false},
+		{"", 0, "", false},
+		{"-", 0, "", false},
+		{".", 0, "", false},
+		{"1,50", 0, "", false},
+		{"1e-3", 0, "", false},
+		{"--1", 0, "", false},
+		{"99999999999999999999", 0, "", false},
+	}
+
+	for idx, test := range tests {
+		m, err := ParseMoney(test.Amount, "usd")
+		if (err == nil) != test.Valid {
+			t.Errorf("Test %v failed; expected valid %v, got %v", idx, test.Valid, err)
+			continue
+		}
+		if test.Valid && (m.Micros != test.Micros || m.Decimal() != test.Decimal ||
+			m.Currency != "USD") {
+			t.Errorf("Test %v failed; expected %v (%v), got %#v", idx, test.Micros,
+				test.Decimal, m)
+		}
+	}
+}
+
+func TestMoneyMath(t *testing.T) {
+	usd := func(micros int64) Money { return Money{Micros: micros, Currency: "USD"} }
+
+	if sum, err := usd(-7500).Add(usd(-7500)); err != nil || sum != usd(-15000) {
+		t.Errorf("expected -0.015 USD, got %v (%v)", sum, err)
+	}
+	if sum, err := (Money{}).Add(usd(10)); err != nil || sum != usd(10) {
+		t.Errorf("expected a zero Money to add to any currency, got %v (%v)", sum, err)
+	}
+	if diff, err := usd(10).Sub(usd(25)); err != nil || diff != usd(-15) {
+		t.Errorf("expected -15 micros, got %v (%v)", diff, err)
+	}
+	if _, err := usd(10).Add(Money{Micros: 10, Currency: "EUR"}); err == nil {
+		t.Errorf("expected a currency mismatch error")
+	}
+	if c, err := usd(-7500).Abs().Cmp(usd(7500)); err != nil || c != 0 {
+		t.Errorf("expected equal amounts, got %v (%v)", c, err)
+	}
+	if c, _ := usd(1).Cmp(usd(2)); c != -1 {
+		t.Errorf("expected less, got %v", c)
+	}
+	if s := usd(-7500).String(); s != "-0.0075 USD" {
+		t.Errorf("expected -0.0075 USD, got %v", s)
+	}
+}
+
+func TestParsedPrice(t *testing.T) {
+	var tests = []struct {
+		Resp   MessageResponse
+		Price  Money
+		Priced bool
+		Valid  bool
+	}{
+		{MessageResponse{Status: "queued"}, Money{}, false, true},
+		{MessageResponse{Price: "-0.00750", PriceUnit: "USD"},
+			Money{Micros: -7500, Currency: "USD"}, true, true},
+		{MessageResponse{Price: "free", PriceUnit: "USD"}, Money{}, false, false},
+	}
+
+	for idx, test := range tests {
+		price, ok, err := test.Resp.ParsedPrice()
+		if price != test.Price || ok != test.Priced || (err == nil) != test.Valid {
+			t.Errorf("Test %v failed; expected %v %v, got %v %v (%v)", idx,
+				test.Price, test.Priced, price, ok, err)
+		}
+	}
+
+	call := CallResponse{Price: "-0.02000", PriceUnit: "GBP"}
+	if price, ok, _ := call.ParsedPrice(); !ok || price.String() != "-0.02 GBP" {
+		t.Errorf("expected -0.02 GBP, got %v", price)
+	}
+}
+
+func TestMaxPriceForm(t *testing.T) {
+	var tests = []struct {
+		Req    SendMessage
+		Expect string
+	}{
+		{SendMessage{To: "+15005550006", MaxPrice: Money{Micros: 50000, Currency: "USD"}},
+			"To=%2B15005550006&MaxPrice=0.05"},
+		{SendMessage{To: "+15005550006"}, "To=%2B15005550006"},
+	}
+
+	for idx, test := range tests {
+		if qs := queryString(test.Req); qs != test.Expect {
+			t.Errorf("Test %v failed; expected %#v, got %#v", idx, test.Expect, qs)
+		}
+	}
+}
+
+func TestSendBulkBudget(t *testing.T) {
+	// the first two messages are priced, the others are still queued
+	var sent int
+	client, ts := testClient(t, http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			sent++
+			price := ""
+			if sent <= 2 {
+				price = "<Price>-0.00750</Price><PriceUnit>USD</PriceUnit>"
+			}
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprintf(w, `<TwilioResponse><Message><Sid>SM%d</Sid>%s</Message></TwilioResponse>`,
+				sent, price)
+		}))
+	defer ts.Close()
+
+	var tests = []struct {
+		Budget   string
+		Estimate string
+		Sent     int
+	}{
+		{"0.02", "0.01", 3},  // 0.0075 + 0.0075 + 0.01 > 0.02
+		{"0.015", "0.01", 3}, // the budget has to be exceeded
+		{"0.01", "0.01", 2},
+		{"1", "0.01", 6},
+		{"0.02", "", 6}, // unpriced messages count as free
+	}
+
+	for idx, test := range tests {
+		sent = 0
+		budget, _ := ParseMoney(test.Budget, "USD")
+		opts := BulkOptions{Concurrency: 1, Clock: newFakeClock(), Budget: budget}
+		if test.Estimate != "" {
+			opts.EstimatedPrice, _ = ParseMoney(test.Estimate, "USD")
+		}
+
+		results := client.SendBulk(context.Background(),
+			bulkMessages(6, "", "55555"), opts)
+		if sent != test.Sent {
+			t.Errorf("Test %v failed; expected %v sent, got %v", idx, test.Sent, sent)
+		}
+		for i, res := range results {
+			var over *ErrBudgetExceeded
+			if i < test.Sent && res.Err != nil {
+				t.Errorf("Test %v failed; result %v: %v", idx, i, res.Err)
+			} else if i >= test.Sent && (!errors.As(res.Err, &over) || over.Sent != test.Sent) {
+				t.Errorf("Test %v failed; result %v: expected ErrBudgetExceeded, got %v",
+					idx, i, res.Err)
+			}
+		}
+	}
+}
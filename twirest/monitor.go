@@ -0,0 +1,105 @@
+package twirest
+
+import "encoding/json"
+
+// Monitor resources (https://www.twilio.com/docs/usage/monitor-alert). Alerts
+// collect webhook failures and warnings across the whole account, Events is
+// an audit log of changes made through the REST API and the console.
+
+// Alert log levels
+const (
+	TwiLogError   = "error"
+	TwiLogWarning = "warning"
+	TwiLogNotice  = "notice"
+	TwiLogDebug   = "debug"
+)
+
+// ListAlerts requests a list of alerts
+type ListAlerts struct {
+	domain    uri    `url:"monitor.twilio.com/v1"`
+	resource  uri    `url:"/Alerts"`
+	LogLevel  string `url:"LogLevel"`
+	StartDate string `url:"StartDate"`
+	EndDate   string `url:"EndDate"`
+	PageSize  int    `url:"PageSize"` // at most 1000, default 50
+	Page      int    `url:"Page"`
+}
+
+// GetAlert requests a single alert including the request and response of
+// the failed webhook
+type GetAlert struct {
+	domain   uri `url:"monitor.twilio.com/v1"`
+	resource uri `url:"/Alerts"`
+	Sid      string
+}
+
+// ListEvents requests a list of events
+type ListEvents struct {
+	domain          uri    `url:"monitor.twilio.com/v1"`
+	resource        uri    `url:"/Events"`
+	ActorSid        string `url:"ActorSid"`
+	EventType       string `url:"EventType"`
+	ResourceSid     string `url:"ResourceSid"`
+	SourceIpAddress string `url:"SourceIpAddress"`
+	StartDate       string `url:"StartDate"`
+	EndDate         string `url:"EndDate"`
+	PageSize        int    `url:"PageSize"` // at most 1000, default 50
+	Page            int    `url:"Page"`
+}
+
+// GetEvent requests a single event
+type GetEvent struct {
+	domain   uri `url:"monitor.twilio.com/v1"`
+	resource uri `url:"/Events"`
+	Sid      string
+}
+
+type AlertsResponse struct {
+	Meta   Meta            `json:"meta"`
+	Alerts []AlertResponse `json:"alerts"`
+}
+
+type AlertResponse struct {
+	Sid           string `json:"sid"`
+	AccountSid    string `json:"account_sid"`
+	AlertText     string `json:"alert_text"`
+	ApiVersion    string `json:"api_version"`
+	DateCreated   string `json:"date_created"`
+	DateGenerated string `json:"date_generated"`
+	DateUpdated   string `json:"date_updated"`
+	ErrorCode     string `json:"error_code"`
+	LogLevel      string `json:"log_level"`
+	MoreInfo      string `json:"more_info"`
+	RequestMethod string `json:"request_method"`
+	RequestUrl    string `json:"request_url"`
+	ResourceSid   string `json:"resource_sid"`
+	ServiceSid    string `json:"service_sid"`
+	Url           string `json:"url"`
+	// The fields below are only included in
+	// resource from 'GetAlert' request
+	RequestVariables string `json:"request_variables"`
+	RequestHeaders   string `json:"request_headers"`
+	ResponseHeaders  string `json:"response_headers"`
+	ResponseBody     string `json:"response_body"`
+}
+
+type EventsResponse struct {
+	Meta   Meta            `json:"meta"`
+	Events []EventResponse `json:"events"`
+}
+
+type EventResponse struct {
+	Sid             string          `json:"sid"`
+	AccountSid      string          `json:"account_sid"`
+	ActorSid        string          `json:"actor_sid"`
+	ActorType       string          `json:"actor_type"`
+	Description     string          `json:"description"`
+	EventData       json.RawMessage `json:"event_data"`
+	EventDate       string          `json:"event_date"`
+	EventType       string          `json:"event_type"`
+	ResourceSid     string          `json:"resource_sid"`
+	ResourceType    string          `json:"resource_type"`
+	Source          string          `json:"source"`
+	SourceIpAddress string          `json:"source_ip_address"`
+	Url             string          `json:"url"`
+}
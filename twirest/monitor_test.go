@@ -0,0 +1,103 @@
+package twirest
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+const fixtureAlert = "NO0123456789abcdef0123456789abcdef"
+
+func TestMonitorResponses(t *testing.T) {
+	const monitor = "https://monitor.twilio.com/v1"
+	var tests = []struct {
+		Fixture string
+		Req     interface{}
+		Url     string // the url requested
+		Got     func(TwilioResponse) interface{}
+		Expect  interface{}
+	}{
+		{"alert.json", GetAlert{Sid: fixtureAlert}, monitor + "/Alerts/" + fixtureAlert,
+			func(r TwilioResponse) interface{} { return r.Alert },
+			&AlertResponse{
+				Sid:           fixtureAlert,
+				AccountSid:    "AC0123456789abcdef0123456789abcdef",
+				AlertText:     "Msg&sourceComponent=14100&httpResponse=502&url=https%3A%2F%2Fexample.com%2Fsms",
+				ApiVersion:    ApiVer,
+				DateCreated:   "2020-03-02T10:00:05Z",
+				DateGenerated: "2020-03-02T10:00:00Z",
+				DateUpdated:   "2020-03-02T10:00:05Z",
+				ErrorCode:     "11200",
+				LogLevel:      TwiLogError,
+				MoreInfo:      "https://www.twilio.com/docs/errors/11200",
+				RequestMethod: "POST",
+				RequestUrl:    "https://example.com/sms",
+				ResourceSid:   "SM0123456789abcdef0123456789abcdef",
+				Url:           monitor + "/Alerts/" + fixtureAlert,
+				// the payload of the failed webhook
+				RequestVariables: "Body=hello&From=%2B15005550006&To=%2B15005550001",
+				RequestHeaders:   "X-Twilio-Signature=abc%3D",
+				ResponseHeaders:  "Content-Type=text%2Fhtml&Content-Length=38",
+				ResponseBody:     "<html><body>Bad Gateway</body></html>",
+			}},
+		{"events.json", ListEvents{ResourceSid: "SD0123456789abcdef0123456789abcdef"},
+			monitor + "/Events?ResourceSid=SD0123456789abcdef0123456789abcdef",
+			func(r TwilioResponse) interface{} { return r.Events },
+			&EventsResponse{
+				Meta: Meta{
+					PageSize: 50,
+					FirstPageUrl: monitor + "/Events?ResourceSid=SD0123456789abcdef0123456789abcdef" +
+						"&PageSize=50&Page=0",
+					Url: monitor + "/Events?ResourceSid=SD0123456789abcdef0123456789abcdef" +
+						"&PageSize=50&Page=0",
+					Key: "events",
+				},
+				Events: []EventResponse{{
+					Sid:        "AE0123456789abcdef0123456789abcdef",
+					AccountSid: "AC0123456789abcdef0123456789abcdef",
+					ActorSid:   "US0123456789abcdef0123456789abcdef",
+					ActorType:  "account",
+					EventData: json.RawMessage(`{
+        "friendly_name": {"previous": "SIP Domain", "updated": "Support"}
+      }`),
+					EventDate:       "2020-03-02T10:00:00Z",
+					EventType:       "sip-domain.updated",
+					ResourceSid:     "SD0123456789abcdef0123456789abcdef",
+					ResourceType:    "sip-domain",
+					Source:          "web",
+					SourceIpAddress: "203.0.113.7",
+					Url:             monitor + "/Events/AE0123456789abcdef0123456789abcdef",
+				}},
+			}},
+	}
+
+	for idx, test := range tests {
+		body, err := ioutil.ReadFile(filepath.Join("testdata", test.Fixture))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var requested string
+		client, ts := testClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requested = "https://" + r.Host + r.URL.RequestURI()
+			w.Header().Set("Content-Type", "application/json")
+			w.Write(body)
+		}))
+		resp, err := client.Request(test.Req, false)
+		ts.Close()
+		if err != nil {
+			t.Errorf("Test %v failed; %v: %v", idx, test.Fixture, err)
+			continue
+		}
+		if requested != test.Url {
+			t.Errorf("Test %v failed; expected a request of %v, got %v", idx, test.Url, requested)
+		}
+		if got := test.Got(resp); !reflect.DeepEqual(got, test.Expect) {
+			t.Errorf("Test %v failed; %v\nexpected %+v\ngot      %+v", idx, test.Fixture,
+				test.Expect, got)
+		}
+	}
+}
@@ -0,0 +1,221 @@
+package twirest
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// NumberConfig is the desired webhook configuration of the phone number
+// PhoneNumber. Empty fields aren't managed, whatever is configured is kept.
+// The fields are named like the fields of IncomingPhoneNumberResponse and the
+// parameters of UpdateIncomingPhoneNumber.
+type NumberConfig struct {
+	PhoneNumber          string
+	VoiceUrl             string
+	VoiceMethod          string
+	VoiceFallbackUrl     string
+	VoiceFallbackMethod  string
+	VoiceApplicationSid  string
+	StatusCallback       string
+	StatusCallbackMethod string
+	SmsUrl               string
+	SmsMethod            string
+	SmsFallbackUrl       string
+	SmsFallbackMethod    string
+	SmsApplicationSid    string
+}
+
+// FieldChange is a field of a phone number that differs from the desired
+// configuration
+type FieldChange struct {
+	Field   string
+	Current string
+	Desired string
+}
+
+// NumberUpdate is the update bringing a phone number to its desired
+// configuration
+type NumberUpdate struct {
+	PhoneNumber string
+	Changes     []FieldChange
+	Request     UpdateIncomingPhoneNumber
+}
+
+// Plan holds the updates that reconcile the phone numbers of the account with
+// their desired configuration. Unmanaged are the phone numbers of the account
+// without a desired configuration, Missing the phone numbers with a desired
+// configuration that aren't in the account. Neither are changed.
+type Plan struct {
+	Updates   []NumberUpdate
+	Unmanaged []string
+	Missing   []string
+	// Concurrency is the maximum number of updates ApplyPlan makes at once,
+	// default 5
+	Concurrency int
+}
+
+// ApplyResult is the outcome of one update of a plan
+type ApplyResult struct {
+	Update   NumberUpdate
+	Response TwilioResponse
+	Err      error
+}
+
+// ApplyReport holds the results of ApplyPlan in the order of the updates
+type ApplyReport struct {
+	Results []ApplyResult
+	Updated int
+	Failed  int
+}
+
+// numberConfigField maps a NumberConfig field to the IncomingPhoneNumberResponse
+// and UpdateIncomingPhoneNumber fields of the same parameter
+type numberConfigField struct {
+	name    string
+	config  int
+	current int
+	update  int
+}
+
+var (
+	numberConfigOnce   sync.Once
+	numberConfigFields []numberConfigField
+)
+
+// configFields returns the fields of NumberConfig with the fields they map to,
+// the update field is matched by its parameter tag
+func configFields() []numberConfigField {
+	numberConfigOnce.Do(func() {
+		config := reflect.TypeOf(NumberConfig{})
+		current := reflect.TypeOf(IncomingPhoneNumberResponse{})
+		update := reflect.TypeOf(UpdateIncomingPhoneNumber{})
+
+		for i := 0; i < config.NumField(); i++ {
+			name := config.Field(i).Name
+			if name == "PhoneNumber" {
+				continue
+			}
+			cur, ok := current.FieldByName(name)
+			if !ok {
+				panic("twirest: no IncomingPhoneNumberResponse field " + name)
+			}
+			f := numberConfigField{name: name, config: i, current: cur.Index[0], update: -1}
+			for j := 0; j < update.NumField(); j++ {
+				if update.Field(j).Tag.Get("url") == name {
+					f.update = j
+				}
+			}
+			if f.update < 0 {
+				panic("twirest: no UpdateIncomingPhoneNumber parameter " + name)
+			}
+			numberConfigFields = append(numberConfigFields, f)
+		}
+	})
+	return numberConfigFields
+}
+
+// PlanNumberConfig lists the incoming phone numbers of the account and plans
+// the updates of the numbers whose configuration differs from desired.
+// Methods are compared case insensitively.
+func (twiClient *TwilioClient) PlanNumberConfig(desired []NumberConfig) (Plan, error) {
+	want := make(map[string]NumberConfig, len(desired))
+	for _, d := range desired {
+		if _, ok := want[d.PhoneNumber]; ok {
+			return Plan{}, fmt.Errorf("duplicate phone number: '%s'", d.PhoneNumber)
+		}
+		want[d.PhoneNumber] = d
+	}
+
+	numbers, err := twiClient.listNumbers(context.Background(), twiClient.accountSid)
+	if err != nil {
+		return Plan{}, err
+	}
+
+	var plan Plan
+	found := make(map[string]bool, len(numbers))
+	for _, n := range numbers {
+		d, ok := want[n.PhoneNumber]
+		if !ok {
+			plan.Unmanaged = append(plan.Unmanaged, n.PhoneNumber)
+			continue
+		}
+		found[n.PhoneNumber] = true
+		if update, ok := planNumber(n, d); ok {
+			plan.Updates = append(plan.Updates, update)
+		}
+	}
+	for _, d := range desired {
+		if !found[d.PhoneNumber] {
+			plan.Missing = append(plan.Missing, d.PhoneNumber)
+		}
+	}
+	return plan, nil
+}
+
+// planNumber returns the update of the phone number n to the configuration d,
+// false if it is configured already
+func planNumber(n IncomingPhoneNumberResponse, d NumberConfig) (NumberUpdate, bool) {
+	update := NumberUpdate{
+		PhoneNumber: n.PhoneNumber,
+		Request:     UpdateIncomingPhoneNumber{Sid: n.Sid},
+	}
+	config := reflect.ValueOf(d)
+	current := reflect.ValueOf(n)
+	req := reflect.ValueOf(&update.Request).Elem()
+
+	for _, f := range configFields() {
+		want := config.Field(f.config).String()
+		have := current.Field(f.current).String()
+		if want == "" || want == have ||
+			(strings.HasSuffix(f.name, "Method") && strings.EqualFold(want, have)) {
+			continue
+		}
+		update.Changes = append(update.Changes,
+			FieldChange{Field: f.name, Current: have, Desired: want})
+		req.Field(f.update).SetString(want)
+	}
+	return update, len(update.Changes) > 0
+}
+
+// ApplyPlan makes the updates of the plan. An error is returned if any update
+// failed, the report tells which.
+func (twiClient *TwilioClient) ApplyPlan(ctx context.Context, plan Plan) (
+	ApplyReport, error) {
+
+	concurrency := plan.Concurrency
+	if concurrency <= 0 {
+		concurrency = 5
+	}
+
+	report := ApplyReport{Results: make([]ApplyResult, len(plan.Updates))}
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, concurrency)
+	for i, update := range plan.Updates {
+		report.Results[i].Update = update
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(res *ApplyResult) {
+			defer wg.Done()
+			res.Response, res.Err = twiClient.RequestWithContext(ctx,
+				res.Update.Request, false)
+			<-sem
+		}(&report.Results[i])
+	}
+	wg.Wait()
+
+	for _, res := range report.Results {
+		if res.Err != nil {
+			report.Failed++
+		} else {
+			report.Updated++
+		}
+	}
+	if report.Failed > 0 {
+		return report, fmt.Errorf("failed to apply %d of %d number updates",
+			report.Failed, len(report.Results))
+	}
+	return report, nil
+}
@@ -0,0 +1,168 @@
+package twirest
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestConfigFields(t *testing.T) {
+	fields := configFields()
+	if len(fields) != reflect.TypeOf(NumberConfig{}).NumField()-1 {
+		t.Errorf("expected every NumberConfig field mapped, got %v", fields)
+	}
+}
+
+func TestNumberConfigPlan(t *testing.T) {
+	const base = "/2010-04-01/Accounts/AC123/IncomingPhoneNumbers"
+	pages := map[string]string{
+		base: `<IncomingPhoneNumbers nextpageuri="` + base + `?Page=1">` +
+			`<IncomingPhoneNumber><Sid>PN1</Sid><PhoneNumber>+15005550001</PhoneNumber>` +
+			`<VoiceUrl>https://example.com/old</VoiceUrl><VoiceMethod>POST</VoiceMethod>` +
+			`<SmsUrl>https://example.com/sms</SmsUrl></IncomingPhoneNumber>` +
+			`<IncomingPhoneNumber><Sid>PN2</Sid><PhoneNumber>+15005550002</PhoneNumber>` +
+			`<VoiceUrl>https://example.com/voice</VoiceUrl><VoiceMethod>POST</VoiceMethod>` +
+			`</IncomingPhoneNumber></IncomingPhoneNumbers>`,
+		base + "?Page=1": `<IncomingPhoneNumbers nextpageuri="">` +
+			`<IncomingPhoneNumber><Sid>PN3</Sid><PhoneNumber>+15005550003</PhoneNumber>` +
+			`<StatusCallback>https://example.com/status</StatusCallback></IncomingPhoneNumber>` +
+			`<IncomingPhoneNumber><Sid>PN4</Sid><PhoneNumber>+15005550004</PhoneNumber>` +
+			`</IncomingPhoneNumber></IncomingPhoneNumbers>`,
+	}
+
+	var mu sync.Mutex
+	updated := map[string]string{}
+	client, ts := testClient(t, http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == "GET" {
+				w.Write([]byte(xmlHeader + "<TwilioResponse>" +
+					pages[r.URL.RequestURI()] + "</TwilioResponse>"))
+				return
+			}
+			r.ParseForm()
+			sid := strings.TrimPrefix(r.URL.Path, base+"/")
+			mu.Lock()
+			updated[sid] = r.PostForm.Encode()
+			mu.Unlock()
+			if sid == "PN3" {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(xmlHeader + `<TwilioResponse><RestException><Code>21402</Code>` +
+					`<Message>Url is not valid</Message><Status>400</Status>` +
+					`</RestException></TwilioResponse>`))
+				return
+			}
+			w.Write([]byte(xmlHeader + `<TwilioResponse><IncomingPhoneNumber><Sid>` + sid +
+				`</Sid></IncomingPhoneNumber></TwilioResponse>`))
+		}))
+	defer ts.Close()
+
+	plan, err := client.PlanNumberConfig([]NumberConfig{
+		{PhoneNumber: "+15005550001", VoiceUrl: "https://example.com/voice",
+			VoiceMethod: "post", SmsUrl: "https://example.com/sms"},
+		{PhoneNumber: "+15005550002", VoiceUrl: "https://example.com/voice"},
+		{PhoneNumber: "+15005550003", StatusCallback: "https://example.com/status2",
+			StatusCallbackMethod: "GET"},
+		{PhoneNumber: "+15005550009", VoiceUrl: "https://example.com/voice"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expect := []NumberUpdate{
+		{PhoneNumber: "+15005550001",
+			Changes: []FieldChange{{"VoiceUrl", "https://example.com/old", "https://example.com/voice"}},
+			Request: UpdateIncomingPhoneNumber{Sid: "PN1", VoiceURL: "https://example.com/voice"}},
+		{PhoneNumber: "+15005550003",
+			Changes: []FieldChange{
+				{"StatusCallback", "https://example.com/status", "https://example.com/status2"},
+				{"StatusCallbackMethod", "", "GET"}},
+			Request: UpdateIncomingPhoneNumber{Sid: "PN3",
+				StatusCallback: "https://example.com/status2", StatusCallbackMethod: "GET"}},
+	}
+	if !reflect.DeepEqual(plan.Updates, expect) {
+		t.Errorf("expected updates %#v, got %#v", expect, plan.Updates)
+	}
+	if !reflect.DeepEqual(plan.Unmanaged, []string{"+15005550004"}) ||
+		!reflect.DeepEqual(plan.Missing, []string{"+15005550009"}) {
+		t.Errorf("unexpected unmanaged %v and missing %v", plan.Unmanaged, plan.Missing)
+	}
+
+	report, err := client.ApplyPlan(context.Background(), plan)
+	if err == nil || report.Updated != 1 || report.Failed != 1 {
+		t.Errorf("expected one failed update, got %+v (%v)", report, err)
+	}
+	if report.Results[0].Err != nil || report.Results[1].Err == nil ||
+		report.Results[1].Response.Status.Twilio != 21402 {
+		t.Errorf("expected the second update to fail, got %+v", report.Results)
+	}
+	if len(updated) != 2 || updated["PN1"] != "VoiceUrl=https%3A%2F%2Fexample.com%2Fvoice" {
+		t.Errorf("expected only planned updates, got %v", updated)
+	}
+
+	_, err = client.PlanNumberConfig([]NumberConfig{
+		{PhoneNumber: "+15005550001"}, {PhoneNumber: "+15005550001"}})
+	if err == nil {
+		t.Errorf("expected an error for duplicate phone numbers")
+	}
+}
+
+func TestNumberConfigApplication(t *testing.T) {
+	const (
+		base = "/2010-04-01/Accounts/AC123/IncomingPhoneNumbers"
+		app  = "AP0123456789abcdef0123456789abcdef"
+	)
+	var page string
+	var desired []NumberConfig
+	for i := 0; i < 50; i++ {
+		number := fmt.Sprintf("+150055501%02d", i)
+		page += fmt.Sprintf("<IncomingPhoneNumber><Sid>PN%d</Sid><PhoneNumber>%s</PhoneNumber>"+
+			"<SmsUrl>https://example.com/sms</SmsUrl></IncomingPhoneNumber>", i, number)
+		desired = append(desired, NumberConfig{PhoneNumber: number, SmsApplicationSid: app})
+	}
+
+	var mu sync.Mutex
+	updated := map[string]string{}
+	client, ts := testClient(t, http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == "GET" {
+				w.Write([]byte(xmlHeader + `<TwilioResponse><IncomingPhoneNumbers nextpageuri="">` +
+					page + "</IncomingPhoneNumbers></TwilioResponse>"))
+				return
+			}
+			r.ParseForm()
+			sid := strings.TrimPrefix(r.URL.Path, base+"/")
+			mu.Lock()
+			updated[sid] = r.PostForm.Get("SmsApplicationSid")
+			mu.Unlock()
+			w.Write([]byte(xmlHeader + `<TwilioResponse><IncomingPhoneNumber><Sid>` + sid +
+				`</Sid><SmsApplicationSid>` + r.PostForm.Get("SmsApplicationSid") +
+				`</SmsApplicationSid></IncomingPhoneNumber></TwilioResponse>`))
+		}))
+	defer ts.Close()
+
+	plan, err := client.PlanNumberConfig(desired)
+	if err != nil || len(plan.Updates) != 50 {
+		t.Fatalf("expected 50 updates, got %v (%v)", len(plan.Updates), err)
+	}
+	report, err := client.ApplyPlan(context.Background(), plan)
+	if err != nil || report.Updated != 50 || len(updated) != 50 {
+		t.Fatalf("expected 50 numbers updated, got %+v (%v)", report, err)
+	}
+	for _, res := range report.Results {
+		resp := res.Response.IncomingPhoneNumber
+		if resp == nil || resp.SmsApplicationSid != app || updated[resp.Sid] != app {
+			t.Errorf("expected %v pointed at %v, got %#v", res.Update.PhoneNumber, app, resp)
+		}
+	}
+
+	desired[0].SmsApplicationSid = "AP123"
+	plan, _ = client.PlanNumberConfig(desired)
+	if report, err = client.ApplyPlan(context.Background(), plan); err == nil ||
+		report.Results[0].Err == nil {
+		t.Errorf("expected the non valid application sid rejected, got %+v", report.Results[0])
+	}
+}
@@ -0,0 +1,87 @@
+package twirest
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// ClientOption configures a TwilioClient, options are passed to NewClient
+// after the credentials. They are only applied by NewClient and
+// NewRequestScopedClient, the configuration of a client doesn't change once
+// it's made.
+type ClientOption struct {
+	apply func(*TwilioClient)
+}
+
+// WithFromValidation makes the client check the From number of SendMessage
+// and MakeCall requests with ValidateFrom before sending them. The list of
+// owned numbers is cached for ttl, zero keeps the default of ten minutes.
+func WithFromValidation(ttl time.Duration) ClientOption {
+	return ClientOption{apply: func(c *TwilioClient) {
+		c.validateFrom = true
+		if ttl > 0 {
+			c.owned.ttl = ttl
+		}
+	}}
+}
+
+// WithLenientNumbers makes the client send the To and From of SendMessage and
+// MakeCall requests as they are. By default a number that isn't in E.164
+// format, such as (604) 555-0123, fails before it is sent, see
+// NormalizePhoneNumber. Short codes, alphanumeric sender IDs and addresses
+// such as client:alice are never checked.
+func WithLenientNumbers() ClientOption {
+	return ClientOption{apply: func(c *TwilioClient) {
+		c.lenientNumbers = true
+	}}
+}
+
+// WithConnectedAccount makes the client act on behalf of accountSid, an
+// account that authorized our connect app. Requests are made on the resources
+// of accountSid while authenticating with the credentials of the client.
+func WithConnectedAccount(accountSid string) ClientOption {
+	return ClientOption{apply: func(c *TwilioClient) {
+		if c.authUser == "" {
+			c.authUser = c.accountSid
+		}
+		c.accountSid = accountSid
+		c.connected = true
+	}}
+}
+
+// WithHTTPClient makes the client send its requests with hc, for custom
+// timeouts, proxies or instrumentation. Pass it before options that change
+// the transport, such as WithDialContext, as it replaces the http client.
+func WithHTTPClient(hc *http.Client) ClientOption {
+	return ClientOption{apply: func(c *TwilioClient) {
+		c.httpclient = hc
+		c.customHTTP = true
+	}}
+}
+
+// WithTimeout limits the time of a request, including reading the response
+// body. The client gets its own http client so clients sharing a transport
+// aren't affected.
+func WithTimeout(d time.Duration) ClientOption {
+	return ClientOption{apply: func(c *TwilioClient) {
+		hc := *c.httpclient
+		hc.Timeout = d
+		c.httpclient = &hc
+	}}
+}
+
+// WithBaseURL sends the requests for twilio's hosts to base instead, such as
+// the url of a httptest server in integration tests. The path of base
+// prefixes the path of the requests.
+func WithBaseURL(base string) ClientOption {
+	return ClientOption{apply: func(c *TwilioClient) {
+		u, err := url.Parse(base)
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			c.optErr = fmt.Errorf("non valid base url: '%s'", base)
+			return
+		}
+		c.baseURL = u
+	}}
+}
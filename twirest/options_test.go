@@ -0,0 +1,129 @@
+package twirest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWithBaseURL(t *testing.T) {
+	var paths []string
+	var user string
+	ts := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			paths = append(paths, r.URL.RequestURI())
+			user, _, _ = r.BasicAuth()
+			w.Write([]byte(`<TwilioResponse><Queue><Sid>QU1</Sid></Queue></TwilioResponse>`))
+		}))
+	defer ts.Close()
+
+	var tests = []struct {
+		New    func() (*TwilioClient, error)
+		Expect string
+		User   string
+	}{
+		{func() (*TwilioClient, error) {
+			return NewClient("AC123", "token", WithBaseURL(ts.URL))
+		}, "/2010-04-01/Accounts/AC123/Queues/QU1", "AC123"},
+		{func() (*TwilioClient, error) {
+			return NewAPIKeyClient("AC123", "SK456", "secret", WithBaseURL(ts.URL+"/twilio/"))
+		}, "/twilio/2010-04-01/Accounts/AC123/Queues/QU1", "SK456"},
+	}
+
+	for idx, test := range tests {
+		paths = nil
+		client, err := test.New()
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp, err := client.Request(Queue{Sid: "QU1"}, false)
+		if err != nil || resp.Queue == nil || resp.Queue.Sid != "QU1" {
+			t.Errorf("Test %v failed; expected the queue, got %#v (%v)", idx, resp.Queue, err)
+		}
+		if len(paths) != 1 || paths[0] != test.Expect || user != test.User {
+			t.Errorf("Test %v failed; expected %v as %v, got %v as %v", idx,
+				test.Expect, test.User, paths, user)
+		}
+	}
+
+	for _, base := range []string{"localhost:8080", "://", ""} {
+		if _, err := NewClient("AC123", "token", WithBaseURL(base)); err == nil {
+			t.Errorf("expected an error for base url %#v", base)
+		}
+	}
+}
+
+func TestWithHTTPClientAndTimeout(t *testing.T) {
+	hc := &http.Client{}
+	client, err := NewClient("AC123", "token", WithHTTPClient(hc))
+	if err != nil || client.httpclient != hc {
+		t.Errorf("expected the http client used, got %v (%v)", client, err)
+	}
+
+	shared := NewSharedTransport()
+	client, err = NewRequestScopedClient(Credentials{AccountSid: "AC123", AuthToken: "token"},
+		shared, WithTimeout(2*time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if client.httpclient.Timeout != 2*time.Second || shared.httpclient.Timeout != 0 ||
+		client.httpclient.Transport != shared.httpclient.Transport {
+		t.Errorf("expected a timeout on the client's own http client sharing the transport")
+	}
+
+	ts := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			time.Sleep(200 * time.Millisecond)
+		}))
+	defer ts.Close()
+	client, _ = NewClient("AC123", "token", WithBaseURL(ts.URL),
+		WithTimeout(20*time.Millisecond))
+	if _, err := client.Request(Queues{}, false); err == nil {
+		t.Errorf("expected the request to time out")
+	}
+}
+
+func TestBaseURLEveryRequest(t *testing.T) {
+	var paths []string
+	ts := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			paths = append(paths, r.Method+" "+r.URL.Path)
+			if !strings.HasPrefix(r.URL.Path, "/mock/"+ApiVer) {
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(`{}`))
+				return
+			}
+			w.Write([]byte(`<TwilioResponse></TwilioResponse>`))
+		}))
+	defer ts.Close()
+
+	client, err := NewClient("AC123", "token", WithBaseURL(ts.URL+"/mock/"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for idx, rt := range RequestTypes() {
+		schema, _ := DescribeRequest(rt)
+		// the request with the placeholders of its schema
+		v := reflect.New(rt).Elem()
+		for _, name := range pathFields {
+			if fld := v.FieldByName(name); fld.IsValid() && fld.Kind() == reflect.String {
+				fld.SetString("{" + name + "}")
+			}
+		}
+
+		paths = nil
+		if _, err := client.Request(withRequired(v.Interface()), false); err != nil {
+			t.Errorf("Test %v failed; %v: %v", idx, rt.Name(), err)
+		}
+		u, _ := url.Parse(strings.Replace(schema.Url, "{AccountSid}", "AC123", 1))
+		// every host of twilio is sent to the base url
+		expect := schema.Method + " /mock" + u.Path
+		if len(paths) != 1 || paths[0] != expect {
+			t.Errorf("Test %v failed; %v expected %v, got %v", idx, rt.Name(), expect, paths)
+		}
+	}
+}
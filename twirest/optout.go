@@ -0,0 +1,79 @@
+package twirest
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/seanhagen/twilio/twiml"
+)
+
+// OptOutAction is what a keyword message asks of the sender
+type OptOutAction string
+
+// Opt-out keyword actions
+const (
+	TwiOptOut OptOutAction = "opt-out"
+	TwiOptIn  OptOutAction = "opt-in"
+	TwiHelp   OptOutAction = "help"
+)
+
+// optOutKeywords are the standard keywords twilio handles for messaging
+// services and long codes
+var optOutKeywords = map[string]OptOutAction{
+	"STOP":        TwiOptOut,
+	"STOPALL":     TwiOptOut,
+	"UNSUBSCRIBE": TwiOptOut,
+	"CANCEL":      TwiOptOut,
+	"END":         TwiOptOut,
+	"QUIT":        TwiOptOut,
+	"START":       TwiOptIn,
+	"UNSTOP":      TwiOptIn,
+	"YES":         TwiOptIn,
+	"HELP":        TwiHelp,
+}
+
+// DetectOptOut reports if the body of an incoming message is an opt-out
+// keyword and which action it asks for. Keywords match case insensitively
+// with surrounding whitespace, but like carriers only when the body is the
+// keyword alone: 'please stop texting me' isn't an opt-out.
+func DetectOptOut(body string) (OptOutAction, bool) {
+	action, ok := optOutKeywords[strings.ToUpper(strings.TrimSpace(body))]
+	return action, ok
+}
+
+// OptOutHooks are called by OptOutHandler for keyword messages, with the
+// incoming message. A nil hook is skipped.
+type OptOutHooks struct {
+	OnOptOut func(msg twiml.InboundMessage) error
+	OnOptIn  func(msg twiml.InboundMessage) error
+}
+
+// OptOutHandler wraps the handler of incoming messages to update suppression
+// lists: the hooks are called for opt-out and opt-in keywords before the
+// message is passed on to next. If a hook fails the request fails with status
+// 500 and next isn't called. Twilio itself blocks messages to recipients that
+// opted out.
+func OptOutHandler(next http.Handler, hooks OptOutHooks) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		msg, err := twiml.ParseInboundMessage(r)
+		if err != nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		var hook func(twiml.InboundMessage) error
+		switch action, _ := DetectOptOut(msg.Body); action {
+		case TwiOptOut:
+			hook = hooks.OnOptOut
+		case TwiOptIn:
+			hook = hooks.OnOptIn
+		}
+		if hook != nil {
+			if err := hook(msg); err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,99 @@
+package twirest
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/seanhagen/twilio/twiml"
+)
+
+func TestDetectOptOut(t *testing.T) {
+	var tests = []struct {
+		Body   string
+		Action OptOutAction
+		Found  bool
+	}{
+		{"STOP", TwiOptOut, true},
+		{"stop", TwiOptOut, true},
+		{"  Unsubscribe\n", TwiOptOut, true},
+		{"StopAll", TwiOptOut, true},
+		{"cancel", TwiOptOut, true},
+		{"END", TwiOptOut, true},
+		{"quit ", TwiOptOut, true},
+		{"start", TwiOptIn, true},
+		{"UNSTOP", TwiOptIn, true},
+		{"Yes", TwiOptIn, true},
+		{"help", TwiHelp, true},
+		// carriers only match the keyword alone
+		{"please stop texting me", "", false},
+		{"STOP!", "", false},
+		{"stop stop", "", false},
+		{"", "", false},
+		{"stopped", "", false},
+	}
+
+	for idx, test := range tests {
+		action, found := DetectOptOut(test.Body)
+		if action != test.Action || found != test.Found {
+			t.Errorf("Test %v failed; expected %v %v, got %v %v",
+				idx, test.Action, test.Found, action, found)
+		}
+	}
+}
+
+func TestOptOutHandler(t *testing.T) {
+	var tests = []struct {
+		Body     string
+		HookErr  error
+		OptedOut []string
+		OptedIn  []string
+		Status   int
+	}{
+		{"STOP", nil, []string{"+15005550006"}, nil, http.StatusOK},
+		{" start ", nil, nil, []string{"+15005550006"}, http.StatusOK},
+		{"help", nil, nil, nil, http.StatusOK},
+		{"stop it please", nil, nil, nil, http.StatusOK},
+		{"quit", fmt.Errorf("list unavailable"), []string{"+15005550006"}, nil,
+			http.StatusInternalServerError},
+	}
+
+	for idx, test := range tests {
+		var optedOut, optedIn []string
+		var passed bool
+		hooks := OptOutHooks{
+			OnOptOut: func(msg twiml.InboundMessage) error {
+				optedOut = append(optedOut, msg.From)
+				return test.HookErr
+			},
+			OnOptIn: func(msg twiml.InboundMessage) error {
+				optedIn = append(optedIn, msg.From)
+				return test.HookErr
+			},
+		}
+		handler := OptOutHandler(http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				passed = r.FormValue(twiml.TwiBody) == test.Body
+			}), hooks)
+
+		form := url.Values{twiml.TwiMessageSid: {"SM123"}, twiml.TwiFrom: {"+15005550006"},
+			twiml.TwiBody: {test.Body}}
+		r := httptest.NewRequest("POST", "/sms", strings.NewReader(form.Encode()))
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+
+		if w.Code != test.Status || passed != (test.Status == http.StatusOK) {
+			t.Errorf("Test %v failed; expected status %v, got %v (passed on %v)",
+				idx, test.Status, w.Code, passed)
+		}
+		if fmt.Sprint(optedOut) != fmt.Sprint(test.OptedOut) ||
+			fmt.Sprint(optedIn) != fmt.Sprint(test.OptedIn) {
+			t.Errorf("Test %v failed; expected %v/%v, got %v/%v",
+				idx, test.OptedOut, test.OptedIn, optedOut, optedIn)
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package twirest
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// MaxPageSize is the largest PageSize of a list request
+const MaxPageSize = 1000
+
+// pageFields holds the index of the PageSize and Page fields of the list
+// request types
+var pageFields = map[reflect.Type][2]int{}
+
+func init() {
+	for _, req := range requestTypes {
+		t := reflect.TypeOf(req)
+		size, ok := t.FieldByName("PageSize")
+		page, hasPage := t.FieldByName("Page")
+		if ok && hasPage {
+			pageFields[t] = [2]int{size.Index[0], page.Index[0]}
+		}
+	}
+}
+
+// validPage checks that the PageSize of a list request, if set, is between 1
+// and MaxPageSize and that its Page isn't negative
+func validPage(reqStruct interface{}) error {
+	fields, ok := pageFields[reflect.TypeOf(reqStruct)]
+	if !ok {
+		return nil
+	}
+	v := reflect.ValueOf(reqStruct)
+	if size := v.Field(fields[0]).Int(); size != 0 && (size < 1 || size > MaxPageSize) {
+		return fmt.Errorf("non valid PageSize: '%d', between 1 and %d", size, MaxPageSize)
+	}
+	if page := v.Field(fields[1]).Int(); page < 0 {
+		return fmt.Errorf("non valid Page: '%d'", page)
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package twirest
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestPageParameters(t *testing.T) {
+	for _, req := range requestTypes {
+		rt := reflect.TypeOf(req)
+		if _, ok := pageFields[rt]; !ok {
+			continue
+		}
+		v := reflect.New(rt).Elem()
+		v.Set(reflect.ValueOf(withRequired(req)))
+		if fld := v.FieldByName("PageSize"); fld.Kind() == reflect.String {
+			fld.SetString("1000")
+		} else {
+			fld.SetInt(1000)
+		}
+		v.FieldByName("Page").SetInt(2)
+
+		httpReq, err := httpRequest(v.Interface(), "AC123", nil)
+		if err != nil {
+			t.Errorf("%v: %v", rt.Name(), err)
+			continue
+		}
+		if q := httpReq.URL.RawQuery; !strings.HasSuffix(q, "PageSize=1000&Page=2") {
+			t.Errorf("%v: expected the page parameters, got %v", rt.Name(), q)
+		}
+	}
+
+	// the list request types all have them
+	for _, rt := range []interface{}{Messages{}, Calls{}, Recordings{}, Notifications{},
+		Conferences{}, Participants{}, OutgoingCallerIds{}, AvailablePhoneNumbers{},
+		IncomingPhoneNumberList{}, Queues{}, ListSims{}} {
+		if _, ok := pageFields[reflect.TypeOf(rt)]; !ok {
+			t.Errorf("%T: expected PageSize and Page fields", rt)
+		}
+	}
+}
+
+func TestValidPage(t *testing.T) {
+	var tests = []struct {
+		Req   interface{}
+		Valid bool
+	}{
+		{Calls{}, true},
+		{Calls{PageSize: 1}, true},
+		{Calls{PageSize: MaxPageSize, Page: 3}, true},
+		{Calls{PageSize: MaxPageSize + 1}, false},
+		{Calls{PageSize: -1}, false},
+		{Calls{Page: -1}, false},
+		{Messages{PageSize: 50}, true},
+		{Messages{PageSize: 1001}, false},
+		{Messages{PageSize: -1}, false},
+		{ListAlerts{PageSize: 20}, true},
+		{ListAlerts{PageSize: 2000}, false},
+		{Message{Sid: "SM123"}, true},
+	}
+
+	for idx, test := range tests {
+		if err := validPage(test.Req); (err == nil) != test.Valid {
+			t.Errorf("Test %v failed; expected valid %v, got %v", idx, test.Valid, err)
+		}
+	}
+
+	if got := Encode(Calls{To: "+15005550006", PageSize: 20}); got != "To=%2B15005550006&PageSize=20" {
+		t.Errorf("expected PageSize after the filters, got %v", got)
+	}
+	if got := Encode(Calls{}); got != "" {
+		t.Errorf("expected no page parameters when unset, got %v", got)
+	}
+}
@@ -0,0 +1,84 @@
+package twirest
+
+import (
+	"testing"
+
+	"github.com/seanhagen/twilio/twiml"
+)
+
+func TestCreateParticipantProfiles(t *testing.T) {
+	var tests = []struct {
+		Name   string
+		Req    CreateParticipant
+		Expect string
+	}{
+		{"supervisor join", CreateParticipant{
+			Sid:                    "CF123",
+			From:                   "+15005550006",
+			To:                     "client:supervisor",
+			Label:                  "supervisor",
+			Muted:                  "true",
+			Beep:                   "false",
+			StartConferenceOnEnter: TwiFalse,
+			EndConferenceOnExit:    TwiFalse,
+		}, "From=%2B15005550006&To=client%3Asupervisor&Label=supervisor" +
+			"&Muted=true&Beep=false" +
+			"&StartConferenceOnEnter=false&EndConferenceOnExit=false"},
+		{"customer join", CreateParticipant{
+			Sid:                            "CF123",
+			From:                           "+15005550006",
+			To:                             "+15005550001",
+			Label:                          "customer",
+			Timeout:                        "30",
+			EarlyMedia:                     TwiTrue,
+			RingTone:                       "us",
+			MaxParticipants:                "3",
+			StartConferenceOnEnter:         BoolOf(true),
+			EndConferenceOnExit:            BoolOf(true),
+			WaitUrl:                        "https://example.com/hold",
+			WaitMethod:                     "GET",
+			ConferenceStatusCallback:       "https://example.com/conference",
+			ConferenceStatusCallbackEvents: []string{"start", "end", "join", "leave"},
+			JitterBufferSize:               "small",
+			CallerId:                       "+15005550006",
+			Byoc:                           "BY0123456789abcdef0123456789abcdef",
+		}, "From=%2B15005550006&To=%2B15005550001&Label=customer&Timeout=30" +
+			"&EarlyMedia=true&RingTone=us&MaxParticipants=3" +
+			"&StartConferenceOnEnter=true&EndConferenceOnExit=true" +
+			"&WaitUrl=https%3A%2F%2Fexample.com%2Fhold&WaitMethod=GET" +
+			"&ConferenceStatusCallback=https%3A%2F%2Fexample.com%2Fconference" +
+			"&ConferenceStatusCallbackEvent=start&ConferenceStatusCallbackEvent=end" +
+			"&ConferenceStatusCallbackEvent=join&ConferenceStatusCallbackEvent=leave" +
+			"&JitterBufferSize=small&CallerId=%2B15005550006" +
+			"&Byoc=BY0123456789abcdef0123456789abcdef"},
+		{"defaults", CreateParticipant{Sid: "CF123", From: "+15005550006", To: "+15005550001"},
+			"From=%2B15005550006&To=%2B15005550001"},
+	}
+
+	for idx, test := range tests {
+		if got := queryString(test.Req); got != test.Expect {
+			t.Errorf("Test %v (%v) failed;\nexpected %#v,\ngot      %#v",
+				idx, test.Name, test.Expect, got)
+		}
+	}
+}
+
+func TestParticipantJitterBufferSize(t *testing.T) {
+	var tests = []struct {
+		Size  twiml.JitterBufferSize
+		Valid bool
+	}{
+		{"", true},
+		{twiml.TwiJitterBufferOff, true},
+		{twiml.TwiJitterBufferMedium, true},
+		{"tiny", false},
+	}
+
+	for idx, test := range tests {
+		err := validate(CreateParticipant{Sid: "CF123", From: "+15005550006",
+			To: "+15005550001", JitterBufferSize: test.Size})
+		if (err == nil) != test.Valid {
+			t.Errorf("Test %v failed; expected valid %v, got %v", idx, test.Valid, err)
+		}
+	}
+}
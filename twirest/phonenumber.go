@@ -0,0 +1,99 @@
+package twirest
+
+import (
+	"fmt"
+	"strings"
+)
+
+// maxShortCode is the length limit of a short code, shorter numbers aren't
+// phone numbers
+const maxShortCode = 6
+
+// nanpCountries are the countries other than the US that share the +1
+// calling code of the north american numbering plan
+var nanpCountries = []string{"CA", "PR", "VI", "GU", "AS", "MP"}
+
+// ValidatePhoneNumber checks that s is a phone number in E.164 format, a +
+// and at most 15 digits without spaces or punctuation, as twilio expects in
+// the To and From of requests. Use NormalizePhoneNumber to format a number.
+func ValidatePhoneNumber(s string) error {
+	if !strings.HasPrefix(s, "+") || !isDigits(s[1:]) || len(s) < 8 || len(s) > 16 ||
+		s[1] == '0' {
+		return fmt.Errorf("non valid phone number: '%s', not E.164", s)
+	}
+	return nil
+}
+
+// NormalizePhoneNumber returns the phone number s in E.164 format, without the
+// spaces, dashes, dots and parentheses of a formatted number. A number without
+// a leading + or 00 is national to defaultCountry, an ISO country code such
+// as CA. Short codes, alphanumeric sender IDs and addresses such as
+// whatsapp:+15005550006 aren't phone numbers, they're returned as they are.
+func NormalizePhoneNumber(s, defaultCountry string) (string, error) {
+	if !phoneNumberLike(s) {
+		return s, nil
+	}
+	var code string
+	if defaultCountry != "" {
+		var ok bool
+		if code, ok = countryCallingCode(defaultCountry); !ok {
+			return "", fmt.Errorf("non valid country: '%s'", defaultCountry)
+		}
+	}
+	return normalizeE164(s, code)
+}
+
+// phoneNumberLike reports if s is meant as a phone number: it has a leading
+// +, or more digits than a short code and only the punctuation of a formatted
+// number
+func phoneNumberLike(s string) bool {
+	s = strings.TrimSpace(s)
+	if strings.HasPrefix(s, "+") {
+		return true
+	}
+	digits := 0
+	for _, c := range s {
+		switch {
+		case c >= '0' && c <= '9':
+			digits++
+		case strings.ContainsRune("+ -.()", c):
+		default:
+			return false
+		}
+	}
+	return digits > maxShortCode
+}
+
+// countryCallingCode returns the calling code of the ISO country code
+func countryCallingCode(country string) (string, bool) {
+	country = strings.ToUpper(country)
+	if stringIn(country, nanpCountries) {
+		return "1", true
+	}
+	for code, c := range CallingCodes {
+		if c == country {
+			return code, true
+		}
+	}
+	return "", false
+}
+
+// checkNumbers checks that the To and From of messages and calls that are
+// meant as phone numbers are in E.164 format, see WithLenientNumbers
+func checkNumbers(reqStruct interface{}) error {
+	var to, from string
+	switch reqSt := reqStruct.(type) {
+	case SendMessage:
+		to, from = reqSt.To, reqSt.From
+	case MakeCall:
+		to, from = reqSt.To, reqSt.From
+	}
+	for _, n := range [...]string{to, from} {
+		if phoneNumberLike(n) {
+			if err := ValidatePhoneNumber(n); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
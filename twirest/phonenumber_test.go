@@ -0,0 +1,91 @@
+package twirest
+
+import (
+	"testing"
+)
+
+func TestValidatePhoneNumber(t *testing.T) {
+	var tests = []struct {
+		Number string
+		Valid  bool
+	}{
+		{"+15005550006", true},
+		{"+442079460958", true},
+		{"+123456789012345", true},
+		{"+1234567890123456", false},
+		{"(604) 555-0123", false},
+		{"+1 604 555 0123", false},
+		{"16045550123", false},
+		{"+0123456789", false},
+		{"+1", false},
+		{"", false},
+	}
+
+	for idx, test := range tests {
+		if err := ValidatePhoneNumber(test.Number); (err == nil) != test.Valid {
+			t.Errorf("Test %v failed; expected valid %v, got %v", idx, test.Valid, err)
+		}
+	}
+}
+
+func TestNormalizePhoneNumber(t *testing.T) {
+	var tests = []struct {
+		Number  string
+		Country string
+		Expect  string
+	}{
+		{"(604) 555-0123", "CA", "+16045550123"},
+		{"604.555.0123", "us", "+16045550123"},
+		{"+1 (604) 555-0123", "", "+16045550123"},
+		{"020 7946 0958", "GB", "+442079460958"},
+		{"0044 20 7946 0958", "", "+442079460958"},
+		{"(604) 555-0123", "", ""},
+		{"(604) 555-0123", "XX", ""},
+		{"+1 604 555 012x", "CA", ""},
+		// not phone numbers, returned as they are
+		{"55555", "US", "55555"},
+		{"898211", "GB", "898211"},
+		{"Acme", "GB", "Acme"},
+		{"whatsapp:+15005550006", "US", "whatsapp:+15005550006"},
+	}
+
+	for idx, test := range tests {
+		got, err := NormalizePhoneNumber(test.Number, test.Country)
+		if (err == nil) != (test.Expect != "") || got != test.Expect {
+			t.Errorf("Test %v failed; expected %q, got %q %v", idx, test.Expect, got, err)
+		}
+	}
+}
+
+func TestCheckNumbers(t *testing.T) {
+	const url = "https://example.com/twiml"
+	var tests = []struct {
+		Req    interface{}
+		Strict bool
+	}{
+		{SendMessage{From: "+15005550006", To: "+15005550001", Text: "Hi"}, true},
+		{SendMessage{From: "+15005550006", To: "(604) 555-0123", Text: "Hi"}, false},
+		{SendMessage{From: "+1 604-555-0123", To: "+15005550001", Text: "Hi"}, false},
+		{SendMessage{From: "55555", To: "+15005550001", Text: "Hi"}, true},
+		{SendMessage{From: "Acme", To: "+447700900123", Text: "Hi"}, true},
+		{SendMessage{From: "whatsapp:+15005550006", To: "whatsapp:+15005550001",
+			Text: "Hi"}, true},
+		{MakeCall{From: "+15005550006", To: "client:alice", Url: url}, true},
+		{MakeCall{From: "+15005550006", To: "sip:alice@example.com", Url: url}, true},
+		{MakeCall{From: "+1 500 555 0006", To: "+15005550001", Url: url}, false},
+	}
+
+	strict, ts := testClient(t, &recorder{})
+	defer ts.Close()
+	lenient, lts := testClient(t, &recorder{}, WithLenientNumbers())
+	defer lts.Close()
+	for idx, test := range tests {
+		if _, err := strict.Request(test.Req, false); (err == nil) != test.Strict {
+			t.Errorf("Test %v failed; expected valid %v, got %v", idx, test.Strict, err)
+		}
+		// sent as they are
+		if _, err := lenient.Request(test.Req, false); err != nil {
+			t.Errorf("Test %v failed; expected the lenient client to send, got %v", idx, err)
+		}
+	}
+}
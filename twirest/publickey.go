@@ -0,0 +1,96 @@
+package twirest
+
+import (
+	"context"
+	"encoding/pem"
+	"fmt"
+	"os"
+)
+
+// Public keys on accounts.twilio.com, used to encrypt recordings, see
+// https://www.twilio.com/docs/iam/credentialpublickey
+
+// ListPublicKeys requests a list of the public keys on the account
+type ListPublicKeys struct {
+	domain   uri `url:"accounts.twilio.com/v1"`
+	resource uri `url:"/Credentials/PublicKeys"`
+	PageSize int `url:"PageSize"` // at most 1000, default 50
+	Page     int `url:"Page"`
+}
+
+// FetchPublicKey requests a single public key
+type FetchPublicKey struct {
+	domain   uri `url:"accounts.twilio.com/v1"`
+	resource uri `url:"/Credentials/PublicKeys"`
+	Sid      string
+}
+
+// CreatePublicKey registers a new public key. PublicKey is the PEM encoded
+// RSA public key, see NewCreatePublicKey to load it from a file.
+type CreatePublicKey struct {
+	domain       uri    `url:"accounts.twilio.com/v1"`
+	resource     uri    `url:"/Credentials/PublicKeys"`
+	FriendlyName string `url:"FriendlyName"`
+	PublicKey    string `url:"PublicKey"`
+	AccountSid   string `url:"AccountSid"`
+}
+
+// UpdatePublicKey changes the friendly name of a public key
+type UpdatePublicKey struct {
+	domain       uri `url:"accounts.twilio.com/v1"`
+	resource     uri `url:"/Credentials/PublicKeys"`
+	Sid          string
+	FriendlyName string `url:"FriendlyName"`
+}
+
+// DeletePublicKey removes a public key
+type DeletePublicKey struct {
+	domain   uri `url:"accounts.twilio.com/v1"`
+	resource uri `url:"/Credentials/PublicKeys"`
+	Sid      string
+}
+
+type PublicKeysResponse struct {
+	Meta        Meta                `json:"meta"`
+	Credentials []PublicKeyResponse `json:"credentials"`
+}
+
+type PublicKeyResponse struct {
+	Sid          string `json:"sid"`
+	AccountSid   string `json:"account_sid"`
+	FriendlyName string `json:"friendly_name"`
+	DateCreated  string `json:"date_created"`
+	DateUpdated  string `json:"date_updated"`
+	Url          string `json:"url"`
+}
+
+// NewCreatePublicKey reads the PEM encoded public key in file and returns the
+// request registering it under friendlyName
+func NewCreatePublicKey(friendlyName, file string) (CreatePublicKey, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return CreatePublicKey{}, err
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != "PUBLIC KEY" {
+		return CreatePublicKey{}, fmt.Errorf("non valid PEM public key: '%s'", file)
+	}
+
+	return CreatePublicKey{
+		FriendlyName: friendlyName,
+		PublicKey:    string(pem.EncodeToMemory(block)),
+	}, nil
+}
+
+// RegisterPublicKey loads the PEM encoded public key in file and registers it
+// under friendlyName. The Sid of the key, to reference in the recording
+// settings, is in the PublicKey field of the response.
+func (twiClient *TwilioClient) RegisterPublicKey(ctx context.Context,
+	friendlyName, file string) (TwilioResponse, error) {
+	req, err := NewCreatePublicKey(friendlyName, file)
+	if err != nil {
+		return TwilioResponse{}, err
+	}
+	return twiClient.RequestWithContext(ctx, req, false)
+}
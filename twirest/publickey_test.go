@@ -0,0 +1,96 @@
+package twirest
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testPublicKey = `-----BEGIN PUBLIC KEY-----
+MFwwDQYJKoZIhvcNAQEBBQADSwAwSAJBAKj34GkxFhD90vcNLYLInFEX6Ppy1tPf
+9Cnzj4p4WGeKLs1Pt8QuKUpRKfFLfRYC9AIKjbJTWit+CqvjWYzvQwECAwEAAQ==
+-----END PUBLIC KEY-----
+`
+
+func TestPublicKeyUrls(t *testing.T) {
+	tests := []struct {
+		Req    interface{}
+		Expect string
+	}{
+		{ListPublicKeys{},
+			"https://accounts.twilio.com/v1/Credentials/PublicKeys"},
+		{FetchPublicKey{Sid: "CR123"},
+			"https://accounts.twilio.com/v1/Credentials/PublicKeys/CR123"},
+		{CreatePublicKey{FriendlyName: "rec"},
+			"https://accounts.twilio.com/v1/Credentials/PublicKeys"},
+		{UpdatePublicKey{Sid: "CR123", FriendlyName: "rec"},
+			"https://accounts.twilio.com/v1/Credentials/PublicKeys/CR123"},
+		{DeletePublicKey{Sid: "CR123"},
+			"https://accounts.twilio.com/v1/Credentials/PublicKeys/CR123"},
+	}
+
+	for idx, test := range tests {
+		got, err := urlString(test.Req, "AC123")
+		if err != nil {
+			t.Errorf("Test %v failed: %v", idx, err)
+		}
+		if got != test.Expect {
+			t.Errorf("Test %v failed; expected %#v, got %#v", idx, test.Expect, got)
+		}
+	}
+}
+
+func TestNewCreatePublicKey(t *testing.T) {
+	dir := t.TempDir()
+	good := filepath.Join(dir, "key.pem")
+	bad := filepath.Join(dir, "bad.pem")
+	os.WriteFile(good, []byte(testPublicKey), 0600)
+	os.WriteFile(bad, []byte("not a key"), 0600)
+
+	req, err := NewCreatePublicKey("rec", good)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if req.FriendlyName != "rec" || req.PublicKey != testPublicKey {
+		t.Errorf("unexpected request %#v", req)
+	}
+
+	if _, err := NewCreatePublicKey("rec", bad); err == nil {
+		t.Errorf("expected error for non PEM file")
+	}
+	if _, err := NewCreatePublicKey("rec", filepath.Join(dir, "none.pem")); err == nil {
+		t.Errorf("expected error for missing file")
+	}
+}
+
+func TestRegisterPublicKey(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "key.pem")
+	os.WriteFile(file, []byte(testPublicKey), 0600)
+
+	var body string
+	client, ts := testClient(t, http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != "POST" || r.URL.Path != "/v1/Credentials/PublicKeys" {
+				t.Errorf("unexpected request %v %v", r.Method, r.URL.Path)
+			}
+			r.ParseForm()
+			body = r.PostForm.Get("PublicKey")
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"sid": "CR123", "friendly_name": "rec"}`))
+		}))
+	defer ts.Close()
+
+	resp, err := client.RegisterPublicKey(context.Background(), "rec", file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.PublicKey == nil || resp.PublicKey.Sid != "CR123" {
+		t.Errorf("expected public key CR123, got %#v", resp.PublicKey)
+	}
+	if !strings.HasPrefix(body, "-----BEGIN PUBLIC KEY-----") {
+		t.Errorf("expected PEM key in body, got %#v", body)
+	}
+}
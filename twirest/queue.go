@@ -0,0 +1,81 @@
+package twirest
+
+import (
+	"fmt"
+	"time"
+)
+
+// QueueInfo is the state of a queue a wait estimate is made from
+type QueueInfo struct {
+	Sid             string
+	FriendlyName    string
+	CurrentSize     int
+	MaxSize         int
+	AverageWaitTime time.Duration
+}
+
+// QueueWaitStrategy estimates the wait of a caller entering the queue
+type QueueWaitStrategy func(info QueueInfo) time.Duration
+
+// DefaultQueueWait estimates the wait from the average wait of the calls in
+// the queue. Waiting calls are on average halfway through their wait, so with
+// calls ahead the estimate is twice their average wait. An empty queue gives
+// the average wait itself.
+func DefaultQueueWait(info QueueInfo) time.Duration {
+	if info.CurrentSize > 0 {
+		return 2 * info.AverageWaitTime
+	}
+	return info.AverageWaitTime
+}
+
+// queueEstimate configures EstimateQueueWait
+type queueEstimate struct {
+	strategy QueueWaitStrategy
+	fallback time.Duration
+}
+
+// WithQueueWaitEstimate sets the strategy of EstimateQueueWait, nil keeps
+// DefaultQueueWait, and the estimate returned for queues without an average
+// wait time yet
+func WithQueueWaitEstimate(strategy QueueWaitStrategy, fallback time.Duration) ClientOption {
+	return ClientOption{apply: func(c *TwilioClient) {
+		if strategy == nil {
+			strategy = DefaultQueueWait
+		}
+		c.queueEstimate = queueEstimate{strategy: strategy, fallback: fallback}
+	}}
+}
+
+// EstimateQueueWait fetches the queue and estimates the wait of a caller
+// entering it. When the queue has no average wait time yet, because it's new
+// or no call waited in it, the fallback set WithQueueWaitEstimate is returned
+// instead of announcing no wait.
+func (twiClient *TwilioClient) EstimateQueueWait(queueSid string) (
+	time.Duration, QueueInfo, error) {
+
+	resp, err := twiClient.Request(Queue{Sid: queueSid}, false)
+	if err != nil {
+		return 0, QueueInfo{}, err
+	}
+	if resp.Queue == nil {
+		return 0, QueueInfo{}, fmt.Errorf("no queue in response: '%s'", queueSid)
+	}
+
+	q := resp.Queue
+	info := QueueInfo{
+		Sid:             q.Sid,
+		FriendlyName:    q.FriendlyName,
+		CurrentSize:     q.CurrentSize,
+		MaxSize:         q.MaxSize,
+		AverageWaitTime: time.Duration(q.AverageWaitTime) * time.Second,
+	}
+	if info.AverageWaitTime == 0 {
+		return twiClient.queueEstimate.fallback, info, nil
+	}
+
+	strategy := twiClient.queueEstimate.strategy
+	if strategy == nil {
+		strategy = DefaultQueueWait
+	}
+	return strategy(info), info, nil
+}
@@ -0,0 +1,54 @@
+package twirest
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestEstimateQueueWait(t *testing.T) {
+	erlang := func(info QueueInfo) time.Duration {
+		return time.Duration(info.CurrentSize) * time.Minute
+	}
+
+	var tests = []struct {
+		CurrentSize int
+		AverageWait int
+		Opts        []ClientOption
+		Expect      time.Duration
+	}{
+		{3, 90, nil, 3 * time.Minute},
+		{0, 90, nil, 90 * time.Second},
+		{0, 0, nil, 0},
+		{2, 0, []ClientOption{WithQueueWaitEstimate(nil, 2*time.Minute)}, 2 * time.Minute},
+		{4, 30, []ClientOption{WithQueueWaitEstimate(erlang, time.Minute)}, 4 * time.Minute},
+		{4, 30, []ClientOption{WithQueueWaitEstimate(nil, time.Minute)}, time.Minute},
+	}
+
+	for idx, test := range tests {
+		client, ts := testClient(t, http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/2010-04-01/Accounts/AC123/Queues/QU123" {
+					t.Errorf("unexpected path %v", r.URL.Path)
+				}
+				fmt.Fprintf(w, xmlHeader+`<TwilioResponse><Queue><Sid>QU123</Sid>`+
+					`<FriendlyName>support</FriendlyName><CurrentSize>%d</CurrentSize>`+
+					`<MaxSize>100</MaxSize><AverageWaitTime>%d</AverageWaitTime>`+
+					`</Queue></TwilioResponse>`, test.CurrentSize, test.AverageWait)
+			}), test.Opts...)
+
+		wait, info, err := client.EstimateQueueWait("QU123")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if wait != test.Expect {
+			t.Errorf("Test %v failed; expected %v, got %v", idx, test.Expect, wait)
+		}
+		if info.CurrentSize != test.CurrentSize || info.MaxSize != 100 ||
+			info.AverageWaitTime != time.Duration(test.AverageWait)*time.Second {
+			t.Errorf("Test %v failed; unexpected info %#v", idx, info)
+		}
+		ts.Close()
+	}
+}
@@ -0,0 +1,133 @@
+package twirest
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RateLimit is what twilio tells of its rate limits in the headers of a
+// response, mostly of a 429 Too Many Requests. Fields are zero when twilio
+// doesn't send their header.
+type RateLimit struct {
+	Limit      int           // X-RateLimit-Limit, requests per window
+	Remaining  int           // X-RateLimit-Remaining, requests left in the window
+	Reset      time.Time     // X-RateLimit-Reset, when the window starts over
+	RetryAfter time.Duration // Retry-After, the wait before trying again
+	// Concurrent is the Twilio-Concurrent-Requests, the requests of the
+	// account in flight
+	Concurrent int
+}
+
+// parseRateLimit returns the rate limit of the headers h of a response
+// received at now. A Reset that isn't a unix time is seconds from now.
+func parseRateLimit(h http.Header, now time.Time) RateLimit {
+	rl := RateLimit{
+		Limit:      headerInt(h, "X-RateLimit-Limit"),
+		Remaining:  headerInt(h, "X-RateLimit-Remaining"),
+		Concurrent: headerInt(h, "Twilio-Concurrent-Requests"),
+	}
+	rl.RetryAfter, _ = retryAfter(h, now)
+	if reset := headerInt(h, "X-RateLimit-Reset"); reset > 1e9 {
+		rl.Reset = time.Unix(int64(reset), 0)
+	} else if reset > 0 {
+		rl.Reset = now.Add(time.Duration(reset) * time.Second)
+	}
+	return rl
+}
+
+// retryAfter returns the wait of the Retry-After header of h, seconds or a
+// date, and whether it is set
+func retryAfter(h http.Header, now time.Time) (time.Duration, bool) {
+	v := h.Get("Retry-After")
+	if secs, err := strconv.Atoi(v); err == nil && secs >= 0 {
+		return time.Duration(secs) * time.Second, true
+	}
+	if at, err := http.ParseTime(v); err == nil {
+		if d := at.Sub(now); d > 0 {
+			return d, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
+
+// headerInt returns the integer value of the header name, 0 if it isn't one
+func headerInt(h http.Header, name string) int {
+	n, _ := strconv.Atoi(h.Get(name))
+	return n
+}
+
+// WithRateLimit throttles the requests of the client to rps per second on
+// average, letting bursts of up to burst requests through at once. Requests
+// wait for their turn before being sent, or until their context is canceled.
+// A rps of zero or less doesn't throttle.
+func WithRateLimit(rps float64, burst int) ClientOption {
+	return ClientOption{apply: func(c *TwilioClient) {
+		c.limiter = newRateLimiter(rps, burst, clientClock{c})
+	}}
+}
+
+// rateLimiter is a token bucket, a nil *rateLimiter doesn't throttle
+type rateLimiter struct {
+	rps    float64
+	burst  float64
+	clock  Clock
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+// newRateLimiter returns a full bucket, or nil if rps doesn't throttle
+func newRateLimiter(rps float64, burst int, clock Clock) *rateLimiter {
+	if rps <= 0 {
+		return nil
+	}
+	if burst < 1 {
+		burst = 1
+	}
+	return &rateLimiter{
+		rps:    rps,
+		burst:  float64(burst),
+		clock:  clock,
+		tokens: float64(burst),
+	}
+}
+
+// wait takes a token, waiting until one is available or ctx is canceled
+func (l *rateLimiter) wait(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+
+	l.mu.Lock()
+	now := l.clock.Now()
+	if l.last.IsZero() {
+		l.last = now
+	}
+	l.tokens += now.Sub(l.last).Seconds() * l.rps
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+	l.last = now
+	// taking the token before it is there reserves it, later callers
+	// queue up behind
+	l.tokens--
+	wait := time.Duration(-l.tokens / l.rps * float64(time.Second))
+	l.mu.Unlock()
+
+	if wait <= 0 {
+		return nil
+	}
+	select {
+	case <-l.clock.After(wait):
+		return nil
+	case <-ctx.Done():
+		l.mu.Lock()
+		l.tokens++
+		l.mu.Unlock()
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,119 @@
+package twirest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestRateLimiter(t *testing.T) {
+	clock := newFakeClock()
+	start := clock.Now()
+	l := newRateLimiter(2, 3, clock)
+
+	// the burst goes through at once, then a request every half second
+	var tests = []time.Duration{0, 0, 0, 500 * time.Millisecond, time.Second, 1500 * time.Millisecond}
+	for idx, expect := range tests {
+		if err := l.wait(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		if got := clock.Now().Sub(start); got != expect {
+			t.Errorf("Test %v failed; expected to be sent at %v, got %v", idx, expect, got)
+		}
+	}
+
+	if newRateLimiter(0, 10, clock) != nil {
+		t.Errorf("expected no limiter for a rate of 0")
+	}
+	var none *rateLimiter
+	if err := none.wait(context.Background()); err != nil {
+		t.Errorf("expected a nil limiter not to throttle, got %v", err)
+	}
+}
+
+func TestRateLimiterCanceled(t *testing.T) {
+	l := newRateLimiter(1, 1, realClock{})
+	l.wait(context.Background())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := l.wait(ctx); err != context.DeadlineExceeded {
+		t.Errorf("expected %v, got %v", context.DeadlineExceeded, err)
+	}
+	// the canceled request gave its token back
+	if l.tokens > 0.1 || l.tokens < -0.1 {
+		t.Errorf("expected no tokens left, got %v", l.tokens)
+	}
+}
+
+func TestWithRateLimit(t *testing.T) {
+	srv := &flakyServer{}
+	clock := newFakeClock()
+	client, ts := testClient(t, srv, WithRateLimit(500, 1), WithClock(clock))
+	defer ts.Close()
+
+	start := clock.Now()
+	for i := 0; i < 10; i++ {
+		client.Request(Messages{}, false)
+	}
+
+	if len(srv.bodies) != 10 {
+		t.Errorf("expected 10 requests, got %v", len(srv.bodies))
+	}
+	if elapsed := clock.Now().Sub(start); elapsed != 18*time.Millisecond {
+		t.Errorf("expected 10 requests at 500/s to take 18ms, took %v", elapsed)
+	}
+}
+
+func TestParseRateLimit(t *testing.T) {
+	now := time.Date(2026, 10, 13, 9, 30, 0, 0, time.UTC)
+	var tests = []struct {
+		Headers map[string]string
+		Expect  RateLimit
+	}{
+		{map[string]string{}, RateLimit{}},
+		{map[string]string{"X-RateLimit-Limit": "100", "X-RateLimit-Remaining": "0",
+			"X-RateLimit-Reset": "30", "Retry-After": "5"},
+			RateLimit{Limit: 100, Remaining: 0, Reset: now.Add(30 * time.Second),
+				RetryAfter: 5 * time.Second}},
+		{map[string]string{"X-RateLimit-Reset": "1791883860",
+			"Retry-After": "Tue, 13 Oct 2026 09:31:00 GMT", "Twilio-Concurrent-Requests": "12"},
+			RateLimit{Reset: time.Unix(1791883860, 0), RetryAfter: time.Minute, Concurrent: 12}},
+		{map[string]string{"X-RateLimit-Limit": "many", "Retry-After": "soon"}, RateLimit{}},
+		{map[string]string{"Retry-After": "Tue, 13 Oct 2026 09:29:00 GMT"}, RateLimit{}},
+	}
+
+	for idx, test := range tests {
+		h := http.Header{}
+		for k, v := range test.Headers {
+			h.Set(k, v)
+		}
+		if rl := parseRateLimit(h, now); !reflect.DeepEqual(rl, test.Expect) {
+			t.Errorf("Test %v failed; expected %+v, got %+v", idx, test.Expect, rl)
+		}
+	}
+}
+
+func TestRateLimitHeaders(t *testing.T) {
+	client, ts := testClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Limit", "10")
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("Retry-After", "2")
+		w.WriteHeader(http.StatusTooManyRequests)
+		w.Write([]byte(xmlHeader + `<TwilioResponse><RestException><Code>20429</Code>` +
+			`<Status>429</Status></RestException></TwilioResponse>`))
+	}))
+	defer ts.Close()
+
+	resp, err := client.Request(Queues{}, false)
+	if !errors.Is(err, ErrTooManyRequests) {
+		t.Errorf("expected too many requests, got %v", err)
+	}
+	expect := RateLimit{Limit: 10, RetryAfter: 2 * time.Second}
+	if resp.RateLimit != expect {
+		t.Errorf("expected %+v, got %+v", expect, resp.RateLimit)
+	}
+}
@@ -0,0 +1,67 @@
+package twirest
+
+import (
+	"fmt"
+	"strings"
+)
+
+// WithRegion sends the requests to the twilio region, such as ie1, so the
+// data is processed there. The hosts become {product}.{region}.twilio.com,
+// the region's own credentials must be used.
+// (see: https://www.twilio.com/docs/global-infrastructure)
+func WithRegion(region string) ClientOption {
+	return ClientOption{apply: func(c *TwilioClient) {
+		if !validLocality(region) {
+			c.optErr = fmt.Errorf("non valid region: '%s'", region)
+			return
+		}
+		c.region = region
+	}}
+}
+
+// WithEdge sends the requests through the twilio edge location, such as
+// dublin, the hosts become {product}.{edge}.{region}.twilio.com. Without a
+// region the edge connects to us1.
+func WithEdge(edge string) ClientOption {
+	return ClientOption{apply: func(c *TwilioClient) {
+		if !validLocality(edge) {
+			c.optErr = fmt.Errorf("non valid edge: '%s'", edge)
+			return
+		}
+		c.edge = edge
+	}}
+}
+
+// validLocality checks that s is a host label of lower case letters, digits
+// and dashes
+func validLocality(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, c := range s {
+		if !(c >= 'a' && c <= 'z' || c >= '0' && c <= '9' || c == '-') {
+			return false
+		}
+	}
+	return true
+}
+
+// regionalHost returns host, a {product}.twilio.com host, in the edge and
+// region. Other hosts and hosts with a locality already are returned as is.
+func regionalHost(host, edge, region string) string {
+	if edge == "" && region == "" {
+		return host
+	}
+	product := strings.TrimSuffix(host, ".twilio.com")
+	if product == host || product == "" || strings.Contains(product, ".") {
+		return host
+	}
+	if edge != "" && region == "" {
+		region = "us1"
+	}
+	parts := []string{product}
+	if edge != "" {
+		parts = append(parts, edge)
+	}
+	return strings.Join(append(parts, region, "twilio.com"), ".")
+}
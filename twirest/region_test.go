@@ -0,0 +1,83 @@
+package twirest
+
+import (
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// urlTransport records the urls of the requests and responds with an empty
+// TwilioResponse
+type urlTransport struct {
+	urls []string
+}
+
+func (t *urlTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	t.urls = append(t.urls, r.URL.String())
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{},
+		Body:       ioutil.NopCloser(strings.NewReader("<TwilioResponse></TwilioResponse>")),
+		Request:    r,
+	}, nil
+}
+
+func TestRegionalHost(t *testing.T) {
+	var tests = []struct {
+		Host   string
+		Edge   string
+		Region string
+		Expect string
+	}{
+		{"api.twilio.com", "", "", "api.twilio.com"},
+		{"api.twilio.com", "", "ie1", "api.ie1.twilio.com"},
+		{"api.twilio.com", "dublin", "ie1", "api.dublin.ie1.twilio.com"},
+		{"api.twilio.com", "sydney", "", "api.sydney.us1.twilio.com"},
+		{"monitor.twilio.com", "dublin", "ie1", "monitor.dublin.ie1.twilio.com"},
+		{"api.dublin.ie1.twilio.com", "tokyo", "jp1", "api.dublin.ie1.twilio.com"},
+		{"example.com", "dublin", "ie1", "example.com"},
+	}
+
+	for idx, test := range tests {
+		if got := regionalHost(test.Host, test.Edge, test.Region); got != test.Expect {
+			t.Errorf("Test %v failed; expected %v, got %v", idx, test.Expect, got)
+		}
+	}
+}
+
+func TestWithRegion(t *testing.T) {
+	const path = "/2010-04-01/Accounts/AC123/Queues/QU1"
+	var tests = []struct {
+		Opts   []ClientOption
+		Expect string
+	}{
+		{nil, "https://api.twilio.com" + path},
+		{[]ClientOption{WithRegion("ie1")}, "https://api.ie1.twilio.com" + path},
+		{[]ClientOption{WithRegion("ie1"), WithEdge("dublin")},
+			"https://api.dublin.ie1.twilio.com" + path},
+	}
+
+	for idx, test := range tests {
+		rt := &urlTransport{}
+		opts := append([]ClientOption{WithHTTPClient(&http.Client{Transport: rt})},
+			test.Opts...)
+		client, err := NewClient("AC123", "token", opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := client.Request(Queue{Sid: "QU1"}, false); err != nil {
+			t.Errorf("Test %v failed; %v", idx, err)
+		}
+		if len(rt.urls) != 1 || rt.urls[0] != test.Expect {
+			t.Errorf("Test %v failed; expected %v, got %v", idx, test.Expect, rt.urls)
+		}
+	}
+
+	for _, opt := range []ClientOption{WithRegion(""), WithEdge("Dublin"),
+		WithRegion("ie1.evil.com")} {
+		if _, err := NewClient("AC123", "token", opt); err == nil {
+			t.Errorf("expected an error for a non valid locality")
+		}
+	}
+}
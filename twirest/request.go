@@ -1,349 +1,582 @@
 package twirest
 
+import (
+	"time"
+
+	"github.com/seanhagen/twilio/twiml"
+)
+
 // uri URI resource
-// Used for the request resource, NOTE: only the tag is used
+// Used for the request resource, NOTE: only the tag is used. A resource tag
+// is under the account of the client, a path tag is the absolute path of
+// resources that aren't, such as the accounts themselves.
+//
+// Fields that pick or extend the path of the resource, such as Call's
+// Recordings, are tagged url:"-" and never sent as parameters. The Sid and
+// CallSid fields are untagged path fields.
 type uri struct {
 }
 
+// Bool is a boolean request parameter. The zero value leaves the parameter
+// out of the request so twilio's default applies.
+type Bool string
+
+const (
+	TwiTrue  Bool = "true"
+	TwiFalse Bool = "false"
+)
+
+// BoolOf returns the Bool of b
+func BoolOf(b bool) Bool {
+	if b {
+		return TwiTrue
+	}
+	return TwiFalse
+}
+
 // IncomingPhoneNumberList is for checking to see what phone numbers are currently associated with
 // the account (see: https://www.twilio.com/docs/api/rest/incoming-phone-numbers#list-get)
 type IncomingPhoneNumberList struct {
-	resource     uri    `/IncomingPhoneNumbers`
-	PhoneNumber  string `PhoneNumber=`
-	FriendlyName string `FriendlyName=`
+	resource     uri    `url:"/IncomingPhoneNumbers"`
+	PhoneNumber  string `url:"PhoneNumber"`
+	FriendlyName string `url:"FriendlyName"`
+	PageSize     int    `url:"PageSize"` // at most 1000, default 50
+	Page         int    `url:"Page"`
 }
 
 // CreateIncomingPhoneNumber is how to purchase a phone number in Twilio. Important: ONLY ONE of the two
 // fields should be set (see: https://www.twilio.com/docs/api/rest/incoming-phone-numbers#list-post)
 type CreateIncomingPhoneNumber struct {
-	resource             uri    `/IncomingPhoneNumbers`
-	PhoneNumber          string `PhoneNumber=`
-	AreaCode             string `AreaCode=`
-	FriendlyName         string `FriendlyName=`
-	VoiceURL             string `VoiceUrl=`
-	VoiceMethod          string `VoiceMethod=`
-	VoiceFallbackURL     string `VoiceFallbackUrl=`
-	VoiceFallbackMethod  string `VoiceFallbackMethod=`
-	StatusCallback       string `StatusCallback=`
-	StatusCallbackMethod string `StatusCallbackMethod=`
-	VoiceCallerIDLookup  string `VoiceCallerIdLookup=`
-	VoiceApplicationSid  string `VoiceApplicationSid=`
-	TrunkSid             string `TrunkSid=`
-	SMSUrl               string `SmsUrl=`
-	SMSMethod            string `SmsMethod=`
-	SMSFallbackURL       string `SmsFallbackUrl=`
-	SMSFallbackMethod    string `SmsFallbackMethod=`
-	SMSApplicationSid    string `SmsApplicationSid=`
+	resource             uri    `url:"/IncomingPhoneNumbers"`
+	PhoneNumber          string `url:"PhoneNumber"`
+	AreaCode             string `url:"AreaCode"`
+	FriendlyName         string `url:"FriendlyName"`
+	VoiceURL             string `url:"VoiceUrl"`
+	VoiceMethod          string `url:"VoiceMethod"`
+	VoiceFallbackURL     string `url:"VoiceFallbackUrl"`
+	VoiceFallbackMethod  string `url:"VoiceFallbackMethod"`
+	StatusCallback       string `url:"StatusCallback"`
+	StatusCallbackMethod string `url:"StatusCallbackMethod"`
+	VoiceCallerIDLookup  string `url:"VoiceCallerIdLookup"`
+	VoiceApplicationSid  string `url:"VoiceApplicationSid"`
+	TrunkSid             string `url:"TrunkSid"`
+	SMSUrl               string `url:"SmsUrl"`
+	SMSMethod            string `url:"SmsMethod"`
+	SMSFallbackURL       string `url:"SmsFallbackUrl"`
+	SMSFallbackMethod    string `url:"SmsFallbackMethod"`
+	SMSApplicationSid    string `url:"SmsApplicationSid"`
+}
+
+// UpdateIncomingPhoneNumber changes the configuration of a phone number,
+// setting AccountSid moves the number to another (sub)account
+// (see: https://www.twilio.com/docs/api/rest/incoming-phone-numbers#instance-post)
+type UpdateIncomingPhoneNumber struct {
+	resource             uri `url:"/IncomingPhoneNumbers"`
+	Sid                  string
+	AccountSid           string `url:"AccountSid"`
+	FriendlyName         string `url:"FriendlyName"`
+	VoiceURL             string `url:"VoiceUrl"`
+	VoiceMethod          string `url:"VoiceMethod"`
+	VoiceFallbackURL     string `url:"VoiceFallbackUrl"`
+	VoiceFallbackMethod  string `url:"VoiceFallbackMethod"`
+	StatusCallback       string `url:"StatusCallback"`
+	StatusCallbackMethod string `url:"StatusCallbackMethod"`
+	VoiceCallerIDLookup  string `url:"VoiceCallerIdLookup"`
+	VoiceApplicationSid  string `url:"VoiceApplicationSid"`
+	TrunkSid             string `url:"TrunkSid"`
+	SMSUrl               string `url:"SmsUrl"`
+	SMSMethod            string `url:"SmsMethod"`
+	SMSFallbackURL       string `url:"SmsFallbackUrl"`
+	SMSFallbackMethod    string `url:"SmsFallbackMethod"`
+	SMSApplicationSid    string `url:"SmsApplicationSid"`
+}
+
+// DeleteIncomingPhoneNumber releases a phone number from the account
+type DeleteIncomingPhoneNumber struct {
+	resource uri    `url:"/IncomingPhoneNumbers"`
+	Sid      string // IncomingPhoneNumberSid
 }
 
 // AvailablePhoneNumbers is a list of currently available phone numbers for a country
 type AvailablePhoneNumbers struct {
-	resource                      uri    `/AvailablePhoneNumbers`
-	CountryCode                   string // Such as 'CA' for Canada, 'US' for the United States, etc
-	Type                          string // Can be 'Local', 'TollFree', or 'Mobile'
-	AreaCode                      string `AreaCode=`
-	Contains                      string `Contains=`
-	SmsEnabled                    string `SmsEnabled=`
-	MmsEnabled                    string `MmsEnabled=`
-	VoiceEnabled                  string `VoiceEnabled=`
-	FaxEnabled                    string `FaxEnabled=`
-	ExcludeAllAddressRequired     string `ExcludeAllAddressRequired=`
-	ExcludeLocalAddressRequired   string `ExcludeLocalAddressRequired=`
-	ExcludeForeignAddressRequired string `ExcludeForeignAddressRequired=`
-	Beta                          string `Beta=`
+	resource                      uri    `url:"/AvailablePhoneNumbers"`
+	CountryCode                   string `url:"-"` // Such as 'CA' for Canada, 'US' for the United States, etc
+	Type                          string `url:"-"` // Can be 'Local', 'TollFree', or 'Mobile'
+	AreaCode                      string `url:"AreaCode"`
+	Contains                      string `url:"Contains"`
+	SmsEnabled                    string `url:"SmsEnabled"`
+	MmsEnabled                    string `url:"MmsEnabled"`
+	VoiceEnabled                  string `url:"VoiceEnabled"`
+	FaxEnabled                    string `url:"FaxEnabled"`
+	ExcludeAllAddressRequired     string `url:"ExcludeAllAddressRequired"`
+	ExcludeLocalAddressRequired   string `url:"ExcludeLocalAddressRequired"`
+	ExcludeForeignAddressRequired string `url:"ExcludeForeignAddressRequired"`
+	Beta                          string `url:"Beta"`
+	PageSize                      int    `url:"PageSize"` // at most 1000, default 50
+	Page                          int    `url:"Page"`
 }
 
 // Request a list of the account resources
 type Accounts struct {
-	FriendlyName string `FriendlyName=`
-	Status       string `Status=`
+	path         uri    `url:"/Accounts"`
+	FriendlyName string `url:"FriendlyName"`
+	Status       string `url:"Status"`
+	PageSize     int    `url:"PageSize"` // at most 1000, default 50
+	Page         int    `url:"Page"`
 }
 
 // Account resource information for a single account
 type Account struct {
-	Sid string
+	path uri `url:"/Accounts"`
+	Sid  string
+}
+
+// UpdateAccount changes the name or status of an account. Setting Status to
+// closed is permanent and releases the phone numbers of the account, see
+// CloseSubaccount.
+type UpdateAccount struct {
+	path         uri `url:"/Accounts"`
+	Sid          string
+	FriendlyName string `url:"FriendlyName"`
+	Status       string `url:"Status"`
 }
 
 // Calls - Request list of calls made to and from account
 type Calls struct {
-	resource        uri    `/Calls`
-	To              string `To=`
-	From            string `From=`
-	Status          string `Status=`
-	StartTime       string `StartTime=`
-	StartTimeBefore string `StartTime<=`
-	StartTimeAfter  string `StartTime>=`
-	ParentCallSid   string `ParentCallSid=`
+	resource        uri    `url:"/Calls"`
+	To              string `url:"To"`
+	From            string `url:"From"`
+	Status          string `url:"Status"`
+	StartTime       string `url:"StartTime"`
+	StartTimeBefore string `url:"StartTime<"` // see StartTimeOnOrBefore
+	StartTimeAfter  string `url:"StartTime>"` // see StartTimeOnOrAfter
+	ParentCallSid   string `url:"ParentCallSid"`
+	// DurationOver and DurationUnder filter on the seconds calls lasted, nil
+	// isn't set, so DurationOver of 0 leaves out the calls not answered
+	DurationOver  *int `url:"Duration>"`
+	DurationUnder *int `url:"Duration<"`
+	// StartTimeOnOrAfter and StartTimeOnOrBefore replace StartTimeAfter and
+	// StartTimeBefore, only one of each may be set. The day given is included.
+	StartTimeOnOrAfter  time.Time `url:"StartTime>,date"`
+	StartTimeOnOrBefore time.Time `url:"StartTime<,date"`
+	PageSize            int       `url:"PageSize"` // at most 1000, default 50
+	Page                int       `url:"Page"`
 }
 
 // Call - Request call information about a single call
 type Call struct {
-	resource      uri    `/Calls`
+	resource      uri    `url:"/Calls"`
 	Sid           string // CallSid
-	Recordings    bool
-	Notifications bool
+	Recordings    bool   `url:"-"`
+	Notifications bool   `url:"-"`
 }
 
 // MakeCall - Request to make a phone call
 type MakeCall struct {
-	resource                uri      `/Calls`
-	From                    string   `From=`
-	To                      string   `To=`
-	Url                     string   `Url=`
-	ApplicationSid          string   `ApplicationSid=`
-	Method                  string   `Method=`
-	FallbackUrl             string   `FallbackUrl=`
-	FallbackMethod          string   `FallbackMethod=`
-	StatusCallback          string   `StatusCallback=`
-	StatusCallbackEvents    []string `StatusCallbackEvent=`
-	StatusCallbackMethod    string   `StatusCallbackMethod=`
-	SendDigits              string   `SendDigits=`
-	MachineDetection        string   `MachineDetection=`
-	MachineDetectionTimeout string   `MachineDetectionTimeout=`
-	Timeout                 string   `Timeout=`
-	Record                  string   `Record=`
-	RecordingChannels       string   `RecordingChannels=`
-	SipAuthUsername         string   `SipAuthUsername=`
-	SipAuthPassword         string   `SipAuthPassword=`
+	resource                uri              `url:"/Calls"`
+	From                    string           `url:"From"`
+	To                      string           `url:"To"`
+	Url                     string           `url:"Url"`
+	ApplicationSid          string           `url:"ApplicationSid"`
+	Method                  string           `url:"Method"`
+	FallbackUrl             string           `url:"FallbackUrl"`
+	FallbackMethod          string           `url:"FallbackMethod"`
+	StatusCallback          string           `url:"StatusCallback"`
+	StatusCallbackEvents    twiml.Events     `url:"StatusCallbackEvent"`
+	StatusCallbackMethod    string           `url:"StatusCallbackMethod"`
+	SendDigits              string           `url:"SendDigits"`
+	MachineDetection        string           `url:"MachineDetection"`
+	MachineDetectionTimeout string           `url:"MachineDetectionTimeout"`
+	Timeout                 string           `url:"Timeout"` // see TimeoutSeconds
+	Record                  string           `url:"Record"`  // see RecordCall
+	RecordingChannels       string           `url:"RecordingChannels"`
+	Trim                    twiml.TrimPolicy `url:"Trim"`
+	SipAuthUsername         string           `url:"SipAuthUsername"`
+	SipAuthPassword         string           `url:"SipAuthPassword"`
+	CallerId                string           `url:"CallerId"`
+	Byoc                    string           `url:"Byoc"`
+	// TimeoutSeconds and RecordCall replace Timeout and Record, only one of
+	// each may be set
+	TimeoutSeconds int  `url:"Timeout"`
+	RecordCall     bool `url:"Record"`
+}
+
+// CreateCallRecording starts recording a call in progress
+type CreateCallRecording struct {
+	resource                      uri              `url:"/Calls"`
+	subresource                   uri              `url:"/Recordings"`
+	Sid                           string           // CallSid
+	RecordingChannels             string           `url:"RecordingChannels"`
+	RecordingTrack                string           `url:"RecordingTrack"`
+	Trim                          twiml.TrimPolicy `url:"Trim"`
+	RecordingStatusCallback       string           `url:"RecordingStatusCallback"`
+	RecordingStatusCallbackEvents twiml.Events     `url:"RecordingStatusCallbackEvent"`
 }
 
 // Request to modify call in queue/progress
 type ModifyCall struct {
-	resource             uri `/Calls`
+	resource             uri `url:"/Calls"`
 	Sid                  string
-	Url                  string `Url=`
-	Method               string `Method=`
-	Status               string `Status=`
-	FallbackUrl          string `FallbackUrl=`
-	FallbackMethod       string `FallbackMethod=`
-	StatusCallback       string `StatusCallback=`
-	StatusCallbackMethod string `StatusCallbackMethod=`
+	Url                  string `url:"Url"`
+	Method               string `url:"Method"`
+	Status               string `url:"Status"`
+	FallbackUrl          string `url:"FallbackUrl"`
+	FallbackMethod       string `url:"FallbackMethod"`
+	StatusCallback       string `url:"StatusCallback"`
+	StatusCallbackMethod string `url:"StatusCallbackMethod"`
 }
 
 // List conferences within an account
 type Conferences struct {
-	resource          uri    `/Conferences`
-	Status            string `Status=`
-	FriendlyName      string `FriendlyName=`
-	DateCreated       string `DateCreated=`
-	DateCreatedBefore string `DateCreated<=`
-	DateCreatedAfter  string `DateCreated>=`
-	DateUpdated       string `DateUpdated=`
-	DateUpdatedBefore string `DateUpdated<=`
-	DateUpdatedAfter  string `DateUpdated>=`
+	resource          uri    `url:"/Conferences"`
+	Status            string `url:"Status"`
+	FriendlyName      string `url:"FriendlyName"`
+	DateCreated       string `url:"DateCreated"`
+	DateCreatedBefore string `url:"DateCreated<"` // see DateCreatedOnOrBefore
+	DateCreatedAfter  string `url:"DateCreated>"` // see DateCreatedOnOrAfter
+	DateUpdated       string `url:"DateUpdated"`
+	DateUpdatedBefore string `url:"DateUpdated<"`
+	DateUpdatedAfter  string `url:"DateUpdated>"`
+	// DateCreatedOnOrAfter and DateCreatedOnOrBefore replace DateCreatedAfter
+	// and DateCreatedBefore, only one of each may be set. The day given is
+	// included.
+	DateCreatedOnOrAfter  time.Time `url:"DateCreated>,date"`
+	DateCreatedOnOrBefore time.Time `url:"DateCreated<,date"`
+	PageSize              int       `url:"PageSize"` // at most 1000, default 50
+	Page                  int       `url:"Page"`
 }
 
 // Resource for individual conference instance
 type Conference struct {
-	resource uri `/Conferences`
+	resource uri `url:"/Conferences"`
 	Sid      string
 }
 
 // Request list of participants in a conference
 type Participants struct {
-	resource    uri    `/Conferences`
-	subresource uri    `/Participants`
+	resource    uri    `url:"/Conferences"`
+	subresource uri    `url:"/Participants"`
 	Sid         string // Conference Sid
-	Muted       string `Muted=`
+	Muted       string `url:"Muted"`
+	PageSize    int    `url:"PageSize"` // at most 1000, default 50
+	Page        int    `url:"Page"`
 }
 
 // Resource about single conference participant
 type Participant struct {
-	resource    uri    `/Conferences`
-	subresource uri    `/Participants`
+	resource    uri    `url:"/Conferences"`
+	subresource uri    `url:"/Participants"`
 	Sid         string // Conference Sid
 	CallSid     string // required field
 }
 
-// Remove a participant from a conference
+// Dial out to a new participant and add it to a conference. Parameters left
+// empty are not sent and twilio's defaults apply, notably a participant starts
+// the conference on enter but doesn't end it on exit.
+type CreateParticipant struct {
+	resource                       uri                    `url:"/Conferences"`
+	subresource                    uri                    `url:"/Participants"`
+	Sid                            string                 // Conference Sid
+	From                           string                 `url:"From"`
+	To                             string                 `url:"To"`
+	Label                          string                 `url:"Label"`
+	StatusCallback                 string                 `url:"StatusCallback"`
+	StatusCallbackMethod           string                 `url:"StatusCallbackMethod"`
+	StatusCallbackEvents           twiml.Events           `url:"StatusCallbackEvent"`
+	Timeout                        string                 `url:"Timeout"`
+	Record                         string                 `url:"Record"`
+	Muted                          string                 `url:"Muted"`
+	Beep                           string                 `url:"Beep"`
+	EarlyMedia                     Bool                   `url:"EarlyMedia"`
+	RingTone                       string                 `url:"RingTone"`
+	MaxParticipants                string                 `url:"MaxParticipants"`
+	StartConferenceOnEnter         Bool                   `url:"StartConferenceOnEnter"`
+	EndConferenceOnExit            Bool                   `url:"EndConferenceOnExit"`
+	WaitUrl                        string                 `url:"WaitUrl"`
+	WaitMethod                     string                 `url:"WaitMethod"`
+	ConferenceStatusCallback       string                 `url:"ConferenceStatusCallback"`
+	ConferenceStatusCallbackMethod string                 `url:"ConferenceStatusCallbackMethod"`
+	ConferenceStatusCallbackEvents twiml.Events           `url:"ConferenceStatusCallbackEvent"`
+	JitterBufferSize               twiml.JitterBufferSize `url:"JitterBufferSize"`
+	CallerId                       string                 `url:"CallerId"`
+	Byoc                           string                 `url:"Byoc"`
+	Coaching                       Bool                   `url:"Coaching"`
+	CallSidToCoach                 string                 `url:"CallSidToCoach"`
+}
+
+// Remove a participant from a conference, CallSid is the Call Sid or the
+// label of the participant
 type DeleteParticipant struct {
-	resource    uri    `/Conferences`
-	subresource uri    `/Participants`
+	resource    uri    `url:"/Conferences"`
+	subresource uri    `url:"/Participants"`
 	Sid         string // Conference Sid
 	CallSid     string // required field
 }
 
-// Request to change the status of a participant
+// Request to change the status of a participant. CallSid is the Call Sid or
+// the label of the participant.
 type UpdateParticipant struct {
-	resource    uri    `/Conferences`
-	subresource uri    `/Participants`
+	resource    uri    `url:"/Conferences"`
+	subresource uri    `url:"/Participants"`
 	Sid         string // Conference Sid
 	CallSid     string // required field
-	Muted       string `Muted=`
+	Muted       string `url:"Muted"`
+	Hold        Bool   `url:"Hold"`
+	HoldUrl     string `url:"HoldUrl"`
+	HoldMethod  string `url:"HoldMethod"`
 }
 
 // Messages struct for request of list of messages
 type Messages struct {
-	resource       uri    `/Messages`
-	To             string `To=`
-	From           string `From=`
-	DateSent       string `DateSent=`
-	DateSentBefore string `DateSent<=`
-	DateSentAfter  string `DateSent>=`
+	resource            uri    `url:"/Messages"`
+	To                  string `url:"To"`
+	From                string `url:"From"`
+	MessagingServiceSid string `url:"MessagingServiceSid"`
+	DateSent            string `url:"DateSent"`
+	DateSentBefore      string `url:"DateSent<"` // see DateSentOnOrBefore
+	DateSentAfter       string `url:"DateSent>"` // see DateSentOnOrAfter
+	PageSize            int    `url:"PageSize"`  // at most 1000, default 50
+	Page                int    `url:"Page"`
+	// DateSentOnOrAfter and DateSentOnOrBefore replace DateSentAfter and
+	// DateSentBefore, only one of each may be set. The day given is included.
+	DateSentOnOrAfter  time.Time `url:"DateSent>,date"`
+	DateSentOnOrBefore time.Time `url:"DateSent<,date"`
 }
 
 // Message struct for request of single message
 type Message struct {
-	resource uri    `/Messages`
+	resource uri    `url:"/Messages"`
 	Sid      string // MessageSid
-	Media    bool
-	MediaSid string
+	Media    bool   `url:"-"`
+	MediaSid string `url:"-"`
 }
 
 // Message struct for request to send a message
 type SendMessage struct {
-	resource            uri    `/Messages`
-	Text                string `Body=`
-	MediaUrl            string `MediaUrl=`
-	From                string `From=`
-	To                  string `To=`
-	MessagingServiceSid string `MessagingServiceSid=`
-	ApplicationSid      string `ApplicationSid=`
-	StatusCallback      string `StatusCallback=`
+	resource            uri    `url:"/Messages"`
+	Text                string `url:"Body"`
+	MediaUrl            string `url:"MediaUrl"`
+	From                string `url:"From"`
+	To                  string `url:"To"`
+	MessagingServiceSid string `url:"MessagingServiceSid"`
+	ApplicationSid      string `url:"ApplicationSid"`
+	StatusCallback      string `url:"StatusCallback"`
+	ValidityPeriod      string `url:"ValidityPeriod"` // see ValiditySeconds
+	MaxPrice            Money  `url:"MaxPrice"`       // the message fails if it costs more
+	// ValiditySeconds replaces ValidityPeriod, only one may be set, 14400 if
+	// neither is
+	ValiditySeconds int `url:"ValidityPeriod"`
+	// SendAt schedules the message, ScheduleType must be fixed and it must be
+	// sent from a MessagingServiceSid
+	SendAt       time.Time `url:"SendAt"`
+	ScheduleType string    `url:"ScheduleType"`
+	// SmartEncoded replaces unicode characters with GSM-7 ones, nil keeps
+	// the setting of the messaging service
+	SmartEncoded *bool `url:"SmartEncoded"`
 }
 
 // Notifications struct for request of a possible list of notifications
 type Notifications struct {
-	resource      uri    `/Notifications`
-	Log           string `Log=`
-	MsgDate       string `MessageDate=`
-	MsgDateBefore string `MessageDate<=`
-	MsgDateAfter  string `MessageDate>=`
+	resource      uri    `url:"/Notifications"`
+	Log           string `url:"Log"`
+	MsgDate       string `url:"MessageDate"`
+	MsgDateBefore string `url:"MessageDate<"` // see MsgDateOnOrBefore
+	MsgDateAfter  string `url:"MessageDate>"` // see MsgDateOnOrAfter
+	// MsgDateOnOrAfter and MsgDateOnOrBefore replace MsgDateAfter and
+	// MsgDateBefore, only one of each may be set. The day given is included.
+	MsgDateOnOrAfter  time.Time `url:"MessageDate>,date"`
+	MsgDateOnOrBefore time.Time `url:"MessageDate<,date"`
+	PageSize          int       `url:"PageSize"` // at most 1000, default 50
+	Page              int       `url:"Page"`
 }
 
 // Notification struct for request of a specific notification
 type Notification struct {
-	resource uri `/Notifications`
+	resource uri `url:"/Notifications"`
 	Sid      string
 }
 
 // DeleteNotification struct for removal of a notification
 type DeleteNotification struct {
-	resource uri `/Notifications`
+	resource uri `url:"/Notifications"`
 	Sid      string
 }
 
 // Get outgoing caller IDs
 type OutgoingCallerIds struct {
-	resource     uri    `/OutgoingCallerIds`
-	PhoneNumber  string `PhoneNumber=`
-	FriendlyName string `FriendlyName=`
+	resource     uri    `url:"/OutgoingCallerIds"`
+	PhoneNumber  string `url:"PhoneNumber"`
+	FriendlyName string `url:"FriendlyName"`
+	PageSize     int    `url:"PageSize"` // at most 1000, default 50
+	Page         int    `url:"Page"`
 }
 
 // Get outgoing caller ID
 type OutgoingCallerId struct {
-	resource uri `/OutgoingCallerIds`
+	resource uri `url:"/OutgoingCallerIds"`
 	Sid      string
 }
 
 type UpdateOutgoingCallerId struct {
-	resource     uri `/OutgoingCallerIds`
+	resource     uri `url:"/OutgoingCallerIds"`
 	Sid          string
-	FriendlyName string `FriendlyName=`
+	FriendlyName string `url:"FriendlyName"`
 }
 
 type DeleteOutgoingCallerId struct {
-	resource uri `/OutgoingCallerIds`
+	resource uri `url:"/OutgoingCallerIds"`
 	Sid      string
 }
 
 type AddOutgoingCallerId struct {
-	resource             uri    `/OutgoingCallerIds`
-	PhoneNumber          string `PhoneNumber=`
-	FriendlyName         string `FriendlyName=`
-	CallDelay            string `CallDelay=`
-	Extension            string `Extension=`
-	StatusCallback       string `StatusCallback=`
-	StatusCallbackMethod string `StatusCallbackMethod=`
+	resource             uri    `url:"/OutgoingCallerIds"`
+	PhoneNumber          string `url:"PhoneNumber"`
+	FriendlyName         string `url:"FriendlyName"`
+	CallDelay            string `url:"CallDelay"`
+	Extension            string `url:"Extension"`
+	StatusCallback       string `url:"StatusCallback"`
+	StatusCallbackMethod string `url:"StatusCallbackMethod"`
 }
 
 // List recordings resource
 type Recordings struct {
-	resource          uri    `/Recordings`
-	CallSid           string `CallSid=`
-	DateCreated       string `DateCreated=`
-	DateCreatedBefore string `DateCreated<=`
-	DateCreatedAfter  string `DateCreated>=`
+	resource          uri    `url:"/Recordings"`
+	CallSid           string `url:"CallSid"`
+	DateCreated       string `url:"DateCreated"`
+	DateCreatedBefore string `url:"DateCreated<"` // see DateCreatedOnOrBefore
+	DateCreatedAfter  string `url:"DateCreated>"` // see DateCreatedOnOrAfter
+	// DateCreatedOnOrAfter and DateCreatedOnOrBefore replace DateCreatedAfter
+	// and DateCreatedBefore, only one of each may be set. The day given is
+	// included.
+	DateCreatedOnOrAfter  time.Time `url:"DateCreated>,date"`
+	DateCreatedOnOrBefore time.Time `url:"DateCreated<,date"`
+	// Source such as TwiSourceRecordVerb is filtered client side, the API
+	// has no such filter, so pages can hold fewer recordings than PageSize
+	Source   string
+	PageSize int `url:"PageSize"` // at most 1000, default 50
+	Page     int `url:"Page"`
+}
+
+// Request resource for an individual recording. Set RequestedChannels to "2"
+// to download both channels of a dual channel recording, otherwise they are
+// mixed down to one.
+type Recording struct {
+	resource          uri    `url:"/Recordings"`
+	Sid               string // RecordingSid
+	GetRecording      bool   `url:"-"`
+	GetMP3            bool   `url:"-"`
+	RequestedChannels string `url:"RequestedChannels"`
 }
 
-// Request resource for an individual recording
-type Recording struct {
-	resource     uri    `/Recordings`
-	Sid          string // RecordingSid
-	GetRecording bool
-	GetMP3       bool
+// RecordingDownload returns the request downloading the media of the recording
+// announced by a recording status callback, as MP3 if mp3 is set and WAV
+// otherwise. Both channels are requested when the recording has two.
+func RecordingDownload(recordingSid string, channels int, mp3 bool) Recording {
+	req := Recording{Sid: recordingSid, GetRecording: true, GetMP3: mp3}
+	if channels == 2 {
+		req.RequestedChannels = "2"
+	}
+	return req
 }
 
 // Delete a recording
 type DeleteRecording struct {
-	resource uri    `/Recordings`
+	resource uri    `url:"/Recordings"`
 	Sid      string // RecordingSid
 }
 
+// List transcriptions within an account
+type Transcriptions struct {
+	resource uri `url:"/Transcriptions"`
+	PageSize int `url:"PageSize"` // at most 1000, default 50
+	Page     int `url:"Page"`
+}
+
+// List the transcriptions of a recording
+type RecordingTranscriptions struct {
+	resource    uri    `url:"/Recordings"`
+	subresource uri    `url:"/Transcriptions"`
+	Sid         string // RecordingSid
+	PageSize    int    `url:"PageSize"` // at most 1000, default 50
+	Page        int    `url:"Page"`
+}
+
+// Request resource for an individual transcription
+type Transcription struct {
+	resource uri    `url:"/Transcriptions"`
+	Sid      string // TranscriptionSid
+}
+
 // Request usage by the account
 type UsageRecords struct {
-	resource    uri `/Usage/Records`
-	SubResource string
-	Category    string `Category=`
-	StartDate   string `StartDate=`
-	EndDate     string `EndDate=`
+	resource    uri    `url:"/Usage/Records"`
+	SubResource string `url:"-"`
+	Category    string `url:"Category"`
+	StartDate   string `url:"StartDate"`
+	EndDate     string `url:"EndDate"`
+	PageSize    int    `url:"PageSize"` // at most 1000, default 50
+	Page        int    `url:"Page"`
 }
 
 // List queues within an account
 type Queues struct {
-	resource uri `/Queues`
+	resource uri `url:"/Queues"`
+	PageSize int `url:"PageSize"` // at most 1000, default 50
+	Page     int `url:"Page"`
 }
 
 // Get resource for an individual Queue instance
 type Queue struct {
-	resource uri    `/Queues`
+	resource uri    `url:"/Queues"`
 	Sid      string // QueueSid
 }
 
 // Create a new queue
 type CreateQueue struct {
-	resource     uri    `/Queues`
-	FriendlyName string `FriendlyName=`
-	MaxSize      string `MaxSize=`
+	resource     uri    `url:"/Queues"`
+	FriendlyName string `url:"FriendlyName"`
+	MaxSize      string `url:"MaxSize"`
 }
 
 // Request to change queue properties
 type ChangeQueue struct {
-	resource     uri `/Queues`
+	resource     uri `url:"/Queues"`
 	Sid          string
-	FriendlyName string `FriendlyName=`
-	MaxSize      string `MaxSize=`
+	FriendlyName string `url:"FriendlyName"`
+	MaxSize      string `url:"MaxSize"`
 }
 
 // Remove a queue
 type DeleteQueue struct {
-	resource uri    `/Queues`
+	resource uri    `url:"/Queues"`
 	Sid      string // QueueSid
 }
 
 // List members of a queue
 type QueueMembers struct {
-	resource    uri    `/Queues`
-	subresource uri    `/Members`
+	resource    uri    `url:"/Queues"`
+	subresource uri    `url:"/Members"`
 	Sid         string // QueueSid
+	PageSize    int    `url:"PageSize"` // at most 1000, default 50
+	Page        int    `url:"Page"`
 }
 
 // Request resource for a queue member
 type QueueMember struct {
-	resource    uri    `/Queues`
-	subresource uri    `/Members`
+	resource    uri    `url:"/Queues"`
+	subresource uri    `url:"/Members"`
 	Sid         string // QueueSid
 	CallSid     string // either this field or Front is required
-	Front       bool
+	Front       bool   `url:"-"`
 }
 
 // Remove a member from a queue and redirect the member's call to a TwiML site
 type DeQueue struct {
-	resource    uri    `/Queues`
-	subresource uri    `/Members`
+	resource    uri    `url:"/Queues"`
+	subresource uri    `url:"/Members"`
 	Sid         string // Queue Sid
 	CallSid     string // either this field or Front is required
-	Front       bool
-	Url         string `Url=`
-	Method      string `Method=`
+	Front       bool   `url:"-"`
+	Url         string `url:"Url"`
+	Method      string `url:"Method"`
 }
@@ -0,0 +1,14 @@
+package twirest
+
+import "context"
+
+// Requester makes requests of the REST API, *TwilioClient is one. Code that
+// takes a Requester rather than a *TwilioClient can be tested with the
+// FakeClient of package twiresttest, without a network or http server.
+type Requester interface {
+	Request(reqStruct interface{}, logit bool) (TwilioResponse, error)
+	RequestWithContext(ctx context.Context, reqStruct interface{}, logit bool) (
+		TwilioResponse, error)
+}
+
+var _ Requester = (*TwilioClient)(nil)
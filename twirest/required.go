@@ -0,0 +1,111 @@
+package twirest
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// requiredFields are the fields twilio requires of the request structs,
+// besides the Sid of the url every request struct with one requires. A field
+// is set when it isn't the zero value, one of alternatives separated by | is
+// required.
+var requiredFields = map[reflect.Type][]string{
+	reflect.TypeOf(SendMessage{}):               {"To", "From|MessagingServiceSid", "Text|MediaUrl"},
+	reflect.TypeOf(MakeCall{}):                  {"To", "From", "Url|ApplicationSid"},
+	reflect.TypeOf(CreateParticipant{}):         {"From", "To"},
+	reflect.TypeOf(Participant{}):               {"CallSid"},
+	reflect.TypeOf(UpdateParticipant{}):         {"CallSid"},
+	reflect.TypeOf(DeleteParticipant{}):         {"CallSid"},
+	reflect.TypeOf(QueueMember{}):               {"CallSid|Front"},
+	reflect.TypeOf(DeQueue{}):                   {"CallSid|Front"},
+	reflect.TypeOf(CreateQueue{}):               {"FriendlyName"},
+	reflect.TypeOf(CreateIncomingPhoneNumber{}): {"PhoneNumber|AreaCode"},
+	reflect.TypeOf(AddOutgoingCallerId{}):       {"PhoneNumber"},
+	reflect.TypeOf(CreatePublicKey{}):           {"PublicKey"},
+	reflect.TypeOf(UpdateDialingPermissions{}):  {"UpdateRequest"},
+}
+
+// requirement is a required field, or its alternatives, of a request struct
+type requirement struct {
+	names string // such as From|MessagingServiceSid
+	index []int
+}
+
+// requirements are the requirements of each request type, by field index
+var requirements = map[reflect.Type][]requirement{}
+
+func init() {
+	for _, req := range requestTypes {
+		t := reflect.TypeOf(req)
+		var reqs []requirement
+		if fld, ok := t.FieldByName("Sid"); ok && fld.Type.Kind() == reflect.String {
+			reqs = append(reqs, requirement{names: "Sid", index: fld.Index})
+		}
+		for _, names := range requiredFields[t] {
+			r := requirement{names: names}
+			for _, name := range strings.Split(names, "|") {
+				fld, ok := t.FieldByName(name)
+				if !ok {
+					panic("twirest: no required field " + name + " in " + t.Name())
+				}
+				r.index = append(r.index, fld.Index[0])
+			}
+			reqs = append(reqs, r)
+		}
+		if reqs != nil {
+			requirements[t] = reqs
+		}
+	}
+}
+
+// ErrMissingFields is returned when fields twilio requires of a request
+// struct aren't set, before the request is sent
+type ErrMissingFields struct {
+	Request string   // the name of the request struct type
+	Fields  []string // all the fields missing, alternatives separated by |
+}
+
+func (e *ErrMissingFields) Error() string {
+	return fmt.Sprintf("required fields missing from %s: %s", e.Request,
+		strings.Join(e.Fields, ", "))
+}
+
+// missingFields checks that the fields twilio requires of the request struct
+// are set, the error lists all the missing ones
+func missingFields(reqStruct interface{}) error {
+	t := reflect.TypeOf(reqStruct)
+	reqs, ok := requirements[t]
+	if !ok {
+		return nil
+	}
+	v := reflect.ValueOf(reqStruct)
+	var missing []string
+	for _, r := range reqs {
+		set := false
+		for _, i := range r.index {
+			if !v.Field(i).IsZero() {
+				set = true
+				break
+			}
+		}
+		if !set {
+			missing = append(missing, r.names)
+		}
+	}
+	if missing == nil {
+		return nil
+	}
+	return &ErrMissingFields{Request: t.Name(), Fields: missing}
+}
+
+// requiredField reports if the field of t is required, not one of
+// alternatives
+func requiredField(t reflect.Type, name string) bool {
+	for _, r := range requirements[t] {
+		if r.names == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,93 @@
+package twirest
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// withRequired returns req with the fields twilio requires set where they're
+// missing, for the tests of its other fields. A number suits all of them.
+func withRequired(req interface{}) interface{} {
+	v := reflect.New(reflect.TypeOf(req)).Elem()
+	v.Set(reflect.ValueOf(req))
+	for _, r := range requirements[v.Type()] {
+		set := false
+		for _, i := range r.index {
+			set = set || !v.Field(i).IsZero()
+		}
+		if fld := v.Field(r.index[0]); !set && fld.Kind() == reflect.String {
+			fld.SetString("+15005550006")
+		}
+	}
+	return v.Interface()
+}
+
+func TestMissingFields(t *testing.T) {
+	const queue = "QU0123456789abcdef0123456789abcdef"
+	var tests = []struct {
+		Req     interface{}
+		Missing []string
+	}{
+		{SendMessage{From: "+15005550006", To: "+15005550001", Text: "Hi"}, nil},
+		{SendMessage{MessagingServiceSid: "MG123", To: "+15005550001",
+			MediaUrl: "https://example.com/a.png"}, nil},
+		{SendMessage{}, []string{"To", "From|MessagingServiceSid", "Text|MediaUrl"}},
+		{SendMessage{From: "+15005550006", Text: "Hi"}, []string{"To"}},
+		{MakeCall{From: "+15005550006", To: "+15005550001", Url: "https://example.com/twiml"}, nil},
+		{MakeCall{From: "+15005550006", To: "+15005550001", ApplicationSid: "AP123"}, nil},
+		{MakeCall{To: "+15005550001"}, []string{"From", "Url|ApplicationSid"}},
+		{CreateParticipant{Sid: "CF123", From: "+15005550006", To: "+15005550001"}, nil},
+		{CreateParticipant{To: "+15005550001"}, []string{"Sid", "From"}},
+		{Participant{Sid: "CF123", CallSid: "CA123"}, nil},
+		{UpdateParticipant{Sid: "CF123"}, []string{"CallSid"}},
+		{DeleteParticipant{}, []string{"Sid", "CallSid"}},
+		{QueueMember{Sid: queue, Front: true}, nil},
+		{QueueMember{Sid: queue}, []string{"CallSid|Front"}},
+		{DeQueue{Sid: queue, CallSid: "CA123", Url: "https://example.com/twiml"}, nil},
+		{DeQueue{Url: "https://example.com/twiml"}, []string{"Sid", "CallSid|Front"}},
+		{CreateQueue{FriendlyName: "support"}, nil},
+		{CreateQueue{MaxSize: "10"}, []string{"FriendlyName"}},
+		{CreateIncomingPhoneNumber{AreaCode: "415"}, nil},
+		{CreateIncomingPhoneNumber{FriendlyName: "support"}, []string{"PhoneNumber|AreaCode"}},
+		{AddOutgoingCallerId{PhoneNumber: "+15005550006"}, nil},
+		{AddOutgoingCallerId{}, []string{"PhoneNumber"}},
+		{CreatePublicKey{PublicKey: "-----BEGIN PUBLIC KEY-----"}, nil},
+		{CreatePublicKey{FriendlyName: "key"}, []string{"PublicKey"}},
+		{UpdateDialingPermissions{UpdateRequest: "[]"}, nil},
+		{UpdateDialingPermissions{}, []string{"UpdateRequest"}},
+		{DeleteQueue{Sid: queue}, nil},
+		{DeleteQueue{}, []string{"Sid"}},
+		{Lookup{}, []string{"Sid"}},
+		{Messages{}, nil},
+	}
+
+	for idx, test := range tests {
+		err := missingFields(test.Req)
+		var missing *ErrMissingFields
+		if test.Missing == nil {
+			if err != nil {
+				t.Errorf("Test %v failed; unexpected %v", idx, err)
+			}
+			continue
+		}
+		if !errors.As(err, &missing) || !reflect.DeepEqual(missing.Fields, test.Missing) ||
+			missing.Request != reflect.TypeOf(test.Req).Name() {
+			t.Errorf("Test %v failed; expected %v missing, got %v", idx, test.Missing, err)
+		}
+	}
+}
+
+func TestMissingFieldsNotSent(t *testing.T) {
+	rec := &recorder{}
+	client, ts := testClient(t, rec, WithFromValidation(time.Hour))
+	defer ts.Close()
+
+	// nothing is sent, the From isn't looked up either
+	_, err := client.Request(SendMessage{From: "+15005550006"}, false)
+	expect := "required fields missing from SendMessage: To, Text|MediaUrl"
+	if err == nil || err.Error() != expect || len(rec.forms) != 0 {
+		t.Errorf("expected %v, got %v and %v requests", expect, err, len(rec.forms))
+	}
+}
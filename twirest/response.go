@@ -5,6 +5,7 @@ import (
 	"io"
 	"net/http"
 	"strconv"
+	"time"
 )
 
 // TwilioResponse holds one possible resource/response depending on type of
@@ -12,13 +13,19 @@ import (
 type TwilioResponse struct {
 	Accounts              *AccountsResponse              `xml:"Accounts"`
 	Account               *AccountResponse               `xml:"Account"`
+	Alerts                *AlertsResponse                `xml:"-"`
+	Alert                 *AlertResponse                 `xml:"-"`
 	AvailablePhoneNumbers *AvailablePhoneNumbersResponse `xml:"AvailablePhoneNumbers"`
+	ByocTrunks            *ByocTrunksResponse            `xml:"-"`
+	ByocTrunk             *ByocTrunkResponse             `xml:"-"`
 	Calls                 *CallsResponse                 `xml:"Calls"`
 	Call                  *CallResponse                  `xml:"Call"`
 	Conferences           *ConferencesResponse           `xml:"Conferences"`
 	Conference            *ConferenceResponse            `xml:"Conference"`
+	Events                *EventsResponse                `xml:"-"`
+	Event                 *EventResponse                 `xml:"-"`
 	Exception             *ExceptionResponse             `xml:"RestException"`
-	IncomingPhoneNumbers  *IncomingPhoneNumbersResponse  `xml:"IncomingPhoneNumbers`
+	IncomingPhoneNumbers  *IncomingPhoneNumbersResponse  `xml:"IncomingPhoneNumbers"`
 	IncomingPhoneNumber   *IncomingPhoneNumberResponse   `xml:"IncomingPhoneNumber"`
 	Messages              *MessagesResponse              `xml:"Messages"`
 	Message               *MessageResponse               `xml:"Message"`
@@ -28,26 +35,62 @@ type TwilioResponse struct {
 	OutgoingCallerId      *OutgoingCallerIdResponse      `xml:"OutgoingCallerId"`
 	Participants          *ParticipantsResponse          `xml:"Participants"`
 	Participant           *ParticipantResponse           `xml:"Participant"`
+	PublicKeys            *PublicKeysResponse            `xml:"-"`
+	PublicKey             *PublicKeyResponse             `xml:"-"`
 	Recordings            *RecordingsResponse            `xml:"Recordings"`
 	Recording             *RecordingResponse             `xml:"Recording"`
+	Transcriptions        *TranscriptionsResponse        `xml:"Transcriptions"`
+	Transcription         *TranscriptionResponse         `xml:"Transcription"`
 	Queues                *QueuesResponse                `xml:"Queues"`
 	Queue                 *QueueResponse                 `xml:"Queue"`
 	QueueMembers          *QueueMembersResponse          `xml:"QueueMembers"`
 	QueueMember           *QueueMemberResponse           `xml:"QueueMember"`
 	UsageRecords          *UsageRecordsResponse          `xml:"UsageRecords"`
 	ValidationRequest     *ValidationRequestResponse     `xml:"ValidationRequest"`
+	Sims                  *SimsResponse                  `xml:"-"`
+	Sim                   *SimResponse                   `xml:"-"`
+	SimStatusUpdate       *SimStatusUpdate               `xml:"-"`
+	SimUsageRecords       *SimUsageRecordsResponse       `xml:"-"`
+	CallSummary           *CallSummaryResponse           `xml:"-"`
+	Lookup                *LookupResponse                `xml:"-"`
+	DialingCountries      *DialingCountriesResponse      `xml:"-"`
+	DialingCountry        *DialingCountryResponse        `xml:"-"`
+	DialingUpdate         *DialingUpdateResponse         `xml:"-"`
 	RecordingAudio        *RecordingAudio
 	Status                ResponseStatus
+	// Headers are the headers of the http response. RequestID is its
+	// Twilio-Request-Id, which twilio support asks for, Date and
+	// ContentType its Date and Content-Type.
+	Headers     http.Header `xml:"-"`
+	RequestID   string      `xml:"-"`
+	Date        time.Time   `xml:"-"`
+	ContentType string      `xml:"-"`
+	RateLimit   RateLimit   `xml:"-"`
 }
 
 func (tr TwilioResponse) OK() bool {
 	return tr.Status.OK()
 }
 
+// setHeaders keeps the headers of the http response received at now, a Date
+// that doesn't parse is left zero
+func (tr *TwilioResponse) setHeaders(h http.Header, now time.Time) {
+	tr.Headers = h
+	tr.RequestID = h.Get("Twilio-Request-Id")
+	tr.ContentType = h.Get("Content-Type")
+	tr.Date, _ = http.ParseTime(h.Get("Date"))
+	tr.RateLimit = parseRateLimit(h, now)
+}
+
 // ResponseStatus is the status of the request and the API
 type ResponseStatus struct {
 	Http   int
 	Twilio int
+	// Attempts is the number of times the request was sent, more than one
+	// if it was retried
+	Attempts int
+	// Format is the format the response was asked in, see WithFormat
+	Format Format
 	//HttpStr  string
 }
 
@@ -99,6 +142,25 @@ type Page struct {
 	LastPageUri     string `xml:"lastpageuri,attr"`
 }
 
+// Meta is the paging information of JSON list responses
+type Meta struct {
+	Page            uint   `json:"page"`
+	PageSize        uint   `json:"page_size"`
+	FirstPageUrl    string `json:"first_page_url"`
+	PreviousPageUrl string `json:"previous_page_url"`
+	Url             string `json:"url"`
+	NextPageUrl     string `json:"next_page_url"`
+	Key             string `json:"key"`
+}
+
+// jsonException is the JSON form of a RestException
+type jsonException struct {
+	Code     int    `json:"code"`
+	Message  string `json:"message"`
+	MoreInfo string `json:"more_info"`
+	Status   int    `json:"status"`
+}
+
 type AccountsResponse struct {
 	Page
 	Account []AccountResponse
@@ -164,20 +226,29 @@ type CallResponse struct {
 	DateUpdated     string
 	AccountSid      string
 	To              string
+	ToFormatted     string
 	From            string
+	FromFormatted   string
 	PhoneNumberSid  string
 	Status          string
 	StartTime       string
 	EndTime         string
-	Duration        string
+	Duration        int // in seconds
 	Price           string
 	PriceUnit       string
 	Direction       string
 	AnsweredBy      string
+	Annotation      string
+	ApiVersion      string
 	ForwardedFrom   string
+	GroupSid        string
 	CallerName      string
+	QueueTime       string // in milliseconds
+	StirStatus      string // SHAKEN attestation of outbound calls: A, B or C
+	StirVerstat     string // verification result, see twiml.StirVerstat
+	TrunkSid        string
 	Uri             string
-	SubResourceUris *CallSubUris
+	SubResourceUris *CallSubUris `xml:"SubresourceUris"`
 }
 
 type CallSubUris struct {
@@ -191,18 +262,25 @@ type ConferencesResponse struct {
 }
 
 type ConferenceResponse struct {
-	Sid             string
-	AccountSid      string
-	FriendlyName    string
-	Status          string
-	DateCreated     string
-	DateUpdated     string
-	Uri             string
-	SubResourceUris *ConferenceSubUris
+	Sid          string
+	AccountSid   string
+	FriendlyName string
+	Status       string
+	DateCreated  string
+	DateUpdated  string
+	ApiVersion   string
+	Region       string
+	// ReasonConferenceEnded and CallSidEndingConference are set once the
+	// conference completed
+	ReasonConferenceEnded   string
+	CallSidEndingConference string
+	Uri                     string
+	SubResourceUris         *ConferenceSubUris `xml:"SubresourceUris"`
 }
 
 type ConferenceSubUris struct {
 	Participants string
+	Recordings   string
 }
 
 type IncomingPhoneNumbersResponse struct {
@@ -235,6 +313,11 @@ type IncomingPhoneNumberResponse struct {
 	MMS                  string `xml:"Capabilities>MMS"`
 	Fax                  string `xml:"Capabilities>Fax"`
 	Beta                 string
+	Origin               string
+	TrunkSid             string
+	AddressRequirements  string
+	AddressSid           string
+	EmergencyStatus      string
 	ApiVersion           string
 	Uri                  string
 }
@@ -245,21 +328,30 @@ type MessagesResponse struct {
 }
 
 type MessageResponse struct {
-	Sid         string
-	DateCreated string
-	DateUpdated string
-	DateSent    string
-	AccountSid  string
-	To          string
-	From        string
-	Body        string
-	NumSegments string
-	Status      string
-	Direction   string
-	Price       string
-	PriceUnit   string
-	ApiVersion  string
-	Uri         string
+	Sid                 string
+	DateCreated         string
+	DateUpdated         string
+	DateSent            string
+	AccountSid          string
+	To                  string
+	From                string
+	MessagingServiceSid string
+	Body                string
+	NumSegments         string
+	NumMedia            string
+	Status              string
+	Direction           string
+	Price               string
+	PriceUnit           string
+	ErrorCode           string // of failed and undelivered messages
+	ErrorMessage        string
+	ApiVersion          string
+	Uri                 string
+	SubResourceUris     *MessageSubUris `xml:"SubresourceUris"`
+}
+
+type MessageSubUris struct {
+	Media string
 }
 
 type NotificationsResponse struct {
@@ -322,9 +414,15 @@ type ParticipantResponse struct {
 	ConferenceSid          string
 	AccountSid             string
 	CallSid                string
+	Label                  string
+	Status                 string
 	Muted                  string
+	Hold                   string
+	QueueTime              string // in milliseconds, while the participant is queued
 	EndConferenceOnExit    string
 	StartConferenceOnEnter string
+	Coaching               string
+	CallSidToCoach         string
 	DateCreated            string
 	DateUpdated            string
 	Uri                    string
@@ -338,9 +436,9 @@ type QueuesResponse struct {
 type QueueResponse struct {
 	Sid             string
 	FriendlyName    string
-	CurrentSize     string
-	MaxSize         string
-	AverageWaitTime string
+	CurrentSize     int
+	MaxSize         int
+	AverageWaitTime int // in seconds
 	DateCreated     string
 	DateUpdated     string
 	Uri             string
@@ -364,19 +462,47 @@ type RecordingsResponse struct {
 }
 
 type RecordingResponse struct {
-	Sid         string
-	AccountSid  string
-	CallSid     string
-	Duration    string
-	DateCreated string
-	ApiVersion  string
-	DateUpdated string
-	Status      string
-	Source      string
-	Channels    string
-	Price       string
-	PriceUnit   string
-	Uri         string
+	Sid             string
+	AccountSid      string
+	CallSid         string
+	ConferenceSid   string
+	Duration        int // in seconds
+	DateCreated     string
+	ApiVersion      string
+	DateUpdated     string
+	StartTime       string
+	Status          string
+	Source          string
+	Channels        int
+	Price           string
+	PriceUnit       string
+	ErrorCode       string
+	Uri             string
+	SubResourceUris *RecordingSubUris `xml:"SubresourceUris"`
+}
+
+type RecordingSubUris struct {
+	Transcriptions string
+	AddOnResults   string
+}
+
+type TranscriptionsResponse struct {
+	Page
+	Transcription []TranscriptionResponse
+}
+
+type TranscriptionResponse struct {
+	Sid               string
+	DateCreated       string
+	DateUpdated       string
+	AccountSid        string
+	Status            string
+	RecordingSid      string
+	Duration          string
+	TranscriptionText string
+	Price             string
+	PriceUnit         string
+	Uri               string
 }
 
 type RecordingAudio struct {
@@ -400,6 +526,8 @@ type UsageRecordResponse struct {
 	CountUnit       string
 	Price           string
 	PriceUnit       string
+	ApiVersion      string
+	AsOf            string
 	Uri             string
 	SubresourceUris *UsageRecordSubUris
 }
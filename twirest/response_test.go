@@ -0,0 +1,359 @@
+package twirest
+
+import (
+	"encoding/xml"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// xmlNode is an element of a fixture with its child elements
+type xmlNode struct {
+	XMLName xml.Name
+	Nodes   []xmlNode `xml:",any"`
+}
+
+// unmapped returns the paths of the elements of node that no field of t
+// parses, the elements a response struct is missing
+func unmapped(node xmlNode, t reflect.Type, path string) []string {
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct || len(node.Nodes) == 0 {
+		return nil
+	}
+
+	var missing []string
+	for _, child := range node.Nodes {
+		name := child.XMLName.Local
+		fld, nested, ok := xmlField(t, name)
+		switch {
+		case !ok:
+			missing = append(missing, path+"/"+name)
+		case nested != nil:
+			// a field tagged a>b parses b of element a
+			for _, grandchild := range child.Nodes {
+				if _, ok := nested[grandchild.XMLName.Local]; !ok {
+					missing = append(missing, path+"/"+name+"/"+grandchild.XMLName.Local)
+				}
+			}
+		default:
+			missing = append(missing, unmapped(child, fld.Type, path+"/"+name)...)
+		}
+	}
+	return missing
+}
+
+// xmlField returns the field of t that parses the element name, or the
+// names of the elements parsed within it by fields tagged name>child
+func xmlField(t reflect.Type, name string) (reflect.StructField, map[string]bool, bool) {
+	var nested map[string]bool
+	for i := 0; i < t.NumField(); i++ {
+		fld := t.Field(i)
+		tag := strings.Split(fld.Tag.Get("xml"), ",")[0]
+		if fld.Anonymous {
+			if f, n, ok := xmlField(fld.Type, name); ok {
+				return f, n, ok
+			}
+			continue
+		}
+		if tag == "-" || fld.PkgPath != "" {
+			continue
+		}
+		if parts := strings.Split(tag, ">"); len(parts) == 2 && parts[0] == name {
+			if nested == nil {
+				nested = map[string]bool{}
+			}
+			nested[parts[1]] = true
+			continue
+		}
+		if tag == name || tag == "" && fld.Name == name {
+			return fld, nil, true
+		}
+	}
+	return reflect.StructField{}, nested, nested != nil
+}
+
+const (
+	fixtureCall       = "CA0123456789abcdef0123456789abcdef"
+	fixtureConference = "CF0123456789abcdef0123456789abcdef"
+	fixtureRecording  = "RE0123456789abcdef0123456789abcdef"
+	fixtureMessage    = "SM0123456789abcdef0123456789abcdef"
+	fixtureNumber     = "PN0123456789abcdef0123456789abcdef"
+	fixtureBase       = "/2010-04-01/Accounts/AC123"
+)
+
+// listPage is the paging of the single page lists of the fixtures
+func listPage(uri string) Page {
+	page := fixtureBase + uri + "?Page=0&PageSize=50"
+	if strings.Contains(uri, "?") {
+		page = fixtureBase + uri + "&Page=0&PageSize=50"
+	}
+	return Page{NumPages: 1, PageSize: 50, Total: 1, Uri: fixtureBase + uri,
+		FirstPageUri: page, LastPageUri: page}
+}
+
+func TestResponseFixtures(t *testing.T) {
+	var tests = []struct {
+		Fixture string
+		Req     interface{}
+		Got     func(TwilioResponse) interface{}
+		Expect  interface{}
+	}{
+		{"message.xml", Message{Sid: fixtureMessage},
+			func(r TwilioResponse) interface{} { return r.Message },
+			&MessageResponse{
+				Sid:                 fixtureMessage,
+				DateCreated:         "Mon, 02 Mar 2020 10:00:00 +0000",
+				DateUpdated:         "Mon, 02 Mar 2020 10:00:05 +0000",
+				DateSent:            "Mon, 02 Mar 2020 10:00:02 +0000",
+				AccountSid:          "AC123",
+				To:                  "+15005550001",
+				From:                "+15005550006",
+				MessagingServiceSid: "MG0123456789abcdef0123456789abcdef",
+				Body:                "Your appointment is tomorrow at 10:00",
+				NumSegments:         "1",
+				NumMedia:            "0",
+				Status:              "undelivered",
+				Direction:           "outbound-api",
+				Price:               "-0.00750",
+				PriceUnit:           "USD",
+				ErrorCode:           "30003",
+				ErrorMessage:        "Unreachable destination handset",
+				ApiVersion:          ApiVer,
+				Uri:                 fixtureBase + "/Messages/" + fixtureMessage,
+				SubResourceUris: &MessageSubUris{
+					Media: fixtureBase + "/Messages/" + fixtureMessage + "/Media"},
+			}},
+		{"call.xml", Call{Sid: fixtureCall},
+			func(r TwilioResponse) interface{} { return r.Call },
+			&CallResponse{
+				Sid:            fixtureCall,
+				DateCreated:    "Mon, 02 Mar 2020 10:00:00 +0000",
+				DateUpdated:    "Mon, 02 Mar 2020 10:01:05 +0000",
+				AccountSid:     "AC123",
+				To:             "+15005550001",
+				ToFormatted:    "(500) 555-0001",
+				From:           "+15005550006",
+				FromFormatted:  "(500) 555-0006",
+				PhoneNumberSid: fixtureNumber,
+				Status:         "completed",
+				StartTime:      "Mon, 02 Mar 2020 10:00:02 +0000",
+				EndTime:        "Mon, 02 Mar 2020 10:01:04 +0000",
+				Duration:       62,
+				Price:          "-0.02600",
+				PriceUnit:      "USD",
+				Direction:      "outbound-api",
+				AnsweredBy:     "human",
+				ApiVersion:     ApiVer,
+				QueueTime:      "0",
+				Uri:            fixtureBase + "/Calls/" + fixtureCall,
+				SubResourceUris: &CallSubUris{
+					Notifications: fixtureBase + "/Calls/" + fixtureCall + "/Notifications",
+					Recordings:    fixtureBase + "/Calls/" + fixtureCall + "/Recordings"},
+			}},
+		{"recording.xml", Recording{Sid: fixtureRecording},
+			func(r TwilioResponse) interface{} { return r.Recording },
+			&RecordingResponse{
+				Sid:           fixtureRecording,
+				AccountSid:    "AC123",
+				CallSid:       fixtureCall,
+				ConferenceSid: fixtureConference,
+				Duration:      61,
+				DateCreated:   "Mon, 02 Mar 2020 10:01:06 +0000",
+				DateUpdated:   "Mon, 02 Mar 2020 10:01:10 +0000",
+				StartTime:     "Mon, 02 Mar 2020 10:00:03 +0000",
+				ApiVersion:    ApiVer,
+				Status:        "completed",
+				Source:        "Conference",
+				Channels:      2,
+				Price:         "-0.00250",
+				PriceUnit:     "USD",
+				Uri:           fixtureBase + "/Recordings/" + fixtureRecording,
+				SubResourceUris: &RecordingSubUris{
+					Transcriptions: fixtureBase + "/Recordings/" + fixtureRecording + "/Transcriptions",
+					AddOnResults:   fixtureBase + "/Recordings/" + fixtureRecording + "/AddOnResults"},
+			}},
+		{"conference.xml", Conference{Sid: fixtureConference},
+			func(r TwilioResponse) interface{} { return r.Conference },
+			&ConferenceResponse{
+				Sid:                     fixtureConference,
+				AccountSid:              "AC123",
+				FriendlyName:            "support-42",
+				Status:                  "completed",
+				DateCreated:             "Mon, 02 Mar 2020 10:00:00 +0000",
+				DateUpdated:             "Mon, 02 Mar 2020 10:20:00 +0000",
+				ApiVersion:              ApiVer,
+				Region:                  "us1",
+				ReasonConferenceEnded:   "participant-with-end-conference-on-exit-left",
+				CallSidEndingConference: fixtureCall,
+				Uri:                     fixtureBase + "/Conferences/" + fixtureConference,
+				SubResourceUris: &ConferenceSubUris{
+					Participants: fixtureBase + "/Conferences/" + fixtureConference + "/Participants",
+					Recordings:   fixtureBase + "/Conferences/" + fixtureConference + "/Recordings"},
+			}},
+		{"participant.xml", Participant{Sid: fixtureConference, CallSid: fixtureCall},
+			func(r TwilioResponse) interface{} { return r.Participant },
+			&ParticipantResponse{
+				ConferenceSid:          fixtureConference,
+				AccountSid:             "AC123",
+				CallSid:                fixtureCall,
+				Label:                  "customer",
+				Status:                 "connected",
+				Muted:                  "false",
+				Hold:                   "false",
+				EndConferenceOnExit:    "true",
+				StartConferenceOnEnter: "true",
+				Coaching:               "false",
+				DateCreated:            "Mon, 02 Mar 2020 10:00:00 +0000",
+				DateUpdated:            "Mon, 02 Mar 2020 10:00:01 +0000",
+				Uri: fixtureBase + "/Conferences/" + fixtureConference +
+					"/Participants/" + fixtureCall,
+			}},
+		{"usagerecords.xml", UsageRecords{SubResource: "LastMonth", Category: "sms"},
+			func(r TwilioResponse) interface{} { return r.UsageRecords },
+			&UsageRecordsResponse{
+				Page: listPage("/Usage/Records/LastMonth?Category=sms"),
+				UsageRecord: []UsageRecordResponse{{
+					Category:    "sms",
+					Description: "SMS Messages",
+					AccountSid:  "AC123",
+					StartDate:   "2020-02-01",
+					EndDate:     "2020-02-29",
+					Usage:       "1350",
+					UsageUnit:   "segments",
+					Count:       "1200",
+					CountUnit:   "messages",
+					Price:       "10.125",
+					PriceUnit:   "usd",
+					ApiVersion:  ApiVer,
+					AsOf:        "2020-03-02T10:00:00+00:00",
+					Uri: fixtureBase + "/Usage/Records/LastMonth?Category=sms" +
+						"&StartDate=2020-02-01&EndDate=2020-02-29",
+					SubresourceUris: &UsageRecordSubUris{
+						Daily:     fixtureBase + "/Usage/Records/Daily?Category=sms",
+						Monthly:   fixtureBase + "/Usage/Records/Monthly?Category=sms",
+						Yearly:    fixtureBase + "/Usage/Records/Yearly?Category=sms",
+						AllTime:   fixtureBase + "/Usage/Records/AllTime?Category=sms",
+						Today:     fixtureBase + "/Usage/Records/Today?Category=sms",
+						Yesterday: fixtureBase + "/Usage/Records/Yesterday?Category=sms",
+						ThisMonth: fixtureBase + "/Usage/Records/ThisMonth?Category=sms",
+						LastMonth: fixtureBase + "/Usage/Records/LastMonth?Category=sms"},
+				}},
+			}},
+		{"notifications.xml", Notifications{},
+			func(r TwilioResponse) interface{} { return r.Notifications },
+			&NotificationsResponse{
+				Page: listPage("/Notifications"),
+				Notification: []NotificationResponse{{
+					Sid:           "NO0123456789abcdef0123456789abcdef",
+					DateCreated:   "Mon, 02 Mar 2020 10:00:01 +0000",
+					DateUpdated:   "Mon, 02 Mar 2020 10:00:01 +0000",
+					AccountSid:    "AC123",
+					CallSid:       fixtureCall,
+					ApiVersion:    ApiVer,
+					Log:           "0",
+					ErrorCode:     "11200",
+					MoreInfo:      "https://www.twilio.com/docs/errors/11200",
+					MessageText:   "Msg=HTTP+retrieval+failure",
+					MessageDate:   "Mon, 02 Mar 2020 10:00:01 +0000",
+					RequestUrl:    "https://example.com/voice",
+					RequestMethod: "POST",
+					Uri:           fixtureBase + "/Notifications/NO0123456789abcdef0123456789abcdef",
+				}},
+			}},
+		{"incomingphonenumbers.xml", IncomingPhoneNumberList{},
+			func(r TwilioResponse) interface{} { return r.IncomingPhoneNumbers },
+			&IncomingPhoneNumbersResponse{
+				Page: listPage("/IncomingPhoneNumbers"),
+				IncomingPhoneNumber: []IncomingPhoneNumberResponse{{
+					Sid:                  fixtureNumber,
+					AccountSid:           "AC123",
+					FriendlyName:         "Support line",
+					PhoneNumber:          "+15005550006",
+					VoiceUrl:             "https://example.com/voice",
+					VoiceMethod:          "POST",
+					VoiceFallbackMethod:  "POST",
+					StatusCallback:       "https://example.com/status",
+					StatusCallbackMethod: "POST",
+					VoiceCallerIdLookup:  "false",
+					DateCreated:          "Mon, 02 Mar 2020 10:00:00 +0000",
+					DateUpdated:          "Mon, 02 Mar 2020 10:00:00 +0000",
+					SmsUrl:               "https://example.com/sms",
+					SmsMethod:            "POST",
+					SmsFallbackMethod:    "POST",
+					SmsApplicationSid:    "AP0123456789abcdef0123456789abcdef",
+					Voice:                "true",
+					SMS:                  "true",
+					MMS:                  "true",
+					Fax:                  "false",
+					Beta:                 "false",
+					Origin:               "twilio",
+					AddressRequirements:  "none",
+					EmergencyStatus:      "Inactive",
+					ApiVersion:           ApiVer,
+					Uri:                  fixtureBase + "/IncomingPhoneNumbers/" + fixtureNumber,
+				}},
+			}},
+	}
+
+	for idx, test := range tests {
+		body, err := ioutil.ReadFile(filepath.Join("testdata", test.Fixture))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var root xmlNode
+		if err := xml.Unmarshal(body, &root); err != nil {
+			t.Fatalf("Test %v failed; %v: %v", idx, test.Fixture, err)
+		}
+		if missing := unmapped(root, reflect.TypeOf(TwilioResponse{}), ""); len(missing) > 0 {
+			t.Errorf("Test %v failed; %v elements not parsed: %v", idx, test.Fixture, missing)
+		}
+
+		client, ts := testClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write(body)
+		}))
+		resp, err := client.Request(test.Req, false)
+		ts.Close()
+		if err != nil {
+			t.Errorf("Test %v failed; %v: %v", idx, test.Fixture, err)
+			continue
+		}
+		if got := test.Got(resp); !reflect.DeepEqual(got, test.Expect) {
+			t.Errorf("Test %v failed; %v\nexpected %+v\ngot      %+v", idx, test.Fixture,
+				test.Expect, got)
+		}
+	}
+}
+
+func TestResponseHeaders(t *testing.T) {
+	client, ts := testClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Twilio-Request-Id", "RQ0123456789abcdef0123456789abcdef")
+		w.Header().Set("Date", "Tue, 13 Oct 2026 09:30:00 GMT")
+		w.Header().Set("Content-Type", "application/xml")
+		if r.URL.Path != "/2010-04-01/Accounts/AC123/Queues" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(xmlHeader + `<TwilioResponse><RestException><Code>20404</Code>` +
+				`<Status>404</Status></RestException></TwilioResponse>`))
+			return
+		}
+		w.Write([]byte(xmlHeader + "<TwilioResponse></TwilioResponse>"))
+	}))
+	defer ts.Close()
+
+	for idx, req := range []interface{}{Queues{}, Queue{Sid: "QU404"}} {
+		resp, _ := client.Request(req, false)
+		if resp.RequestID != "RQ0123456789abcdef0123456789abcdef" ||
+			resp.ContentType != "application/xml" ||
+			!resp.Date.Equal(time.Date(2026, 10, 13, 9, 30, 0, 0, time.UTC)) ||
+			resp.Headers.Get("Twilio-Request-Id") != resp.RequestID {
+			t.Errorf("Test %v failed; unexpected headers %v %v %v %v", idx,
+				resp.RequestID, resp.ContentType, resp.Date, resp.Headers)
+		}
+	}
+}
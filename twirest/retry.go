@@ -0,0 +1,118 @@
+package twirest
+
+import (
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"time"
+)
+
+// RetryOptions configures WithRetry
+type RetryOptions struct {
+	// MaxAttempts is the number of times a request is sent at most,
+	// default 3
+	MaxAttempts int
+	// BaseDelay is the wait before the first retry, doubling for each
+	// retry after it, default 500 milliseconds
+	BaseDelay time.Duration
+	// MaxDelay caps the wait between attempts, default 30 seconds
+	MaxDelay time.Duration
+	// Jitter is the fraction of the wait, between 0 and 1, that is taken off
+	// at random so clients that failed together don't retry together
+	Jitter float64
+	// RetryPOST retries POST requests too. Twilio may have acted on a POST
+	// that failed with a 5xx, such as sending the message, so only enable it
+	// along with WithDedupe or WithIdempotencyKey, or when duplicates are
+	// harmless.
+	RetryPOST bool
+	// Clock is the time source, the clock of the client if nil
+	Clock Clock
+}
+
+// WithRetry makes the client retry requests that fail with 429 Too Many
+// Requests, a 5xx status or a network error, waiting the Retry-After of the
+// response if it has one and an exponential backoff if it hasn't. GET and
+// DELETE requests are retried, POST requests only with RetryPOST. The number
+// of attempts is in the Status.Attempts of the response.
+func WithRetry(opts RetryOptions) ClientOption {
+	if opts.MaxAttempts <= 0 {
+		opts.MaxAttempts = 3
+	}
+	if opts.BaseDelay <= 0 {
+		opts.BaseDelay = 500 * time.Millisecond
+	}
+	if opts.MaxDelay <= 0 {
+		opts.MaxDelay = 30 * time.Second
+	}
+	return ClientOption{apply: func(c *TwilioClient) {
+		opts := opts
+		if opts.Clock == nil {
+			opts.Clock = clientClock{c}
+		}
+		c.retry = &opts
+	}}
+}
+
+// doRetry sends httpReq like do, retrying it as configured by WithRetry, and
+// returns the last response and the number of attempts
+func (twiClient *TwilioClient) doRetry(httpReq *http.Request) (*http.Response, int, error) {
+	opts := twiClient.retry
+	if opts == nil || (httpReq.Method == "POST" && !opts.RetryPOST) {
+		resp, err := twiClient.do(httpReq)
+		return resp, 1, err
+	}
+
+	for attempt := 1; ; attempt++ {
+		resp, err := twiClient.do(httpReq)
+		if attempt == opts.MaxAttempts || !retryable(resp, err) ||
+			httpReq.Context().Err() != nil {
+			return resp, attempt, err
+		}
+
+		wait := opts.delay(attempt, resp)
+		if resp != nil {
+			io.Copy(ioutil.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		// the body was read by the failed attempt
+		if httpReq.GetBody != nil {
+			if httpReq.Body, err = httpReq.GetBody(); err != nil {
+				return nil, attempt, err
+			}
+		}
+
+		select {
+		case <-opts.Clock.After(wait):
+		case <-httpReq.Context().Done():
+			return nil, attempt, httpReq.Context().Err()
+		}
+	}
+}
+
+// retryable reports if a request that got resp and err is worth retrying
+func retryable(resp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+}
+
+// delay returns the wait after the failed attempt, the Retry-After of resp
+// if set, see RateLimit
+func (opts *RetryOptions) delay(attempt int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if d, ok := retryAfter(resp.Header, opts.Clock.Now()); ok {
+			return d
+		}
+	}
+
+	d := opts.BaseDelay << uint(attempt-1)
+	if d > opts.MaxDelay || d <= 0 {
+		d = opts.MaxDelay
+	}
+	if opts.Jitter > 0 {
+		d -= time.Duration(opts.Jitter * rand.Float64() * float64(d))
+	}
+	return d
+}
@@ -0,0 +1,118 @@
+package twirest
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+// flakyServer fails the first failures requests with status, then responds
+// with a message. It records the bodies of the requests it gets.
+type flakyServer struct {
+	mu       sync.Mutex
+	failures int
+	status   int
+	bodies   []string
+}
+
+func (s *flakyServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	r.ParseForm()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.bodies = append(s.bodies, r.PostForm.Encode())
+	if len(s.bodies) <= s.failures {
+		w.Header().Set("Retry-After", "2")
+		w.WriteHeader(s.status)
+		return
+	}
+	w.Write([]byte(xmlHeader + "<TwilioResponse><Message><Sid>SM1</Sid></Message>" +
+		"</TwilioResponse>"))
+}
+
+func TestRetry(t *testing.T) {
+	var tests = []struct {
+		Req      interface{}
+		Opts     RetryOptions
+		Failures int
+		Status   int
+		Attempts int
+		Http     int
+	}{
+		{Messages{}, RetryOptions{}, 2, 503, 3, 200},
+		{Messages{}, RetryOptions{}, 3, 503, 3, 503},
+		{Messages{}, RetryOptions{MaxAttempts: 5}, 4, 429, 5, 200},
+		{Messages{}, RetryOptions{}, 1, 404, 1, 404},
+		{SendMessage{From: "+15005550006", To: "+15005550001", Text: "Hi"},
+			RetryOptions{}, 1, 503, 1, 503},
+		{SendMessage{From: "+15005550006", To: "+15005550001", Text: "Hi"},
+			RetryOptions{RetryPOST: true}, 1, 503, 2, 200},
+	}
+
+	for idx, test := range tests {
+		srv := &flakyServer{failures: test.Failures, status: test.Status}
+		clock := newFakeClock()
+		test.Opts.Clock = clock
+		client, ts := testClient(t, srv, WithRetry(test.Opts))
+
+		resp, _ := client.Request(test.Req, false)
+		ts.Close()
+		if resp.Status.Attempts != test.Attempts || resp.Status.Http != test.Http {
+			t.Errorf("Test %v failed; expected %v attempts and status %v, got %v and %v",
+				idx, test.Attempts, test.Http, resp.Status.Attempts, resp.Status.Http)
+		}
+		// every attempt sends the whole body and waits the Retry-After
+		for _, body := range srv.bodies {
+			if body != srv.bodies[0] {
+				t.Errorf("Test %v failed; expected body %#v, got %#v", idx,
+					srv.bodies[0], body)
+			}
+		}
+		waited := clock.Now().Sub(newFakeClock().Now())
+		if expect := time.Duration(test.Attempts-1) * 2 * time.Second; waited != expect {
+			t.Errorf("Test %v failed; expected to wait %v, got %v", idx, expect, waited)
+		}
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	opts := RetryOptions{BaseDelay: time.Second, MaxDelay: 5 * time.Second}
+	var tests = []struct {
+		Attempt int
+		Expect  time.Duration
+	}{
+		{1, time.Second},
+		{2, 2 * time.Second},
+		{3, 4 * time.Second},
+		{4, 5 * time.Second},
+		{80, 5 * time.Second},
+	}
+
+	for idx, test := range tests {
+		if d := opts.delay(test.Attempt, nil); d != test.Expect {
+			t.Errorf("Test %v failed; expected %v, got %v", idx, test.Expect, d)
+		}
+	}
+
+	opts.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		if d := opts.delay(2, nil); d <= time.Second || d > 2*time.Second {
+			t.Fatalf("expected a jittered delay in (1s, 2s], got %v", d)
+		}
+	}
+}
+
+func TestRetryCanceled(t *testing.T) {
+	srv := &flakyServer{failures: 5, status: 503}
+	client, ts := testClient(t, srv, WithRetry(RetryOptions{BaseDelay: time.Hour}))
+	defer ts.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	resp, err := client.RequestWithContext(ctx, Messages{}, false)
+	if err != context.DeadlineExceeded || resp.Status.Attempts != 1 {
+		t.Errorf("expected a canceled request after 1 attempt, got %v after %v",
+			err, resp.Status.Attempts)
+	}
+}
@@ -0,0 +1,158 @@
+package twirest
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/seanhagen/twilio/twiml"
+)
+
+// requestTypes are the request structs the client sends
+var requestTypes = []interface{}{
+	IncomingPhoneNumberList{}, CreateIncomingPhoneNumber{},
+	UpdateIncomingPhoneNumber{}, DeleteIncomingPhoneNumber{},
+	AvailablePhoneNumbers{}, Accounts{}, Account{}, UpdateAccount{},
+	Calls{}, Call{}, MakeCall{}, CreateCallRecording{}, ModifyCall{},
+	Conferences{}, Conference{}, Participants{}, Participant{},
+	CreateParticipant{}, DeleteParticipant{}, UpdateParticipant{},
+	Messages{}, Message{}, SendMessage{},
+	Notifications{}, Notification{}, DeleteNotification{},
+	OutgoingCallerIds{}, OutgoingCallerId{}, UpdateOutgoingCallerId{},
+	DeleteOutgoingCallerId{}, AddOutgoingCallerId{},
+	Recordings{}, Recording{}, DeleteRecording{},
+	Transcriptions{}, RecordingTranscriptions{}, Transcription{},
+	UsageRecords{}, Queues{}, Queue{}, CreateQueue{}, ChangeQueue{},
+	DeleteQueue{}, QueueMembers{}, QueueMember{}, DeQueue{},
+	ListByocTrunks{}, FetchByocTrunk{}, CreateByocTrunk{}, UpdateByocTrunk{},
+	DeleteByocTrunk{}, ListAlerts{}, GetAlert{}, ListEvents{}, GetEvent{},
+	ListPublicKeys{}, FetchPublicKey{}, CreatePublicKey{}, UpdatePublicKey{},
+	DeletePublicKey{}, ListSims{}, FetchSim{}, UpdateSim{}, SimUsageRecords{},
+	CallSummary{}, Lookup{}, DialingCountries{},
+	DialingCountry{}, UpdateDialingPermissions{},
+}
+
+// RequestTypes returns the types of the request structs the client sends
+func RequestTypes() []reflect.Type {
+	types := make([]reflect.Type, len(requestTypes))
+	for i, req := range requestTypes {
+		types[i] = reflect.TypeOf(req)
+	}
+	return types
+}
+
+// isRequestType reports if t is one of the request types
+func isRequestType(t reflect.Type) bool {
+	for _, req := range requestTypes {
+		if reflect.TypeOf(req) == t {
+			return true
+		}
+	}
+	return false
+}
+
+// enumValues are the values of typed request fields
+var enumValues = map[reflect.Type][]string{
+	reflect.TypeOf(Bool("")): {string(TwiTrue), string(TwiFalse)},
+	reflect.TypeOf(twiml.TrimPolicy("")): {string(twiml.TwiTrimSilence),
+		string(twiml.TwiDoNotTrim)},
+	reflect.TypeOf(twiml.JitterBufferSize("")): {string(twiml.TwiJitterBufferOff),
+		string(twiml.TwiJitterBufferSmall), string(twiml.TwiJitterBufferMedium),
+		string(twiml.TwiJitterBufferLarge)},
+}
+
+// pathFields are the untagged fields that become part of the url
+var pathFields = []string{"Sid", "CallSid", "SubResource", "CountryCode",
+	"Type", "MediaSid"}
+
+// Field locations of a FieldSchema
+const (
+	InPath   = "path"   // part of the url
+	InQuery  = "query"  // a query parameter
+	InForm   = "form"   // a parameter of the form body
+	InClient = "client" // used by the client, such as a filter, not sent
+)
+
+// FieldSchema describes a field of a request struct
+type FieldSchema struct {
+	Field    string   `json:"field"`
+	Param    string   `json:"param,omitempty"` // the parameter name on the wire
+	In       string   `json:"in"`
+	List     bool     `json:"list,omitempty"` // the parameter is repeated per value
+	Required bool     `json:"required,omitempty"`
+	Enum     []string `json:"enum,omitempty"`
+	// ReplacedBy is the typed field replacing this deprecated one on the
+	// same parameter, only one of them may be set
+	ReplacedBy string `json:"replaced_by,omitempty"`
+}
+
+// RequestSchema describes a request struct. Url has the fields in the path
+// as placeholders such as {Sid}. Format is the format the request type is
+// pinned to, empty if it follows the format of the client.
+type RequestSchema struct {
+	Name   string        `json:"name"`
+	Method string        `json:"method"`
+	Url    string        `json:"url"`
+	Format Format        `json:"format,omitempty"`
+	Fields []FieldSchema `json:"fields"`
+}
+
+// DescribeRequest describes the request struct of type t, one of
+// RequestTypes or a pointer to it, from the tags and the checks used when the
+// request is sent
+func DescribeRequest(t reflect.Type) (RequestSchema, error) {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if !isRequestType(t) {
+		return RequestSchema{}, fmt.Errorf("non valid request type: '%s'", t)
+	}
+
+	// a request with placeholders for the path fields
+	v := reflect.New(t).Elem()
+	for _, name := range pathFields {
+		if fld := v.FieldByName(name); fld.IsValid() && fld.Kind() == reflect.String {
+			fld.SetString("{" + name + "}")
+		}
+	}
+	req := v.Interface()
+
+	schema := RequestSchema{Name: t.Name(), Method: requestMethod(req),
+		Format: formatPins[t]}
+	schema.Url, _ = urlString(req, "{AccountSid}")
+	// escaped path segments, such as the CallSid, hold a placeholder too
+	schema.Url = strings.NewReplacer("%7B", "{", "%7D", "}").Replace(schema.Url)
+
+	params := InQuery
+	if schema.Method == "POST" {
+		params = InForm
+	}
+	encoded := map[int]formField{}
+	if hasForm(req) {
+		for _, f := range formFields(t) {
+			encoded[f.index] = f
+		}
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		fld := t.Field(i)
+		if fld.PkgPath != "" {
+			continue // unexported, resource tags
+		}
+		fs := FieldSchema{Field: fld.Name, Enum: enumValues[fld.Type]}
+		if f, ok := encoded[i]; ok {
+			fs.Param = f.name
+			fs.In = params
+			fs.List = f.slice
+		} else if (fld.Tag == "" || pathControl(fld.Tag)) && stringIn(fld.Name, pathFields) {
+			fs.Param = fld.Name
+			fs.In = InPath
+		} else {
+			fs.In = InClient
+		}
+		fs.Required = requiredField(t, fld.Name)
+		fs.ReplacedBy = replacedBy(t, fld.Name)
+		schema.Fields = append(schema.Fields, fs)
+	}
+	return schema, nil
+}
@@ -0,0 +1,116 @@
+package twirest
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestDescribeRequestRegistered(t *testing.T) {
+	for _, typ := range RequestTypes() {
+		schema, err := DescribeRequest(typ)
+		if err != nil {
+			t.Errorf("%v: %v", typ, err)
+			continue
+		}
+		if schema.Name == "" || schema.Method == "" || schema.Url == "" {
+			t.Errorf("%v: expected a schema, got %#v", typ, schema)
+		}
+
+		params := map[string]string{}
+		for _, fs := range schema.Fields {
+			// a deprecated field shares its parameter with the typed field
+			// replacing it, such as Timeout with TimeoutSeconds
+			if fs.Param == "" || fs.ReplacedBy != "" {
+				continue
+			}
+			if params[fs.Param] != "" {
+				t.Errorf("%v: duplicate parameter %v", typ, fs.Param)
+			}
+			params[fs.Param] = fs.Field
+		}
+		for _, fs := range schema.Fields {
+			if fs.ReplacedBy != "" && params[fs.Param] != fs.ReplacedBy {
+				t.Errorf("%v: expected %v to replace %v on %v, got %v", typ, fs.ReplacedBy,
+					fs.Field, fs.Param, params[fs.Param])
+			}
+		}
+	}
+}
+
+func TestDescribeRequest(t *testing.T) {
+	schema, err := DescribeRequest(reflect.TypeOf(CreateCallRecording{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := RequestSchema{
+		Name:   "CreateCallRecording",
+		Method: "POST",
+		Url:    "https://api.twilio.com/2010-04-01/Accounts/{AccountSid}/Calls/{Sid}/Recordings",
+		Format: FormatXML,
+		Fields: []FieldSchema{
+			{Field: "Sid", Param: "Sid", In: InPath, Required: true},
+			{Field: "RecordingChannels", Param: "RecordingChannels", In: InForm},
+			{Field: "RecordingTrack", Param: "RecordingTrack", In: InForm},
+			{Field: "Trim", Param: "Trim", In: InForm,
+				Enum: []string{"trim-silence", "do-not-trim"}},
+			{Field: "RecordingStatusCallback", Param: "RecordingStatusCallback", In: InForm},
+			{Field: "RecordingStatusCallbackEvents", Param: "RecordingStatusCallbackEvent",
+				In: InForm, List: true},
+		},
+	}
+	if !reflect.DeepEqual(schema, expect) {
+		t.Errorf("expected %#v, got %#v", expect, schema)
+	}
+
+	var tests = []struct {
+		Req    interface{}
+		Field  string
+		Expect FieldSchema
+	}{
+		{Calls{}, "StartTimeBefore", FieldSchema{Field: "StartTimeBefore",
+			Param: "StartTime<", In: InQuery, ReplacedBy: "StartTimeOnOrBefore"}},
+		{&Recordings{}, "Source", FieldSchema{Field: "Source", In: InClient}},
+		{CreateParticipant{}, "Coaching", FieldSchema{Field: "Coaching",
+			Param: "Coaching", In: InForm, Enum: []string{"true", "false"}}},
+		{GetAlert{}, "Sid", FieldSchema{Field: "Sid", Param: "Sid", In: InPath,
+			Required: true}},
+		{MakeCall{}, "Timeout", FieldSchema{Field: "Timeout", Param: "Timeout",
+			In: InForm, ReplacedBy: "TimeoutSeconds"}},
+		{MakeCall{}, "TimeoutSeconds", FieldSchema{Field: "TimeoutSeconds",
+			Param: "Timeout", In: InForm}},
+	}
+
+	for idx, test := range tests {
+		schema, err := DescribeRequest(reflect.TypeOf(test.Req))
+		if err != nil {
+			t.Fatalf("Test %v failed: %v", idx, err)
+		}
+		var got FieldSchema
+		for _, fs := range schema.Fields {
+			if fs.Field == test.Field {
+				got = fs
+			}
+		}
+		if !reflect.DeepEqual(got, test.Expect) {
+			t.Errorf("Test %v failed; expected %#v, got %#v", idx, test.Expect, got)
+		}
+	}
+
+	for _, typ := range []reflect.Type{reflect.TypeOf(""), reflect.TypeOf(CallInfo{})} {
+		if _, err := DescribeRequest(typ); err == nil {
+			t.Errorf("expected an error describing %v", typ)
+		}
+	}
+}
+
+func TestRequestSchemaJSON(t *testing.T) {
+	schema, _ := DescribeRequest(reflect.TypeOf(DeleteQueue{}))
+	out, err := json.Marshal(schema)
+	expect := `{"name":"DeleteQueue","method":"DELETE",` +
+		`"url":"https://api.twilio.com/2010-04-01/Accounts/{AccountSid}/Queues/{Sid}",` +
+		`"format":"xml","fields":[{"field":"Sid","param":"Sid","in":"path","required":true}]}`
+	if err != nil || string(out) != expect {
+		t.Errorf("expected %v, got %s (%v)", expect, out, err)
+	}
+}
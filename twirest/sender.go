@@ -0,0 +1,105 @@
+package twirest
+
+import (
+	"fmt"
+	"strings"
+)
+
+// maxAlphanumericSender is the length limit of an alphanumeric sender ID
+const maxAlphanumericSender = 11
+
+// AlphanumericSenderSupport tells per ISO country code if messages to the
+// country can be sent from an alphanumeric sender ID. Countries that aren't
+// listed aren't checked. Twilio changes the list now and then, set entries
+// to keep up before the package does.
+var AlphanumericSenderSupport = map[string]bool{
+	"US": false, "CN": false, "BR": false, "CL": false, "CR": false,
+	"EC": false, "NZ": false,
+	"GB": true, "IE": true, "DE": true, "FR": true, "ES": true, "IT": true,
+	"NL": true, "BE": true, "PT": true, "CH": true, "AT": true, "SE": true,
+	"NO": true, "DK": true, "FI": true, "PL": true, "AU": true, "IN": true,
+	"SG": true, "HK": true, "ZA": true, "AE": true,
+}
+
+// CallingCodes maps the country calling codes of E.164 numbers to ISO
+// country codes, to infer the country of a To number. The +1 countries share
+// the north american numbering plan and are counted as US.
+var CallingCodes = map[string]string{
+	"1": "US", "7": "RU", "20": "EG", "27": "ZA", "30": "GR", "31": "NL",
+	"32": "BE", "33": "FR", "34": "ES", "36": "HU", "39": "IT", "40": "RO",
+	"41": "CH", "43": "AT", "44": "GB", "45": "DK", "46": "SE", "47": "NO",
+	"48": "PL", "49": "DE", "51": "PE", "52": "MX", "54": "AR", "55": "BR",
+	"56": "CL", "57": "CO", "60": "MY", "61": "AU", "62": "ID", "63": "PH",
+	"64": "NZ", "65": "SG", "66": "TH", "81": "JP", "82": "KR", "84": "VN",
+	"86": "CN", "90": "TR", "91": "IN", "351": "PT", "353": "IE", "358": "FI",
+	"506": "CR", "593": "EC", "852": "HK", "971": "AE", "972": "IL",
+}
+
+// ErrAlphanumericSenderUnsupported is returned when a message is sent from
+// an alphanumeric sender ID to a country that doesn't allow them
+type ErrAlphanumericSenderUnsupported struct {
+	From    string
+	Country string
+}
+
+func (e *ErrAlphanumericSenderUnsupported) Error() string {
+	return fmt.Sprintf("alphanumeric sender ID %s not supported in %s", e.From, e.Country)
+}
+
+// isAlphanumericSender reports if from is meant as an alphanumeric sender
+// ID, not a number, short code or channel address such as whatsapp:+1...
+func isAlphanumericSender(from string) bool {
+	return from != "" && !strings.HasPrefix(from, "+") && !strings.Contains(from, ":") &&
+		ClassifySender(SendMessage{From: from}) == SenderAlphanumeric
+}
+
+// validAlphanumericSender checks that from is an alphanumeric sender ID of at
+// most 11 letters, digits and spaces with at least one letter
+func validAlphanumericSender(from string) error {
+	letter := false
+	for _, c := range from {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z':
+			letter = true
+		case c >= '0' && c <= '9', c == ' ':
+		default:
+			return fmt.Errorf("non valid alphanumeric sender ID: '%s'", from)
+		}
+	}
+	if len(from) > maxAlphanumericSender || !letter {
+		return fmt.Errorf("non valid alphanumeric sender ID: '%s'", from)
+	}
+	return nil
+}
+
+// countryOf returns the ISO country code of the E.164 number, "" if it isn't
+// known
+func countryOf(number string) string {
+	if !strings.HasPrefix(number, "+") {
+		return ""
+	}
+	for n := 3; n >= 1; n-- {
+		if len(number) > n {
+			if country, ok := CallingCodes[number[1:n+1]]; ok {
+				return country
+			}
+		}
+	}
+	return ""
+}
+
+// checkSender validates the alphanumeric sender ID of a message against its
+// format and the country of To
+func checkSender(msg SendMessage) error {
+	if !isAlphanumericSender(msg.From) {
+		return nil
+	}
+	if err := validAlphanumericSender(msg.From); err != nil {
+		return err
+	}
+	country := countryOf(msg.To)
+	if supported, ok := AlphanumericSenderSupport[country]; ok && !supported {
+		return &ErrAlphanumericSenderUnsupported{From: msg.From, Country: country}
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package twirest
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestAlphanumericSender(t *testing.T) {
+	var tests = []struct {
+		Msg     SendMessage
+		Valid   bool
+		Country string // of the unsupported error
+	}{
+		{SendMessage{From: "ACMECORP", To: "+447700900123"}, true, ""},
+		{SendMessage{From: "Acme 24", To: "+4915112345678"}, true, ""},
+		{SendMessage{From: "ACMECORPORATE", To: "+447700900123"}, false, ""},
+		{SendMessage{From: "12345678", To: "+447700900123"}, false, ""},
+		{SendMessage{From: "ACME-CORP", To: "+447700900123"}, false, ""},
+		{SendMessage{From: "ACMECORP", To: "+15005550006"}, false, "US"},
+		{SendMessage{From: "ACMECORP", To: "+5511912345678"}, false, "BR"},
+		// unknown countries and channel addresses aren't checked
+		{SendMessage{From: "ACMECORP", To: "+999123456"}, true, ""},
+		{SendMessage{From: "whatsapp:+14155238886", To: "whatsapp:+15005550006"}, true, ""},
+		{SendMessage{From: "55555", To: "+15005550006"}, true, ""},
+		{SendMessage{From: "+15005550006", To: "+15005550001"}, true, ""},
+	}
+
+	for idx, test := range tests {
+		err := checkSender(test.Msg)
+		var unsupported *ErrAlphanumericSenderUnsupported
+		if (err == nil) != test.Valid || errors.As(err, &unsupported) != (test.Country != "") ||
+			(unsupported != nil && unsupported.Country != test.Country) {
+			t.Errorf("Test %v failed; expected valid %v (%v), got %v", idx, test.Valid,
+				test.Country, err)
+		}
+	}
+
+	// the table can be overridden
+	defer func() { AlphanumericSenderSupport["US"] = false }()
+	AlphanumericSenderSupport["US"] = true
+	msg := SendMessage{From: "ACMECORP", To: "+15005550006", Text: "Hi"}
+	if _, err := httpRequest(msg, "AC123", nil); err != nil {
+		t.Errorf("expected the override to allow US, got %v", err)
+	}
+}
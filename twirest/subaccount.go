@@ -0,0 +1,185 @@
+package twirest
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// SubaccountRequest is a request on the resources of a subaccount made with
+// the credentials of the client, see OnSubaccount
+type SubaccountRequest struct {
+	AccountSid string
+	Request    interface{}
+}
+
+// OnSubaccount returns reqStruct as a request on the resources of the
+// subaccount sid, the url has the subaccount while the credentials of the
+// parent account authorize it. Only requests of the resources of the 2010
+// API scoped to an account can be made on a subaccount.
+func OnSubaccount(sid string, reqStruct interface{}) SubaccountRequest {
+	return SubaccountRequest{AccountSid: sid, Request: reqStruct}
+}
+
+// validSubaccount checks that sid is an account sid, "AC" and 32 characters
+func validSubaccount(sid string) error {
+	if !strings.HasPrefix(sid, "AC") || len(sid) != 34 {
+		return fmt.Errorf("non valid subaccount sid: '%s'", sid)
+	}
+	return nil
+}
+
+// subaccountRequest validates the request on a subaccount
+func subaccountRequest(sub SubaccountRequest) error {
+	if err := validSubaccount(sub.AccountSid); err != nil {
+		return err
+	}
+	if _, ok := sub.Request.(SubaccountRequest); ok || isJSONRequest(sub.Request) {
+		return fmt.Errorf("non valid subaccount request: '%T'", sub.Request)
+	}
+	return nil
+}
+
+// CloseOptions configures CloseSubaccount
+type CloseOptions struct {
+	// TransferNumbers moves the phone numbers of the subaccount to the
+	// parent account before closing it
+	TransferNumbers bool
+	// Force closes the subaccount even if it has phone numbers, which are
+	// released, or active calls
+	Force bool
+}
+
+// ErrSubaccountInUse is returned by CloseSubaccount when the subaccount still
+// has phone numbers or active calls
+type ErrSubaccountInUse struct {
+	AccountSid   string
+	PhoneNumbers []string
+	ActiveCalls  []string
+}
+
+func (e *ErrSubaccountInUse) Error() string {
+	return fmt.Sprintf("subaccount in use: '%s' (%d phone numbers, %d active calls)",
+		e.AccountSid, len(e.PhoneNumbers), len(e.ActiveCalls))
+}
+
+// NumberTransferFailure is a phone number that couldn't be transferred
+type NumberTransferFailure struct {
+	Sid         string
+	PhoneNumber string
+	Err         error
+}
+
+// ErrNumberTransfer is returned by CloseSubaccount when some phone numbers
+// couldn't be transferred to the parent account, the subaccount is left open
+type ErrNumberTransfer struct {
+	AccountSid  string
+	Transferred []string
+	Failed      []NumberTransferFailure
+}
+
+func (e *ErrNumberTransfer) Error() string {
+	failed := make([]string, len(e.Failed))
+	for i, f := range e.Failed {
+		failed[i] = fmt.Sprintf("%s (%s): %v", f.PhoneNumber, f.Sid, f.Err)
+	}
+	return fmt.Sprintf("failed to transfer %d of %d phone numbers of subaccount: '%s': %s",
+		len(e.Failed), len(e.Failed)+len(e.Transferred), e.AccountSid,
+		strings.Join(failed, "; "))
+}
+
+// CloseSubaccount closes the subaccount sid of the account of the client.
+// Closing is permanent and releases the phone numbers of the subaccount, so
+// unless opts.Force is set an *ErrSubaccountInUse is returned if it has phone
+// numbers or active calls. With opts.TransferNumbers the phone numbers are
+// moved to the parent account first, if any of them fails an
+// *ErrNumberTransfer tells which and the subaccount isn't closed.
+func (twiClient *TwilioClient) CloseSubaccount(ctx context.Context, sid string,
+	opts CloseOptions) error {
+
+	if err := validSubaccount(sid); err != nil {
+		return err
+	}
+	if sid == twiClient.accountSid {
+		return fmt.Errorf("not a subaccount: '%s'", sid)
+	}
+
+	numbers, err := twiClient.listNumbers(ctx, sid)
+	if err != nil {
+		return err
+	}
+	calls, err := twiClient.subaccountCalls(ctx, sid)
+	if err != nil {
+		return err
+	}
+
+	if opts.TransferNumbers && len(numbers) > 0 {
+		terr := &ErrNumberTransfer{AccountSid: sid}
+		for _, n := range numbers {
+			_, err := twiClient.request(ctx, sid, UpdateIncomingPhoneNumber{
+				Sid:        n.Sid,
+				AccountSid: twiClient.accountSid,
+			}, false)
+			if err != nil {
+				terr.Failed = append(terr.Failed, NumberTransferFailure{
+					Sid: n.Sid, PhoneNumber: n.PhoneNumber, Err: err})
+				continue
+			}
+			terr.Transferred = append(terr.Transferred, n.PhoneNumber)
+		}
+		if len(terr.Failed) > 0 {
+			return terr
+		}
+		numbers = nil
+	}
+
+	if !opts.Force && (len(numbers) > 0 || len(calls) > 0) {
+		inUse := &ErrSubaccountInUse{AccountSid: sid, ActiveCalls: calls}
+		for _, n := range numbers {
+			inUse.PhoneNumbers = append(inUse.PhoneNumbers, n.PhoneNumber)
+		}
+		return inUse
+	}
+
+	_, err = twiClient.RequestWithContext(ctx,
+		UpdateAccount{Sid: sid, Status: TwiClosed}, false)
+	return err
+}
+
+// listNumbers lists all incoming phone numbers of the account sid
+func (twiClient *TwilioClient) listNumbers(ctx context.Context, sid string) (
+	[]IncomingPhoneNumberResponse, error) {
+
+	var numbers []IncomingPhoneNumberResponse
+	resp, err := twiClient.request(ctx, sid, IncomingPhoneNumberList{}, false)
+	for err == nil && resp.IncomingPhoneNumbers != nil {
+		numbers = append(numbers, resp.IncomingPhoneNumbers.IncomingPhoneNumber...)
+		next := resp.IncomingPhoneNumbers.NextPageUri
+		if next == "" {
+			break
+		}
+		resp, err = twiClient.NextPage(ctx, next, IncomingPhoneNumberList{})
+	}
+	return numbers, err
+}
+
+// subaccountCalls returns the Sids of the queued, ringing and in progress
+// calls of the subaccount, only the first page of each is checked
+func (twiClient *TwilioClient) subaccountCalls(ctx context.Context, sid string) (
+	[]string, error) {
+
+	var calls []string
+	for _, status := range []string{TwiQueued, TwiRinging, TwiInProgress} {
+		resp, err := twiClient.request(ctx, sid, Calls{Status: status}, false)
+		if err != nil {
+			return nil, err
+		}
+		if resp.Calls == nil {
+			continue
+		}
+		for _, c := range resp.Calls.Call {
+			calls = append(calls, c.Sid)
+		}
+	}
+	return calls, nil
+}
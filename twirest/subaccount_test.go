@@ -0,0 +1,193 @@
+package twirest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+const testSubaccount = "AC0123456789abcdef0123456789abcdef"
+
+// subaccountServer serves a subaccount with the numbers PN1..PNn, of which
+// the ones in failing can't be transferred, and calls active calls
+func subaccountServer(t *testing.T, numbers int, failing map[string]bool,
+	active int, closed *bool, transferred *[]string) http.Handler {
+
+	const sub = "/2010-04-01/Accounts/" + testSubaccount
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, _, _ := r.BasicAuth(); user != "AC123" {
+			t.Errorf("expected parent credentials, got %v", user)
+		}
+		r.ParseForm()
+		body := ""
+		switch {
+		case r.Method == "GET" && r.URL.Path == sub+"/IncomingPhoneNumbers":
+			for i := 1; i <= numbers; i++ {
+				body += fmt.Sprintf("<IncomingPhoneNumber><Sid>PN%d</Sid>"+
+					"<PhoneNumber>+1500555000%d</PhoneNumber></IncomingPhoneNumber>", i, i)
+			}
+			body = `<IncomingPhoneNumbers nextpageuri="">` + body + `</IncomingPhoneNumbers>`
+		case r.Method == "GET" && r.URL.Path == sub+"/Calls":
+			if r.Form.Get("Status") == TwiInProgress {
+				for i := 1; i <= active; i++ {
+					body += fmt.Sprintf("<Call><Sid>CA%d</Sid></Call>", i)
+				}
+			}
+			body = "<Calls>" + body + "</Calls>"
+		case r.Method == "POST" && strings.HasPrefix(r.URL.Path, sub+"/IncomingPhoneNumbers/"):
+			pn := strings.TrimPrefix(r.URL.Path, sub+"/IncomingPhoneNumbers/")
+			if r.PostForm.Get("AccountSid") != "AC123" {
+				t.Errorf("expected transfer to parent, got %v", r.PostForm)
+			}
+			if failing[pn] {
+				w.WriteHeader(http.StatusBadRequest)
+				body = "<RestException><Code>21452</Code><Message>in use</Message>" +
+					"<Status>400</Status></RestException>"
+				break
+			}
+			*transferred = append(*transferred, pn)
+			body = "<IncomingPhoneNumber><Sid>" + pn + "</Sid></IncomingPhoneNumber>"
+		case r.Method == "POST" && r.URL.Path == sub:
+			if r.PostForm.Get("Status") != TwiClosed {
+				t.Errorf("expected close, got %v", r.PostForm)
+			}
+			*closed = true
+			body = "<Account><Sid>" + testSubaccount + "</Sid></Account>"
+		default:
+			t.Errorf("unexpected request %v %v", r.Method, r.URL.Path)
+		}
+		w.Write([]byte(xmlHeader + "<TwilioResponse>" + body + "</TwilioResponse>"))
+	})
+}
+
+func TestCloseSubaccount(t *testing.T) {
+	var tests = []struct {
+		Name        string
+		Numbers     int
+		Failing     map[string]bool
+		Active      int
+		Opts        CloseOptions
+		Closed      bool
+		Transferred int
+		InUse       bool
+		Failed      []string
+	}{
+		{"empty", 0, nil, 0, CloseOptions{}, true, 0, false, nil},
+		{"numbers", 2, nil, 0, CloseOptions{}, false, 0, true, nil},
+		{"active calls", 0, nil, 1, CloseOptions{}, false, 0, true, nil},
+		{"forced", 2, nil, 1, CloseOptions{Force: true}, true, 0, false, nil},
+		{"transfer", 3, nil, 0, CloseOptions{TransferNumbers: true}, true, 3, false, nil},
+		{"transfer with calls", 1, nil, 1, CloseOptions{TransferNumbers: true}, false, 1, true, nil},
+		{"partial transfer", 5, map[string]bool{"PN2": true, "PN4": true}, 0,
+			CloseOptions{TransferNumbers: true, Force: true}, false, 3, false,
+			[]string{"PN2", "PN4"}},
+	}
+
+	for idx, test := range tests {
+		var closed bool
+		var transferred []string
+		client, ts := testClient(t, subaccountServer(t, test.Numbers, test.Failing,
+			test.Active, &closed, &transferred))
+
+		err := client.CloseSubaccount(context.Background(), testSubaccount, test.Opts)
+
+		var inUse *ErrSubaccountInUse
+		var terr *ErrNumberTransfer
+		switch {
+		case test.InUse && !errors.As(err, &inUse):
+			t.Errorf("Test %v (%v) failed; expected in use, got %v", idx, test.Name, err)
+		case test.Failed != nil:
+			if !errors.As(err, &terr) || len(terr.Failed) != len(test.Failed) ||
+				len(terr.Transferred) != test.Transferred {
+				t.Errorf("Test %v (%v) failed; got %v", idx, test.Name, err)
+				break
+			}
+			for i, f := range terr.Failed {
+				if f.Sid != test.Failed[i] || !strings.Contains(err.Error(), f.Sid) {
+					t.Errorf("Test %v (%v) failed; expected %v in %v",
+						idx, test.Name, test.Failed[i], err)
+				}
+			}
+		case !test.InUse && err != nil:
+			t.Errorf("Test %v (%v) failed: %v", idx, test.Name, err)
+		}
+		if closed != test.Closed || len(transferred) != test.Transferred {
+			t.Errorf("Test %v (%v) failed; closed %v, transferred %v",
+				idx, test.Name, closed, transferred)
+		}
+		ts.Close()
+	}
+}
+
+func TestCloseSubaccountSid(t *testing.T) {
+	client, _ := NewClient("AC123", "token")
+	for _, sid := range []string{"AC123", "PN0123456789abcdef0123456789abcdef", ""} {
+		if err := client.CloseSubaccount(context.Background(), sid, CloseOptions{}); err == nil {
+			t.Errorf("expected error for sid %#v", sid)
+		}
+	}
+}
+
+func TestOnSubaccount(t *testing.T) {
+	var path, user string
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.Method + " " + r.URL.Path
+		user, _, _ = r.BasicAuth()
+		w.Write([]byte(xmlHeader + "<TwilioResponse><Message><Sid>SM1</Sid></Message>" +
+			"</TwilioResponse>"))
+	})
+	client, ts := testClient(t, handler)
+	defer ts.Close()
+	// an API key of the parent account
+	keyClient, err := NewAPIKeyClient("AC123", "SK123", "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyClient.httpclient = client.httpclient
+
+	const sub = "/2010-04-01/Accounts/" + testSubaccount
+	var tests = []struct {
+		Client *TwilioClient
+		Req    interface{}
+		Path   string
+		User   string
+	}{
+		{client, OnSubaccount(testSubaccount, SendMessage{From: "+15005550006",
+			To: "+15005550001", Text: "Hi"}), "POST " + sub + "/Messages", "AC123"},
+		{client, OnSubaccount(testSubaccount, Message{Sid: "SM1"}),
+			"GET " + sub + "/Messages/SM1", "AC123"},
+		{client, OnSubaccount(testSubaccount, DeleteQueue{Sid: "QU1"}),
+			"DELETE " + sub + "/Queues/QU1", "AC123"},
+		{client, Message{Sid: "SM1"}, "GET /2010-04-01/Accounts/AC123/Messages/SM1", "AC123"},
+		{keyClient, OnSubaccount(testSubaccount, Message{Sid: "SM1"}),
+			"GET " + sub + "/Messages/SM1", "SK123"},
+	}
+	for idx, test := range tests {
+		path, user = "", ""
+		if _, err := test.Client.Request(test.Req, false); err != nil {
+			t.Errorf("Test %v failed; unexpected error %v", idx, err)
+		}
+		if path != test.Path {
+			t.Errorf("Test %v failed; expected the url %v, got %v", idx, test.Path, path)
+		}
+		if user != test.User {
+			t.Errorf("Test %v failed; expected the auth user %v, got %v", idx, test.User, user)
+		}
+	}
+
+	for idx, req := range []interface{}{
+		OnSubaccount("AC123", Message{Sid: "SM1"}),
+		OnSubaccount("PN0123456789abcdef0123456789abcdef", Message{Sid: "SM1"}),
+		OnSubaccount(testSubaccount, ListSims{}),
+		OnSubaccount(testSubaccount, OnSubaccount(testSubaccount, Message{Sid: "SM1"})),
+	} {
+		path = ""
+		if _, err := client.Request(req, false); err == nil || path != "" {
+			t.Errorf("Test %v failed; expected an error without request, got %v %v", idx,
+				err, path)
+		}
+	}
+}
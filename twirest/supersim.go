@@ -0,0 +1,134 @@
+package twirest
+
+import "net/http"
+
+// Super SIM resources (https://www.twilio.com/docs/iot/supersim/api). These
+// live on supersim.twilio.com rather than the 2010 API and use JSON responses.
+
+// Super SIM status strings
+const (
+	TwiSimNew       = "new"
+	TwiSimReady     = "ready"
+	TwiSimActive    = "active"
+	TwiSimInactive  = "inactive"
+	TwiSimScheduled = "scheduled"
+)
+
+// Super SIM usage record granularity
+const (
+	TwiGranularityHour = "hour"
+	TwiGranularityDay  = "day"
+	TwiGranularityAll  = "all"
+)
+
+// ListSims requests a list of the Super SIMs on the account
+type ListSims struct {
+	domain   uri    `url:"supersim.twilio.com/v1"`
+	resource uri    `url:"/Sims"`
+	Status   string `url:"Status"`
+	Fleet    string `url:"Fleet"`
+	Iccid    string `url:"Iccid"`
+	PageSize int    `url:"PageSize"` // at most 1000, default 50
+	Page     int    `url:"Page"`
+}
+
+// FetchSim requests a single Super SIM
+type FetchSim struct {
+	domain   uri `url:"supersim.twilio.com/v1"`
+	resource uri `url:"/Sims"`
+	Sid      string
+}
+
+// UpdateSim changes the status, unique name or fleet of a Super SIM. Status
+// changes are applied asynchronously, see SimStatusUpdate.
+type UpdateSim struct {
+	domain         uri `url:"supersim.twilio.com/v1"`
+	resource       uri `url:"/Sims"`
+	Sid            string
+	Status         string `url:"Status"`
+	UniqueName     string `url:"UniqueName"`
+	FleetSid       string `url:"Fleet"`
+	CallbackUrl    string `url:"CallbackUrl"`
+	CallbackMethod string `url:"CallbackMethod"`
+}
+
+// SimUsageRecords requests data usage of Super SIMs, set Sim to restrict the
+// records to a single SIM
+type SimUsageRecords struct {
+	domain      uri    `url:"supersim.twilio.com/v1"`
+	resource    uri    `url:"/UsageRecords"`
+	Sim         string `url:"Sim"`
+	Fleet       string `url:"Fleet"`
+	Network     string `url:"Network"`
+	IsoCountry  string `url:"IsoCountry"`
+	Group       string `url:"Group"`
+	Granularity string `url:"Granularity"`
+	StartTime   string `url:"StartTime"`
+	EndTime     string `url:"EndTime"`
+	PageSize    int    `url:"PageSize"` // at most 1000, default 50
+	Page        int    `url:"Page"`
+}
+
+type SimsResponse struct {
+	Meta Meta          `json:"meta"`
+	Sims []SimResponse `json:"sims"`
+}
+
+type SimResponse struct {
+	Sid         string `json:"sid"`
+	UniqueName  string `json:"unique_name"`
+	AccountSid  string `json:"account_sid"`
+	Iccid       string `json:"iccid"`
+	Status      string `json:"status"`
+	FleetSid    string `json:"fleet_sid"`
+	DateCreated string `json:"date_created"`
+	DateUpdated string `json:"date_updated"`
+	Url         string `json:"url"`
+}
+
+// SimStatusUpdate is returned for UpdateSim requests that change the SIM
+// status. Twilio accepts the change with 202 Accepted and applies it later,
+// while Pending is true the SIM should be polled with FetchSim until its
+// status equals Requested.
+type SimStatusUpdate struct {
+	Requested string
+	Pending   bool
+}
+
+type SimUsageRecordsResponse struct {
+	Meta         Meta                     `json:"meta"`
+	UsageRecords []SimUsageRecordResponse `json:"usage_records"`
+}
+
+type SimUsageRecordResponse struct {
+	AccountSid      string    `json:"account_sid"`
+	SimSid          string    `json:"sim_sid"`
+	FleetSid        string    `json:"fleet_sid"`
+	NetworkSid      string    `json:"network_sid"`
+	IsoCountry      string    `json:"iso_country"`
+	Period          SimPeriod `json:"period"`
+	DataUpload      int64     `json:"data_upload"`
+	DataDownload    int64     `json:"data_download"`
+	DataTotal       int64     `json:"data_total"`
+	DataTotalBilled string    `json:"data_total_billed"`
+	BilledUnit      string    `json:"billed_unit"`
+}
+
+type SimPeriod struct {
+	StartTime string `json:"start_time"`
+	EndTime   string `json:"end_time"`
+}
+
+// simStatusUpdate maps the outcome of an UpdateSim request to a
+// SimStatusUpdate, nil is returned if the request didn't change the status
+func simStatusUpdate(req UpdateSim, twir TwilioResponse) *SimStatusUpdate {
+	if req.Status == "" {
+		return nil
+	}
+	u := &SimStatusUpdate{Requested: req.Status}
+	if twir.Status.Http == http.StatusAccepted ||
+		(twir.Sim != nil && twir.Sim.Status != req.Status) {
+		u.Pending = true
+	}
+	return u
+}
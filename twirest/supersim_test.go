@@ -0,0 +1,71 @@
+package twirest
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestSuperSimUrls(t *testing.T) {
+	tests := []struct {
+		Req    interface{}
+		Expect string
+	}{
+		{ListSims{Status: TwiSimActive},
+			"https://supersim.twilio.com/v1/Sims"},
+		{FetchSim{Sid: "HS123"},
+			"https://supersim.twilio.com/v1/Sims/HS123"},
+		{UpdateSim{Sid: "HS123", Status: TwiSimInactive},
+			"https://supersim.twilio.com/v1/Sims/HS123"},
+		{SimUsageRecords{Sim: "HS123"},
+			"https://supersim.twilio.com/v1/UsageRecords"},
+	}
+
+	for idx, test := range tests {
+		got, err := urlString(test.Req, "AC123")
+		if err != nil {
+			t.Errorf("Test %v failed: %v", idx, err)
+		}
+		if got != test.Expect {
+			t.Errorf("Test %v failed; expected %#v, got %#v", idx, test.Expect, got)
+		}
+	}
+}
+
+func TestUpdateSimPending(t *testing.T) {
+	body := []byte(`{"sid": "HS123", "status": "ready", "iccid": "8901"}`)
+
+	twir := TwilioResponse{Status: ResponseStatus{Http: http.StatusAccepted}}
+	err := decodeJSON(UpdateSim{Sid: "HS123", Status: TwiSimActive}, body, &twir)
+	if err != nil {
+		t.Fatalf("decode failed: %v", err)
+	}
+	if twir.Sim == nil || twir.Sim.Iccid != "8901" {
+		t.Fatalf("expected decoded sim, got %#v", twir.Sim)
+	}
+	if twir.SimStatusUpdate == nil || !twir.SimStatusUpdate.Pending ||
+		twir.SimStatusUpdate.Requested != TwiSimActive {
+		t.Errorf("expected pending status update, got %#v", twir.SimStatusUpdate)
+	}
+
+	twir = TwilioResponse{Status: ResponseStatus{Http: http.StatusOK}}
+	err = decodeJSON(UpdateSim{Sid: "HS123", UniqueName: "pump-7"}, body, &twir)
+	if err != nil {
+		t.Fatalf("decode failed: %v", err)
+	}
+	if twir.SimStatusUpdate != nil {
+		t.Errorf("expected no status update, got %#v", twir.SimStatusUpdate)
+	}
+}
+
+func TestJSONException(t *testing.T) {
+	body := []byte(`{"code": 20404, "message": "Not found", "more_info": "https://www.twilio.com/docs/errors/20404", "status": 404}`)
+
+	twir := TwilioResponse{Status: ResponseStatus{Http: http.StatusNotFound}}
+	if err := decodeJSON(FetchSim{Sid: "HS404"}, body, &twir); err != nil {
+		t.Fatalf("decode failed: %v", err)
+	}
+	code, err := exceptionToErr(twir)
+	if code != 20404 || err == nil {
+		t.Errorf("expected exception 20404, got %v, %v", code, err)
+	}
+}
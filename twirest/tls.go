@@ -0,0 +1,155 @@
+package twirest
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// WithTLSConfig makes the client use cfg for its TLS connections, for
+// example to trust other roots or require a TLS version. The client gets its
+// own copy of the transport so clients sharing a transport aren't affected.
+// Pass it before WithPinnedCertificates, it replaces the whole configuration.
+// The TLS configuration of a http client given with WithHTTPClient wins.
+func WithTLSConfig(cfg *tls.Config) ClientOption {
+	return ClientOption{apply: func(c *TwilioClient) {
+		if tr := c.tlsTransport(); tr != nil {
+			tr.TLSClientConfig = cfg.Clone()
+		}
+	}}
+}
+
+// WithRootCAs makes the client trust the certificate authorities of roots
+// instead of the system's, such as the private CA of a TLS intercepting
+// proxy. Like WithTLSConfig it leaves the http client of WithHTTPClient be.
+func WithRootCAs(roots *x509.CertPool) ClientOption {
+	return ClientOption{apply: func(c *TwilioClient) {
+		tr := c.tlsTransport()
+		if tr == nil {
+			return
+		}
+		cfg := &tls.Config{}
+		if tr.TLSClientConfig != nil {
+			cfg = tr.TLSClientConfig.Clone()
+		}
+		cfg.RootCAs = roots
+		tr.TLSClientConfig = cfg
+	}}
+}
+
+// tlsTransport returns the client's own transport to configure TLS on, nil
+// if the http client given with WithHTTPClient configures TLS itself or has
+// a transport other than a *http.Transport
+func (twiClient *TwilioClient) tlsTransport() *http.Transport {
+	if base, _ := twiClient.transport(); twiClient.customHTTP && base != nil {
+		tr, ok := base.(*http.Transport)
+		if !ok || tr.TLSClientConfig != nil {
+			return nil
+		}
+	}
+	return twiClient.ownTransport()
+}
+
+// ErrCertificateNotPinned is returned when none of the certificates twilio
+// presented has a pinned public key. Chain lists the certificates presented,
+// leaf first, as subject and SPKI hash.
+type ErrCertificateNotPinned struct {
+	Chain []string
+}
+
+func (e *ErrCertificateNotPinned) Error() string {
+	return fmt.Sprintf("no pinned certificate in chain: %s",
+		strings.Join(e.Chain, ", "))
+}
+
+// WithPinnedCertificates makes the client reject TLS connections unless a
+// certificate of the verified chain has the public key of one of pins, the
+// base64 of the SHA-256 hash of a SubjectPublicKeyInfo, as used by HPKP.
+// Pin twilio's intermediate CA and its successor so the CA can be rotated.
+// The usual verification of the chain still applies.
+func WithPinnedCertificates(pins []string) ClientOption {
+	return pinCertificates(pins, false)
+}
+
+// WithPinnedCertificatesWarnOnly checks the chain like WithPinnedCertificates
+// but only logs a mismatch, for rolling out pins or an emergency CA change
+func WithPinnedCertificatesWarnOnly(pins []string) ClientOption {
+	return pinCertificates(pins, true)
+}
+
+func pinCertificates(pins []string, warnOnly bool) ClientOption {
+	pinned := map[string]bool{}
+	for _, pin := range pins {
+		pinned[pin] = true
+	}
+
+	return ClientOption{apply: func(c *TwilioClient) {
+		tr := c.ownTransport()
+		cfg := &tls.Config{}
+		if tr.TLSClientConfig != nil {
+			cfg = tr.TLSClientConfig.Clone()
+		}
+		verify := cfg.VerifyPeerCertificate
+
+		cfg.VerifyPeerCertificate = func(rawCerts [][]byte,
+			chains [][]*x509.Certificate) error {
+
+			if verify != nil {
+				if err := verify(rawCerts, chains); err != nil {
+					return err
+				}
+			}
+			err := checkPins(pinned, rawCerts, chains)
+			if err != nil && warnOnly {
+				c.warnf("twilio certificate pinning: %v", err)
+				return nil
+			}
+			return err
+		}
+		tr.TLSClientConfig = cfg
+	}}
+}
+
+// checkPins returns an error unless a certificate of chains has a pinned
+// key. Without verified chains, as with InsecureSkipVerify, the presented
+// certificates are checked.
+func checkPins(pinned map[string]bool, rawCerts [][]byte,
+	chains [][]*x509.Certificate) error {
+
+	if len(chains) == 0 {
+		var presented []*x509.Certificate
+		for _, raw := range rawCerts {
+			cert, err := x509.ParseCertificate(raw)
+			if err != nil {
+				return err
+			}
+			presented = append(presented, cert)
+		}
+		chains = [][]*x509.Certificate{presented}
+	}
+
+	for _, chain := range chains {
+		for _, cert := range chain {
+			if pinned[spkiHash(cert)] {
+				return nil
+			}
+		}
+	}
+
+	e := &ErrCertificateNotPinned{}
+	for _, cert := range chains[0] {
+		e.Chain = append(e.Chain, fmt.Sprintf("%s (%s)",
+			cert.Subject.CommonName, spkiHash(cert)))
+	}
+	return e
+}
+
+// spkiHash returns the base64 SHA-256 hash of the public key of cert
+func spkiHash(cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
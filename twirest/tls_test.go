@@ -0,0 +1,160 @@
+package twirest
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testCert creates a certificate for name signed by parent, self-signed if
+// parent is nil
+func testCert(t *testing.T, name string, parent *tls.Certificate) tls.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+
+	signer, signKey := tmpl, interface{}(key)
+	if parent == nil {
+		tmpl.IsCA = true
+		tmpl.BasicConstraintsValid = true
+		tmpl.KeyUsage = x509.KeyUsageCertSign
+	} else {
+		tmpl.IPAddresses = []net.IP{net.ParseIP("127.0.0.1")}
+		tmpl.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}
+		signer, signKey = parent.Leaf, parent.PrivateKey
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, signer, &key.PublicKey, signKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	leaf, _ := x509.ParseCertificate(der)
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: leaf}
+}
+
+func TestPinnedCertificates(t *testing.T) {
+	ca := testCert(t, "Test CA", nil)
+	other := testCert(t, "Other CA", nil)
+	server := testCert(t, "api.twilio.test", &ca)
+
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {}))
+	ts.TLS = &tls.Config{Certificates: []tls.Certificate{server}}
+	// the handshakes the pins reject aren't errors of the test
+	ts.Config.ErrorLog = log.New(io.Discard, "", 0)
+	ts.StartTLS()
+	defer ts.Close()
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.Leaf)
+	trust := WithTLSConfig(&tls.Config{RootCAs: roots})
+
+	var tests = []struct {
+		Pin     ClientOption
+		Pinned  bool
+		Warning bool
+	}{
+		{WithPinnedCertificates([]string{spkiHash(ca.Leaf)}), true, false},
+		{WithPinnedCertificates([]string{spkiHash(other.Leaf), spkiHash(ca.Leaf)}), true, false},
+		{WithPinnedCertificates([]string{spkiHash(server.Leaf)}), true, false},
+		{WithPinnedCertificates([]string{spkiHash(other.Leaf)}), false, false},
+		{WithPinnedCertificatesWarnOnly([]string{spkiHash(other.Leaf)}), true, true},
+		{WithPinnedCertificatesWarnOnly([]string{spkiHash(ca.Leaf)}), true, false},
+	}
+
+	for idx, test := range tests {
+		logged := &lockedBuffer{}
+		warnings := WithLogger(LoggerFunc(func(format string, args ...interface{}) {
+			fmt.Fprintf(logged, format+"\n", args...)
+		}))
+		client, err := NewClient("AC123", "token", trust, test.Pin, warnings)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		resp, err := client.Do("GET", ts.URL, nil)
+		if err == nil {
+			resp.Body.Close()
+		}
+
+		var notPinned *ErrCertificateNotPinned
+		if test.Pinned && err != nil {
+			t.Errorf("Test %v failed; expected success, got %v", idx, err)
+		} else if !test.Pinned {
+			if !errors.As(err, &notPinned) {
+				t.Errorf("Test %v failed; expected ErrCertificateNotPinned, got %v", idx, err)
+			} else if len(notPinned.Chain) != 2 ||
+				!strings.HasPrefix(notPinned.Chain[0], "api.twilio.test (") ||
+				!strings.HasPrefix(notPinned.Chain[1], "Test CA (") {
+				t.Errorf("Test %v failed; expected the chain in %v", idx, notPinned.Chain)
+			}
+		}
+		if warned := strings.Contains(logged.String(), "no pinned certificate"); warned != test.Warning {
+			t.Errorf("Test %v failed; expected warning %v, got log %#v", idx, test.Warning,
+				logged.String())
+		}
+	}
+}
+
+func TestWithRootCAs(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(xmlHeader + "<TwilioResponse></TwilioResponse>"))
+		}))
+	defer ts.Close()
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ts.Certificate())
+	other := x509.NewCertPool()
+	other.AddCert(testCert(t, "Other CA", nil).Leaf)
+
+	var tests = []struct {
+		Opts    []ClientOption
+		Trusted bool
+	}{
+		{nil, false},
+		{[]ClientOption{WithRootCAs(roots)}, true},
+		{[]ClientOption{WithRootCAs(other)}, false},
+		{[]ClientOption{WithTLSConfig(&tls.Config{MinVersion: tls.VersionTLS12}),
+			WithRootCAs(roots)}, true},
+		// the TLS config of the http client given wins
+		{[]ClientOption{WithHTTPClient(ts.Client()), WithRootCAs(other)}, true},
+		{[]ClientOption{WithHTTPClient(&http.Client{}), WithRootCAs(roots)}, true},
+	}
+
+	for idx, test := range tests {
+		opts := append([]ClientOption{WithBaseURL(ts.URL)}, test.Opts...)
+		client, err := NewClient("AC123", "token", opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = client.Request(Queues{}, false)
+		if (err == nil) != test.Trusted {
+			t.Errorf("Test %v failed; expected trusted %v, got %v", idx, test.Trusted, err)
+		}
+	}
+	if defaultTransport.TLSClientConfig != nil && defaultTransport.TLSClientConfig.RootCAs != nil {
+		t.Errorf("expected the default transport unchanged")
+	}
+}
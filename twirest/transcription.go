@@ -0,0 +1,58 @@
+package twirest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrTranscriptPending is returned by RecordingWithTranscript when the
+// transcription of the recording is still being processed
+var ErrTranscriptPending = errors.New("transcription pending")
+
+// RecordingInfo is a recording with its transcription, Transcription is nil
+// if the recording isn't transcribed
+type RecordingInfo struct {
+	Recording     RecordingResponse
+	Transcription *TranscriptionResponse
+}
+
+// RecordingWithTranscript fetches the recording recordingSid and the text of
+// its transcription. The text is empty if the recording isn't transcribed.
+// ErrTranscriptPending is returned, with the recording, while the
+// transcription is in progress.
+func (twiClient *TwilioClient) RecordingWithTranscript(recordingSid string) (
+	RecordingInfo, string, error) {
+
+	ctx := context.Background()
+	var info RecordingInfo
+	rec, err := twiClient.RecordingInfo(ctx, recordingSid)
+	if err != nil {
+		return info, "", err
+	}
+	info.Recording = *rec
+	if rec.SubResourceUris == nil || rec.SubResourceUris.Transcriptions == "" {
+		return info, "", nil
+	}
+
+	req := RecordingTranscriptions{Sid: recordingSid}
+	resp, err := twiClient.NextPage(ctx, rec.SubResourceUris.Transcriptions, req)
+	if err != nil {
+		return info, "", err
+	}
+	if resp.Transcriptions == nil || len(resp.Transcriptions.Transcription) == 0 {
+		return info, "", nil
+	}
+
+	tr := resp.Transcriptions.Transcription[0]
+	info.Transcription = &tr
+	switch tr.Status {
+	case TwiCompleted:
+		return info, tr.TranscriptionText, nil
+	case TwiInProgress:
+		return info, "", ErrTranscriptPending
+	case TwiFailed:
+		return info, "", fmt.Errorf("transcription failed: '%s'", tr.Sid)
+	}
+	return info, "", fmt.Errorf("non valid transcription status: '%s'", tr.Status)
+}
@@ -0,0 +1,123 @@
+package twirest
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestRecordingsSourceFilter(t *testing.T) {
+	client, ts := testClient(t, http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.RawQuery != "" {
+				t.Errorf("expected no filter parameters, got %v", r.URL.RawQuery)
+			}
+			w.Write([]byte(xmlHeader + `<TwilioResponse><Recordings>` +
+				`<Recording><Sid>RE1</Sid><Source>DialVerb</Source></Recording>` +
+				`<Recording><Sid>RE2</Sid><Source>RecordVerb</Source></Recording>` +
+				`<Recording><Sid>RE3</Sid><Source>Conference</Source></Recording>` +
+				`<Recording><Sid>RE4</Sid><Source>RecordVerb</Source></Recording>` +
+				`</Recordings></TwilioResponse>`))
+		}))
+	defer ts.Close()
+
+	var tests = []struct {
+		Source string
+		Expect string
+	}{
+		{"", "RE1,RE2,RE3,RE4"},
+		{TwiSourceRecordVerb, "RE2,RE4"},
+		{TwiSourceConference, "RE3"},
+		{TwiSourceTrunking, ""},
+	}
+
+	for idx, test := range tests {
+		resp, err := client.Request(Recordings{Source: test.Source}, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var sids []string
+		for _, rec := range resp.Recordings.Recording {
+			sids = append(sids, rec.Sid)
+		}
+		if strings.Join(sids, ",") != test.Expect {
+			t.Errorf("Test %v failed; expected %v, got %v", idx, test.Expect, sids)
+		}
+	}
+}
+
+func TestRecordingWithTranscript(t *testing.T) {
+	const transcriptions = "/2010-04-01/Accounts/AC123/Recordings/%s/Transcriptions"
+
+	var tests = []struct {
+		Sid            string
+		Transcriptions string
+		Text           string
+		Err            error
+		Valid          bool
+	}{
+		{"RE1", `<Transcription><Sid>TR1</Sid><Status>completed</Status>` +
+			`<TranscriptionText>Hello monkey</TranscriptionText></Transcription>`,
+			"Hello monkey", nil, true},
+		{"RE2", `<Transcription><Sid>TR2</Sid><Status>in-progress</Status></Transcription>`,
+			"", ErrTranscriptPending, false},
+		{"RE3", `<Transcription><Sid>TR3</Sid><Status>failed</Status></Transcription>`,
+			"", nil, false},
+		{"RE4", ``, "", nil, true},
+		{"RE5", "-", "", nil, true},
+	}
+
+	for idx, test := range tests {
+		uri := strings.Replace(transcriptions, "%s", test.Sid, 1)
+		client, ts := testClient(t, http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/2010-04-01/Accounts/AC123/Recordings/" + test.Sid + ".xml":
+					subUris := `<SubresourceUris><Transcriptions>` + uri +
+						`</Transcriptions></SubresourceUris>`
+					if test.Transcriptions == "-" {
+						subUris = ""
+					}
+					w.Write([]byte(xmlHeader + `<TwilioResponse><Recording><Sid>` + test.Sid +
+						`</Sid><Duration>12</Duration>` + subUris + `</Recording></TwilioResponse>`))
+				case uri:
+					w.Write([]byte(xmlHeader + `<TwilioResponse><Transcriptions>` +
+						test.Transcriptions + `</Transcriptions></TwilioResponse>`))
+				default:
+					t.Errorf("Test %v failed; unexpected path %v", idx, r.URL.Path)
+				}
+			}))
+
+		info, text, err := client.RecordingWithTranscript(test.Sid)
+		ts.Close()
+		if (err == nil) != test.Valid || (test.Err != nil && err != test.Err) {
+			t.Errorf("Test %v failed; expected valid %v (%v), got %v", idx, test.Valid, test.Err, err)
+		}
+		if text != test.Text || info.Recording.Sid != test.Sid || info.Recording.Duration != 12 {
+			t.Errorf("Test %v failed; got %#v, %#v", idx, info, text)
+		}
+		if (info.Transcription != nil) != (test.Transcriptions != "" && test.Transcriptions != "-") {
+			t.Errorf("Test %v failed; unexpected transcription %#v", idx, info.Transcription)
+		}
+	}
+}
+
+func TestTranscriptionUrls(t *testing.T) {
+	var tests = []struct {
+		Req    interface{}
+		Expect string
+	}{
+		{Transcriptions{}, "https://api.twilio.com/2010-04-01/Accounts/AC123/Transcriptions"},
+		{Transcription{Sid: "TR123"},
+			"https://api.twilio.com/2010-04-01/Accounts/AC123/Transcriptions/TR123"},
+		{RecordingTranscriptions{Sid: "RE123"},
+			"https://api.twilio.com/2010-04-01/Accounts/AC123/Recordings/RE123/Transcriptions"},
+	}
+
+	for idx, test := range tests {
+		got, err := urlString(test.Req, "AC123")
+		if err != nil || got != test.Expect {
+			t.Errorf("Test %v failed; expected %v, got %v (%v)", idx, test.Expect, got, err)
+		}
+	}
+}
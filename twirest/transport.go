@@ -0,0 +1,76 @@
+package twirest
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// Credentials are the credentials a client authenticates with. AccountSid is
+// always required as it becomes part of the request URLs, set ApiKey to
+// authenticate with an API key Sid and its secret as AuthToken.
+type Credentials struct {
+	AccountSid string
+	ApiKey     string
+	AuthToken  string
+}
+
+// SharedTransport holds a http client whose connections are reused by every
+// client created from it. Use it where credentials change per invocation but
+// the process lives on, such as serverless functions, so a new client doesn't
+// mean a new TLS handshake with twilio.
+type SharedTransport struct {
+	httpclient *http.Client
+}
+
+// defaultShared is used by NewRequestScopedClient when no transport is given
+var defaultShared = &SharedTransport{httpclient: newHttpClient()}
+
+// NewSharedTransport creates a transport to share between clients
+func NewSharedTransport() *SharedTransport {
+	return &SharedTransport{httpclient: &http.Client{Transport: newTransport()}}
+}
+
+// NewRequestScopedClient creates a client with its own credentials that
+// sends its requests over the connections of base. The package wide shared
+// transport is used if base is nil.
+func NewRequestScopedClient(creds Credentials, base *SharedTransport,
+	opts ...ClientOption) (*TwilioClient, error) {
+
+	if creds.AccountSid == "" || creds.AuthToken == "" {
+		return nil, fmt.Errorf("AccountSid and AuthToken are required")
+	}
+	if base == nil {
+		base = defaultShared
+	}
+
+	c := &TwilioClient{
+		httpclient: base.httpclient,
+		accountSid: creds.AccountSid,
+		authUser:   creds.ApiKey,
+		authToken:  creds.AuthToken,
+		owned:      newOwnedNumbers(),
+	}
+	for _, opt := range opts {
+		opt.apply(c)
+	}
+	if c.optErr != nil {
+		return nil, c.optErr
+	}
+	return c, nil
+}
+
+// defaultTransport is the transport of the clients made by NewClient, so they
+// share their connections to twilio. It is a copy of http.DefaultTransport:
+// keep-alives, gzip responses and the proxy of the environment.
+var defaultTransport = newTransport()
+
+// newTransport creates a transport with the defaults of http.DefaultTransport
+func newTransport() *http.Transport {
+	return http.DefaultTransport.(*http.Transport).Clone()
+}
+
+// newHttpClient creates the http client used to talk to twilio, on the
+// shared default transport. Use WithHTTPClient for another transport.
+func newHttpClient() *http.Client {
+	return &http.Client{Transport: defaultTransport}
+}
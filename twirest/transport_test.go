@@ -0,0 +1,151 @@
+package twirest
+
+import (
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// connServer starts a test server counting the connections opened to it
+func connServer() (*httptest.Server, *int64) {
+	var conns int64
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			io.WriteString(w, "<TwilioResponse></TwilioResponse>")
+		}))
+	ts.Config.ConnState = func(c net.Conn, s http.ConnState) {
+		if s == http.StateNew {
+			atomic.AddInt64(&conns, 1)
+		}
+	}
+	ts.Start()
+	return ts, &conns
+}
+
+// invoke simulates one serverless invocation using client
+func invoke(tb testing.TB, client *TwilioClient, url string) {
+	resp, err := client.Do("GET", url, nil)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	io.Copy(ioutil.Discard, resp.Body)
+	resp.Body.Close()
+}
+
+func TestRequestScopedClientReusesConnections(t *testing.T) {
+	ts, conns := connServer()
+	defer ts.Close()
+
+	shared := NewSharedTransport()
+	for i := 0; i < 10; i++ {
+		client, err := NewRequestScopedClient(Credentials{
+			AccountSid: "AC123",
+			AuthToken:  "token",
+		}, shared)
+		if err != nil {
+			t.Fatal(err)
+		}
+		invoke(t, client, ts.URL)
+	}
+
+	if n := atomic.LoadInt64(conns); n != 1 {
+		t.Errorf("expected 1 connection for 10 invocations, got %v", n)
+	}
+}
+
+func TestRequestScopedClientCredentials(t *testing.T) {
+	var user, pass string
+	ts := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			user, pass, _ = r.BasicAuth()
+		}))
+	defer ts.Close()
+
+	shared := NewSharedTransport()
+	var tests = []struct {
+		Creds        Credentials
+		User, Secret string
+	}{
+		{Credentials{AccountSid: "AC1", AuthToken: "t1"}, "AC1", "t1"},
+		{Credentials{AccountSid: "AC2", ApiKey: "SK2", AuthToken: "s2"}, "SK2", "s2"},
+	}
+
+	for idx, test := range tests {
+		client, err := NewRequestScopedClient(test.Creds, shared)
+		if err != nil {
+			t.Fatal(err)
+		}
+		invoke(t, client, ts.URL)
+		if user != test.User || pass != test.Secret {
+			t.Errorf("Test %v failed; got auth %v:%v", idx, user, pass)
+		}
+	}
+
+	if _, err := NewRequestScopedClient(Credentials{AccountSid: "AC1"}, nil); err == nil {
+		t.Errorf("expected error for missing AuthToken")
+	}
+}
+
+func BenchmarkNewClientPerInvocation(b *testing.B) {
+	ts, conns := connServer()
+	defer ts.Close()
+
+	for i := 0; i < b.N; i++ {
+		client, _ := NewClient("AC123", "token")
+		invoke(b, client, ts.URL)
+	}
+	b.ReportMetric(float64(atomic.LoadInt64(conns)), "conns")
+}
+
+func BenchmarkRequestScopedClient(b *testing.B) {
+	ts, conns := connServer()
+	defer ts.Close()
+
+	shared := NewSharedTransport()
+	for i := 0; i < b.N; i++ {
+		client, _ := NewRequestScopedClient(Credentials{
+			AccountSid: "AC123",
+			AuthToken:  "token",
+		}, shared)
+		invoke(b, client, ts.URL)
+	}
+	b.ReportMetric(float64(atomic.LoadInt64(conns)), "conns")
+}
+
+func TestDefaultTransport(t *testing.T) {
+	var encoding string
+	ts := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			encoding = r.Header.Get("Accept-Encoding")
+			w.Header().Set("Content-Encoding", "gzip")
+			gz := gzip.NewWriter(w)
+			io.WriteString(gz, xmlHeader+"<TwilioResponse><Messages>"+
+				strings.Repeat("<Message><Sid>SM1</Sid></Message>", 1000)+
+				"</Messages></TwilioResponse>")
+			gz.Close()
+		}))
+	defer ts.Close()
+
+	client, err := NewClient("AC123", "token", WithBaseURL(ts.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, _ := NewClient("AC456", "token")
+	if client.httpclient.Transport != other.httpclient.Transport {
+		t.Errorf("expected clients to share the default transport")
+	}
+
+	resp, err := client.Request(Messages{}, false)
+	if err != nil || resp.Messages == nil || len(resp.Messages.Message) != 1000 {
+		t.Fatalf("expected the gzip response decoded, got %v", err)
+	}
+	if encoding != "gzip" {
+		t.Errorf("expected gzip accepted, got %#v", encoding)
+	}
+}
@@ -6,17 +6,21 @@
 package twirest
 
 import (
-	"crypto/tls"
+	"bytes"
+	"context"
 	//"crypto/x509"
+	"encoding/json"
 	"encoding/xml"
 	"fmt"
 	"io"
 	"io/ioutil"
-	"log"
 	"net/http"
 	"net/url"
 	"reflect"
+	"strconv"
 	"strings"
+
+	"github.com/seanhagen/twilio/twiml"
 )
 
 const ApiVer string = "2010-04-01"
@@ -26,221 +30,745 @@ const (
 	value = 1
 )
 
-// TwilioClient struct for holding a http client and user credentials
+// TwilioClient struct for holding a http client and user credentials. A
+// client is safe for concurrent use by multiple goroutines: its configuration
+// is fixed by NewClient and the state shared by its requests, such as the rate
+// limiter and the caches, is guarded. The Logger, Metrics and hooks given to
+// it are called from the goroutines making requests.
 type TwilioClient struct {
 	httpclient *http.Client
+	// customHTTP is set when the http client was given with WithHTTPClient
+	customHTTP bool
 	accountSid string
 	authUser   string
 	authToken  string
+	// owned caches the numbers the account may send from
+	owned        *ownedNumbers
+	validateFrom bool
+	// lenientNumbers skips the E.164 check of To and From, see checkNumbers
+	lenientNumbers bool
+	// connected is set when acting on behalf of a connect app customer
+	connected bool
+	dedupe    *dedupeGuard
+	media     *mediaPolicy
+
+	queueEstimate queueEstimate
+	retry         *RetryOptions
+	limiter       *rateLimiter
+	logger        Logger
+	clock         Clock
+	// userAgentSuffix is appended to the User-Agent of the requests
+	userAgentSuffix string
+	requestHooks    []RequestHook
+	responseHooks   []ResponseHook
+	metrics         Metrics
+	debug           *debugWriter
+	// region and edge are the twilio locality of the requests
+	region string
+	edge   string
+	// baseURL replaces the scheme and host of requests to twilio
+	baseURL *url.URL
+	// format is the format of the responses of unpinned request types
+	format Format
+	// optErr is the error of a ClientOption
+	optErr error
 }
 
-// Create a new client. With two arguments, it's assumed you're passing AccountSID & AuthToken.
-// With three arguments, it's assumed the first is the AccountSID, the second is APIKeySID,
-// and the third is APIKeyToken. This is done so that you can use API keys with the client -- AccountSID
-// is always required, as it becomes part of the URL that is built to make requests of the API.
-func NewClient(authBits ...string) (*TwilioClient, error) {
-	tr := &http.Transport{
-		TLSClientConfig:    &tls.Config{RootCAs: nil},
-		DisableCompression: true,
-	}
-	client := &http.Client{Transport: tr}
+// NewClient creates a client authenticating with the AccountSID and
+// AuthToken of an account, configured by opts. Use NewAPIKeyClient to
+// authenticate with an API key, it replaces the three string form
+// NewClient(accountSid, apiKeySid, apiKeySecret).
+func NewClient(accountSid, authToken string, opts ...ClientOption) (*TwilioClient, error) {
+	return newClient(accountSid, "", authToken, opts)
+}
 
-	if len(authBits) <= 1 || len(authBits) > 3 {
-		return nil, fmt.Errorf("2 or 3 arguments only")
-	}
+// NewAPIKeyClient creates a client authenticating with an API key and its
+// secret, configured by opts. The AccountSID is still required, it's part of
+// the url of the requests.
+func NewAPIKeyClient(accountSid, apiKeySid, apiKeySecret string,
+	opts ...ClientOption) (*TwilioClient, error) {
+
+	return newClient(accountSid, apiKeySid, apiKeySecret, opts)
+}
+
+// newClient creates a client authenticating as authUser, the account if
+// empty, and applies opts
+func newClient(accountSid, authUser, authToken string, opts []ClientOption) (
+	*TwilioClient, error) {
 
 	c := TwilioClient{
-		httpclient: client,
-		accountSid: authBits[0],
+		httpclient: newHttpClient(),
+		accountSid: accountSid,
+		authUser:   authUser,
+		authToken:  authToken,
+		owned:      newOwnedNumbers(),
 	}
 
-	if len(authBits) == 2 {
-		c.authToken = authBits[1]
-	} else {
-		c.authUser = authBits[1]
-		c.authToken = authBits[2]
+	for _, opt := range opts {
+		opt.apply(&c)
+	}
+	if c.optErr != nil {
+		return nil, c.optErr
 	}
 
 	return &c, nil
 }
 
+// setAuth adds the basic authentication of the client to the http request
+func (twiClient *TwilioClient) setAuth(httpReq *http.Request) {
+	if twiClient.authUser != "" {
+		httpReq.SetBasicAuth(twiClient.authUser, twiClient.authToken)
+	} else {
+		httpReq.SetBasicAuth(twiClient.accountSid, twiClient.authToken)
+	}
+}
+
 func (twiClient *TwilioClient) Do(method, url string, body io.Reader) (*http.Response, error) {
 	httpReq, err := http.NewRequest(method, url, body)
 	if err != nil {
 		return nil, err
 	}
 
-	if twiClient.authUser != "" {
-		httpReq.SetBasicAuth(twiClient.authUser, twiClient.authToken)
-	} else {
-		httpReq.SetBasicAuth(twiClient.accountSid, twiClient.authToken)
+	twiClient.setAuth(httpReq)
+	if body != nil {
+		httpReq.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 	}
-	httpReq.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 	httpReq.Header.Set("Accept", "*/*")
 
-	return twiClient.httpclient.Do(httpReq)
+	return twiClient.do(httpReq)
+}
+
+// do sends httpReq with the http client, to the region and edge of the
+// client, or to the base url if one is set
+func (twiClient *TwilioClient) do(httpReq *http.Request) (*http.Response, error) {
+	twiClient.setUserAgent(httpReq)
+	if host := regionalHost(httpReq.URL.Host, twiClient.edge, twiClient.region); host !=
+		httpReq.URL.Host {
+		u := *httpReq.URL
+		u.Host = host
+		httpReq.URL = &u
+		httpReq.Host = ""
+	}
+	if base := twiClient.baseURL; base != nil &&
+		strings.HasSuffix(httpReq.URL.Hostname(), "twilio.com") {
+		u := *httpReq.URL
+		u.Scheme = base.Scheme
+		u.Host = base.Host
+		u.Path = strings.TrimSuffix(base.Path, "/") + u.Path
+		u.RawPath = ""
+		httpReq.URL = &u
+		httpReq.Host = ""
+	}
+	return twiClient.sendHooked(httpReq)
 }
 
 // Request makes a REST resource or action request from twilio servers and
 // returns the response. The type of request is determined by the request
 // struct supplied.
+//
+// When twilio responds with an exception the response is returned along
+// with the error: Status holds the http status and the twilio error code,
+// Exception the exception, and the resources twilio included, if any, are
+// parsed like in a successful response. The error is a *TwilioError, use
+// errors.As to get it or errors.Is with the sentinel errors. The methods
+// that return a single resource, such as RecordingInfo, only return the
+// error.
 func (twiClient *TwilioClient) Request(reqStruct interface{}, logit bool) (
 	TwilioResponse, error) {
 
-	twiResp := TwilioResponse{}
+	return twiClient.RequestWithContext(context.Background(), reqStruct, logit)
+}
+
+// RequestWithContext is like Request but the request is bound to ctx, it is
+// aborted if ctx is canceled before the response is received. A request of
+// OnSubaccount is made on the subaccount.
+func (twiClient *TwilioClient) RequestWithContext(ctx context.Context,
+	reqStruct interface{}, logit bool) (TwilioResponse, error) {
 
-	// setup a POST/GET/DELETE http request from request struct
-	httpReq, err := httpRequest(reqStruct, twiClient.accountSid, logit)
+	reqStruct, err := requestStruct(reqStruct)
 	if err != nil {
-		return twiResp, err
+		return TwilioResponse{}, err
 	}
-	// add authentication and headers to the http request
-	if logit {
-		log.Printf("Setting basic auth to username %#v, password %#v", twiClient.accountSid, twiClient.authToken)
+	if sub, ok := reqStruct.(SubaccountRequest); ok {
+		if sub.Request, err = requestStruct(sub.Request); err != nil {
+			return TwilioResponse{}, err
+		}
+		if err := subaccountRequest(sub); err != nil {
+			return TwilioResponse{}, err
+		}
+		return twiClient.request(ctx, sub.AccountSid, sub.Request, logit)
 	}
+	return twiClient.request(ctx, twiClient.accountSid, reqStruct, logit)
+}
 
-	if twiClient.authUser != "" {
-		httpReq.SetBasicAuth(twiClient.authUser, twiClient.authToken)
-	} else {
-		httpReq.SetBasicAuth(twiClient.accountSid, twiClient.authToken)
+// requestStruct returns the request struct reqStruct points to, or reqStruct
+// if it isn't a pointer, and an error if it isn't a request struct. Any other
+// type would make a GET request of /Accounts.
+func requestStruct(reqStruct interface{}) (interface{}, error) {
+	if v := reflect.ValueOf(reqStruct); v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil, fmt.Errorf("non valid request: nil %T", reqStruct)
+		}
+		reqStruct = v.Elem().Interface()
 	}
+	t := reflect.TypeOf(reqStruct)
+	if t == nil || !isRequestType(t) && !foreignRequest(t) &&
+		t != reflect.TypeOf(SubaccountRequest{}) {
+		return nil, fmt.Errorf("non valid request type: '%T'", reqStruct)
+	}
+	return reqStruct, nil
+}
 
-	httpReq.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	httpReq.Header.Set("Accept", "*/*")
+// request makes the request on the resources of accountSid, authenticating
+// with the credentials of the client
+func (twiClient *TwilioClient) request(ctx context.Context, accountSid string,
+	reqStruct interface{}, logit bool) (twiResp TwilioResponse, err error) {
+
+	if twiClient.metrics != nil {
+		defer twiClient.observe(reqStruct, twiClient.timeSource().Now(), &twiResp)
+	}
 
-	response, err := twiClient.httpclient.Do(httpReq)
+	logger, logBody := twiClient.requestLogger(logit)
+
+	// setup a POST/GET/DELETE http request from request struct, validated
+	// before its From is looked up
+	httpReq, err := httpRequest(reqStruct, accountSid, logger)
+	if err != nil {
+		return TwilioResponse{}, err
+	}
+	if !twiClient.lenientNumbers {
+		if err := checkNumbers(reqStruct); err != nil {
+			return TwilioResponse{}, err
+		}
+	}
+	if twiClient.validateFrom {
+		if err := twiClient.checkFrom(ctx, reqStruct); err != nil {
+			return TwilioResponse{}, err
+		}
+	}
+	setIdempotencyKey(ctx, httpReq)
+	format := twiClient.requestFormat(reqStruct)
+	if format == FormatJSON && !isJSONRequest(reqStruct) {
+		requestJSON(httpReq)
+	}
+
+	if err := twiClient.limiter.wait(ctx); err != nil {
+		return TwilioResponse{}, err
+	}
+
+	key, err := twiClient.dedupe.claim(ctx, reqStruct)
+	if err != nil {
+		return TwilioResponse{}, err
+	}
+
+	twiResp, err = twiClient.send(httpReq.WithContext(ctx), reqStruct, format, logger,
+		logBody)
+	twiClient.dedupe.done(ctx, key, twiResp, err)
+	if twiResp.OK() {
+		twiClient.owned.update(reqStruct)
+	}
+	if twiClient.connected && twiResp.Status.Twilio == codeAccessDenied {
+		err = &ErrConnectAccessDenied{
+			AccountSid: accountSid,
+			Exception:  twiResp.Exception,
+		}
+	}
+	return twiResp, err
+}
+
+// NextPage requests the page of a list at uri, the NextPageUri of a list
+// response, and parses it like the response to reqStruct, the request of the
+// first page
+func (twiClient *TwilioClient) NextPage(ctx context.Context, uri string,
+	reqStruct interface{}) (TwilioResponse, error) {
+
+	return twiClient.requestUri(ctx, uri, reqStruct)
+}
+
+// requestUri makes a GET request of a resource uri returned by twilio, such
+// as the next page of a list, and parses the response like reqStruct's
+func (twiClient *TwilioClient) requestUri(ctx context.Context, uri string,
+	reqStruct interface{}) (TwilioResponse, error) {
+
+	httpReq, err := http.NewRequest("GET", "https://api.twilio.com"+uri, nil)
+	if err != nil {
+		return TwilioResponse{}, err
+	}
+	setHeaders(httpReq, reqStruct)
+	format := twiClient.requestFormat(reqStruct)
+	if format == FormatJSON && !isJSONRequest(reqStruct) {
+		requestJSON(httpReq)
+	}
+	logger, _ := twiClient.requestLogger(false)
+	return twiClient.send(httpReq.WithContext(ctx), reqStruct, format, logger, false)
+}
+
+// send adds authentication to the http request, sends it and
+// parses the response of the format according to the type of the request
+// struct. The request is logged to logger if not nil, the response body if
+// logBody is set.
+func (twiClient *TwilioClient) send(httpReq *http.Request, reqStruct interface{},
+	format Format, logger Logger, logBody bool) (TwilioResponse, error) {
+
+	twiResp := TwilioResponse{}
+
+	// add authentication and headers to the http request
+	if logger != nil {
+		user := twiClient.authUser
+		if user == "" {
+			user = twiClient.accountSid
+		}
+		logger.Printf("Setting basic auth to username %#v, password REDACTED", user)
+	}
+
+	twiClient.setAuth(httpReq)
+
+	response, attempts, err := twiClient.doRetry(httpReq)
+	twiResp.Status.Attempts = attempts
 	if err != nil {
 		return twiResp, err
 	}
 
-	// Save http status code to response struct
+	// Save http status code and headers to response struct
 	twiResp.Status.Http = response.StatusCode
+	twiResp.setHeaders(response.Header, twiClient.timeSource().Now())
 
-	format := response.Header.Get("Content-Type")
-	if format == "audio/mpeg" || format == "audio/x-wav" {
+	contentType := response.Header.Get("Content-Type")
+	if contentType == "audio/mpeg" || contentType == "audio/x-wav" {
 		twiResp.RecordingAudio = &RecordingAudio{
 			Data: response.Body,
 		}
 		return twiResp, err
 	}
+	twiResp.Status.Format = format
+
+	// large JSON lists are decoded as they are read instead of held whole,
+	// their body isn't logged
+	var body []byte
+	if isJSONRequest(reqStruct) && twiResp.OK() && !isDeleteRequest(reqStruct) {
+		body, _ = ioutil.ReadAll(io.LimitReader(response.Body, jsonStreamThreshold+1))
+		if int64(len(body)) > jsonStreamThreshold {
+			err = decodeJSONStream(reqStruct,
+				io.MultiReader(bytes.NewReader(body), response.Body), &twiResp)
+			response.Body.Close()
+			return twiResp, err
+		}
+	}
 
-	body, _ := ioutil.ReadAll(response.Body)
+	if body == nil {
+		body, _ = ioutil.ReadAll(response.Body)
+	}
 	response.Body.Close()
-	if logit {
-		log.Printf("got body:\n\n%v\n\n", string(body))
+	if logger != nil && logBody {
+		logger.Printf("got body:\n\n%v\n\n", string(body))
 	}
 
-	// don't try to parse XML that isn't there ( delete requests return no content )
-	if isDeleteRequest(reqStruct) && twiResp.OK() {
-		return twiResp, err
+	// don't try to parse a body that isn't there, no content is a success
+	// ( delete requests return no content, or an empty 200 through proxies )
+	if twiResp.Status.Http == http.StatusNoContent ||
+		(isDeleteRequest(reqStruct) && twiResp.OK()) {
+		return twiResp, nil
 	}
 
-	// parse xml response into twilioResponse struct
-	err = xml.Unmarshal(body, &twiResp)
+	// resources outside the 2010 API respond with JSON, those of the 2010
+	// API when asked to
+	if format == FormatJSON {
+		err = decodeJSON(reqStruct, body, &twiResp)
+		if err != nil {
+			return twiResp, newResponseError(twiResp.Status.Http, body, err)
+		}
+		filterResponse(reqStruct, &twiResp)
+		twiResp.Status.Twilio, err = exceptionToErr(twiResp)
+		return twiResp, geoPermissionErr(reqStruct, err)
+	}
+
+	// parse xml response into twilioResponse struct, a body that isn't a
+	// twilio response is an error whatever the status
+	err = decodeXML(body, &twiResp)
 	if err != nil {
-		return twiResp, err
+		return twiResp, newResponseError(twiResp.Status.Http, body, err)
+	}
+	filterResponse(reqStruct, &twiResp)
+	if !twiResp.OK() && twiResp.Exception == nil {
+		return twiResp, newResponseError(twiResp.Status.Http, body, nil)
 	}
 	twiResp.Status.Twilio, err = exceptionToErr(twiResp)
-	return twiResp, err
+	return twiResp, geoPermissionErr(reqStruct, err)
+}
+
+// filterResponse applies the filters of the request struct that the API
+// lacks to the response
+func filterResponse(reqStruct interface{}, twir *TwilioResponse) {
+	switch reqSt := reqStruct.(type) {
+	case Recordings:
+		if reqSt.Source == "" || twir.Recordings == nil {
+			return
+		}
+		recs := twir.Recordings.Recording[:0]
+		for _, rec := range twir.Recordings.Recording {
+			if rec.Source == reqSt.Source {
+				recs = append(recs, rec)
+			}
+		}
+		twir.Recordings.Recording = recs
+	}
 }
 
-// exceptiontToErr converts a Twilio response exception (if any) to a go error
+// decodeXML parses a xml response body into the TwilioResponse. Resources
+// are normally wrapped in a <TwilioResponse> root element, but some endpoints
+// and error pages return the resource or the RestException as the root. Those
+// are decoded into the TwilioResponse field with the same xml name.
+func decodeXML(body []byte, twir *TwilioResponse) error {
+	root, err := rootElement(body)
+	if err != nil {
+		return err
+	}
+	if root == "TwilioResponse" {
+		return xml.Unmarshal(body, twir)
+	}
+
+	t := reflect.TypeOf(*twir)
+	for i := 0; i < t.NumField(); i++ {
+		fld := t.Field(i)
+		if fld.Tag.Get("xml") != root || fld.Type.Kind() != reflect.Ptr {
+			continue
+		}
+		v := reflect.New(fld.Type.Elem())
+		if err := xml.Unmarshal(body, v.Interface()); err != nil {
+			return err
+		}
+		reflect.ValueOf(twir).Elem().Field(i).Set(v)
+		return nil
+	}
+	return fmt.Errorf("unknown response element: '%s'", root)
+}
+
+// rootElement returns the name of the root element of a xml document
+func rootElement(body []byte) (string, error) {
+	dec := xml.NewDecoder(bytes.NewReader(body))
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return "", err
+		}
+		if se, ok := tok.(xml.StartElement); ok {
+			return se.Name.Local, nil
+		}
+	}
+}
+
+// exceptiontToErr converts a Twilio response exception (if any) to a
+// *TwilioError
 func exceptionToErr(twir TwilioResponse) (code int, err error) {
 	if twir.Exception != nil {
 		twir.Exception.Parse()
-		return twir.Exception.Code, twir.Exception
+		return twir.Exception.Code, newTwilioError(twir.Exception)
 	}
 	return
 }
 
+// isJSONRequest reports if the request struct targets a resource outside the
+// 2010 API, these are declared with a domain tag and respond with JSON
+func isJSONRequest(reqStruct interface{}) bool {
+	t := reflect.TypeOf(reqStruct)
+	if t == nil || t.Kind() != reflect.Struct {
+		return false
+	}
+	_, ok := t.FieldByName("domain")
+	return ok
+}
+
+// decodeJSON parses a JSON response body into the field of the
+// TwilioResponse that matches the request struct
+func decodeJSON(reqStruct interface{}, body []byte, twir *TwilioResponse) error {
+	if !twir.OK() {
+		jex := jsonException{}
+		if err := json.Unmarshal(body, &jex); err != nil {
+			return err
+		}
+		twir.Exception = &ExceptionResponse{
+			Code:     jex.Code,
+			Message:  jex.Message,
+			MoreInfo: jex.MoreInfo,
+			Status:   strconv.Itoa(jex.Status),
+		}
+		decodePartialJSON(reqStruct, body, twir)
+		return nil
+	}
+
+	v, err := jsonTarget(reqStruct, twir)
+	if err != nil {
+		return err
+	}
+	if !isJSONRequest(reqStruct) {
+		return decodeLegacyJSON(body, v)
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		return err
+	}
+
+	if req, ok := reqStruct.(UpdateSim); ok {
+		twir.SimStatusUpdate = simStatusUpdate(req, *twir)
+	}
+	return nil
+}
+
+// decodePartialJSON parses the fields of a JSON exception body other than
+// the exception's into the response of the request struct. What doesn't
+// parse is left out, the exception is what matters.
+func decodePartialJSON(reqStruct interface{}, body []byte, twir *TwilioResponse) {
+	fields := map[string]json.RawMessage{}
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return
+	}
+	for _, key := range []string{"code", "message", "more_info", "status"} {
+		delete(fields, key)
+	}
+	if len(fields) == 0 {
+		return
+	}
+
+	v, err := jsonTarget(reqStruct, twir)
+	if err != nil {
+		return
+	}
+	rest, _ := json.Marshal(fields)
+	json.Unmarshal(rest, v)
+}
+
+// jsonTarget sets the field of the TwilioResponse that matches the request
+// struct to a new response and returns it to decode into
+func jsonTarget(reqStruct interface{}, twir *TwilioResponse) (interface{}, error) {
+	var v interface{}
+	switch req := reqStruct.(type) {
+	default:
+		return nil, fmt.Errorf("no JSON response for request: '%T'", req)
+	case ListSims:
+		twir.Sims = new(SimsResponse)
+		v = twir.Sims
+	case FetchSim, UpdateSim:
+		twir.Sim = new(SimResponse)
+		v = twir.Sim
+	case SimUsageRecords:
+		twir.SimUsageRecords = new(SimUsageRecordsResponse)
+		v = twir.SimUsageRecords
+	case ListAlerts:
+		twir.Alerts = new(AlertsResponse)
+		v = twir.Alerts
+	case GetAlert:
+		twir.Alert = new(AlertResponse)
+		v = twir.Alert
+	case ListEvents:
+		twir.Events = new(EventsResponse)
+		v = twir.Events
+	case GetEvent:
+		twir.Event = new(EventResponse)
+		v = twir.Event
+	case ListByocTrunks:
+		twir.ByocTrunks = new(ByocTrunksResponse)
+		v = twir.ByocTrunks
+	case FetchByocTrunk, CreateByocTrunk, UpdateByocTrunk:
+		twir.ByocTrunk = new(ByocTrunkResponse)
+		v = twir.ByocTrunk
+	case ListPublicKeys:
+		twir.PublicKeys = new(PublicKeysResponse)
+		v = twir.PublicKeys
+	case FetchPublicKey, CreatePublicKey, UpdatePublicKey:
+		twir.PublicKey = new(PublicKeyResponse)
+		v = twir.PublicKey
+	case CallSummary:
+		twir.CallSummary = new(CallSummaryResponse)
+		v = twir.CallSummary
+	case Lookup:
+		twir.Lookup = new(LookupResponse)
+		v = twir.Lookup
+	case DialingCountries:
+		twir.DialingCountries = new(DialingCountriesResponse)
+		v = twir.DialingCountries
+	case DialingCountry:
+		twir.DialingCountry = new(DialingCountryResponse)
+		v = twir.DialingCountry
+	case UpdateDialingPermissions:
+		twir.DialingUpdate = new(DialingUpdateResponse)
+		v = twir.DialingUpdate
+	// the 2010 API, see WithFormat
+	case Messages:
+		twir.Messages = new(MessagesResponse)
+		v = twir.Messages
+	case Message, SendMessage:
+		twir.Message = new(MessageResponse)
+		v = twir.Message
+	case Calls:
+		twir.Calls = new(CallsResponse)
+		v = twir.Calls
+	case Call, MakeCall, ModifyCall:
+		twir.Call = new(CallResponse)
+		v = twir.Call
+	case Recordings:
+		twir.Recordings = new(RecordingsResponse)
+		v = twir.Recordings
+	case Recording:
+		twir.Recording = new(RecordingResponse)
+		v = twir.Recording
+	case IncomingPhoneNumberList:
+		twir.IncomingPhoneNumbers = new(IncomingPhoneNumbersResponse)
+		v = twir.IncomingPhoneNumbers
+	case CreateIncomingPhoneNumber, UpdateIncomingPhoneNumber:
+		twir.IncomingPhoneNumber = new(IncomingPhoneNumberResponse)
+		v = twir.IncomingPhoneNumber
+	case Queues:
+		twir.Queues = new(QueuesResponse)
+		v = twir.Queues
+	case Queue, CreateQueue, ChangeQueue:
+		twir.Queue = new(QueueResponse)
+		v = twir.Queue
+	}
+	return v, nil
+}
+
 func isDeleteRequest(reqStruct interface{}) bool {
+	return requestMethod(reqStruct) == "DELETE"
+}
+
+// requestMethod returns the http method of the request struct
+func requestMethod(reqStruct interface{}) string {
 	switch reqStruct.(type) {
 	case DeleteNotification, DeleteOutgoingCallerId,
-		DeleteRecording, DeleteParticipant, DeleteQueue:
-		return true
+		DeleteRecording, DeleteParticipant, DeleteQueue, DeleteByocTrunk,
+		DeleteIncomingPhoneNumber, DeletePublicKey:
+		return "DELETE"
+	case SendMessage, MakeCall, ModifyCall, CreateQueue, ChangeQueue,
+		DeQueue, UpdateParticipant, UpdateOutgoingCallerId,
+		CreateIncomingPhoneNumber, AddOutgoingCallerId, UpdateSim,
+		CreateParticipant, CreateByocTrunk, UpdateByocTrunk,
+		UpdateIncomingPhoneNumber, CreatePublicKey, UpdatePublicKey,
+		UpdateAccount, CreateCallRecording, UpdateDialingPermissions:
+		return "POST"
 	}
-
-	return false
+	return "GET"
 }
 
 // httpRequest creates a http REST request from the supplied request struct
-// and the account Sid
-func httpRequest(reqStruct interface{}, accountSid string, logit bool) (
+// and the account Sid, logging it to logger if not nil
+func httpRequest(reqStruct interface{}, accountSid string, logger Logger) (
 	httpReq *http.Request, err error) {
 
+	if err = validate(reqStruct); err != nil {
+		return httpReq, err
+	}
+
 	url, err := urlString(reqStruct, accountSid)
 	if err != nil {
 		return httpReq, err
 	}
 
+	logLegacyTags(reqStruct, logger)
 	queryStr := queryString(reqStruct)
 	requestBody := strings.NewReader(queryStr)
 
-	switch reqStruct.(type) {
+	switch requestMethod(reqStruct) {
 	// GET query method
 	default:
 		if queryStr != "" {
 			url = url + "?" + queryStr
 		}
-		if logit {
-			log.Printf("making twilio GET request to url: %v", url)
+		if logger != nil {
+			logger.Printf("making twilio GET request to url: %v", url)
 		}
 		httpReq, err = http.NewRequest("GET", url, nil)
-	// DELETE query method
-	case DeleteNotification, DeleteOutgoingCallerId,
-		DeleteRecording, DeleteParticipant, DeleteQueue:
-		if logit {
-			log.Printf("making twilio DELETE request to url: %v", url)
+	// DELETE query method, without a body as some proxies reject one
+	case "DELETE":
+		if queryStr != "" {
+			url = url + "?" + queryStr
+		}
+		if logger != nil {
+			logger.Printf("making twilio DELETE request to url: %v", url)
 		}
-		httpReq, err = http.NewRequest("DELETE", url, requestBody)
+		httpReq, err = http.NewRequest("DELETE", url, nil)
 	// POST query method
-	case SendMessage, MakeCall, ModifyCall, CreateQueue, ChangeQueue,
-		DeQueue, UpdateParticipant, UpdateOutgoingCallerId,
-		CreateIncomingPhoneNumber, AddOutgoingCallerId:
-		if logit {
-			log.Printf("making twilio POST request to url: %v with body: %#v", url, queryStr)
+	case "POST":
+		if logger != nil {
+			logger.Printf("making twilio POST request to url: %v with body: %#v", url,
+				redactQuery(queryStr))
 		}
 		httpReq, err = http.NewRequest("POST", url, requestBody)
 	}
 
+	if err == nil {
+		setHeaders(httpReq, reqStruct)
+	}
 	return httpReq, err
 }
 
+// setHeaders sets the Accept header to the format of the response to the
+// request, the Content-Type header on requests with a form body and the
+// User-Agent of the library
+func setHeaders(httpReq *http.Request, reqStruct interface{}) {
+	if httpReq.Method == "POST" || httpReq.ContentLength > 0 {
+		httpReq.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+	httpReq.Header.Set("Accept", acceptType(reqStruct))
+	httpReq.Header.Set("User-Agent", defaultUserAgent)
+}
+
+// acceptType returns the media type of the response to the request
+func acceptType(reqStruct interface{}) string {
+	if rec, ok := reqStruct.(Recording); ok && rec.GetRecording {
+		if rec.GetMP3 {
+			return "audio/mpeg"
+		}
+		return "audio/x-wav"
+	}
+	if isJSONRequest(reqStruct) {
+		return "application/json"
+	}
+	return "application/xml"
+}
+
 // queryString constructs the request string by combining struct tags and
 // elements from the request struct. Each element string is being url
-// encoded/escaped before included.
-func queryString(reqSt interface{}) (qryStr string) {
-	switch reqSt := reqSt.(type) {
-	default:
+// encoded/escaped before included. The fields of each request type are
+// cached and the string is built in a pooled buffer, see form.go.
+func queryString(reqSt interface{}) string {
+	if !hasForm(reqSt) {
+		return ""
+	}
+	return encodeForm(reqSt)
+}
+
+// hasForm reports if the tagged fields of the request struct are sent, the
+// fields of structs defined outside the package are sent if they're tagged
+func hasForm(reqSt interface{}) bool {
+	switch reqSt.(type) {
 	case SendMessage, Messages, MakeCall, Calls, ModifyCall, Accounts,
-		Notifications, OutgoingCallerIds, Recordings, UsageRecords,
+		Notifications, OutgoingCallerIds, Recordings, Recording, UsageRecords,
 		CreateQueue, ChangeQueue, DeQueue, CreateIncomingPhoneNumber,
-		Conferences, Participants, AvailablePhoneNumbers:
-		for i := 0; i < reflect.ValueOf(reqSt).NumField(); i++ {
-			fld := reflect.ValueOf(reqSt).Type().Field(i)
-			val := reflect.ValueOf(reqSt).Field(i).String()
+		Conferences, Participants, AvailablePhoneNumbers, ListSims, UpdateSim,
+		SimUsageRecords, ListAlerts, ListEvents, CreateParticipant,
+		CreateByocTrunk, UpdateByocTrunk, UpdateIncomingPhoneNumber,
+		CreatePublicKey, UpdatePublicKey, UpdateAccount, CreateCallRecording,
+		CallSummary, Lookup, UpdateParticipant, DialingCountries,
+		UpdateDialingPermissions, IncomingPhoneNumberList, Transcriptions,
+		RecordingTranscriptions, Queues, QueueMembers, ListByocTrunks,
+		ListPublicKeys:
+		return true
+	}
+	return foreignForm(reqSt)
+}
 
-			if fld.Type.Kind() == reflect.String &&
-				string(fld.Tag) != "" && val != "" {
-				qryStr += string(fld.Tag) +
-					url.QueryEscape(val) + "&"
-			}
+// pathSegment escapes a value of the url path, such as a participant label
+// used in place of its Call Sid
+func pathSegment(s string) string {
+	return url.PathEscape(s)
+}
 
-			if fld.Type.Kind() == reflect.Slice {
-				v2 := reflect.ValueOf(reqSt).Field(i).Interface().([]string)
-				if string(fld.Tag) != "" && len(v2) > 0 {
-					for _, v := range v2 {
-						qryStr += string(fld.Tag) + url.QueryEscape(v) + "&"
-					}
-				}
-			}
-		}
-		// remove the last '&' if we created a query string
-		if len(qryStr) > 0 {
-			qryStr = qryStr[:len(qryStr)-1]
-		}
+// urlTag returns the url key of a field tag, such as the path of a resource,
+// or the whole tag if it's a legacy raw one
+func urlTag(tag reflect.StructTag) string {
+	if !keyedTag(tag) {
+		return string(tag)
 	}
-	return qryStr
+	return tag.Get("url")
 }
 
 // urlString constructs the REST resource url
@@ -256,13 +784,25 @@ func urlString(reqStruct interface{}, accSid string) (url string, err error) {
 			fld := reflect.ValueOf(reqSt).Type().Field(i)
 			val := reflect.ValueOf(reqSt).Field(i).String()
 
-			m[fld.Name] = [2]string{string(fld.Tag), val}
+			m[fld.Name] = [2]string{urlTag(fld.Tag), val}
 		}
 	}
 
 	// Make base resource URL by adding fields if they exists
 	// ... /Accounts/{accSid}/{resource}/{Sid}/{subresource}/{CallSid}
-	if fld, ok := m["resource"]; ok {
+	// resources of the 2010 API that aren't scoped to the account declare
+	// their absolute path instead
+	// ... /{path}/{Sid}
+	// resources outside the 2010 API replace the base URL with their domain
+	// https://{domain}/{resource}/{Sid}
+	if fld, ok := m["path"]; ok {
+		url = "https://api.twilio.com/" + ApiVer + fld[tag]
+	} else if fld, ok := m["domain"]; ok {
+		url = "https://" + fld[tag]
+		if fld, ok := m["resource"]; ok {
+			url = url + fld[tag]
+		}
+	} else if fld, ok := m["resource"]; ok {
 		url = url + "/" + accSid + fld[tag]
 	}
 	if fld, ok := m["Sid"]; ok {
@@ -272,8 +812,8 @@ func urlString(reqStruct interface{}, accSid string) (url string, err error) {
 	if fld, ok := m["subresource"]; ok {
 		url = url + fld[tag]
 	}
-	if fld, ok := m["CallSid"]; ok && fld[tag] == "" {
-		url = url + "/" + fld[value]
+	if fld, ok := m["CallSid"]; ok && fld[tag] == "" && fld[value] != "" {
+		url = url + "/" + pathSegment(fld[value])
 	}
 
 	// Request cases with additional/optional resources added
@@ -334,6 +874,142 @@ func stringIn(a string, list []string) bool {
 	return false
 }
 
+// validate checks the fields of the request struct that twilio would reject
+func validate(reqStruct interface{}) error {
+	if err := missingFields(reqStruct); err != nil {
+		return err
+	}
+	if err := validPage(reqStruct); err != nil {
+		return err
+	}
+	if err := replacedSet(reqStruct); err != nil {
+		return err
+	}
+	switch reqSt := reqStruct.(type) {
+	case MakeCall:
+		if err := validDigits(reqSt.SendDigits); err != nil {
+			return err
+		}
+		if (reqSt.SipAuthUsername != "" || reqSt.SipAuthPassword != "") &&
+			!isSipUri(reqSt.To) {
+			return fmt.Errorf("SipAuth set for non sip To: '%s'", reqSt.To)
+		}
+		if err := reqSt.Trim.Validate(); err != nil {
+			return err
+		}
+		if err := reqSt.StatusCallbackEvents.Validate(twiml.TwiCallEvents); err != nil {
+			return err
+		}
+		return optionalSid("BY", reqSt.Byoc)
+	case CreateCallRecording:
+		if err := reqSt.RecordingStatusCallbackEvents.Validate(twiml.TwiRecordingEvents); err != nil {
+			return err
+		}
+		return reqSt.Trim.Validate()
+	case CreateParticipant:
+		if err := reqSt.StatusCallbackEvents.Validate(twiml.TwiCallEvents); err != nil {
+			return err
+		}
+		if err := reqSt.ConferenceStatusCallbackEvents.Validate(twiml.TwiConferenceEvents); err != nil {
+			return err
+		}
+		if err := reqSt.JitterBufferSize.Validate(); err != nil {
+			return err
+		}
+		return optionalSid("BY", reqSt.Byoc)
+	case SendMessage:
+		if err := checkSender(reqSt); err != nil {
+			return err
+		}
+		if !reqSt.SendAt.IsZero() && reqSt.ScheduleType != TwiScheduleFixed {
+			return fmt.Errorf("non valid ScheduleType: '%s', SendAt needs %s",
+				reqSt.ScheduleType, TwiScheduleFixed)
+		}
+		return optionalSid("AP", reqSt.ApplicationSid)
+	case CreateIncomingPhoneNumber:
+		if err := optionalSid("AP", reqSt.VoiceApplicationSid); err != nil {
+			return err
+		}
+		return optionalSid("AP", reqSt.SMSApplicationSid)
+	case UpdateIncomingPhoneNumber:
+		if err := optionalSid("AP", reqSt.VoiceApplicationSid); err != nil {
+			return err
+		}
+		return optionalSid("AP", reqSt.SMSApplicationSid)
+	}
+	return nil
+}
+
+// replacement is a string field of a request struct replaced by a typed
+// field on the same parameter, the string field is kept for compatibility
+type replacement struct {
+	old, typed string
+}
+
+// replacedFields are the replacements of each request type
+var replacedFields = map[reflect.Type][]replacement{
+	reflect.TypeOf(MakeCall{}):    {{"Timeout", "TimeoutSeconds"}, {"Record", "RecordCall"}},
+	reflect.TypeOf(SendMessage{}): {{"ValidityPeriod", "ValiditySeconds"}},
+	reflect.TypeOf(Messages{}): {{"DateSentAfter", "DateSentOnOrAfter"},
+		{"DateSentBefore", "DateSentOnOrBefore"}},
+	reflect.TypeOf(Calls{}): {{"StartTimeAfter", "StartTimeOnOrAfter"},
+		{"StartTimeBefore", "StartTimeOnOrBefore"}},
+	reflect.TypeOf(Conferences{}): {{"DateCreatedAfter", "DateCreatedOnOrAfter"},
+		{"DateCreatedBefore", "DateCreatedOnOrBefore"}},
+	reflect.TypeOf(Notifications{}): {{"MsgDateAfter", "MsgDateOnOrAfter"},
+		{"MsgDateBefore", "MsgDateOnOrBefore"}},
+	reflect.TypeOf(Recordings{}): {{"DateCreatedAfter", "DateCreatedOnOrAfter"},
+		{"DateCreatedBefore", "DateCreatedOnOrBefore"}},
+}
+
+// replacedBy returns the typed field replacing the field name of t, empty if
+// it isn't replaced
+func replacedBy(t reflect.Type, name string) string {
+	for _, r := range replacedFields[t] {
+		if r.old == name {
+			return r.typed
+		}
+	}
+	return ""
+}
+
+// replacedSet checks that no parameter is set by both a string field and the
+// typed field that replaces it
+func replacedSet(reqStruct interface{}) error {
+	v := reflect.ValueOf(reqStruct)
+	for _, r := range replacedFields[v.Type()] {
+		if !v.FieldByName(r.old).IsZero() && !v.FieldByName(r.typed).IsZero() {
+			return fmt.Errorf("non valid %s: set by both %s and %s", r.old, r.old, r.typed)
+		}
+	}
+	return nil
+}
+
+// optionalSid checks that sid, if set, is a 34 character Sid with the given
+// two letter prefix
+func optionalSid(prefix, sid string) error {
+	if sid != "" && (len(sid) != 34 || !strings.HasPrefix(sid, prefix)) {
+		return fmt.Errorf("invalid %s Sid: '%s'", prefix, sid)
+	}
+	return nil
+}
+
+// validDigits checks that digits only holds keypad keys and 'w', a half
+// second pause
+func validDigits(digits string) error {
+	for _, c := range digits {
+		if !(c >= '0' && c <= '9' || c == '*' || c == '#' || c == 'w' || c == 'W') {
+			return fmt.Errorf("non valid SendDigits: '%s'", digits)
+		}
+	}
+	return nil
+}
+
+// isSipUri reports if to is a sip uri rather than a phone number or client
+func isSipUri(to string) bool {
+	return len(to) > 4 && strings.EqualFold(to[:4], "sip:")
+}
+
 // check that string(s) is(are) not empty, return error otherwise
 func required(rs ...string) (err error) {
 	for _, s := range rs {
@@ -0,0 +1,778 @@
+package twirest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/seanhagen/twilio/twiml"
+)
+
+// rerouteTransport sends every request to a test server instead of twilio
+type rerouteTransport struct {
+	target *url.URL
+}
+
+func (rt rerouteTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	r = r.Clone(r.Context())
+	r.URL.Scheme = rt.target.Scheme
+	r.URL.Host = rt.target.Host
+	return http.DefaultTransport.RoundTrip(r)
+}
+
+// testClient creates a client talking to a test server running handler
+func testClient(t testing.TB, handler http.Handler, opts ...ClientOption) (
+	*TwilioClient, *httptest.Server) {
+
+	ts := httptest.NewServer(handler)
+	target, _ := url.Parse(ts.URL)
+
+	client, err := NewClient("AC123", "token", opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.httpclient = &http.Client{Transport: rerouteTransport{target}}
+	return client, ts
+}
+
+// recorder is a test handler recording the parameters of the requests it
+// serves, compare them with expectForm
+type recorder struct {
+	mu    sync.Mutex
+	forms []url.Values
+}
+
+func (rec *recorder) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	r.ParseForm()
+	rec.mu.Lock()
+	rec.forms = append(rec.forms, r.Form)
+	rec.mu.Unlock()
+	w.Write([]byte("<TwilioResponse></TwilioResponse>"))
+}
+
+// expectForm checks that request i had the parameters of reqSt. They are
+// compared canonically, the order of the fields of reqSt doesn't matter.
+func (rec *recorder) expectForm(t testing.TB, i int, reqSt interface{}) {
+	t.Helper()
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	if i >= len(rec.forms) {
+		t.Fatalf("expected request %v, got %v requests", i, len(rec.forms))
+	}
+	if got, expect := rec.forms[i].Encode(), EncodeCanonical(reqSt); got != expect {
+		t.Errorf("request %v: expected %v, got %v", i, expect, got)
+	}
+}
+
+func TestRecorderCanonical(t *testing.T) {
+	rec := &recorder{}
+	client, ts := testClient(t, rec)
+	defer ts.Close()
+
+	reqs := []interface{}{
+		MakeCall{To: "+15005550001", From: "+15005550006", Url: "https://example.com/voice",
+			StatusCallbackEvents: []string{"ringing", "answered"}},
+		Calls{Status: TwiCompleted, To: "+15005550001"},
+	}
+	for i, req := range reqs {
+		if _, err := client.Request(req, false); err != nil {
+			t.Fatal(err)
+		}
+		rec.expectForm(t, i, req)
+	}
+}
+
+func TestByocValidation(t *testing.T) {
+	const byoc = "BY0123456789abcdef0123456789abcdef"
+
+	var tests = []struct {
+		Req   interface{}
+		Valid bool
+	}{
+		{MakeCall{To: "+15005550006", Byoc: byoc}, true},
+		{MakeCall{To: "+15005550006"}, true},
+		{MakeCall{To: "+15005550006", Byoc: "TK0123456789abcdef0123456789abcdef"}, false},
+		{MakeCall{To: "+15005550006", Byoc: "BY123"}, false},
+		{CreateParticipant{Sid: "CF123", Byoc: byoc}, true},
+		{CreateParticipant{Sid: "CF123", Byoc: "BY"}, false},
+	}
+
+	for idx, test := range tests {
+		_, err := httpRequest(withRequired(test.Req), "AC123", nil)
+		if (err == nil) != test.Valid {
+			t.Errorf("Test %v failed; expected valid %v, got %v", idx, test.Valid, err)
+		}
+	}
+}
+
+func TestByocQueryString(t *testing.T) {
+	const byoc = "BY0123456789abcdef0123456789abcdef"
+
+	qs := queryString(MakeCall{To: "+15005550006", Byoc: byoc})
+	if !strings.Contains(qs, "Byoc="+byoc) {
+		t.Errorf("expected Byoc in query string, got %#v", qs)
+	}
+
+	got, err := urlString(CreateParticipant{Sid: "CF123", Byoc: byoc}, "AC123")
+	expect := "https://api.twilio.com/2010-04-01/Accounts/AC123/Conferences/CF123/Participants"
+	if err != nil || got != expect {
+		t.Errorf("expected %#v, got %#v (%v)", expect, got, err)
+	}
+}
+
+func TestApplicationSidValidation(t *testing.T) {
+	const app = "AP0123456789abcdef0123456789abcdef"
+	msg := SendMessage{From: "+15005550006", To: "+15005550001", Text: "Hi"}
+	withApp := msg
+	withApp.ApplicationSid = app
+	badApp := msg
+	badApp.ApplicationSid = "PN0123456789abcdef0123456789abcdef"
+
+	var tests = []struct {
+		Req   interface{}
+		Valid bool
+	}{
+		{msg, true},
+		{withApp, true},
+		{badApp, false},
+		{CreateIncomingPhoneNumber{AreaCode: "415", VoiceApplicationSid: app,
+			SMSApplicationSid: app}, true},
+		{CreateIncomingPhoneNumber{AreaCode: "415", SMSApplicationSid: "AP123"}, false},
+		{UpdateIncomingPhoneNumber{Sid: "PN123", SMSApplicationSid: app}, true},
+		{UpdateIncomingPhoneNumber{Sid: "PN123", VoiceApplicationSid: "app"}, false},
+	}
+
+	for idx, test := range tests {
+		_, err := httpRequest(test.Req, "AC123", nil)
+		if (err == nil) != test.Valid {
+			t.Errorf("Test %v failed; expected valid %v, got %v", idx, test.Valid, err)
+		}
+	}
+}
+
+func TestMakeCallValidation(t *testing.T) {
+	var tests = []struct {
+		Req   MakeCall
+		Valid bool
+	}{
+		{MakeCall{To: "+15005550006", SendDigits: "1234#"}, true},
+		{MakeCall{To: "+15005550006", SendDigits: "ww12wW*#"}, true},
+		{MakeCall{To: "+15005550006", SendDigits: "12 34"}, false},
+		{MakeCall{To: "+15005550006", SendDigits: "p123"}, false},
+		{MakeCall{To: "sip:alice@example.com", SipAuthUsername: "alice",
+			SipAuthPassword: "secret", CallerId: "bob"}, true},
+		{MakeCall{To: "SIP:alice@example.com", SipAuthUsername: "alice"}, true},
+		{MakeCall{To: "+15005550006", SipAuthUsername: "alice"}, false},
+		{MakeCall{To: "client:alice", SipAuthPassword: "secret"}, false},
+	}
+
+	for idx, test := range tests {
+		_, err := httpRequest(withRequired(test.Req), "AC123", nil)
+		if (err == nil) != test.Valid {
+			t.Errorf("Test %v failed; expected valid %v, got %v", idx, test.Valid, err)
+		}
+	}
+}
+
+func TestMakeCallForm(t *testing.T) {
+	var tests = []struct {
+		Req    MakeCall
+		Expect string
+	}{
+		{MakeCall{From: "+15005550006", To: "+15005550001", SendDigits: "ww1234#"},
+			"From=%2B15005550006&To=%2B15005550001&SendDigits=ww1234%23"},
+		{MakeCall{From: "+15005550006", To: "sip:alice@example.com",
+			SipAuthUsername: "alice", SipAuthPassword: "s&cret", CallerId: "bob"},
+			"From=%2B15005550006&To=sip%3Aalice%40example.com&SipAuthUsername=alice" +
+				"&SipAuthPassword=s%26cret&CallerId=bob"},
+	}
+
+	for idx, test := range tests {
+		if qs := queryString(test.Req); qs != test.Expect {
+			t.Errorf("Test %v failed; expected %#v, got %#v", idx, test.Expect, qs)
+		}
+	}
+}
+
+func TestEvents(t *testing.T) {
+	var tests = []struct {
+		Req    interface{}
+		Expect string
+	}{
+		{MakeCall{To: "+15005550001", StatusCallbackEvents: twiml.Events{
+			twiml.TwiEventInitiated, twiml.TwiEventCompleted}},
+			"StatusCallbackEvent=initiated&StatusCallbackEvent=completed&To=%2B15005550001"},
+		{CreateParticipant{Sid: "CF1", ConferenceStatusCallbackEvents: twiml.Events{
+			twiml.TwiEventStart, twiml.TwiEventEnd}},
+			"ConferenceStatusCallbackEvent=start&ConferenceStatusCallbackEvent=end"},
+		{CreateCallRecording{Sid: "CA1", RecordingStatusCallbackEvents: twiml.Events{
+			twiml.TwiEventAbsent}}, "RecordingStatusCallbackEvent=absent"},
+		{MakeCall{StatusCallbackEvents: []string{"start"}}, ""},
+		{CreateParticipant{Sid: "CF1", StatusCallbackEvents: twiml.Events{"join"}}, ""},
+		{CreateParticipant{Sid: "CF1", ConferenceStatusCallbackEvents: twiml.Events{"answered"}}, ""},
+		{CreateCallRecording{Sid: "CA1", RecordingStatusCallbackEvents: twiml.Events{"ringing"}}, ""},
+	}
+
+	for idx, test := range tests {
+		_, err := httpRequest(withRequired(test.Req), "AC123", nil)
+		if test.Expect == "" {
+			if err == nil || !strings.Contains(err.Error(), "event") {
+				t.Errorf("Test %v failed; expected an event error, got %v", idx, err)
+			}
+			continue
+		}
+		if qs := EncodeCanonical(test.Req); err != nil || qs != test.Expect {
+			t.Errorf("Test %v failed; expected %#v, got %#v (%v)", idx, test.Expect, qs, err)
+		}
+	}
+
+	// the same events are repeated parameters in REST and an attribute in TwiML
+	events := twiml.Events(twiml.AllowedEvents(twiml.TwiCallEvents))
+	out, err := twiml.Response{Response: []interface{}{twiml.Dial{Nested: []interface{}{
+		twiml.Number{StatusCallbackEvent: events}}}}}.Render()
+	attr := `statusCallbackEvent="` + strings.Join(events, " ") + `"`
+	form := queryString(MakeCall{StatusCallbackEvents: events})
+	if err != nil || !strings.Contains(string(out), attr) ||
+		form != "StatusCallbackEvent="+strings.Join(events, "&StatusCallbackEvent=") {
+		t.Errorf("expected %v in TwiML and repeated in the form, got %s and %v", attr, out, form)
+	}
+}
+
+func TestTrimPolicy(t *testing.T) {
+	var tests = []struct {
+		Req    interface{}
+		Expect string
+	}{
+		{MakeCall{To: "+15005550001", Record: "true", Trim: twiml.TwiDoNotTrim},
+			"To=%2B15005550001&Record=true&Trim=do-not-trim"},
+		{CreateCallRecording{Sid: "CA1", RecordingTrack: "inbound", Trim: twiml.TwiTrimSilence},
+			"RecordingTrack=inbound&Trim=trim-silence"},
+		{MakeCall{To: "+15005550001", Trim: "trim"}, ""},
+		{CreateCallRecording{Sid: "CA1", Trim: "do_not_trim"}, ""},
+	}
+
+	for idx, test := range tests {
+		req, err := httpRequest(withRequired(test.Req), "AC123", nil)
+		if test.Expect == "" {
+			if err == nil || !strings.Contains(err.Error(), "non valid trim") {
+				t.Errorf("Test %v failed; expected a trim error, got %v", idx, err)
+			}
+			continue
+		}
+		if err != nil || req.Method != "POST" {
+			t.Fatalf("Test %v failed; expected a POST request, got %v", idx, err)
+		}
+		if qs := queryString(test.Req); qs != test.Expect {
+			t.Errorf("Test %v failed; expected %#v, got %#v", idx, test.Expect, qs)
+		}
+	}
+
+	// the verbs spell the same constants the same way
+	for _, trim := range []twiml.TrimPolicy{twiml.TwiTrimSilence, twiml.TwiDoNotTrim} {
+		out, err := twiml.Response{Response: []interface{}{
+			twiml.Record{Trim: trim}, twiml.Dial{Trim: trim}}}.Render()
+		form := queryString(MakeCall{Trim: trim})
+		attr := `trim="` + string(trim) + `"`
+		if err != nil || strings.Count(string(out), attr) != 2 || form != "Trim="+string(trim) {
+			t.Errorf("expected %v in TwiML and form, got %s and %v", attr, out, form)
+		}
+	}
+
+	req, _ := httpRequest(CreateCallRecording{Sid: "CA1"}, "AC123", nil)
+	if expect := "/Accounts/AC123/Calls/CA1/Recordings"; !strings.HasSuffix(req.URL.Path, expect) {
+		t.Errorf("expected url %v, got %v", expect, req.URL)
+	}
+}
+
+func TestRequestLogRedaction(t *testing.T) {
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	defer log.SetOutput(os.Stderr)
+
+	_, err := httpRequest(withRequired(MakeCall{To: "sip:alice@example.com",
+		SipAuthUsername: "alice", SipAuthPassword: "hunter2"}), "AC123", LoggerFunc(log.Printf))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(buf.String(), "hunter2") || !strings.Contains(buf.String(), "SipAuthPassword=REDACTED") {
+		t.Errorf("expected the password redacted, got %#v", buf.String())
+	}
+}
+
+func TestResponseEnvelopes(t *testing.T) {
+	const message = `<Message><Sid>SM123</Sid><Body>Hello monkey</Body><Status>queued</Status></Message>`
+	const exception = `<RestException><Code>20404</Code><Message>The requested resource was not found</Message><MoreInfo>https://www.twilio.com/docs/errors/20404</MoreInfo><Status>404</Status></RestException>`
+
+	var tests = []struct {
+		Body string
+		Http int
+		Sid  string
+		Code int
+	}{
+		{Body: xmlHeader + "<TwilioResponse>" + message + "</TwilioResponse>", Http: 200, Sid: "SM123"},
+		{Body: xmlHeader + message, Http: 200, Sid: "SM123"},
+		{Body: "<TwilioResponse>" + exception + "</TwilioResponse>", Http: 404, Code: 20404},
+		{Body: xmlHeader + exception, Http: 404, Code: 20404},
+	}
+
+	for idx, test := range tests {
+		test := test
+		client, ts := testClient(t, http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(test.Http)
+				w.Write([]byte(test.Body))
+			}))
+
+		resp, err := client.Request(Message{Sid: "SM123"}, false)
+		ts.Close()
+
+		if test.Code != 0 {
+			if err == nil || resp.Exception == nil || resp.Status.Twilio != test.Code {
+				t.Errorf("Test %v failed; expected exception %v, got %v, %#v",
+					idx, test.Code, err, resp.Exception)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Test %v failed: %v", idx, err)
+			continue
+		}
+		if resp.Message == nil || resp.Message.Sid != test.Sid ||
+			resp.Message.Body != "Hello monkey" {
+			t.Errorf("Test %v failed; got message %#v", idx, resp.Message)
+		}
+	}
+}
+
+const xmlHeader = `<?xml version="1.0" encoding="UTF-8"?>` + "\n"
+
+func TestUpdateIncomingPhoneNumber(t *testing.T) {
+	req := UpdateIncomingPhoneNumber{Sid: "PN123", VoiceCallerIDLookup: "true"}
+
+	got, err := urlString(req, "AC123")
+	expect := "https://api.twilio.com/2010-04-01/Accounts/AC123/IncomingPhoneNumbers/PN123"
+	if err != nil || got != expect {
+		t.Errorf("expected %#v, got %#v (%v)", expect, got, err)
+	}
+	if qs := queryString(req); qs != "VoiceCallerIdLookup=true" {
+		t.Errorf("expected caller ID lookup toggle, got %#v", qs)
+	}
+}
+
+func TestRecordingDownload(t *testing.T) {
+	var tests = []struct {
+		Channels int
+		MP3      bool
+		Url      string
+		Query    string
+	}{
+		{1, false, "https://api.twilio.com/2010-04-01/Accounts/AC123/Recordings/RE123.wav", ""},
+		{1, true, "https://api.twilio.com/2010-04-01/Accounts/AC123/Recordings/RE123.mp3", ""},
+		{2, false, "https://api.twilio.com/2010-04-01/Accounts/AC123/Recordings/RE123.wav",
+			"RequestedChannels=2"},
+	}
+
+	for idx, test := range tests {
+		req := RecordingDownload("RE123", test.Channels, test.MP3)
+		got, err := urlString(req, "AC123")
+		if err != nil || got != test.Url {
+			t.Errorf("Test %v failed; expected %#v, got %#v (%v)", idx, test.Url, got, err)
+		}
+		if qs := queryString(req); qs != test.Query {
+			t.Errorf("Test %v failed; expected query %#v, got %#v", idx, test.Query, qs)
+		}
+	}
+}
+
+func TestCallsFilters(t *testing.T) {
+	secs := func(n int) *int { return &n }
+	var tests = []struct {
+		Req    Calls
+		Expect string
+	}{
+		{Calls{StartTime: "2009-07-06"}, "StartTime=2009-07-06"},
+		{Calls{StartTimeBefore: "2009-07-06"}, "StartTime%3C=2009-07-06"},
+		{Calls{StartTimeAfter: "2009-07-06"}, "StartTime%3E=2009-07-06"},
+		{Calls{DurationOver: secs(3600)}, "Duration%3E=3600"},
+		{Calls{DurationUnder: secs(5)}, "Duration%3C=5"},
+		// a set zero is sent
+		{Calls{DurationOver: secs(0)}, "Duration%3E=0"},
+		{Calls{Status: TwiCompleted, StartTimeAfter: "2009-07-06", DurationOver: secs(3600)},
+			"Status=completed&StartTime%3E=2009-07-06&Duration%3E=3600"},
+	}
+
+	for idx, test := range tests {
+		if got := queryString(test.Req); got != test.Expect {
+			t.Errorf("Test %v failed; expected %#v, got %#v", idx, test.Expect, got)
+		}
+	}
+
+	// the operators must come through as part of the parameter names
+	q, err := url.ParseQuery(queryString(Calls{DurationOver: secs(3600),
+		DurationUnder: secs(7200)}))
+	if err != nil || q.Get("Duration>") != "3600" || q.Get("Duration<") != "7200" {
+		t.Errorf("expected duration operators in %#v (%v)", q, err)
+	}
+}
+
+func TestCallDuration(t *testing.T) {
+	body := xmlHeader + `<TwilioResponse><Calls page="0">` +
+		`<Call><Sid>CA1</Sid><Duration>4000</Duration></Call>` +
+		`<Call><Sid>CA2</Sid><Duration/></Call>` +
+		`</Calls></TwilioResponse>`
+
+	twir := TwilioResponse{}
+	if err := decodeXML([]byte(body), &twir); err != nil {
+		t.Fatal(err)
+	}
+	calls := twir.Calls.Call
+	if len(calls) != 2 || calls[0].Duration != 4000 || calls[1].Duration != 0 {
+		t.Errorf("unexpected calls %#v", calls)
+	}
+}
+
+func TestNextPage(t *testing.T) {
+	pages := map[string]string{
+		"/2010-04-01/Accounts/AC123/Messages": `<Messages page="0" nextpageuri="/2010-04-01/Accounts/AC123/Messages?Page=1">` +
+			`<Message><Sid>SM1</Sid></Message></Messages>`,
+		"/2010-04-01/Accounts/AC123/Messages?Page=1": `<Messages page="1" nextpageuri="">` +
+			`<Message><Sid>SM2</Sid></Message></Messages>`,
+	}
+	client, ts := testClient(t, http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(xmlHeader + "<TwilioResponse>" +
+				pages[r.URL.RequestURI()] + "</TwilioResponse>"))
+		}))
+	defer ts.Close()
+
+	var sids []string
+	ctx := context.Background()
+	resp, err := client.RequestWithContext(ctx, Messages{}, false)
+	for err == nil && resp.Messages != nil {
+		for _, m := range resp.Messages.Message {
+			sids = append(sids, m.Sid)
+		}
+		if resp.Messages.NextPageUri == "" {
+			break
+		}
+		resp, err = client.NextPage(ctx, resp.Messages.NextPageUri, Messages{})
+	}
+	if err != nil || strings.Join(sids, ",") != "SM1,SM2" {
+		t.Errorf("expected both pages, got %v (%v)", sids, err)
+	}
+}
+
+func TestHttpRequestHeaders(t *testing.T) {
+	var tests = []struct {
+		Req         interface{}
+		Method      string
+		ContentType string
+		Accept      string
+	}{
+		{Messages{To: "+15005550006"}, "GET", "", "application/xml"},
+		{SendMessage{To: "+15005550006", Text: "hi"}, "POST", "application/x-www-form-urlencoded", "application/xml"},
+		{UpdatePublicKey{Sid: "CR123"}, "POST", "application/x-www-form-urlencoded", "application/json"},
+		{DeleteRecording{Sid: "RE123"}, "DELETE", "", "application/xml"},
+		{DeleteByocTrunk{Sid: "BY123"}, "DELETE", "", "application/json"},
+		{ListSims{}, "GET", "", "application/json"},
+		{Recording{Sid: "RE123"}, "GET", "", "application/xml"},
+		{Recording{Sid: "RE123", GetRecording: true}, "GET", "", "audio/x-wav"},
+		{Recording{Sid: "RE123", GetRecording: true, GetMP3: true}, "GET", "", "audio/mpeg"},
+	}
+
+	for idx, test := range tests {
+		req, err := httpRequest(withRequired(test.Req), "AC123", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if req.Method != test.Method ||
+			req.Header.Get("Content-Type") != test.ContentType ||
+			req.Header.Get("Accept") != test.Accept {
+			t.Errorf("Test %v failed; expected %v %#v %#v, got %v %#v %#v", idx,
+				test.Method, test.ContentType, test.Accept, req.Method,
+				req.Header.Get("Content-Type"), req.Header.Get("Accept"))
+		}
+	}
+}
+
+// TestRequestPaths locks down the method and url of every request type, path
+// fields are placeholders as in DescribeRequest
+func TestRequestPaths(t *testing.T) {
+	const api = "https://api.twilio.com/" + ApiVer
+	var golden = []struct {
+		Name   string
+		Expect string
+	}{
+		{"IncomingPhoneNumberList", "GET /Accounts/{AccountSid}/IncomingPhoneNumbers"},
+		{"CreateIncomingPhoneNumber", "POST /Accounts/{AccountSid}/IncomingPhoneNumbers"},
+		{"UpdateIncomingPhoneNumber", "POST /Accounts/{AccountSid}/IncomingPhoneNumbers/{Sid}"},
+		{"DeleteIncomingPhoneNumber", "DELETE /Accounts/{AccountSid}/IncomingPhoneNumbers/{Sid}"},
+		{"AvailablePhoneNumbers", "GET /Accounts/{AccountSid}/AvailablePhoneNumbers/{CountryCode}/{Type}"},
+		{"Accounts", "GET /Accounts"},
+		{"Account", "GET /Accounts/{Sid}"},
+		{"UpdateAccount", "POST /Accounts/{Sid}"},
+		{"Calls", "GET /Accounts/{AccountSid}/Calls"},
+		{"Call", "GET /Accounts/{AccountSid}/Calls/{Sid}"},
+		{"MakeCall", "POST /Accounts/{AccountSid}/Calls"},
+		{"CreateCallRecording", "POST /Accounts/{AccountSid}/Calls/{Sid}/Recordings"},
+		{"ModifyCall", "POST /Accounts/{AccountSid}/Calls/{Sid}"},
+		{"Conferences", "GET /Accounts/{AccountSid}/Conferences"},
+		{"Conference", "GET /Accounts/{AccountSid}/Conferences/{Sid}"},
+		{"Participants", "GET /Accounts/{AccountSid}/Conferences/{Sid}/Participants"},
+		{"Participant", "GET /Accounts/{AccountSid}/Conferences/{Sid}/Participants/{CallSid}"},
+		{"CreateParticipant", "POST /Accounts/{AccountSid}/Conferences/{Sid}/Participants"},
+		{"DeleteParticipant", "DELETE /Accounts/{AccountSid}/Conferences/{Sid}/Participants/{CallSid}"},
+		{"UpdateParticipant", "POST /Accounts/{AccountSid}/Conferences/{Sid}/Participants/{CallSid}"},
+		{"Messages", "GET /Accounts/{AccountSid}/Messages"},
+		{"Message", "GET /Accounts/{AccountSid}/Messages/{Sid}"},
+		{"SendMessage", "POST /Accounts/{AccountSid}/Messages"},
+		{"Notifications", "GET /Accounts/{AccountSid}/Notifications"},
+		{"Notification", "GET /Accounts/{AccountSid}/Notifications/{Sid}"},
+		{"DeleteNotification", "DELETE /Accounts/{AccountSid}/Notifications/{Sid}"},
+		{"OutgoingCallerIds", "GET /Accounts/{AccountSid}/OutgoingCallerIds"},
+		{"OutgoingCallerId", "GET /Accounts/{AccountSid}/OutgoingCallerIds/{Sid}"},
+		{"UpdateOutgoingCallerId", "POST /Accounts/{AccountSid}/OutgoingCallerIds/{Sid}"},
+		{"DeleteOutgoingCallerId", "DELETE /Accounts/{AccountSid}/OutgoingCallerIds/{Sid}"},
+		{"AddOutgoingCallerId", "POST /Accounts/{AccountSid}/OutgoingCallerIds"},
+		{"Recordings", "GET /Accounts/{AccountSid}/Recordings"},
+		{"Recording", "GET /Accounts/{AccountSid}/Recordings/{Sid}.xml"},
+		{"DeleteRecording", "DELETE /Accounts/{AccountSid}/Recordings/{Sid}"},
+		{"Transcriptions", "GET /Accounts/{AccountSid}/Transcriptions"},
+		{"RecordingTranscriptions", "GET /Accounts/{AccountSid}/Recordings/{Sid}/Transcriptions"},
+		{"Transcription", "GET /Accounts/{AccountSid}/Transcriptions/{Sid}"},
+		{"UsageRecords", "GET /Accounts/{AccountSid}/Usage/Records/{SubResource}"},
+		{"Queues", "GET /Accounts/{AccountSid}/Queues"},
+		{"Queue", "GET /Accounts/{AccountSid}/Queues/{Sid}"},
+		{"CreateQueue", "POST /Accounts/{AccountSid}/Queues"},
+		{"ChangeQueue", "POST /Accounts/{AccountSid}/Queues/{Sid}"},
+		{"DeleteQueue", "DELETE /Accounts/{AccountSid}/Queues/{Sid}"},
+		{"QueueMembers", "GET /Accounts/{AccountSid}/Queues/{Sid}/Members"},
+		{"QueueMember", "GET /Accounts/{AccountSid}/Queues/{Sid}/Members/{CallSid}"},
+		{"DeQueue", "POST /Accounts/{AccountSid}/Queues/{Sid}/Members/{CallSid}"},
+		{"ListByocTrunks", "GET https://voice.twilio.com/v1/ByocTrunks"},
+		{"FetchByocTrunk", "GET https://voice.twilio.com/v1/ByocTrunks/{Sid}"},
+		{"CreateByocTrunk", "POST https://voice.twilio.com/v1/ByocTrunks"},
+		{"UpdateByocTrunk", "POST https://voice.twilio.com/v1/ByocTrunks/{Sid}"},
+		{"DeleteByocTrunk", "DELETE https://voice.twilio.com/v1/ByocTrunks/{Sid}"},
+		{"ListAlerts", "GET https://monitor.twilio.com/v1/Alerts"},
+		{"GetAlert", "GET https://monitor.twilio.com/v1/Alerts/{Sid}"},
+		{"ListEvents", "GET https://monitor.twilio.com/v1/Events"},
+		{"GetEvent", "GET https://monitor.twilio.com/v1/Events/{Sid}"},
+		{"ListPublicKeys", "GET https://accounts.twilio.com/v1/Credentials/PublicKeys"},
+		{"FetchPublicKey", "GET https://accounts.twilio.com/v1/Credentials/PublicKeys/{Sid}"},
+		{"CreatePublicKey", "POST https://accounts.twilio.com/v1/Credentials/PublicKeys"},
+		{"UpdatePublicKey", "POST https://accounts.twilio.com/v1/Credentials/PublicKeys/{Sid}"},
+		{"DeletePublicKey", "DELETE https://accounts.twilio.com/v1/Credentials/PublicKeys/{Sid}"},
+		{"ListSims", "GET https://supersim.twilio.com/v1/Sims"},
+		{"FetchSim", "GET https://supersim.twilio.com/v1/Sims/{Sid}"},
+		{"UpdateSim", "POST https://supersim.twilio.com/v1/Sims/{Sid}"},
+		{"SimUsageRecords", "GET https://supersim.twilio.com/v1/UsageRecords"},
+		{"CallSummary", "GET https://insights.twilio.com/v1/Voice/{Sid}/Summary"},
+		{"Lookup", "GET https://lookups.twilio.com/v2/PhoneNumbers/{Sid}"},
+		{"DialingCountries", "GET https://voice.twilio.com/v1/DialingPermissions/Countries"},
+		{"DialingCountry", "GET https://voice.twilio.com/v1/DialingPermissions/Countries/{Sid}"},
+		{"UpdateDialingPermissions", "POST https://voice.twilio.com/v1/DialingPermissions/BulkCountryUpdates"},
+	}
+
+	types := RequestTypes()
+	if len(types) != len(golden) {
+		t.Errorf("expected %v request types, got %v", len(golden), len(types))
+	}
+	for idx, rt := range types {
+		s, err := DescribeRequest(rt)
+		if err != nil || idx >= len(golden) {
+			t.Fatalf("Test %v failed; %v", idx, err)
+		}
+		got := s.Method + " " + strings.TrimPrefix(s.Url, api)
+		if s.Name != golden[idx].Name || got != golden[idx].Expect {
+			t.Errorf("Test %v failed; expected %v %v, got %v %v", idx, golden[idx].Name,
+				golden[idx].Expect, s.Name, got)
+		}
+	}
+}
+
+func TestDeleteNoContent(t *testing.T) {
+	var tests = []struct {
+		Req    interface{}
+		Code   int
+		Body   string
+		Expect error
+	}{
+		{DeleteRecording{Sid: "RE123"}, http.StatusNoContent, "", nil},
+		{DeleteByocTrunk{Sid: "BY123"}, http.StatusNoContent, "", nil},
+		{DeleteParticipant{Sid: "CF123", CallSid: "CA123"}, http.StatusOK, "", nil},
+		{DeleteRecording{Sid: "RE404"}, http.StatusNotFound, xmlHeader +
+			`<TwilioResponse><RestException><Code>20404</Code><Message>The requested ` +
+			`resource was not found</Message><Status>404</Status></RestException>` +
+			`</TwilioResponse>`, ErrNotFound},
+		{DeleteByocTrunk{Sid: "BY404"}, http.StatusNotFound,
+			`{"code":20404,"message":"not found","status":404}`, ErrNotFound},
+	}
+
+	for idx, test := range tests {
+		client, ts := testClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := ioutil.ReadAll(r.Body)
+			if r.Method != "DELETE" || len(body) > 0 || r.ContentLength > 0 ||
+				r.Header.Get("Content-Type") != "" {
+				t.Errorf("Test %v failed; expected a DELETE without a body, got %v %q",
+					idx, r.Method, body)
+			}
+			w.WriteHeader(test.Code)
+			w.Write([]byte(test.Body))
+		}))
+
+		resp, err := client.Request(test.Req, false)
+		ts.Close()
+		if !errors.Is(err, test.Expect) || (test.Expect == nil && err != nil) {
+			t.Errorf("Test %v failed; expected %v, got %v", idx, test.Expect, err)
+		}
+		if resp.Status.Http != test.Code {
+			t.Errorf("Test %v failed; expected status %v, got %v", idx, test.Code,
+				resp.Status.Http)
+		}
+	}
+}
+
+func TestPointerRequests(t *testing.T) {
+	var got []string
+	rec := &recorder{}
+	client, ts := testClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = append(got, r.Method+" "+r.URL.Path)
+		rec.ServeHTTP(w, r)
+	}))
+	defer ts.Close()
+
+	const base = "/2010-04-01/Accounts/AC123"
+	msg := SendMessage{From: "+15005550006", To: "+15005550001", Text: "Hi"}
+	var tests = []struct {
+		Req    interface{}
+		Expect string
+	}{
+		{Message{Sid: "SM1"}, "GET " + base + "/Messages/SM1"},
+		{msg, "POST " + base + "/Messages"},
+		{DeleteQueue{Sid: "QU1"}, "DELETE " + base + "/Queues/QU1"},
+		{OnSubaccount(testSubaccount, &msg),
+			"POST /2010-04-01/Accounts/" + testSubaccount + "/Messages"},
+	}
+
+	for idx, test := range tests {
+		ptr := reflect.New(reflect.TypeOf(test.Req))
+		ptr.Elem().Set(reflect.ValueOf(test.Req))
+		// the pointer and the value make the same request
+		got = nil
+		for _, req := range []interface{}{test.Req, ptr.Interface()} {
+			if _, err := client.Request(req, false); err != nil {
+				t.Errorf("Test %v failed; %T: %v", idx, req, err)
+			}
+		}
+		if expect := []string{test.Expect, test.Expect}; !reflect.DeepEqual(got, expect) {
+			t.Errorf("Test %v failed; expected %v, got %v", idx, expect, got)
+		}
+	}
+	rec.expectForm(t, 2, &msg)
+	rec.expectForm(t, 3, msg)
+
+	var nilMsg *SendMessage
+	for idx, req := range []interface{}{nil, nilMsg, "SM1", struct{ To string }{"+1"},
+		OnSubaccount(testSubaccount, struct{}{})} {
+		got = nil
+		if _, err := client.Request(req, false); err == nil || len(got) > 0 {
+			t.Errorf("Test %v failed; expected %T to fail, got %v %v", idx, req, err, got)
+		}
+	}
+}
+
+// countingMetrics counts the observed requests per method
+type countingMetrics struct {
+	mu       sync.Mutex
+	observed map[string]int
+}
+
+func (m *countingMetrics) ObserveRequest(resource, method string, status int,
+	d time.Duration) {
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.observed[method]++
+}
+
+// TestConcurrentRequests shares a client between goroutines, run it with
+// -race to check the shared state of the client is guarded
+func TestConcurrentRequests(t *testing.T) {
+	var mu sync.Mutex
+	var lines int
+	served := map[string]int{}
+	client, ts := testClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		served[r.Method]++
+		mu.Unlock()
+		switch r.Method {
+		case "DELETE":
+			w.WriteHeader(http.StatusNoContent)
+		case "POST":
+			w.WriteHeader(http.StatusCreated)
+			fallthrough
+		default:
+			w.Write([]byte(xmlHeader + "<TwilioResponse><Message><Sid>SM1</Sid>" +
+				"</Message></TwilioResponse>"))
+		}
+	}),
+		WithRateLimit(10000, 100),
+		WithDedupe(time.Minute, NewMemoryDedupeStore()),
+		WithMetrics(&countingMetrics{observed: map[string]int{}}),
+		// the lines logged aren't guarded by the logger itself
+		WithLogger(LoggerFunc(func(format string, args ...interface{}) {
+			lines++
+		})))
+	defer ts.Close()
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 100)
+	for i := 0; i < 100; i++ {
+		var req interface{}
+		switch i % 3 {
+		case 0:
+			req = Message{Sid: "SM1"}
+		case 1:
+			req = SendMessage{From: "+15005550006", To: "+15005550001",
+				Text: fmt.Sprintf("message %d", i)}
+		case 2:
+			req = DeleteQueue{Sid: "QU1"}
+		}
+		wg.Add(1)
+		go func(req interface{}) {
+			defer wg.Done()
+			if _, err := client.Request(req, false); err != nil {
+				errs <- err
+			}
+		}(req)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Errorf("unexpected error %v", err)
+	}
+
+	if lines < 100 {
+		t.Errorf("expected every request logged, got %v lines", lines)
+	}
+	metrics := client.metrics.(*countingMetrics)
+	for method, expect := range map[string]int{"GET": 34, "POST": 33, "DELETE": 33} {
+		if served[method] != expect || metrics.observed[method] != expect {
+			t.Errorf("expected %v %v requests, served %v, observed %v", expect, method,
+				served[method], metrics.observed[method])
+		}
+	}
+}
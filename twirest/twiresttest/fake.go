@@ -0,0 +1,133 @@
+// Package twiresttest provides a fake twirest client for the tests of code
+// that makes twilio requests through a twirest.Requester.
+package twiresttest
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/seanhagen/twilio/twirest"
+)
+
+// response is a canned response to the requests of a type
+type response struct {
+	resp twirest.TwilioResponse
+	err  error
+}
+
+// FakeClient is a twirest.Requester that records the requests made and
+// returns canned responses by the type of the request struct. Requests of a
+// type without a canned response get an empty response with http status 200.
+// It is safe for concurrent use.
+type FakeClient struct {
+	mu        sync.Mutex
+	requests  []interface{}
+	responses map[reflect.Type]response
+}
+
+var _ twirest.Requester = (*FakeClient)(nil)
+
+// NewFakeClient returns a FakeClient without canned responses
+func NewFakeClient() *FakeClient {
+	return &FakeClient{responses: map[reflect.Type]response{}}
+}
+
+// Respond makes the client return resp and err to the requests of the type
+// of reqStruct, such as twirest.SendMessage{}. A resp without a http status
+// gets 200, or 400 if err is set.
+func (f *FakeClient) Respond(reqStruct interface{}, resp twirest.TwilioResponse, err error) {
+	if resp.Status.Http == 0 {
+		resp.Status.Http = http.StatusOK
+		if err != nil {
+			resp.Status.Http = http.StatusBadRequest
+		}
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.responses[reflect.TypeOf(reqStruct)] = response{resp: resp, err: err}
+}
+
+func (f *FakeClient) Request(reqStruct interface{}, logit bool) (
+	twirest.TwilioResponse, error) {
+
+	return f.RequestWithContext(context.Background(), reqStruct, logit)
+}
+
+// RequestWithContext records reqStruct and returns the canned response of
+// its type. The error of ctx is returned if it's done.
+func (f *FakeClient) RequestWithContext(ctx context.Context, reqStruct interface{},
+	logit bool) (twirest.TwilioResponse, error) {
+
+	if err := ctx.Err(); err != nil {
+		return twirest.TwilioResponse{}, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.requests = append(f.requests, reqStruct)
+	if r, ok := f.responses[reflect.TypeOf(reqStruct)]; ok {
+		return r.resp, r.err
+	}
+	return twirest.TwilioResponse{Status: twirest.ResponseStatus{Http: http.StatusOK}}, nil
+}
+
+// Requests returns the request structs of the requests made, in order
+func (f *FakeClient) Requests() []interface{} {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]interface{}(nil), f.requests...)
+}
+
+// Messages returns the messages sent, in order
+func (f *FakeClient) Messages() []twirest.SendMessage {
+	var msgs []twirest.SendMessage
+	for _, req := range f.Requests() {
+		if msg, ok := req.(twirest.SendMessage); ok {
+			msgs = append(msgs, msg)
+		}
+	}
+	return msgs
+}
+
+// Calls returns the calls made, in order
+func (f *FakeClient) Calls() []twirest.MakeCall {
+	var calls []twirest.MakeCall
+	for _, req := range f.Requests() {
+		if call, ok := req.(twirest.MakeCall); ok {
+			calls = append(calls, call)
+		}
+	}
+	return calls
+}
+
+// Reset forgets the requests made, the canned responses are kept
+func (f *FakeClient) Reset() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.requests = nil
+}
+
+// AssertSent fails the test unless a request equal to reqStruct, such as the
+// exact SendMessage expected, was made
+func (f *FakeClient) AssertSent(t testing.TB, reqStruct interface{}) {
+	t.Helper()
+	requests := f.Requests()
+	for _, req := range requests {
+		if reflect.DeepEqual(req, reqStruct) {
+			return
+		}
+	}
+	t.Errorf("expected request %#v, got %#v", reqStruct, requests)
+}
+
+// AssertNotSent fails the test if a request of the type of reqStruct was made
+func (f *FakeClient) AssertNotSent(t testing.TB, reqStruct interface{}) {
+	t.Helper()
+	for _, req := range f.Requests() {
+		if reflect.TypeOf(req) == reflect.TypeOf(reqStruct) {
+			t.Errorf("expected no %T request, got %#v", reqStruct, req)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package twiresttest
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/seanhagen/twilio/twirest"
+)
+
+// remind is code under test that sends through a Requester
+func remind(r twirest.Requester, to string) (string, error) {
+	resp, err := r.Request(twirest.SendMessage{From: "+15005550006", To: to,
+		Text: "Your appointment is tomorrow"}, false)
+	if err != nil {
+		return "", err
+	}
+	if resp.Message == nil {
+		return "", nil
+	}
+	return resp.Message.Sid, nil
+}
+
+func TestFakeClient(t *testing.T) {
+	fake := NewFakeClient()
+	fake.Respond(twirest.SendMessage{}, twirest.TwilioResponse{
+		Message: &twirest.MessageResponse{Sid: "SM1"}}, nil)
+
+	sid, err := remind(fake, "+15005550001")
+	if err != nil || sid != "SM1" {
+		t.Errorf("expected SM1, got %v (%v)", sid, err)
+	}
+	fake.AssertSent(t, twirest.SendMessage{From: "+15005550006", To: "+15005550001",
+		Text: "Your appointment is tomorrow"})
+	fake.AssertNotSent(t, twirest.MakeCall{})
+
+	if _, err := fake.Request(twirest.MakeCall{To: "+15005550001"}, false); err != nil {
+		t.Error(err)
+	}
+	if msgs, calls := fake.Messages(), fake.Calls(); len(msgs) != 1 || len(calls) != 1 ||
+		calls[0].To != "+15005550001" {
+		t.Errorf("expected a message and a call, got %v and %v", msgs, calls)
+	}
+	if len(fake.Requests()) != 2 {
+		t.Errorf("expected 2 requests, got %v", fake.Requests())
+	}
+
+	fake.Reset()
+	fail := errors.New("unreachable")
+	fake.Respond(twirest.SendMessage{}, twirest.TwilioResponse{}, fail)
+	if _, err := remind(fake, "+15005550001"); err != fail {
+		t.Errorf("expected the canned error, got %v", err)
+	}
+	if len(fake.Requests()) != 1 {
+		t.Errorf("expected the requests to be reset, got %v", fake.Requests())
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := fake.RequestWithContext(ctx, twirest.Queues{}, false); err != context.Canceled {
+		t.Errorf("expected the context error, got %v", err)
+	}
+	resp, err := fake.Request(twirest.Queues{}, false)
+	if err != nil || !resp.OK() {
+		t.Errorf("expected a default OK response, got %v (%v)", resp.Status, err)
+	}
+}
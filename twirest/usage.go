@@ -0,0 +1,205 @@
+package twirest
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// UsageCategory is a category of UsageRecords, such as TwiCalls or TwiSms
+type UsageCategory string
+
+// usageCategories are the known UsageRecords categories
+var usageCategories = map[UsageCategory]bool{
+	TwiCalls: true, TwiCallsInbound: true, TwiCallsInboundLocal: true,
+	TwiCallsInboundTollfree: true, TwiCallsOutbound: true, TwiCallsClient: true,
+	TwiCallsSip: true, TwiSms: true, TwiSmsInbound: true,
+	TwiSmsInboundShortcode: true, TwiSmsInboundLongcode: true,
+	TwiPhoneNumbers: true, TwiPhoneNumbersTollFree: true,
+	TwiPhoneNumbersLocal: true, TwiShortcodes: true, TwiShortcodesVanity: true,
+	TwiShortcodesRandom: true, TwiShortcodesCustomerOwned: true,
+	TwiCallerIdLookups: true, TwiRecordings: true, TwiTranscriptions: true,
+	TwiRecordingStorage: true, TwiTotalPrice: true,
+}
+
+// ThresholdBreach is a usage category at or over its threshold this month.
+// Usage and Limit are in UsageUnit, Price in PriceUnit.
+type ThresholdBreach struct {
+	Category  UsageCategory
+	Usage     float64
+	Limit     float64
+	UsageUnit string
+	Price     float64
+	PriceUnit string
+}
+
+func (b ThresholdBreach) String() string {
+	return fmt.Sprintf("%s usage %g %s over threshold %g", b.Category, b.Usage,
+		b.UsageUnit, b.Limit)
+}
+
+// CheckUsageThresholds compares the usage of this month with thresholds, for
+// environments that can't receive the webhooks of usage triggers. The usage
+// of TwiTotalPrice is the total price, to limit spending. The breaches are in
+// category order.
+func (twiClient *TwilioClient) CheckUsageThresholds(ctx context.Context,
+	thresholds map[UsageCategory]float64) ([]ThresholdBreach, error) {
+
+	for category := range thresholds {
+		if !usageCategories[category] {
+			return nil, fmt.Errorf("non valid usage category: '%s'", category)
+		}
+	}
+	if len(thresholds) == 0 {
+		return nil, nil
+	}
+
+	req := UsageRecords{SubResource: TwiThisMonth}
+	resp, err := twiClient.RequestWithContext(ctx, req, false)
+	var breaches []ThresholdBreach
+	for err == nil && resp.UsageRecords != nil {
+		for _, rec := range resp.UsageRecords.UsageRecord {
+			limit, ok := thresholds[UsageCategory(rec.Category)]
+			if !ok {
+				continue
+			}
+			b, berr := usageBreach(rec, limit)
+			if berr != nil {
+				return nil, berr
+			}
+			if b != nil {
+				breaches = append(breaches, *b)
+			}
+		}
+		next := resp.UsageRecords.NextPageUri
+		if next == "" {
+			break
+		}
+		resp, err = twiClient.NextPage(ctx, next, req)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(breaches, func(i, j int) bool {
+		return breaches[i].Category < breaches[j].Category
+	})
+	return breaches, nil
+}
+
+// ServiceUsageReport is the messaging of a messaging service in a month
+type ServiceUsageReport struct {
+	ServiceSid string
+	Month      time.Time // the first day of the month, UTC
+	Messages   int
+	Segments   int
+	// Price is the sum of the known prices, negative like twilio's. Unpriced
+	// is the number of messages whose price isn't known yet.
+	Price    Money
+	Unpriced int
+}
+
+// serviceUsagePageSize is the size of the pages of messages ServiceUsage
+// requests, the largest twilio allows
+const serviceUsagePageSize = MaxPageSize
+
+// ServiceUsage adds up the messages the messaging service serviceSid sent in
+// the month of month, in UTC, such as for billing the tenant of a service.
+// Only the messages of the service sent in the month are listed, in pages of
+// 1000.
+func (twiClient *TwilioClient) ServiceUsage(ctx context.Context, serviceSid string,
+	month time.Time) (ServiceUsageReport, error) {
+
+	month = month.UTC()
+	first := time.Date(month.Year(), month.Month(), 1, 0, 0, 0, 0, time.UTC)
+	report := ServiceUsageReport{ServiceSid: serviceSid, Month: first}
+	if err := required(serviceSid); err != nil {
+		return report, err
+	}
+	if err := optionalSid("MG", serviceSid); err != nil {
+		return report, err
+	}
+
+	req := Messages{
+		MessagingServiceSid: serviceSid,
+		DateSentAfter:       first.Format("2006-01-02"),
+		DateSentBefore:      first.AddDate(0, 1, -1).Format("2006-01-02"),
+		PageSize:            serviceUsagePageSize,
+	}
+	resp, err := twiClient.RequestWithContext(ctx, req, false)
+	for err == nil && resp.Messages != nil {
+		for _, m := range resp.Messages.Message {
+			if m.MessagingServiceSid != serviceSid {
+				continue
+			}
+			if err = report.add(m); err != nil {
+				return report, fmt.Errorf("message %s: %v", m.Sid, err)
+			}
+		}
+		next := resp.Messages.NextPageUri
+		if next == "" {
+			break
+		}
+		resp, err = twiClient.NextPage(ctx, next, req)
+	}
+	return report, err
+}
+
+// add counts the message m in the report
+func (r *ServiceUsageReport) add(m MessageResponse) error {
+	info, err := NewMessageInfo(m)
+	if err != nil {
+		return err
+	}
+	price, ok, err := m.ParsedPrice()
+	if err != nil {
+		return err
+	}
+	if ok {
+		if r.Price, err = r.Price.Add(price); err != nil {
+			return err
+		}
+	} else {
+		r.Unpriced++
+	}
+	r.Messages++
+	r.Segments += info.NumSegments
+	return nil
+}
+
+// usageBreach returns the breach of the usage record, nil if the usage is
+// below limit
+func usageBreach(rec UsageRecordResponse, limit float64) (*ThresholdBreach, error) {
+	usage, err := parseUsage(rec.Usage)
+	if err != nil {
+		return nil, fmt.Errorf("non valid usage of %s: '%s'", rec.Category, rec.Usage)
+	}
+	if usage < limit {
+		return nil, nil
+	}
+	price, err := parseUsage(rec.Price)
+	if err != nil {
+		return nil, fmt.Errorf("non valid price of %s: '%s'", rec.Category, rec.Price)
+	}
+	return &ThresholdBreach{
+		Category:  UsageCategory(rec.Category),
+		Usage:     usage,
+		Limit:     limit,
+		UsageUnit: rec.UsageUnit,
+		Price:     price,
+		PriceUnit: rec.PriceUnit,
+	}, nil
+}
+
+// parseUsage parses a decimal usage value such as '1340.5', an empty value is
+// no usage
+func parseUsage(val string) (float64, error) {
+	val = strings.TrimSpace(val)
+	if val == "" {
+		return 0, nil
+	}
+	return strconv.ParseFloat(val, 64)
+}
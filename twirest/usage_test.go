@@ -0,0 +1,127 @@
+package twirest
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// usageRecord returns the xml of a usage record
+func usageRecord(category, usage, unit, price string) string {
+	return `<UsageRecord><Category>` + category + `</Category><Usage>` + usage +
+		`</Usage><UsageUnit>` + unit + `</UsageUnit><Price>` + price +
+		`</Price><PriceUnit>usd</PriceUnit></UsageRecord>`
+}
+
+func TestCheckUsageThresholds(t *testing.T) {
+	pages := map[string]string{
+		"/2010-04-01/Accounts/AC123/Usage/Records/ThisMonth": `<UsageRecords page="0" ` +
+			`nextpageuri="/2010-04-01/Accounts/AC123/Usage/Records/ThisMonth?Page=1">` +
+			usageRecord("calls", "1340.5", "minutes", "26.81") +
+			usageRecord("sms", "120", "segments", "0.90") +
+			usageRecord("recordings", "", "minutes", "") + `</UsageRecords>`,
+		"/2010-04-01/Accounts/AC123/Usage/Records/ThisMonth?Page=1": `<UsageRecords page="1" nextpageuri="">` +
+			usageRecord("totalprice", "27.71", "usd", "27.71") +
+			usageRecord("transcriptions", "n/a", "minutes", "0") + `</UsageRecords>`,
+	}
+	client, ts := testClient(t, http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(xmlHeader + "<TwilioResponse>" +
+				pages[r.URL.RequestURI()] + "</TwilioResponse>"))
+		}))
+	defer ts.Close()
+
+	var tests = []struct {
+		Thresholds map[UsageCategory]float64
+		Expect     []ThresholdBreach
+		Valid      bool
+	}{
+		{map[UsageCategory]float64{TwiCalls: 2000, TwiSms: 500}, nil, true},
+		{map[UsageCategory]float64{TwiCalls: 1000, TwiSms: 120, TwiTotalPrice: 25},
+			[]ThresholdBreach{
+				{Category: TwiCalls, Usage: 1340.5, Limit: 1000, UsageUnit: "minutes",
+					Price: 26.81, PriceUnit: "usd"},
+				{Category: TwiSms, Usage: 120, Limit: 120, UsageUnit: "segments",
+					Price: 0.9, PriceUnit: "usd"},
+				{Category: TwiTotalPrice, Usage: 27.71, Limit: 25, UsageUnit: "usd",
+					Price: 27.71, PriceUnit: "usd"},
+			}, true},
+		{map[UsageCategory]float64{TwiRecordings: 1}, nil, true},
+		{map[UsageCategory]float64{TwiCalls: 1, "minutes": 1}, nil, false},
+		{map[UsageCategory]float64{TwiTranscriptions: 1}, nil, false},
+		{map[UsageCategory]float64{}, nil, true},
+	}
+
+	for idx, test := range tests {
+		breaches, err := client.CheckUsageThresholds(context.Background(), test.Thresholds)
+		if (err == nil) != test.Valid {
+			t.Errorf("Test %v failed; expected valid %v, got %v", idx, test.Valid, err)
+			continue
+		}
+		if !reflect.DeepEqual(breaches, test.Expect) {
+			t.Errorf("Test %v failed; expected %v, got %v", idx, test.Expect, breaches)
+		}
+	}
+}
+
+// serviceMessage returns the xml of a message of a messaging service
+func serviceMessage(sid, service, segments, price string) string {
+	return `<Message><Sid>` + sid + `</Sid><MessagingServiceSid>` + service +
+		`</MessagingServiceSid><NumSegments>` + segments + `</NumSegments><Price>` +
+		price + `</Price><PriceUnit>USD</PriceUnit></Message>`
+}
+
+func TestServiceUsage(t *testing.T) {
+	const (
+		service = "MG0123456789abcdef0123456789abcdef"
+		other   = "MGfedcba9876543210fedcba9876543210"
+		first   = "/2010-04-01/Accounts/AC123/Messages?" +
+			"MessagingServiceSid=MG0123456789abcdef0123456789abcdef" +
+			"&DateSent%3C=2020-02-29&DateSent%3E=2020-02-01&PageSize=1000"
+		next = "/2010-04-01/Accounts/AC123/Messages?Page=1"
+	)
+	pages := map[string]string{
+		first: `<Messages page="0" nextpageuri="` + next + `">` +
+			serviceMessage("SM1", service, "1", "-0.00750") +
+			serviceMessage("SM2", service, "3", "-0.02250") +
+			serviceMessage("SM3", other, "2", "-0.01500") + `</Messages>`,
+		next: `<Messages page="1" nextpageuri="">` +
+			serviceMessage("SM4", service, "2", "") + `</Messages>`,
+	}
+	var requested []string
+	client, ts := testClient(t, http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			requested = append(requested, r.URL.RequestURI())
+			w.Write([]byte(xmlHeader + "<TwilioResponse>" +
+				pages[r.URL.RequestURI()] + "</TwilioResponse>"))
+		}))
+	defer ts.Close()
+
+	month := time.Date(2020, 2, 17, 23, 0, 0, 0, time.FixedZone("PST", -8*3600))
+	report, err := client.ServiceUsage(context.Background(), service, month)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := ServiceUsageReport{
+		ServiceSid: service,
+		Month:      time.Date(2020, 2, 1, 0, 0, 0, 0, time.UTC),
+		Messages:   3,
+		Segments:   6,
+		Price:      Money{Micros: -30000, Currency: "USD"},
+		Unpriced:   1,
+	}
+	if !reflect.DeepEqual(report, expect) {
+		t.Errorf("expected %+v, got %+v", expect, report)
+	}
+	if !reflect.DeepEqual(requested, []string{first, next}) {
+		t.Errorf("expected the pages of the month, got %v", requested)
+	}
+
+	for _, sid := range []string{"", "PN0123456789abcdef0123456789abcdef"} {
+		if _, err := client.ServiceUsage(context.Background(), sid, month); err == nil {
+			t.Errorf("expected an error for service %#v", sid)
+		}
+	}
+}
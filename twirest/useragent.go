@@ -0,0 +1,36 @@
+package twirest
+
+import (
+	"fmt"
+	"net/http"
+	"runtime"
+	"strings"
+)
+
+// Version is the version of the library, sent in the User-Agent header
+const Version = "0.1.0"
+
+// defaultUserAgent identifies the library in twilio's request logs
+var defaultUserAgent = "seanhagen-twilio/" + Version + " Go/" +
+	strings.TrimPrefix(runtime.Version(), "go")
+
+// WithUserAgentSuffix appends s to the User-Agent header of the requests of
+// the client, such as "myapp/1.2", to tell the traffic of applications apart
+func WithUserAgentSuffix(s string) ClientOption {
+	return ClientOption{apply: func(c *TwilioClient) {
+		if strings.ContainsAny(s, "\r\n") {
+			c.optErr = fmt.Errorf("non valid User-Agent suffix: '%s'", s)
+			return
+		}
+		c.userAgentSuffix = strings.TrimSpace(s)
+	}}
+}
+
+// setUserAgent sets the User-Agent header of the requests of the client
+func (twiClient *TwilioClient) setUserAgent(httpReq *http.Request) {
+	ua := defaultUserAgent
+	if twiClient.userAgentSuffix != "" {
+		ua += " " + twiClient.userAgentSuffix
+	}
+	httpReq.Header.Set("User-Agent", ua)
+}
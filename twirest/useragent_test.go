@@ -0,0 +1,52 @@
+package twirest
+
+import (
+	"net/http"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestUserAgent(t *testing.T) {
+	var agents []string
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		agents = append(agents, r.Method+" "+r.UserAgent())
+		w.Write([]byte(xmlHeader + "<TwilioResponse></TwilioResponse>"))
+	})
+	reqs := []interface{}{
+		Queues{},
+		SendMessage{From: "+15005550006", To: "+15005550001", Text: "Hi"},
+		DeleteQueue{Sid: "QU123"},
+	}
+
+	expect := "seanhagen-twilio/" + Version + " Go/" + strings.TrimPrefix(runtime.Version(), "go")
+	var tests = []struct {
+		Opts   []ClientOption
+		Expect string
+	}{
+		{nil, expect},
+		{[]ClientOption{WithUserAgentSuffix("myapp/1.2")}, expect + " myapp/1.2"},
+	}
+
+	for idx, test := range tests {
+		agents = nil
+		client, ts := testClient(t, handler, test.Opts...)
+		for _, req := range reqs {
+			client.Request(req, false)
+		}
+		ts.Close()
+
+		want := []string{"GET " + test.Expect, "POST " + test.Expect, "DELETE " + test.Expect}
+		if strings.Join(agents, "\n") != strings.Join(want, "\n") {
+			t.Errorf("Test %v failed; expected %v, got %v", idx, want, agents)
+		}
+	}
+
+	httpReq, _ := httpRequest(Queues{}, "AC123", nil)
+	if httpReq.UserAgent() != expect {
+		t.Errorf("expected %v on the http request, got %v", expect, httpReq.UserAgent())
+	}
+	if _, err := NewClient("AC123", "token", WithUserAgentSuffix("a\r\nX-Evil: 1")); err == nil {
+		t.Errorf("expected an error for a suffix with a newline")
+	}
+}
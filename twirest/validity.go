@@ -0,0 +1,209 @@
+package twirest
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+)
+
+const (
+	// DefaultValidityPeriod is how long twilio queues a message without a
+	// ValidityPeriod before it expires
+	DefaultValidityPeriod = 14400 * time.Second
+	// MaxValidityPeriod is the longest ValidityPeriod of a message
+	MaxValidityPeriod = 14400 * time.Second
+)
+
+// ValidityPolicy is what SendBulk does when messages are estimated to be sent
+// after their ValidityPeriod
+type ValidityPolicy int
+
+const (
+	// ValidityWarn logs the senders whose messages expire to the client
+	// logger and sends them
+	ValidityWarn ValidityPolicy = iota
+	// ValidityError sends nothing, every result has an ErrValidityExceeded
+	ValidityError
+	// ValidityRaise raises the ValidityPeriod of the expiring messages up
+	// to MaxValidityPeriod, then warns about those still expiring
+	ValidityRaise
+	// ValiditySplit spreads the messages of the senders whose messages
+	// expire over their BulkOptions.SplitFrom numbers, then warns about
+	// those still expiring
+	ValiditySplit
+)
+
+// ErrValidityExceeded is the error of a bulk send with ValidityError when
+// the messages of a sender are estimated to expire before they are sent
+type ErrValidityExceeded struct {
+	Sender         string
+	Drain          time.Duration
+	ValidityPeriod time.Duration
+	Expiring       int
+}
+
+func (e *ErrValidityExceeded) Error() string {
+	return fmt.Sprintf("%s takes %v to send its messages, %d expire after %v",
+		e.Sender, e.Drain, e.Expiring, e.ValidityPeriod)
+}
+
+// SenderEstimate is the estimated drain of the messages of a sender
+type SenderEstimate struct {
+	Sender   string
+	Class    SenderClass
+	Messages int
+	Interval time.Duration
+	// Drain is the time until the last message of the sender is sent
+	Drain time.Duration
+	// Expiring is the number of messages sent after their ValidityPeriod
+	Expiring int
+	// ValidityPeriod is the shortest ValidityPeriod of the messages
+	ValidityPeriod time.Duration
+}
+
+// BulkEstimate is the estimated drain of a bulk send
+type BulkEstimate struct {
+	// Drain is the time until the last message is sent
+	Drain time.Duration
+	// Senders are the estimates per sender, in order of appearance
+	Senders []SenderEstimate
+	// Expiring is the number of messages sent after their ValidityPeriod
+	Expiring int
+	// Warnings describe the senders whose messages still expire after the
+	// ValidityPolicy, set by PlanBulk
+	Warnings []string
+
+	sendAt []time.Duration
+}
+
+// EstimateBulk estimates when SendBulk sends each of msgs from the rate
+// limits of their senders, assuming twilio accepts the messages at once
+func EstimateBulk(msgs []SendMessage, opts BulkOptions) BulkEstimate {
+	est := BulkEstimate{sendAt: make([]time.Duration, len(msgs))}
+	senders := map[string]int{}
+	for i, msg := range msgs {
+		key := senderKey(msg)
+		idx, ok := senders[key]
+		if !ok {
+			class, ok := opts.Senders[key]
+			if !ok {
+				class = ClassifySender(msg)
+			}
+			idx = len(est.Senders)
+			senders[key] = idx
+			est.Senders = append(est.Senders, SenderEstimate{
+				Sender:         key,
+				Class:          class,
+				Interval:       opts.interval(key, msg),
+				ValidityPeriod: MaxValidityPeriod,
+			})
+		}
+
+		s := &est.Senders[idx]
+		at := time.Duration(s.Messages) * s.Interval
+		validity := validityPeriod(msg)
+		if validity < s.ValidityPeriod {
+			s.ValidityPeriod = validity
+		}
+		if at > validity {
+			s.Expiring++
+			est.Expiring++
+		}
+		s.Drain = at
+		s.Messages++
+		if at > est.Drain {
+			est.Drain = at
+		}
+		est.sendAt[i] = at
+	}
+	return est
+}
+
+// PlanBulk returns msgs as SendBulk sends them with opts, after the
+// ValidityPolicy, with their estimate and its warnings, without sending or
+// logging anything. The error is the ErrValidityExceeded of ValidityError.
+func PlanBulk(msgs []SendMessage, opts BulkOptions) ([]SendMessage, BulkEstimate, error) {
+	return opts.applyValidity(msgs)
+}
+
+// applyValidity applies the ValidityPolicy of opts to msgs, returning a copy
+// of msgs if they are changed, their estimate and the ErrValidityExceeded of
+// ValidityError
+func (opts BulkOptions) applyValidity(msgs []SendMessage) ([]SendMessage,
+	BulkEstimate, error) {
+
+	est := EstimateBulk(msgs, opts)
+	if est.Expiring == 0 {
+		return msgs, est, nil
+	}
+
+	switch opts.Validity {
+	case ValidityError:
+		for _, s := range est.Senders {
+			if s.Expiring > 0 {
+				return msgs, est, &ErrValidityExceeded{
+					Sender:         s.Sender,
+					Drain:          s.Drain,
+					ValidityPeriod: s.ValidityPeriod,
+					Expiring:       s.Expiring,
+				}
+			}
+		}
+	case ValidityRaise:
+		msgs = append([]SendMessage(nil), msgs...)
+		for i := range msgs {
+			if at := est.sendAt[i]; at > validityPeriod(msgs[i]) {
+				raised := time.Duration(math.Ceil(at.Seconds())) * time.Second
+				if raised > MaxValidityPeriod {
+					raised = MaxValidityPeriod
+				}
+				if msgs[i].ValiditySeconds != 0 {
+					msgs[i].ValiditySeconds = int(raised / time.Second)
+				} else {
+					msgs[i].ValidityPeriod = strconv.Itoa(int(raised / time.Second))
+				}
+			}
+		}
+		est = EstimateBulk(msgs, opts)
+	case ValiditySplit:
+		msgs = append([]SendMessage(nil), msgs...)
+		for _, s := range est.Senders {
+			spares := opts.SplitFrom[s.Sender]
+			if s.Expiring == 0 || len(spares) == 0 {
+				continue
+			}
+			pool := append([]string{s.Sender}, spares...)
+			n := 0
+			for i := range msgs {
+				if msgs[i].From != "" && msgs[i].From == s.Sender {
+					msgs[i].From = pool[n%len(pool)]
+					n++
+				}
+			}
+		}
+		est = EstimateBulk(msgs, opts)
+	}
+
+	for _, s := range est.Senders {
+		if s.Expiring > 0 {
+			est.Warnings = append(est.Warnings, fmt.Sprintf("%d messages of %s "+
+				"expire, it takes %v to send them with a validity period of %v",
+				s.Expiring, s.Sender, s.Drain, s.ValidityPeriod))
+		}
+	}
+	return msgs, est, nil
+}
+
+// validityPeriod returns the ValidityPeriod of msg, the default if not set
+// or not valid
+func validityPeriod(msg SendMessage) time.Duration {
+	if msg.ValiditySeconds > 0 {
+		return time.Duration(msg.ValiditySeconds) * time.Second
+	}
+	secs, err := strconv.Atoi(msg.ValidityPeriod)
+	if err != nil || secs <= 0 {
+		return DefaultValidityPeriod
+	}
+	return time.Duration(secs) * time.Second
+}
@@ -0,0 +1,177 @@
+package twirest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+// slowOpts sends from long codes at one message per 100 seconds
+var slowOpts = BulkOptions{MPS: map[SenderClass]float64{SenderLongCode: 0.01}}
+
+// bulkMessages returns n messages from each of the from numbers
+func bulkMessages(n int, validity string, from ...string) []SendMessage {
+	var msgs []SendMessage
+	for i := 0; i < n; i++ {
+		for _, f := range from {
+			msgs = append(msgs, SendMessage{From: f, To: "+16045550001", Text: "Hi",
+				ValidityPeriod: validity})
+		}
+	}
+	return msgs
+}
+
+func TestEstimateBulk(t *testing.T) {
+	const a, b = "+16045550123", "+16045550124"
+	var tests = []struct {
+		Msgs     []SendMessage
+		Opts     BulkOptions
+		Drain    time.Duration
+		Expiring int
+	}{
+		// 50k messages on a long code at 1 MPS
+		{bulkMessages(50000, "", a), BulkOptions{}, 49999 * time.Second, 35599},
+		{bulkMessages(14401, "", a), BulkOptions{}, 14400 * time.Second, 0},
+		{bulkMessages(14402, "", a), BulkOptions{}, 14401 * time.Second, 1},
+		{bulkMessages(4, "300", a), slowOpts, 300 * time.Second, 0},
+		{bulkMessages(5, "300", a), slowOpts, 400 * time.Second, 1},
+		{bulkMessages(5, "300", a, b), slowOpts, 400 * time.Second, 2},
+		{bulkMessages(5, "300", "55555"), slowOpts, 40 * time.Millisecond, 0},
+		{bulkMessages(5, "bogus", a), slowOpts, 400 * time.Second, 0},
+	}
+
+	for idx, test := range tests {
+		est := EstimateBulk(test.Msgs, test.Opts)
+		if est.Drain != test.Drain || est.Expiring != test.Expiring {
+			t.Errorf("Test %v failed; expected drain %v with %v expiring, got %v with %v",
+				idx, test.Drain, test.Expiring, est.Drain, est.Expiring)
+		}
+	}
+
+	est := EstimateBulk(bulkMessages(3, "150", a, b), slowOpts)
+	expect := SenderEstimate{Sender: b, Class: SenderLongCode, Messages: 3,
+		Interval: 100 * time.Second, Drain: 200 * time.Second, Expiring: 1,
+		ValidityPeriod: 150 * time.Second}
+	if len(est.Senders) != 2 || est.Senders[1] != expect {
+		t.Errorf("expected sender estimate %#v, got %#v", expect, est.Senders)
+	}
+}
+
+func TestSendBulkValidity(t *testing.T) {
+	const a, b, c = "+16045550123", "+16045550124", "+16045550125"
+	var sent int
+	client, ts := testClient(t, http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			sent++
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`<TwilioResponse><Message/></TwilioResponse>`))
+		}))
+	defer ts.Close()
+
+	var logged bytes.Buffer
+	log.SetOutput(&logged)
+	defer log.SetOutput(os.Stderr)
+
+	var tests = []struct {
+		Msgs     []SendMessage
+		Policy   ValidityPolicy
+		From     []string
+		Validity []string
+		Warning  bool
+	}{
+		{bulkMessages(3, "100", a), ValidityWarn,
+			[]string{a, a, a}, []string{"100", "100", "100"}, true},
+		{bulkMessages(3, "100", a), ValidityRaise,
+			[]string{a, a, a}, []string{"100", "100", "200"}, false},
+		{bulkMessages(3, "14300", a), ValidityRaise,
+			[]string{a, a, a}, []string{"14300", "14300", "14300"}, false},
+		{bulkMessages(146, "14300", a)[144:], ValidityRaise,
+			[]string{a, a}, []string{"14300", "14300"}, false},
+		{bulkMessages(3, "100", a), ValiditySplit,
+			[]string{a, b, a}, []string{"100", "100", "100"}, false},
+		{bulkMessages(5, "100", a), ValiditySplit,
+			[]string{a, b, a, b, a}, []string{"100", "100", "100", "100", "100"}, true},
+		{bulkMessages(3, "100", c), ValiditySplit,
+			[]string{c, c, c}, []string{"100", "100", "100"}, true},
+	}
+
+	for idx, test := range tests {
+		logged.Reset()
+		opts := slowOpts
+		opts.Validity = test.Policy
+		opts.SplitFrom = map[string][]string{a: {b}}
+		opts.DryRun = true
+		orig := append([]SendMessage(nil), test.Msgs...)
+
+		results := client.SendBulk(context.Background(), test.Msgs, opts)
+		for i, res := range results {
+			if res.Err != nil || res.Message.From != test.From[i] ||
+				res.Message.ValidityPeriod != test.Validity[i] {
+				t.Errorf("Test %v failed; result %v: expected %v valid for %v, got %#v",
+					idx, i, test.From[i], test.Validity[i], res)
+			}
+		}
+		// a dry run leaves the warnings to PlanBulk
+		if logged.Len() > 0 {
+			t.Errorf("Test %v failed; expected nothing logged, got %#v", idx, logged.String())
+		}
+		_, est, _ := PlanBulk(test.Msgs, opts)
+		if warned := len(est.Warnings) > 0; warned != test.Warning {
+			t.Errorf("Test %v failed; expected warning %v, got %#v", idx,
+				test.Warning, est.Warnings)
+		}
+		for i := range orig {
+			if test.Msgs[i] != orig[i] {
+				t.Errorf("Test %v failed; expected the messages unchanged", idx)
+			}
+		}
+	}
+
+	// a long code with a raised validity of 14400 still expires
+	opts := slowOpts
+	opts.Validity = ValidityRaise
+	opts.DryRun = true
+	results := client.SendBulk(context.Background(), bulkMessages(146, "100", a), opts)
+	_, est, _ := PlanBulk(bulkMessages(146, "100", a), opts)
+	if results[145].SendAt != 14500*time.Second ||
+		results[145].Message.ValidityPeriod != "14400" ||
+		len(est.Warnings) != 1 || !strings.Contains(est.Warnings[0], "1 messages of "+a) {
+		t.Errorf("expected the last message to expire, got %#v, warnings %#v",
+			results[145], est.Warnings)
+	}
+
+	if sent != 0 {
+		t.Fatalf("expected a dry run to send nothing, sent %v", sent)
+	}
+
+	opts.Validity = ValidityError
+	opts.DryRun = false
+	results = client.SendBulk(context.Background(), bulkMessages(3, "100", a, b), opts)
+	for i, res := range results {
+		var exceeded *ErrValidityExceeded
+		if !errors.As(res.Err, &exceeded) || exceeded.Sender != a ||
+			exceeded.Drain != 200*time.Second || exceeded.Expiring != 1 {
+			t.Errorf("result %v: expected ErrValidityExceeded, got %v", i, res.Err)
+		}
+	}
+	if sent != 0 {
+		t.Errorf("expected nothing sent when the validity is exceeded, sent %v", sent)
+	}
+
+	// the warnings of a send go to the client logger
+	l := &bufLogger{}
+	WithLogger(l).apply(client)
+	opts.Validity = ValidityWarn
+	opts.Clock = newFakeClock()
+	client.SendBulk(context.Background(), bulkMessages(3, "100", a), opts)
+	if !strings.Contains(l.String(), "1 messages of "+a) || logged.Len() > 0 {
+		t.Errorf("expected the warning in the client logger, got %#v, log %#v",
+			l.String(), logged.String())
+	}
+}